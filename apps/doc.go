@@ -0,0 +1,20 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package apps contains reference applications as library packages, that can be embedded or extended
+instead of starting from scratch:
+
+	apps/midimon   a MIDI monitor that logs every message of an input
+	apps/thru      a MIDI thru box with filters and transforms
+	apps/player    a player for Standard MIDI Files
+	apps/recorder  a recorder that writes Standard MIDI Files
+	apps/sequencer a step/pattern sequencer with loops and pattern chaining
+	apps/metronome a metronome for count-ins and click tracks
+
+The applications work on io.Readers and io.Writers for the MIDI ports (e.g. the raw MIDI devices
+/dev/snd/midiC1D0 on Linux or the ports of a driver package).
+
+*/
+package apps
@@ -0,0 +1,33 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package metronome provides a metronome that writes click notes on the beats, with a different note and velocity
+on the downbeat.
+
+The clicks are written in real time at the pulses of a clock.Clock (Run and CountIn) or generated as click track
+for a SMF file, that follows the time signatures and tempo changes of the file (Track).
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/apps/metronome"
+		"github.com/gomidi/midi/apps/player"
+		"github.com/gomidi/midi/clock"
+		"github.com/gomidi/midi/midiwriter"
+	)
+
+	out := midiwriter.New(port)
+	m := metronome.New(metronome.Meter(3, 4))
+
+	// two bars count-in before playing
+	err := m.CountIn(ctx, clock.NewInternal(96), out, 2)
+	err = p.Play(ctx, out)
+
+	// a click track for a file
+	clicks, err := m.Track(f)
+	f.Tracks = append(f.Tracks, clicks)
+
+*/
+package metronome
@@ -0,0 +1,202 @@
+package metronome
+
+import (
+	"context"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/clock"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf"
+)
+
+// Option is an option for a Metronome
+type Option func(*Metronome)
+
+// Channel is an option that sets the channel of the clicks (default: channel.Channel9, the GM drums)
+func Channel(ch channel.Channel) Option {
+	return func(m *Metronome) {
+		m.channel = ch
+	}
+}
+
+// Notes is an option that sets the keys of the clicks on the downbeat and on the other beats
+// (default: 76 and 77, the high and low wood block of GM drums)
+func Notes(downbeat, beat uint8) Option {
+	return func(m *Metronome) {
+		m.keys = [2]uint8{downbeat, beat}
+	}
+}
+
+// Velocity is an option that sets the velocities of the clicks on the downbeat and on the other beats (default: 127 and 100)
+func Velocity(downbeat, beat uint8) Option {
+	return func(m *Metronome) {
+		m.velocities = [2]uint8{downbeat, beat}
+	}
+}
+
+// Meter is an option that sets the time signature of the clicks in real time (default: 4/4). The beats are the note
+// values of the denominator, that must be one of 1, 2, 4, 8, 16 and 32, otherwise the option is ignored.
+// It does not affect Track, that follows the time signatures of the file.
+func Meter(numerator, denominator uint8) Option {
+	return func(m *Metronome) {
+		switch denominator {
+		case 1, 2, 4, 8, 16, 32:
+			if numerator > 0 {
+				m.numerator, m.denominator = numerator, denominator
+			}
+		}
+	}
+}
+
+// Metronome writes click notes on the beats. A click lasts a quarter of a beat.
+type Metronome struct {
+	channel     channel.Channel
+	keys        [2]uint8
+	velocities  [2]uint8
+	numerator   uint8
+	denominator uint8
+}
+
+// New returns a new metronome
+func New(opts ...Option) *Metronome {
+	m := &Metronome{
+		channel:     channel.Channel9,
+		keys:        [2]uint8{76, 77},
+		velocities:  [2]uint8{127, 100},
+		numerator:   4,
+		denominator: 4,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// click returns the note on and note off message for the given beat of the bar (starting with 0)
+func (m *Metronome) click(beat int) (on, off midi.Message) {
+	i := 1
+	if beat == 0 {
+		i = 0
+	}
+	return m.channel.NoteOn(m.keys[i], m.velocities[i]), m.channel.NoteOff(m.keys[i])
+}
+
+// Run writes the clicks to w at the pulses of clk, starting with a downbeat, until ctx is done.
+// The tempo is the tempo of clk, e.g. a clock.Internal whose tempo may be changed while running.
+// It returns ctx.Err() or the error of a failed write.
+func (m *Metronome) Run(ctx context.Context, clk clock.Clock, w midi.Writer) error {
+	return m.run(ctx, clk, w, 0)
+}
+
+// CountIn writes the clicks of the given number of bars like Run and returns nil at the downbeat after them,
+// e.g. to start playing or recording there.
+func (m *Metronome) CountIn(ctx context.Context, clk clock.Clock, w midi.Writer, bars int) error {
+	if bars <= 0 {
+		return nil
+	}
+	return m.run(ctx, clk, w, bars)
+}
+
+// run writes the clicks; if bars is positive, it returns after the given number of bars
+func (m *Metronome) run(ctx context.Context, clk clock.Clock, w midi.Writer, bars int) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		beatPulses = clock.PPQN * 4 / int(m.denominator)
+		offPulse   = beatPulses / 4
+		pulses     int
+		off        midi.Message
+		done       bool
+	)
+
+	if offPulse == 0 {
+		offPulse = 1
+	}
+
+	clkErr := clk.Run(ctx, func() {
+		if done || err != nil {
+			return
+		}
+
+		defer func() { pulses++ }()
+
+		beat, rel := pulses/beatPulses, pulses%beatPulses
+
+		if rel == offPulse && off != nil {
+			err = w.Write(off)
+			off = nil
+		}
+
+		if rel == 0 {
+			if bars > 0 && beat == bars*int(m.numerator) {
+				done = true
+				cancel()
+				return
+			}
+
+			var on midi.Message
+			on, off = m.click(beat % int(m.numerator))
+
+			if err == nil {
+				err = w.Write(on)
+			}
+		}
+
+		if err != nil {
+			cancel()
+		}
+	})
+
+	if off != nil {
+		w.Write(off)
+	}
+
+	switch {
+	case err != nil:
+		return err
+	case done:
+		return nil
+	default:
+		return clkErr
+	}
+}
+
+// Track returns a click track for the file, following its time signatures (without time signature 4/4 is assumed).
+// It lasts until the end of the longest track. Since the clicks are positioned in ticks, they follow the tempo
+// changes of the file. The file must have a metric time format.
+func (m *Metronome) Track(f *smf.File) (*smf.Track, error) {
+	tl, err := smf.NewTimeline(f)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &smf.Track{}
+
+	for _, tr := range f.Tracks {
+		if tr.End > t.End {
+			t.End = tr.End
+		}
+	}
+
+	for bar := uint32(1); ; bar++ {
+		for beat := uint32(1); ; beat++ {
+			abs := tl.AbsTicks(smf.Position{Bar: bar, Beat: beat})
+
+			if abs >= t.End {
+				return t, nil
+			}
+
+			if tl.Position(abs).Bar != bar {
+				break
+			}
+
+			next := tl.AbsTicks(smf.Position{Bar: bar, Beat: beat + 1})
+			on, off := m.click(int(beat - 1))
+			t.Insert(abs, on)
+			t.Insert(abs+(next-abs)/4, off)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package metronome
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/clock"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+)
+
+type fakeTime struct {
+	t time.Time
+}
+
+func (f *fakeTime) now() time.Time {
+	return f.t
+}
+
+func (f *fakeTime) sleep(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+type logWriter struct {
+	bf    *bytes.Buffer
+	ft    *fakeTime
+	start time.Time
+}
+
+func (l logWriter) Write(msg midi.Message) error {
+	fmt.Fprintf(l.bf, "%s %s\n", l.ft.t.Sub(l.start), msg)
+	return nil
+}
+
+func TestCountIn(t *testing.T) {
+	ft := &fakeTime{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+	start := ft.t
+
+	m := New(Meter(3, 4))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	err := m.CountIn(context.Background(), clock.NewInternal(120, clock.Time(ft.now, ft.sleep)), logWriter{&out, ft, start}, 1)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := ft.t.Sub(start), 1500*time.Millisecond; got != want {
+		t.Errorf("count in returned at %v; wanted %v", got, want)
+	}
+
+	expected := `
+0s channel.NoteOn channel 9 key 76 velocity 127
+125ms channel.NoteOff channel 9 key 76
+500ms channel.NoteOn channel 9 key 77 velocity 100
+625ms channel.NoteOff channel 9 key 77
+1s channel.NoteOn channel 9 key 77 velocity 100
+1.125s channel.NoteOff channel 9 key 77
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	ft := &fakeTime{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+	start := ft.t
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sleep := func(d time.Duration) {
+		ft.sleep(d)
+		if ft.t.Sub(start) >= 700*time.Millisecond {
+			cancel()
+		}
+	}
+
+	m := New(Meter(6, 8), Notes(33, 34), Velocity(90, 60))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	err := m.Run(ctx, clock.NewInternal(120, clock.Time(ft.now, sleep)), logWriter{&out, ft, start})
+
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	// eighth notes of 250ms
+	expected := `
+0s channel.NoteOn channel 9 key 33 velocity 90
+62.5ms channel.NoteOff channel 9 key 33
+250ms channel.NoteOn channel 9 key 34 velocity 60
+312.5ms channel.NoteOff channel 9 key 34
+500ms channel.NoteOn channel 9 key 34 velocity 60
+562.5ms channel.NoteOff channel 9 key 34
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestTrack(t *testing.T) {
+	f := &smf.File{Format: smf.SMF1, TimeFormat: smf.MetricTicks(96)}
+	tr := f.AddTrack()
+	tr.Insert(0, meta.TimeSig{Numerator: 3, Denominator: 4, ClocksPerClick: 24, DemiSemiQuaverPerQuarter: 8})
+	tr.Insert(288, meta.TimeSig{Numerator: 2, Denominator: 4, ClocksPerClick: 24, DemiSemiQuaverPerQuarter: 8})
+	tr.End = 480
+
+	clicks, err := New().Track(f)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for _, ev := range clicks.Events {
+		fmt.Fprintf(&out, "%v %s\n", ev.AbsTicks, ev.Message)
+	}
+
+	fmt.Fprintf(&out, "end %v\n", clicks.End)
+
+	expected := `
+0 channel.NoteOn channel 9 key 76 velocity 127
+24 channel.NoteOff channel 9 key 76
+96 channel.NoteOn channel 9 key 77 velocity 100
+120 channel.NoteOff channel 9 key 77
+192 channel.NoteOn channel 9 key 77 velocity 100
+216 channel.NoteOff channel 9 key 77
+288 channel.NoteOn channel 9 key 76 velocity 127
+312 channel.NoteOff channel 9 key 76
+384 channel.NoteOn channel 9 key 77 velocity 100
+408 channel.NoteOff channel 9 key 77
+end 480
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
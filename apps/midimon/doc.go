@@ -0,0 +1,22 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package midimon provides a MIDI monitor application that logs every message of an input
+with its time, raw bytes, channel and description (see the monitor package), optionally
+passing the messages through to an output.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/apps/midimon"
+	)
+
+	in, _ := os.Open("/dev/snd/midiC1D0")
+
+	// logs until ctx is done or the input is closed
+	err := midimon.Run(ctx, in, os.Stdout, midimon.HideClock())
+
+*/
+package midimon
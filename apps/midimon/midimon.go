@@ -0,0 +1,126 @@
+package midimon
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+	"github.com/gomidi/midi/monitor"
+)
+
+type config struct {
+	thru      io.Writer
+	hideClock bool
+	now       func() time.Time
+	monitor   []monitor.Option
+}
+
+// Option is an option for Run
+type Option func(*config)
+
+// Thru is an option that passes all messages of the input through to the given output
+func Thru(out io.Writer) Option {
+	return func(c *config) {
+		c.thru = out
+	}
+}
+
+// HideClock is an option that hides the frequent timing clock and active sensing messages from the log
+// (they are still passed through)
+func HideClock() Option {
+	return func(c *config) {
+		c.hideClock = true
+	}
+}
+
+// Name is an option that sets the name of the input that is shown in the log
+func Name(name string) Option {
+	return func(c *config) {
+		c.monitor = append(c.monitor, monitor.Name(name))
+	}
+}
+
+// Clock is an option that sets the function that returns the current time (default: the time of arrival
+// that is captured by the reader)
+func Clock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+		c.monitor = append(c.monitor, monitor.Clock(now))
+	}
+}
+
+// Run logs the messages of in to log, one line per message, until ctx is done or in returns an error.
+// It returns nil for io.EOF and ctx.Err() when ctx is done.
+func Run(ctx context.Context, in io.Reader, log io.Writer, opts ...Option) error {
+	var c config
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	mon := monitor.New(monitor.WriteTo(log), c.monitor...)
+
+	var thru midiwriter.RealtimeWriter
+	if c.thru != nil {
+		thru = midiwriter.New(c.thru).(midiwriter.RealtimeWriter)
+	}
+
+	pass := func(msg realtime.Message) {
+		if thru != nil {
+			thru.WriteRealtime(msg)
+		}
+	}
+
+	logged := mon.Realtime(pass)
+
+	rthandler := func(msg realtime.Message) {
+		if c.hideClock && (msg == realtime.TimingClock || msg == realtime.Activesense) {
+			pass(msg)
+			return
+		}
+		logged(msg)
+	}
+
+	var ropts []midireader.Option
+	if c.now == nil {
+		ropts = append(ropts, midireader.Timestamps())
+	}
+
+	rd := mon.Reader(&contextReader{ctx, midireader.New(in, rthandler, ropts...).(midireader.ContextReader)})
+
+	for {
+		msg, err := rd.Read()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if thru != nil {
+			thru.Write(msg)
+		}
+	}
+}
+
+// contextReader reads with the context and passes the time of arrival
+type contextReader struct {
+	ctx context.Context
+	rd  midireader.ContextReader
+}
+
+// Read reads the next message with the context
+func (c *contextReader) Read() (midi.Message, error) {
+	return c.rd.ReadContext(c.ctx)
+}
+
+// Time returns the time of arrival of the last message
+func (c *contextReader) Time() time.Time {
+	return c.rd.(midireader.Timestamped).Time()
+}
@@ -0,0 +1,49 @@
+package midimon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+func TestRun(t *testing.T) {
+	var in bytes.Buffer
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+	wr.Write(realtime.TimingClock)
+	wr.Write(realtime.Start)
+	wr.Write(channel.Channel1.NoteOff(60))
+
+	var log, thru bytes.Buffer
+	log.WriteString("\n")
+
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	err := Run(context.Background(), &in, &log, Thru(&thru), HideClock(), Name("keys"), Clock(clock))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+12:00:00.000000  in   keys  91 3C 64  ch 1   channel.NoteOn channel 1 key 60 velocity 100
+12:00:00.000000  in   keys  FA  ch --  Start
+12:00:00.000000  in   keys  91 3C 00  ch 1   channel.NoteOff channel 1 key 60
+`
+
+	if got, want := log.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	// the input is passed through unchanged, including the running status
+	if got, want := fmt.Sprintf("% X", thru.Bytes()), "91 3C 64 F8 FA 3C 00"; got != want {
+		t.Errorf("thru got %q; wanted %q", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+package player
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+)
+
+// control are the settings of the position and tempo, that may be changed while playing
+type control struct {
+	// seek is the time to continue playing at, if not nil
+	seek *time.Duration
+
+	// the loop region, if loopTo > loopFrom
+	loopFrom, loopTo time.Duration
+
+	// factor is the tempo multiplier
+	factor float64
+}
+
+// control returns the settings of the position and tempo and clears the pending seek
+func (p *Player) control() control {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	tr := p.ctrl
+	p.ctrl.seek = nil
+	return tr
+}
+
+// Seek continues playing at the given absolute position in ticks. It is meant to be called while playing:
+// the sounding notes are ended and the programs and controllers at the position are written first (see PlayFrom).
+func (p *Player) Seek(tick uint64) {
+	to := p.TimeAt(tick)
+
+	p.mx.Lock()
+	p.ctrl.seek = &to
+	p.mx.Unlock()
+	p.signal()
+}
+
+// SetLoop sets the region from the tick from up to (excluding) the tick to, that is repeated when playing reaches it.
+// At the end of the region, the sounding notes are ended and playing continues at from with the programs and
+// controllers at that position. If to is not after from, looping is turned off. It may be called while playing.
+func (p *Player) SetLoop(from, to uint64) {
+	f, t := p.TimeAt(from), p.TimeAt(to)
+
+	p.mx.Lock()
+	if to > from {
+		p.ctrl.loopFrom, p.ctrl.loopTo = f, t
+	} else {
+		p.ctrl.loopFrom, p.ctrl.loopTo = 0, 0
+	}
+	p.mx.Unlock()
+	p.signal()
+}
+
+// SetTempoFactor sets the factor the tempos of the file are multiplied with, e.g. 0.5 plays at half speed to practice.
+// Factors that are not positive are ignored. The times of the events and Duration are not affected.
+// It may be called while playing.
+func (p *Player) SetTempoFactor(factor float64) {
+	if factor <= 0 {
+		return
+	}
+
+	p.mx.Lock()
+	p.ctrl.factor = factor
+	p.mx.Unlock()
+	p.signal()
+}
+
+// TempoFactor returns the factor the tempos of the file are multiplied with (default: 1)
+func (p *Player) TempoFactor() float64 {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	return p.ctrl.factor
+}
+
+// TimeAt returns the time of the absolute position in ticks, respecting the tempo changes
+func (p *Player) TimeAt(tick uint64) time.Duration {
+	return p.timeAt(float64(tick))
+}
+
+// timeAt returns the time of the (fractional) absolute position in ticks
+func (p *Player) timeAt(tick float64) time.Duration {
+	// the tempo only changes at events, so the time is interpolated between the surrounding events
+	i := sort.Search(len(p.events), func(i int) bool {
+		return float64(p.events[i].Tick) > tick
+	})
+
+	var (
+		lastTick uint64
+		lastTime time.Duration
+		nextTick = p.end
+		nextTime = p.duration
+	)
+
+	if i > 0 {
+		lastTick, lastTime = p.events[i-1].Tick, p.events[i-1].Time
+	}
+
+	if i < len(p.events) {
+		nextTick, nextTime = p.events[i].Tick, p.events[i].Time
+	}
+
+	if nextTick <= lastTick {
+		return lastTime
+	}
+
+	return lastTime + time.Duration(math.Round(float64(nextTime-lastTime)*(tick-float64(lastTick))/float64(nextTick-lastTick)))
+}
+
+// tickAt returns the (fractional) absolute position in ticks that is played at the given time
+func (p *Player) tickAt(d time.Duration) float64 {
+	i := sort.Search(len(p.events), func(i int) bool {
+		return p.events[i].Time > d
+	})
+
+	var (
+		lastTick uint64
+		lastTime time.Duration
+		nextTick = p.end
+		nextTime = p.duration
+	)
+
+	if i > 0 {
+		lastTick, lastTime = p.events[i-1].Tick, p.events[i-1].Time
+	}
+
+	if i < len(p.events) {
+		nextTick, nextTime = p.events[i].Tick, p.events[i].Time
+	}
+
+	if nextTime <= lastTime {
+		return float64(lastTick)
+	}
+
+	return float64(lastTick) + float64(nextTick-lastTick)*float64(d-lastTime)/float64(nextTime-lastTime)
+}
+
+// BarTick returns the absolute position in ticks of the start of the given bar (starting with 1),
+// respecting the time signatures. The time format of the file must be metric.
+func (p *Player) BarTick(bar uint32) (uint64, error) {
+	var t smf.Track
+
+	for _, ev := range p.events {
+		if _, is := ev.Message.(meta.TimeSig); is {
+			t.Events = append(t.Events, smf.Event{AbsTicks: ev.Tick, Message: ev.Message})
+		}
+	}
+
+	tl, err := smf.NewTimeline(&smf.File{TimeFormat: p.header.TimeFormat, Tracks: []*smf.Track{&t}})
+	if err != nil {
+		return 0, err
+	}
+
+	return tl.AbsTicks(smf.Position{Bar: bar, Beat: 1}), nil
+}
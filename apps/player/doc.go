@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package player provides a player for Standard MIDI Files (SMF).
+
+The file is completely loaded when the player is created: the tracks are merged into a single list of events
+with their absolute positions in ticks and in time (respecting the tempo changes). Playing writes the events
+at their time to a midi.Writer. Meta messages are not written, since they can't be sent over the wire.
+When playing is canceled, the notes that are still sounding are released.
+PlayFrom and PlayFromMarker start in the middle of the song (e.g. at a marker named "chorus"),
+after sending the programs and controllers that are in effect there.
+
+The tracks of SMF2 files are independent patterns (e.g. of a drum machine). They are played one after another,
+each with its own tempo. Playlist returns a player for a sequence of patterns, where a pattern may be repeated:
+
+	intro, err := p.Playlist(0, 1, 1, 2)
+
+For live backing tracks, tracks can be muted and soloed, and written to another writer or channel,
+also while playing (e.g. from a controller goroutine). The sounding notes of a muted track are ended at once:
+
+	p.Solo(2, true)
+	p.SetOutput(3, midiwriter.New(synth))
+	p.SetChannel(3, 9)
+
+To practice, a region can be looped and the tempo can be slowed down. Positions are given in ticks,
+BarTick converts bars to ticks. Seek jumps while playing; like PlayFrom, it ends the sounding notes and
+restores the programs and controllers at the new position:
+
+	from, _ := p.BarTick(9)
+	to, _ := p.BarTick(17)
+	p.SetLoop(from, to)
+	p.SetTempoFactor(0.75)
+
+	go p.PlayFromBar(ctx, wr, 9)
+
+To integrate with hardware sequencers and drum machines, the player can be the MIDI clock master with
+the SendClock option (writing Start/Continue, song position pointers, timing clock and Stop messages),
+or follow the clock of another device as slave:
+
+	in := make(chan midi.Message, 64)
+
+	// the realtime messages (clock, start, stop) are passed to the handler,
+	// the song position pointers are read as usual and must be sent to in, too
+	rd := midireader.New(port, func(m realtime.Message) { in <- m })
+
+	err = p.Follow(ctx, wr, in)
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/apps/player"
+		"github.com/gomidi/midi/midiwriter"
+	)
+
+	p, err := player.Load("song.mid")
+
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("duration: %s\n", p.Duration())
+
+	err = p.Play(ctx, midiwriter.New(out))
+
+*/
+package player
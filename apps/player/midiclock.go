@@ -0,0 +1,155 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/clock"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/transport"
+)
+
+// SendClock is an option that lets the player write MIDI clock messages to the writer it plays to (master):
+// Start or a song position pointer and Continue when playing starts or jumps, a timing clock message per pulse
+// (24 per quarter note, following the tempo changes and the tempo factor) and Stop when playing stops.
+// The clock runs up to the end of the song. It requires a metric time format and is ignored otherwise.
+func SendClock() Option {
+	return func(p *Player) {
+		p.sendClock = true
+	}
+}
+
+// ticksPerPulse returns the ticks per MIDI clock pulse, or 0 if the time format is not metric
+func (p *Player) ticksPerPulse() float64 {
+	tf, is := p.header.TimeFormat.(smf.MetricTicks)
+	if !is {
+		return 0
+	}
+	return float64(tf.Ticks4th()) / clock.PPQN
+}
+
+// master sends the MIDI clock while playing
+type master struct {
+	p   *Player
+	t   *transport.Transport
+	tpp float64
+}
+
+func newMaster(p *Player, w midi.Writer, tpp float64) *master {
+	return &master{p: p, t: transport.New(transport.Output(w)), tpp: tpp}
+}
+
+// locate writes the position of the given time to the slaves and starts them. The position is rounded up to
+// the next 16th note, since song position pointers have no finer resolution.
+func (m *master) locate(to time.Duration) error {
+	if err := m.stop(); err != nil {
+		return err
+	}
+
+	spp := uint32(math.Ceil(m.p.tickAt(to)/(m.tpp*transport.PulsesPerStep) - 1e-9))
+
+	if spp == 0 {
+		return m.t.Start()
+	}
+
+	if err := m.t.Locate(spp); err != nil {
+		return err
+	}
+
+	return m.t.Continue()
+}
+
+// pending returns whether there is a pulse before the end of the song
+func (m *master) pending() bool {
+	return float64(m.t.State().Pulses())*m.tpp < float64(m.p.end)
+}
+
+// time returns the time of the next pulse
+func (m *master) time() time.Duration {
+	return m.p.timeAt(float64(m.t.State().Pulses()) * m.tpp)
+}
+
+// pulse writes the next pulse
+func (m *master) pulse() error {
+	return m.t.Pulse()
+}
+
+// stop stops the slaves, if they are running
+func (m *master) stop() error {
+	if !m.t.State().Playing {
+		return nil
+	}
+	return m.t.Stop()
+}
+
+// Follow plays the song to w following the MIDI clock of another device (slave), until ctx is done or in is closed.
+// The messages of the other device are passed via in and interpreted by a transport.Transport: Start plays from the start,
+// Stop stops playing and ends the sounding notes, Continue continues at the current position, a song position pointer
+// (syscommon.SPP) moves the position (restoring the programs and controllers like PlayFrom) and each timing clock message
+// advances the position by a pulse (1/24 quarter note), writing the events up to it. Other messages are ignored.
+// The tempo changes and the tempo factor have no effect, the settings of the tracks are respected.
+// The time format must be metric.
+func (p *Player) Follow(ctx context.Context, w midi.Writer, in <-chan midi.Message) (err error) {
+	tpp := p.ticksPerPulse()
+	if tpp == 0 {
+		return fmt.Errorf("following a MIDI clock requires a metric time format, not %v", p.header.TimeFormat)
+	}
+
+	o := newOutput(p, w)
+
+	defer o.release(true)
+
+	// i is the index of the next event
+	var i int
+
+	tr := transport.New()
+
+	tr.OnChange(func(c transport.Change, s transport.State) {
+		if err != nil {
+			return
+		}
+
+		tick := float64(s.Pulses()) * tpp
+
+		switch c {
+		case transport.Started, transport.Located:
+			o.release(true)
+
+			i = sort.Search(len(p.events), func(i int) bool {
+				return float64(p.events[i].Tick) >= tick
+			})
+
+			err = o.chase(i)
+		case transport.Stopped:
+			o.release(true)
+		case transport.Clocked:
+			for ; i < len(p.events) && float64(p.events[i].Tick) <= tick; i++ {
+				if err = o.write(p.events[i]); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-in:
+			if !ok {
+				return nil
+			}
+
+			o.release(false)
+			tr.Write(msg)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
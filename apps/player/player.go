@@ -0,0 +1,766 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+// Event is a message of a SMF file at its absolute position
+type Event struct {
+	// Track is the number of the track (starting with 0)
+	Track int16
+
+	// Tick is the absolute position in ticks
+	Tick uint64
+
+	// Time is the absolute position in time, respecting the tempo changes
+	Time time.Duration
+
+	// Message is the message
+	Message midi.Message
+}
+
+// Option is an option for a Player
+type Option func(*Player)
+
+// Clock is an option that sets the functions that return the current time and that sleep (default: time.Now and a timer).
+// A sleep that is set this way can't be interrupted by the context.
+func Clock(now func() time.Time, sleep func(time.Duration)) Option {
+	return func(p *Player) {
+		p.now = now
+		p.sleep = sleep
+	}
+}
+
+// Player plays a SMF file
+type Player struct {
+	header   smf.Header
+	events   []Event
+	duration time.Duration
+	now      func() time.Time
+	sleep    func(time.Duration)
+
+	// sendClock is set by the SendClock option
+	sendClock bool
+
+	// patterns are the tracks of SMF2 files
+	patterns []pattern
+
+	// end is the end of the song in ticks
+	end uint64
+
+	// mx protects the settings of the tracks, of the position and of the tempo, that may be changed while playing
+	mx     sync.Mutex
+	tracks map[int16]*trackSettings
+	ctrl   control
+
+	// changed signals a change of the settings of the tracks to the playing goroutine
+	changed chan struct{}
+}
+
+// trackSettings are the settings of a track
+type trackSettings struct {
+	mute    bool
+	solo    bool
+	output  midi.Writer
+	channel int
+}
+
+// pattern is a track of a SMF2 file
+type pattern struct {
+	events   []Event
+	end      uint64
+	duration time.Duration
+}
+
+// Load loads the SMF file with the given name
+func Load(file string, opts ...Option) (*Player, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return New(smfreader.New(f), opts...)
+}
+
+// New reads all events from rd and returns a player for them.
+// The tracks of SMF1 files are merged. The tracks of SMF2 files are independent patterns that are played
+// one after another (see Playlist).
+func New(rd smf.Reader, opts ...Option) (*Player, error) {
+	p := &Player{now: time.Now, changed: make(chan struct{}, 1), ctrl: control{factor: 1}}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if err := rd.ReadHeader(); err != nil {
+		return nil, err
+	}
+
+	p.header = rd.Header()
+
+	var (
+		track = int16(-1)
+		tick  uint64
+		end   uint64
+	)
+
+	for {
+		msg, err := rd.Read()
+
+		if err == smf.ErrFinished {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if rd.Track() != track {
+			track, tick = rd.Track(), 0
+
+			if p.header.Format == smf.SMF2 {
+				p.patterns = append(p.patterns, pattern{})
+				end = 0
+			}
+		}
+
+		tick += uint64(rd.Delta())
+
+		if tick > end {
+			end = tick
+		}
+
+		if p.header.Format == smf.SMF2 {
+			pt := &p.patterns[len(p.patterns)-1]
+			pt.end = end
+
+			if msg != meta.EndOfTrack {
+				pt.events = append(pt.events, Event{Track: track, Tick: tick, Message: msg})
+			}
+			continue
+		}
+
+		if msg == meta.EndOfTrack {
+			continue
+		}
+
+		p.events = append(p.events, Event{Track: track, Tick: tick, Message: msg})
+	}
+
+	if p.header.Format == smf.SMF2 {
+		for i := range p.patterns {
+			pt := &p.patterns[i]
+			pt.duration = timeEvents(p.header.TimeFormat, pt.events, pt.end)
+		}
+		p.events, p.end, p.duration = p.playlist(nil)
+		return p, nil
+	}
+
+	// keeps the order of the tracks for events at the same tick
+	sort.SliceStable(p.events, func(a, b int) bool {
+		return p.events[a].Tick < p.events[b].Tick
+	})
+
+	p.end = end
+	p.duration = timeEvents(p.header.TimeFormat, p.events, end)
+	return p, nil
+}
+
+// Playlist returns a player that plays the given tracks of a SMF2 file one after another, e.g. the patterns of a drum machine.
+// A track may be given multiple times. Each track starts with the default tempo, unless it sets its own tempo.
+// The ticks and times of the events are relative to the start of the playlist.
+func (p *Player) Playlist(tracks ...int) (*Player, error) {
+	if p.header.Format != smf.SMF2 {
+		return nil, fmt.Errorf("playlists are only supported for SMF2 files")
+	}
+
+	for _, tr := range tracks {
+		if tr < 0 || tr >= len(p.patterns) {
+			return nil, fmt.Errorf("track %v not found (the file has %v tracks)", tr, len(p.patterns))
+		}
+	}
+
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("empty playlist")
+	}
+
+	pl := &Player{
+		header:    p.header,
+		now:       p.now,
+		sleep:     p.sleep,
+		sendClock: p.sendClock,
+		patterns:  p.patterns,
+		changed:   make(chan struct{}, 1),
+		ctrl:      control{factor: 1},
+	}
+	pl.events, pl.end, pl.duration = p.playlist(tracks)
+	return pl, nil
+}
+
+// NumPatterns returns the number of tracks of a SMF2 file that can be played via Playlist (0 for other files)
+func (p *Player) NumPatterns() int {
+	return len(p.patterns)
+}
+
+// playlist returns the events of the given patterns one after another, the end tick and the total duration.
+// If tracks is nil, all patterns are returned in their order.
+func (p *Player) playlist(tracks []int) (events []Event, tick uint64, duration time.Duration) {
+	if tracks == nil {
+		for i := range p.patterns {
+			tracks = append(tracks, i)
+		}
+	}
+
+	for _, tr := range tracks {
+		pt := p.patterns[tr]
+
+		for _, ev := range pt.events {
+			ev.Tick += tick
+			ev.Time += duration
+			events = append(events, ev)
+		}
+
+		tick += pt.end
+		duration += pt.duration
+	}
+
+	return
+}
+
+// timeEvents calculates the times of the events and returns the time of the given end tick
+func timeEvents(timeFormat smf.TimeFormat, events []Event, end uint64) time.Duration {
+	var (
+		// nanoseconds per tick
+		nsPerTick float64
+		last      uint64
+		t         float64
+	)
+
+	switch tf := timeFormat.(type) {
+	case smf.MetricTicks:
+		// the default tempo is 120 BPM
+		nsPerTick = 500000000 / float64(tf.Ticks4th())
+	case smf.TimeCode:
+		fps := float64(tf.FramesPerSecond)
+		if tf.FramesPerSecond == 29 {
+			fps = 29.97
+		}
+		nsPerTick = 1000000000 / (fps * float64(tf.SubFrames))
+	}
+
+	for i, ev := range events {
+		t += float64(ev.Tick-last) * nsPerTick
+		last = ev.Tick
+		events[i].Time = time.Duration(t)
+
+		if tempo, is := ev.Message.(meta.Tempo); is {
+			if tf, is := timeFormat.(smf.MetricTicks); is {
+				nsPerTick = float64(tempo.MuSecPerQN()) * 1000 / float64(tf.Ticks4th())
+			}
+		}
+	}
+
+	return time.Duration(t + float64(end-last)*nsPerTick)
+}
+
+// Header returns the header of the SMF file
+func (p *Player) Header() smf.Header {
+	return p.header
+}
+
+// Events returns the events of all tracks ordered by their position
+func (p *Player) Events() []Event {
+	return p.events
+}
+
+// Duration returns the duration of the song (up to the last end of track)
+func (p *Player) Duration() time.Duration {
+	return p.duration
+}
+
+// settings returns the settings of the track, p.mx must be locked
+func (p *Player) settings(track int16) *trackSettings {
+	if p.tracks == nil {
+		p.tracks = map[int16]*trackSettings{}
+	}
+
+	ts, has := p.tracks[track]
+	if !has {
+		ts = &trackSettings{channel: -1}
+		p.tracks[track] = ts
+	}
+
+	return ts
+}
+
+// change changes the settings of the track and signals the change to the playing goroutine
+func (p *Player) change(track int16, fn func(*trackSettings)) {
+	p.mx.Lock()
+	fn(p.settings(track))
+	p.mx.Unlock()
+	p.signal()
+}
+
+// signal signals a change of the settings to the playing goroutine
+func (p *Player) signal() {
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Mute mutes or unmutes the track (starting with 0). It may be called while playing: the sounding notes of the track are
+// ended and its following notes are not played. The other messages of muted tracks are still written, so that the
+// controllers and programs are right, when the track is unmuted.
+func (p *Player) Mute(track int16, mute bool) {
+	p.change(track, func(ts *trackSettings) { ts.mute = mute })
+}
+
+// Solo sets or removes the solo of the track. If any track is soloed, only the notes of the soloed tracks
+// are played (see Mute). It may be called while playing.
+func (p *Player) Solo(track int16, solo bool) {
+	p.change(track, func(ts *trackSettings) { ts.solo = solo })
+}
+
+// Audible returns whether the notes of the track are played, respecting mute and solo
+func (p *Player) Audible(track int16) bool {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	return p.audible(track)
+}
+
+func (p *Player) audible(track int16) bool {
+	if ts := p.tracks[track]; ts != nil && ts.mute {
+		return false
+	}
+
+	for tr, ts := range p.tracks {
+		if ts.solo && tr != track {
+			if own := p.tracks[track]; own == nil || !own.solo {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// SetOutput sets the writer the messages of the track are written to. If w is nil, they are written to the writer
+// passed to Play (the default). It may be called while playing; sounding notes are ended via the writer they were started with.
+func (p *Player) SetOutput(track int16, w midi.Writer) {
+	p.change(track, func(ts *trackSettings) { ts.output = w })
+}
+
+// SetChannel sets the channel (0-15) the channel messages of the track are written to. If ch is -1, the messages keep their
+// channel (the default). It may be called while playing; sounding notes are ended on the channel they were started with.
+func (p *Player) SetChannel(track int16, ch int) {
+	if ch > 15 {
+		ch = 15
+	}
+	if ch < -1 {
+		ch = -1
+	}
+	p.change(track, func(ts *trackSettings) { ts.channel = ch })
+}
+
+// route returns the writer and the message for the event, respecting the output and channel of its track,
+// and whether the notes of the track are played
+func (p *Player) route(w midi.Writer, ev Event) (midi.Writer, midi.Message, bool) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	msg := ev.Message
+
+	if ts := p.tracks[ev.Track]; ts != nil {
+		if ts.output != nil {
+			w = ts.output
+		}
+
+		if cm, is := msg.(channel.Message); is && ts.channel >= 0 {
+			msg = channel.SetChannel(cm, uint8(ts.channel))
+		}
+	}
+
+	return w, msg, p.audible(ev.Track)
+}
+
+// noteKey identifies a sounding note by its track and its original channel and key
+type noteKey struct {
+	track        int16
+	channel, key uint8
+}
+
+// soundingNote is a note that has been started on a writer and channel
+type soundingNote struct {
+	w       midi.Writer
+	channel uint8
+}
+
+// noteOf returns the channel and key of note messages and whether it is a note on
+func noteOf(msg midi.Message) (ch, key uint8, on bool, is bool) {
+	switch v := msg.(type) {
+	case channel.NoteOn:
+		return v.Channel(), v.Key(), v.Velocity() > 0, true
+	case channel.NoteOff:
+		return v.Channel(), v.Key(), false, true
+	case channel.NoteOffVelocity:
+		return v.Channel(), v.Key(), false, true
+	default:
+		return 0, 0, false, false
+	}
+}
+
+// output writes the events of the player to w, respecting the settings of the tracks, and keeps track of the sounding notes
+type output struct {
+	p        *Player
+	w        midi.Writer
+	sounding map[noteKey]soundingNote
+}
+
+func newOutput(p *Player, w midi.Writer) *output {
+	return &output{p: p, w: w, sounding: map[noteKey]soundingNote{}}
+}
+
+// release ends the sounding notes, all or those of the tracks that are not audible
+func (o *output) release(all bool) {
+	for k, n := range o.sounding {
+		if all || !o.p.Audible(k.track) {
+			n.w.Write(channel.Channel(n.channel).NoteOff(k.key))
+			delete(o.sounding, k)
+		}
+	}
+}
+
+// chase writes the programs and controllers that are in effect at the event with the given index
+func (o *output) chase(i int) error {
+	for _, ev := range o.p.chase(i) {
+		out, msg, _ := o.p.route(o.w, ev)
+		if err := out.Write(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write writes the event, meta messages are skipped
+func (o *output) write(ev Event) error {
+	if _, is := ev.Message.(meta.Message); is {
+		return nil
+	}
+
+	out, msg, audible := o.p.route(o.w, ev)
+	ch, key, on, isNote := noteOf(ev.Message)
+	k := noteKey{ev.Track, ch, key}
+
+	switch {
+	case isNote && on:
+		if !audible {
+			return nil
+		}
+		o.sounding[k] = soundingNote{out, msg.(channel.Message).Channel()}
+	case isNote:
+		// the note off is written like its note on; note offs without note on (e.g. when starting in between)
+		// are only written for audible tracks
+		n, has := o.sounding[k]
+		if !has {
+			if !audible {
+				return nil
+			}
+			break
+		}
+		delete(o.sounding, k)
+		out, msg = n.w, channel.SetChannel(ev.Message.(channel.Message), n.channel)
+	}
+
+	return out.Write(msg)
+}
+
+// Play writes the events at their time to w, until all events are written or ctx is done.
+// Meta messages are skipped. When playing is canceled or a write fails, note offs are sent for the notes that are still sounding.
+// It returns ctx.Err(), if ctx is done before the end.
+func (p *Player) Play(ctx context.Context, w midi.Writer) error {
+	return p.PlayFrom(ctx, w, 0)
+}
+
+// PlayFrom plays like Play, but starts at the given time. The last program change, control change, pitch bend and
+// aftertouch message of each channel before that time are written first, so that the channels sound as they would.
+// The messages are written according to the mute, solo, output and channel settings of their tracks.
+// While playing, the position can be changed with Seek, a region can be looped with SetLoop and the tempo can be
+// changed with SetTempoFactor. With the SendClock option, MIDI clock messages are written to w, too.
+func (p *Player) PlayFrom(ctx context.Context, w midi.Writer, from time.Duration) (err error) {
+	o := newOutput(p, w)
+
+	var (
+		// i is the index of the next event
+		i int
+
+		// the time in the song pos is played at the time start
+		pos   time.Duration
+		start time.Time
+
+		factor = p.TempoFactor()
+
+		// mc sends the MIDI clock, if it is not nil
+		mc *master
+	)
+
+	if tpp := p.ticksPerPulse(); p.sendClock && tpp > 0 {
+		mc = newMaster(p, w, tpp)
+	}
+
+	defer func() {
+		if err != nil {
+			o.release(true)
+		}
+		if mc != nil {
+			mc.stop()
+		}
+	}()
+
+	// jump continues playing at the given time in the song
+	jump := func(to time.Duration) error {
+		o.release(true)
+
+		i = sort.Search(len(p.events), func(i int) bool {
+			return p.events[i].Time >= to
+		})
+
+		if err := o.chase(i); err != nil {
+			return err
+		}
+
+		if mc != nil {
+			if err := mc.locate(to); err != nil {
+				return err
+			}
+		}
+
+		pos, start = to, p.now()
+		return nil
+	}
+
+	// a seek before playing has no effect
+	p.control()
+
+	if err = jump(from); err != nil {
+		return
+	}
+
+	// the kinds of the next thing to do
+	const (
+		done = iota
+		event
+		pulse
+		loop
+		end
+	)
+
+	for {
+		if i < len(p.events) {
+			if _, is := p.events[i].Message.(meta.Message); is {
+				i++
+				continue
+			}
+		}
+
+		tr := p.control()
+		now := p.now()
+		song := pos + time.Duration(float64(now.Sub(start))*factor)
+
+		if tr.factor != factor {
+			pos, start, factor = song, now, tr.factor
+		}
+
+		if tr.seek != nil {
+			if err = jump(*tr.seek); err != nil {
+				return
+			}
+			continue
+		}
+
+		var (
+			next   = done
+			target time.Duration
+		)
+
+		if i < len(p.events) {
+			next, target = event, p.events[i].Time
+		}
+
+		if mc != nil && mc.pending() {
+			if t := mc.time(); next == done || t <= target {
+				next, target = pulse, t
+			}
+		}
+
+		if tr.loopTo > tr.loopFrom && song < tr.loopTo && (next == done || tr.loopTo <= target) {
+			next, target = loop, tr.loopTo
+		}
+
+		if next == done {
+			// the clock runs up to the end of the song
+			if mc == nil || song >= p.duration {
+				return nil
+			}
+			next, target = end, p.duration
+		}
+
+		var changed bool
+		if changed, err = p.wait(ctx, time.Duration(float64(target-song)/factor)); err != nil {
+			return
+		}
+
+		o.release(false)
+
+		if changed {
+			continue
+		}
+
+		switch next {
+		case loop:
+			err = jump(tr.loopFrom)
+		case pulse:
+			err = mc.pulse()
+		case event:
+			err = o.write(p.events[i])
+			i++
+		case end:
+			return nil
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// PlayFromTick plays like Play, but starts at the given absolute position in ticks (see PlayFrom)
+func (p *Player) PlayFromTick(ctx context.Context, w midi.Writer, tick uint64) error {
+	return p.PlayFrom(ctx, w, p.TimeAt(tick))
+}
+
+// PlayFromBar plays like Play, but starts at the given bar, starting with 1 (see PlayFrom and BarTick)
+func (p *Player) PlayFromBar(ctx context.Context, w midi.Writer, bar uint32) error {
+	tick, err := p.BarTick(bar)
+	if err != nil {
+		return err
+	}
+	return p.PlayFromTick(ctx, w, tick)
+}
+
+// PlayFromMarker plays like Play, but starts at the first marker or cue point with the given name (see PlayFrom)
+func (p *Player) PlayFromMarker(ctx context.Context, w midi.Writer, name string) error {
+	for _, ev := range p.Markers() {
+		if markerName(ev.Message) == name {
+			return p.PlayFrom(ctx, w, ev.Time)
+		}
+	}
+	return fmt.Errorf("marker %q not found", name)
+}
+
+// Markers returns the events of the markers and cue points
+func (p *Player) Markers() (res []Event) {
+	for _, ev := range p.events {
+		switch ev.Message.(type) {
+		case meta.Marker, meta.Cuepoint:
+			res = append(res, ev)
+		}
+	}
+	return
+}
+
+// markerName returns the name of a marker or cue point
+func markerName(msg midi.Message) string {
+	switch m := msg.(type) {
+	case meta.Marker:
+		return string(m)
+	case meta.Cuepoint:
+		return string(m)
+	default:
+		return ""
+	}
+}
+
+// chase returns the events of the last program change, control change, pitch bend and aftertouch message of each channel
+// (and controller) before the event with the given index, in their order
+func (p *Player) chase(end int) (res []Event) {
+	type key struct {
+		kind, channel, controller uint8
+	}
+
+	var (
+		last = map[key]int{}
+		idx  []int
+	)
+
+	for i, ev := range p.events[:end] {
+		var k key
+
+		switch m := ev.Message.(type) {
+		case channel.ProgramChange:
+			k = key{0xC, m.Channel(), 0}
+		case channel.ControlChange:
+			k = key{0xB, m.Channel(), m.Controller()}
+		case channel.Pitchbend:
+			k = key{0xE, m.Channel(), 0}
+		case channel.Aftertouch:
+			k = key{0xD, m.Channel(), 0}
+		default:
+			continue
+		}
+
+		last[k] = i
+	}
+
+	for _, i := range last {
+		idx = append(idx, i)
+	}
+
+	sort.Ints(idx)
+
+	for _, i := range idx {
+		res = append(res, p.events[i])
+	}
+
+	return
+}
+
+// wait waits for the duration d, until ctx is done or until the settings of the tracks are changed (then changed is true)
+func (p *Player) wait(ctx context.Context, d time.Duration) (changed bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if d <= 0 {
+		return false, nil
+	}
+
+	if p.sleep != nil {
+		p.sleep(d)
+		return false, ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-p.changed:
+		return true, nil
+	case <-t.C:
+		return false, nil
+	}
+}
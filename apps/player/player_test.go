@@ -0,0 +1,685 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package player
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+// mkSMF returns a SMF1 file with 96 ticks per quarter note, a tempo track with a tempo change from 120 to 60 BPM
+// after a quarter note and a note track
+func mkSMF() []byte {
+	var bf bytes.Buffer
+
+	wr := smfwriter.New(&bf, smfwriter.NumTracks(2), smfwriter.TimeFormat(smf.MetricTicks(96)))
+
+	// tempo track
+	wr.Write(meta.FractionalBPM(120))
+	wr.SetDelta(96)
+	wr.Write(meta.FractionalBPM(60))
+	wr.Write(meta.EndOfTrack)
+
+	// note track
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+	wr.SetDelta(96)
+	wr.Write(channel.Channel0.NoteOff(60))
+	wr.Write(channel.Channel0.NoteOn(62, 100))
+	wr.SetDelta(96)
+	wr.Write(channel.Channel0.NoteOff(62))
+	wr.SetDelta(48)
+	wr.Write(meta.EndOfTrack)
+
+	return bf.Bytes()
+}
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time {
+	return f.t
+}
+
+func (f *fakeClock) sleep(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+type logWriter struct {
+	bf    *bytes.Buffer
+	clock *fakeClock
+	start time.Time
+}
+
+func (l logWriter) Write(msg midi.Message) error {
+	fmt.Fprintf(l.bf, "%s %s\n", l.clock.t.Sub(l.start), msg)
+	return nil
+}
+
+func TestPlayer(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	p, err := New(smfreader.New(bytes.NewReader(mkSMF())), Clock(clock.now, clock.sleep))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := p.Duration(), 2000*time.Millisecond; got != want {
+		t.Errorf("Duration() = %v; wanted %v", got, want)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	err = p.Play(context.Background(), logWriter{&out, clock, clock.t})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+0s channel.NoteOn channel 0 key 60 velocity 100
+500ms channel.NoteOff channel 0 key 60
+500ms channel.NoteOn channel 0 key 62 velocity 100
+1.5s channel.NoteOff channel 0 key 62
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestPlayerCancel(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// cancel while waiting for the second note
+	sleep := func(d time.Duration) {
+		clock.sleep(d)
+		if clock.t.Sub(time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)) >= time.Second {
+			cancel()
+		}
+	}
+
+	p, err := New(smfreader.New(bytes.NewReader(mkSMF())), Clock(clock.now, sleep))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	err = p.Play(ctx, logWriter{&out, clock, clock.t})
+
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	expected := `
+0s channel.NoteOn channel 0 key 60 velocity 100
+500ms channel.NoteOff channel 0 key 60
+500ms channel.NoteOn channel 0 key 62 velocity 100
+1.5s channel.NoteOff channel 0 key 62
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestPlayFromMarker(t *testing.T) {
+	var bf bytes.Buffer
+
+	wr := smfwriter.New(&bf, smfwriter.NumTracks(2), smfwriter.TimeFormat(smf.MetricTicks(96)))
+
+	wr.Write(meta.Marker("A"))
+	wr.SetDelta(96)
+	wr.Write(meta.Marker("B"))
+	wr.Write(meta.EndOfTrack)
+
+	wr.Write(channel.Channel0.ProgramChange(5))
+	wr.Write(channel.Channel0.ControlChange(7, 100))
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+	wr.SetDelta(48)
+	wr.Write(channel.Channel0.ControlChange(7, 80))
+	wr.SetDelta(48)
+	wr.Write(channel.Channel0.NoteOff(60))
+	wr.Write(channel.Channel0.NoteOn(62, 100))
+	wr.SetDelta(96)
+	wr.Write(channel.Channel0.NoteOff(62))
+	wr.Write(meta.EndOfTrack)
+
+	clock := &fakeClock{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	p, err := New(smfreader.New(bytes.NewReader(bf.Bytes())), Clock(clock.now, clock.sleep))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := len(p.Markers()), 2; got != want {
+		t.Errorf("len(Markers()) = %v; wanted %v", got, want)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	if err := p.PlayFromMarker(context.Background(), logWriter{&out, clock, clock.t}, "B"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+0s channel.ProgramChange channel 0 program 5
+0s channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 80
+0s channel.NoteOff channel 0 key 60
+0s channel.NoteOn channel 0 key 62 velocity 100
+500ms channel.NoteOff channel 0 key 62
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if err := p.PlayFromMarker(context.Background(), logWriter{&out, clock, clock.t}, "C"); err == nil {
+		t.Errorf("expected error for unknown marker")
+	}
+}
+
+func TestPlaylist(t *testing.T) {
+	var bf bytes.Buffer
+
+	wr := smfwriter.New(&bf, smfwriter.NumTracks(2), smfwriter.Format(smf.SMF2), smfwriter.TimeFormat(smf.MetricTicks(96)))
+
+	// pattern 0: one bar of a quarter note at 60 BPM
+	wr.Write(meta.FractionalBPM(60))
+	wr.Write(channel.Channel9.NoteOn(36, 100))
+	wr.SetDelta(96)
+	wr.Write(channel.Channel9.NoteOff(36))
+	wr.Write(meta.EndOfTrack)
+
+	// pattern 1: an eighth note and an eighth rest at the default tempo
+	wr.Write(channel.Channel9.NoteOn(38, 100))
+	wr.SetDelta(48)
+	wr.Write(channel.Channel9.NoteOff(38))
+	wr.SetDelta(48)
+	wr.Write(meta.EndOfTrack)
+
+	clock := &fakeClock{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	p, err := New(smfreader.New(bytes.NewReader(bf.Bytes())), Clock(clock.now, clock.sleep))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := p.Header().Format, smf.SMF2; got != want {
+		t.Errorf("Format = %v; wanted %v", got, want)
+	}
+
+	if got, want := p.NumPatterns(), 2; got != want {
+		t.Errorf("NumPatterns() = %v; wanted %v", got, want)
+	}
+
+	tests := []struct {
+		tracks   []int
+		duration time.Duration
+		expected string
+	}{
+		{nil, 1500 * time.Millisecond, `
+0s channel.NoteOn channel 9 key 36 velocity 100
+1s channel.NoteOff channel 9 key 36
+1s channel.NoteOn channel 9 key 38 velocity 100
+1.25s channel.NoteOff channel 9 key 38
+`},
+		{[]int{1, 0, 1}, 2000 * time.Millisecond, `
+0s channel.NoteOn channel 9 key 38 velocity 100
+250ms channel.NoteOff channel 9 key 38
+500ms channel.NoteOn channel 9 key 36 velocity 100
+1.5s channel.NoteOff channel 9 key 36
+1.5s channel.NoteOn channel 9 key 38 velocity 100
+1.75s channel.NoteOff channel 9 key 38
+`},
+	}
+
+	for i, test := range tests {
+		pl := p
+
+		if test.tracks != nil {
+			pl, err = p.Playlist(test.tracks...)
+
+			if err != nil {
+				t.Fatalf("[%v] unexpected error: %v", i, err)
+			}
+		}
+
+		if got, want := pl.Duration(), test.duration; got != want {
+			t.Errorf("[%v] Duration() = %v; wanted %v", i, got, want)
+		}
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		if err := pl.Play(context.Background(), logWriter{&out, clock, clock.t}); err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+
+	if _, err := p.Playlist(2); err == nil {
+		t.Errorf("expected error for unknown track")
+	}
+
+	p, _ = New(smfreader.New(bytes.NewReader(mkSMF())))
+
+	if _, err := p.Playlist(0); err == nil {
+		t.Errorf("expected error for playlist of SMF1 file")
+	}
+}
+
+func TestTrackSettings(t *testing.T) {
+	var bf bytes.Buffer
+
+	wr := smfwriter.New(&bf, smfwriter.NumTracks(3), smfwriter.TimeFormat(smf.MetricTicks(96)))
+
+	// tempo track
+	wr.Write(meta.FractionalBPM(120))
+	wr.Write(meta.EndOfTrack)
+
+	// bass
+	wr.Write(channel.Channel1.ProgramChange(33))
+	wr.Write(channel.Channel1.NoteOn(36, 100))
+	wr.SetDelta(192)
+	wr.Write(channel.Channel1.NoteOff(36))
+	wr.Write(meta.EndOfTrack)
+
+	// melody
+	wr.Write(channel.Channel2.NoteOn(72, 100))
+	wr.SetDelta(96)
+	wr.Write(channel.Channel2.NoteOff(72))
+	wr.Write(channel.Channel2.NoteOn(74, 100))
+	wr.SetDelta(96)
+	wr.Write(channel.Channel2.NoteOff(74))
+	wr.Write(meta.EndOfTrack)
+
+	start := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		setup    func(p *Player, clock *fakeClock, other midi.Writer)
+		expected string
+		other    string
+	}{
+		{
+			func(p *Player, clock *fakeClock, other midi.Writer) {},
+			`
+0s channel.ProgramChange channel 1 program 33
+0s channel.NoteOn channel 1 key 36 velocity 100
+0s channel.NoteOn channel 2 key 72 velocity 100
+500ms channel.NoteOff channel 2 key 72
+500ms channel.NoteOn channel 2 key 74 velocity 100
+1s channel.NoteOff channel 1 key 36
+1s channel.NoteOff channel 2 key 74
+`,
+			"\n",
+		},
+		{
+			func(p *Player, clock *fakeClock, other midi.Writer) {
+				p.Solo(2, true)
+			},
+			`
+0s channel.ProgramChange channel 1 program 33
+0s channel.NoteOn channel 2 key 72 velocity 100
+500ms channel.NoteOff channel 2 key 72
+500ms channel.NoteOn channel 2 key 74 velocity 100
+1s channel.NoteOff channel 2 key 74
+`,
+			"\n",
+		},
+		{
+			func(p *Player, clock *fakeClock, other midi.Writer) {
+				p.SetOutput(1, other)
+				p.SetChannel(1, 5)
+			},
+			`
+0s channel.NoteOn channel 2 key 72 velocity 100
+500ms channel.NoteOff channel 2 key 72
+500ms channel.NoteOn channel 2 key 74 velocity 100
+1s channel.NoteOff channel 2 key 74
+`,
+			`
+0s channel.ProgramChange channel 5 program 33
+0s channel.NoteOn channel 5 key 36 velocity 100
+1s channel.NoteOff channel 5 key 36
+`,
+		},
+		{
+			// mute the bass while playing, rerouting does not affect the sounding note
+			func(p *Player, clock *fakeClock, other midi.Writer) {
+				p.sleep = func(d time.Duration) {
+					clock.sleep(d)
+					p.SetChannel(1, 5)
+					if clock.t.Sub(start) >= 500*time.Millisecond {
+						p.Mute(1, true)
+					}
+				}
+			},
+			`
+0s channel.ProgramChange channel 1 program 33
+0s channel.NoteOn channel 1 key 36 velocity 100
+0s channel.NoteOn channel 2 key 72 velocity 100
+500ms channel.NoteOff channel 1 key 36
+500ms channel.NoteOff channel 2 key 72
+500ms channel.NoteOn channel 2 key 74 velocity 100
+1s channel.NoteOff channel 2 key 74
+`,
+			"\n",
+		},
+	}
+
+	for i, test := range tests {
+		clock := &fakeClock{t: start}
+
+		p, err := New(smfreader.New(bytes.NewReader(bf.Bytes())), Clock(clock.now, clock.sleep))
+
+		if err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		var out, other bytes.Buffer
+		out.WriteString("\n")
+		other.WriteString("\n")
+
+		test.setup(p, clock, logWriter{&other, clock, start})
+
+		if err := p.Play(context.Background(), logWriter{&out, clock, start}); err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+
+		if got, want := other.String(), test.other; got != want {
+			t.Errorf("[%v] other got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	p, err := New(smfreader.New(bytes.NewReader(mkSMF())))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		tick     uint64
+		expected time.Duration
+	}{
+		{0, 0},
+		{48, 250 * time.Millisecond},
+		{96, 500 * time.Millisecond},
+		{144, 1000 * time.Millisecond},
+		{240, 2000 * time.Millisecond},
+	}
+
+	for i, test := range tests {
+		if got, want := p.TimeAt(test.tick), test.expected; got != want {
+			t.Errorf("[%v] TimeAt(%v) = %v; wanted %v", i, test.tick, got, want)
+		}
+	}
+
+	for bar, want := range map[uint32]uint64{1: 0, 2: 384, 3: 768} {
+		got, err := p.BarTick(bar)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != want {
+			t.Errorf("BarTick(%v) = %v; wanted %v", bar, got, want)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	start := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		play     func(p *Player, clock *fakeClock, w midi.Writer) error
+		expected string
+	}{
+		{
+			func(p *Player, clock *fakeClock, w midi.Writer) error {
+				return p.PlayFromTick(context.Background(), w, 96)
+			},
+			`
+0s channel.NoteOff channel 0 key 60
+0s channel.NoteOn channel 0 key 62 velocity 100
+1s channel.NoteOff channel 0 key 62
+`,
+		},
+		{
+			func(p *Player, clock *fakeClock, w midi.Writer) error {
+				p.SetTempoFactor(2)
+				return p.Play(context.Background(), w)
+			},
+			`
+0s channel.NoteOn channel 0 key 60 velocity 100
+250ms channel.NoteOff channel 0 key 60
+250ms channel.NoteOn channel 0 key 62 velocity 100
+750ms channel.NoteOff channel 0 key 62
+`,
+		},
+		{
+			// seek back to the start once
+			func(p *Player, clock *fakeClock, w midi.Writer) error {
+				var seeked bool
+				p.sleep = func(d time.Duration) {
+					clock.sleep(d)
+					if !seeked {
+						seeked = true
+						p.Seek(0)
+					}
+				}
+				return p.Play(context.Background(), w)
+			},
+			`
+0s channel.NoteOn channel 0 key 60 velocity 100
+500ms channel.NoteOff channel 0 key 60
+500ms channel.NoteOn channel 0 key 60 velocity 100
+1s channel.NoteOff channel 0 key 60
+1s channel.NoteOn channel 0 key 62 velocity 100
+2s channel.NoteOff channel 0 key 62
+`,
+		},
+		{
+			// loop the second note until canceled
+			func(p *Player, clock *fakeClock, w midi.Writer) error {
+				ctx, cancel := context.WithCancel(context.Background())
+				p.sleep = func(d time.Duration) {
+					clock.sleep(d)
+					if clock.t.Sub(start) >= 3*time.Second {
+						cancel()
+					}
+				}
+				p.SetLoop(96, 192)
+				return p.Play(ctx, w)
+			},
+			`
+0s channel.NoteOn channel 0 key 60 velocity 100
+500ms channel.NoteOff channel 0 key 60
+500ms channel.NoteOn channel 0 key 62 velocity 100
+1.5s channel.NoteOff channel 0 key 62
+1.5s channel.NoteOff channel 0 key 60
+1.5s channel.NoteOn channel 0 key 62 velocity 100
+2.5s channel.NoteOff channel 0 key 62
+2.5s channel.NoteOff channel 0 key 60
+2.5s channel.NoteOn channel 0 key 62 velocity 100
+3.5s channel.NoteOff channel 0 key 62
+`,
+		},
+	}
+
+	for i, test := range tests {
+		clock := &fakeClock{t: start}
+
+		p, err := New(smfreader.New(bytes.NewReader(mkSMF())), Clock(clock.now, clock.sleep))
+
+		if err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		err = test.play(p, clock, logWriter{&out, clock, start})
+
+		if err != nil && err != context.Canceled {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+// clockWriter logs the messages except the timing clock messages, whose times are collected
+type clockWriter struct {
+	logWriter
+	pulses []time.Duration
+}
+
+func (c *clockWriter) Write(msg midi.Message) error {
+	if msg == realtime.TimingClock {
+		c.pulses = append(c.pulses, c.clock.t.Sub(c.start))
+		return nil
+	}
+	return c.logWriter.Write(msg)
+}
+
+func TestSendClock(t *testing.T) {
+	start := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		from     uint64
+		expected string
+		pulses   int
+		// the times of pulses by their index
+		times map[int]time.Duration
+	}{
+		{0, `
+0s Start
+0s channel.NoteOn channel 0 key 60 velocity 100
+500ms channel.NoteOff channel 0 key 60
+500ms channel.NoteOn channel 0 key 62 velocity 100
+1.5s channel.NoteOff channel 0 key 62
+2s Stop
+`, 60, map[int]time.Duration{1: 500 * time.Millisecond / 24, 24: 500 * time.Millisecond, 48: 1500 * time.Millisecond}},
+		{96, `
+0s syscommon.SPP: 4
+0s Continue
+0s channel.NoteOff channel 0 key 60
+0s channel.NoteOn channel 0 key 62 velocity 100
+1s channel.NoteOff channel 0 key 62
+1.5s Stop
+`, 36, map[int]time.Duration{0: 0, 24: 1000 * time.Millisecond}},
+	}
+
+	for i, test := range tests {
+		clock := &fakeClock{t: start}
+
+		p, err := New(smfreader.New(bytes.NewReader(mkSMF())), Clock(clock.now, clock.sleep), SendClock())
+
+		if err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		w := &clockWriter{logWriter: logWriter{&out, clock, start}}
+
+		if err := p.PlayFromTick(context.Background(), w, test.from); err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+
+		if got, want := len(w.pulses), test.pulses; got != want {
+			t.Errorf("[%v] pulses = %v; wanted %v", i, got, want)
+			continue
+		}
+
+		for idx, want := range test.times {
+			if got := w.pulses[idx]; got != want {
+				t.Errorf("[%v] time of pulse %v = %v; wanted %v", i, idx, got, want)
+			}
+		}
+	}
+}
+
+func TestFollow(t *testing.T) {
+	p, err := New(smfreader.New(bytes.NewReader(mkSMF())))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	in := make(chan midi.Message, 100)
+
+	in <- realtime.Start
+	for i := 0; i < 25; i++ {
+		in <- realtime.TimingClock
+	}
+	in <- realtime.Stop
+	in <- realtime.TimingClock
+	in <- syscommon.SPP(4)
+	in <- realtime.Continue
+	in <- realtime.TimingClock
+	close(in)
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	clock := &fakeClock{}
+
+	if err := p.Follow(context.Background(), logWriter{&out, clock, clock.t}, in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+0s channel.NoteOn channel 0 key 60 velocity 100
+0s channel.NoteOff channel 0 key 60
+0s channel.NoteOn channel 0 key 62 velocity 100
+0s channel.NoteOff channel 0 key 62
+0s channel.NoteOff channel 0 key 60
+0s channel.NoteOn channel 0 key 62 velocity 100
+0s channel.NoteOff channel 0 key 62
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package recorder provides a recorder that timestamps incoming MIDI messages and writes them
+to a Standard MIDI File (SMF0) with a fixed tempo.
+
+Realtime messages are not recorded. The recording starts with the first message, unless Start is called before.
+CountIn plays bars of metronome clicks and starts the recording at the downbeat after them.
+With the Punch option only the events between the punch-in and punch-out are recorded. MergeInto adds the recording
+to an existing track, replacing its events in the punch region or, with the Overdub option, merging them.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/apps/recorder"
+		"github.com/gomidi/midi/midireader"
+	)
+
+	rec := recorder.New(recorder.Tempo(100))
+
+	// records until ctx is done
+	err := rec.Record(ctx, midireader.New(in, nil, midireader.Timestamps()))
+
+	err = rec.Save("take1.mid")
+
+	// overdub bars 5-8 of a 4/4 track with 960 ticks per quarter note after two bars count-in
+	rec = recorder.New(recorder.Tempo(100), recorder.Punch(4*4*960, 8*4*960), recorder.Overdub())
+	err = rec.CountIn(ctx, nil, out, nil, 2)
+	err = rec.Record(ctx, in)
+	rec.MergeInto(file.Tracks[1])
+
+*/
+package recorder
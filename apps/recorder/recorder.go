@@ -0,0 +1,386 @@
+package recorder
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/apps/metronome"
+	"github.com/gomidi/midi/clock"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+// Option is an option for a Recorder
+type Option func(*Recorder)
+
+// Tempo is an option that sets the tempo of the recording in beats per minute (default: 120)
+func Tempo(bpm float64) Option {
+	return func(r *Recorder) {
+		r.tempo = bpm
+	}
+}
+
+// Resolution is an option that sets the ticks per quarter note of the SMF file (default: 960)
+func Resolution(ticks smf.MetricTicks) Option {
+	return func(r *Recorder) {
+		r.resolution = ticks
+	}
+}
+
+// Clock is an option that sets the function that returns the current time (default: time.Now)
+func Clock(now func() time.Time) Option {
+	return func(r *Recorder) {
+		r.now = now
+	}
+}
+
+// Punch is an option that restricts the recording to the ticks from in (punch-in) to out (punch-out),
+// counted from the start of the recording. If out is 0, there is no punch-out.
+// Notes that are still sounding at the punch-out end there.
+func Punch(in, out uint32) Option {
+	return func(r *Recorder) {
+		r.punchIn, r.punchOut = in, out
+	}
+}
+
+// Overdub is an option that lets MergeInto merge the recording into the existing events of a track
+// instead of replacing them.
+func Overdub() Option {
+	return func(r *Recorder) {
+		r.overdub = true
+	}
+}
+
+type event struct {
+	time time.Duration
+	msg  midi.Message
+}
+
+// Recorder records messages. It is safe for concurrent use.
+type Recorder struct {
+	mx         sync.Mutex
+	tempo      float64
+	resolution smf.MetricTicks
+	now        func() time.Time
+	start      time.Time
+	events     []event
+	punchIn    uint32
+	punchOut   uint32
+	overdub    bool
+
+	// counting is set during the count-in
+	counting bool
+}
+
+// New returns a new recorder
+func New(opts ...Option) *Recorder {
+	r := &Recorder{
+		tempo:      120,
+		resolution: smf.MetricTicks(960),
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Start starts the recording at the current time. Otherwise the recording starts with the first message.
+func (r *Recorder) Start() {
+	r.mx.Lock()
+	r.start = r.now()
+	r.mx.Unlock()
+}
+
+// CountIn writes the given number of bars of clicks of m to w and starts the recording at the downbeat after them.
+// The clicks are driven by clk; if clk is nil, an internal clock with the tempo of the recorder is used.
+// If m is nil, a metronome with the default options is used. Messages during the count-in are not recorded.
+// It returns ctx.Err(), if ctx is done before the end of the count-in; the recording is not started then.
+func (r *Recorder) CountIn(ctx context.Context, clk clock.Clock, w midi.Writer, m *metronome.Metronome, bars int) error {
+	if clk == nil {
+		clk = clock.NewInternal(r.tempo)
+	}
+
+	if m == nil {
+		m = metronome.New()
+	}
+
+	r.mx.Lock()
+	r.counting = true
+	r.mx.Unlock()
+
+	err := m.CountIn(ctx, clk, w, bars)
+
+	r.mx.Lock()
+	r.counting = false
+	r.mx.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	r.Start()
+	return nil
+}
+
+// Write records the message at the current time. It allows to use the recorder as midi.Writer
+// (e.g. as output of a router).
+func (r *Recorder) Write(msg midi.Message) error {
+	r.add(r.now(), msg)
+	return nil
+}
+
+// add records the message at the given time
+func (r *Recorder) add(t time.Time, msg midi.Message) {
+	if _, is := msg.(realtime.Message); is {
+		return
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.counting {
+		return
+	}
+
+	if r.start.IsZero() {
+		r.start = t
+	}
+
+	d := t.Sub(r.start)
+	if d < 0 {
+		d = 0
+	}
+
+	r.events = append(r.events, event{d, msg})
+}
+
+type timed interface {
+	Time() time.Time
+}
+
+type contextReader interface {
+	ReadContext(ctx context.Context) (midi.Message, error)
+}
+
+// Record reads and records the messages of rd, until ctx is done or rd returns an error.
+// The time of a message is taken from rd, if it has a Time method that returns a non zero time
+// (like the reader returned by midireader.New with the Timestamps option).
+// If rd has a ReadContext method (like the readers of midireader), a pending read is canceled when ctx is done.
+// It returns nil for io.EOF and smf.ErrFinished and ctx.Err() when ctx is done.
+func (r *Recorder) Record(ctx context.Context, rd midi.Reader) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var msg midi.Message
+		var err error
+
+		if cr, ok := rd.(contextReader); ok {
+			msg, err = cr.ReadContext(ctx)
+		} else {
+			msg, err = rd.Read()
+		}
+
+		if err == io.EOF || err == smf.ErrFinished {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var t time.Time
+		if tr, ok := rd.(timed); ok {
+			t = tr.Time()
+		}
+
+		if t.IsZero() {
+			t = r.now()
+		}
+
+		r.add(t, msg)
+	}
+}
+
+// Len returns the number of recorded messages
+func (r *Recorder) Len() int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return len(r.events)
+}
+
+// Reset removes the recorded messages. The next message starts a new recording.
+func (r *Recorder) Reset() {
+	r.mx.Lock()
+	r.events = nil
+	r.start = time.Time{}
+	r.mx.Unlock()
+}
+
+// noteOf returns the channel and key of note messages and whether it is a note on
+func noteOf(msg midi.Message) (k [2]uint8, on bool, is bool) {
+	switch v := msg.(type) {
+	case channel.NoteOn:
+		return [2]uint8{v.Channel(), v.Key()}, v.Velocity() > 0, true
+	case channel.NoteOff:
+		return [2]uint8{v.Channel(), v.Key()}, false, true
+	case channel.NoteOffVelocity:
+		return [2]uint8{v.Channel(), v.Key()}, false, true
+	default:
+		return k, false, false
+	}
+}
+
+// Track returns the recorded messages as track with their positions in ticks (at the tempo of the recorder),
+// restricted to the punch region. Note offs whose note has started before the punch-in are left out.
+func (r *Recorder) Track() *smf.Track {
+	r.mx.Lock()
+	events := append([]event(nil), r.events...)
+	r.mx.Unlock()
+
+	var (
+		t        = &smf.Track{}
+		sounding = map[[2]uint8]bool{}
+		keys     [][2]uint8
+		last     uint32
+	)
+
+	for _, ev := range events {
+		tick := r.resolution.FractionalTicks(r.tempo, ev.time)
+		if tick < last {
+			tick = last
+		}
+		last = tick
+
+		if tick < r.punchIn || (r.punchOut > 0 && tick >= r.punchOut) {
+			continue
+		}
+
+		if k, on, is := noteOf(ev.msg); is {
+			if !on && !sounding[k] {
+				continue
+			}
+
+			if on && !sounding[k] {
+				keys = append(keys, k)
+			}
+
+			sounding[k] = on
+		}
+
+		t.Events = append(t.Events, smf.Event{AbsTicks: uint64(tick), Message: ev.msg})
+		t.End = uint64(tick)
+	}
+
+	// ends the notes at the punch-out, in the order of their start
+	for _, k := range keys {
+		if r.punchOut > 0 && sounding[k] {
+			t.Events = append(t.Events, smf.Event{AbsTicks: uint64(r.punchOut), Message: channel.Channel(k[0]).NoteOff(k[1])})
+			sounding[k] = false
+			t.End = uint64(r.punchOut)
+		}
+	}
+
+	return t
+}
+
+// MergeInto adds the recording (see Track) to the track t. Unless the Overdub option is set, the channel messages of t
+// within the punch region (after the punch-in, if there is no punch-out) are replaced: they are removed with the
+// note offs of the removed notes, before the recording is added.
+func (r *Recorder) MergeInto(t *smf.Track) {
+	rec := r.Track()
+
+	if !r.overdub {
+		var (
+			kept    []smf.Event
+			removed = map[[2]uint8]bool{}
+		)
+
+		for _, ev := range t.Events {
+			inside := ev.AbsTicks >= uint64(r.punchIn) && (r.punchOut == 0 || ev.AbsTicks < uint64(r.punchOut))
+
+			if k, on, is := noteOf(ev.Message); is {
+				switch {
+				case on && inside:
+					removed[k] = true
+					continue
+				case on:
+					delete(removed, k)
+				case removed[k]:
+					delete(removed, k)
+					continue
+				}
+			} else if _, is := ev.Message.(channel.Message); is && inside {
+				continue
+			}
+
+			kept = append(kept, ev)
+		}
+
+		t.Events = kept
+	}
+
+	for _, ev := range rec.Events {
+		t.Insert(ev.AbsTicks, ev.Message)
+	}
+
+	if rec.End > t.End {
+		t.End = rec.End
+	}
+}
+
+// WriteSMF writes the recording as SMF0 file to w: the tempo, the messages (see Track) and the end of track.
+func (r *Recorder) WriteSMF(w io.Writer) error {
+	t := r.Track()
+
+	wr := smfwriter.New(w, smfwriter.TimeFormat(r.resolution))
+
+	if err := wr.Write(meta.FractionalBPM(r.tempo)); err != nil {
+		return err
+	}
+
+	var last uint64
+
+	for _, ev := range t.Events {
+		wr.SetDelta(uint32(ev.AbsTicks - last))
+		last = ev.AbsTicks
+
+		if err := wr.Write(ev.Message); err != nil {
+			return err
+		}
+	}
+
+	wr.SetDelta(uint32(t.End - last))
+
+	if err := wr.Write(meta.EndOfTrack); err != smf.ErrFinished {
+		return err
+	}
+
+	return nil
+}
+
+// Save writes the recording to the SMF file with the given name
+func (r *Recorder) Save(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err = r.WriteSMF(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
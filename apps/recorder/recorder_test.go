@@ -0,0 +1,252 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/clock"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+func dump(t *testing.T, r *Recorder) string {
+	var bf bytes.Buffer
+
+	if err := r.WriteSMF(&bf); err != nil {
+		t.Fatalf("can't write SMF: %v", err)
+	}
+
+	rd := smfreader.New(&bf)
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for {
+		msg, err := rd.Read()
+		if err != nil {
+			break
+		}
+		fmt.Fprintf(&out, "%v %s\n", rd.Delta(), msg)
+	}
+
+	return out.String()
+}
+
+func TestRecorder(t *testing.T) {
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	r := New(Tempo(60), Resolution(smf.MetricTicks(96)), Clock(clock))
+
+	r.Start()
+	now = now.Add(time.Second)
+	r.Write(channel.Channel0.NoteOn(60, 100))
+	r.Write(realtime.TimingClock)
+	now = now.Add(500 * time.Millisecond)
+	r.Write(channel.Channel0.NoteOff(60))
+
+	expected := `
+0 meta.Tempo BPM: 60.00
+96 channel.NoteOn channel 0 key 60 velocity 100
+48 channel.NoteOff channel 0 key 60
+0 meta.EndOfTrack
+`
+
+	if got, want := dump(t, r), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	var in bytes.Buffer
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+	wr.Write(channel.Channel1.NoteOff(60))
+
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(250 * time.Millisecond)
+		return now
+	}
+
+	r := New(Resolution(smf.MetricTicks(96)), Clock(clock))
+
+	if err := r.Record(context.Background(), midireader.New(&in, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+0 meta.Tempo BPM: 120.00
+0 channel.NoteOn channel 1 key 60 velocity 100
+48 channel.NoteOff channel 1 key 60
+0 meta.EndOfTrack
+`
+
+	if got, want := dump(t, r), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func trackString(t *smf.Track) string {
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+
+	for _, ev := range t.Events {
+		fmt.Fprintf(&bf, "%v %s\n", ev.AbsTicks, ev.Message)
+	}
+
+	fmt.Fprintf(&bf, "end %v\n", t.End)
+	return bf.String()
+}
+
+// mkPunched returns a recorder with a punch region from 1s to 2s and a recording that starts before and ends after it
+func mkPunched(opts ...Option) *Recorder {
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	start := now
+	clock := func() time.Time { return now }
+
+	r := New(append([]Option{Tempo(60), Resolution(smf.MetricTicks(96)), Clock(clock), Punch(96, 192)}, opts...)...)
+	r.Start()
+
+	at := func(d time.Duration, msg midi.Message) {
+		now = start.Add(d)
+		r.Write(msg)
+	}
+
+	at(500*time.Millisecond, channel.Channel0.NoteOn(60, 100))
+	at(1250*time.Millisecond, channel.Channel0.NoteOff(60))
+	at(1500*time.Millisecond, channel.Channel0.NoteOn(62, 100))
+	at(1750*time.Millisecond, channel.Channel0.ControlChange(7, 90))
+	at(2500*time.Millisecond, channel.Channel0.NoteOff(62))
+
+	return r
+}
+
+func TestPunch(t *testing.T) {
+	expected := `
+144 channel.NoteOn channel 0 key 62 velocity 100
+168 channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 90
+192 channel.NoteOff channel 0 key 62
+end 192
+`
+
+	if got, want := trackString(mkPunched().Track()), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestMergeInto(t *testing.T) {
+	tests := []struct {
+		overdub  bool
+		expected string
+	}{
+		{false, `
+0 meta.Track: "piano"
+0 channel.NoteOn channel 0 key 48 velocity 100
+120 channel.NoteOff channel 0 key 48
+144 channel.NoteOn channel 0 key 62 velocity 100
+168 channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 90
+192 channel.NoteOff channel 0 key 62
+end 384
+`},
+		{true, `
+0 meta.Track: "piano"
+0 channel.NoteOn channel 0 key 48 velocity 100
+100 channel.NoteOn channel 0 key 50 velocity 100
+120 channel.NoteOff channel 0 key 48
+144 channel.NoteOn channel 0 key 62 velocity 100
+150 channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 100
+168 channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 90
+192 channel.NoteOff channel 0 key 62
+300 channel.NoteOff channel 0 key 50
+end 384
+`},
+	}
+
+	for i, test := range tests {
+		tr := &smf.Track{End: 384}
+		tr.Insert(0, meta.Track("piano"))
+		tr.Insert(0, channel.Channel0.NoteOn(48, 100))
+		tr.Insert(100, channel.Channel0.NoteOn(50, 100))
+		tr.Insert(120, channel.Channel0.NoteOff(48))
+		tr.Insert(150, channel.Channel0.ControlChange(7, 100))
+		tr.Insert(300, channel.Channel0.NoteOff(50))
+
+		var opts []Option
+		if test.overdub {
+			opts = append(opts, Overdub())
+		}
+
+		mkPunched(opts...).MergeInto(tr)
+
+		if got, want := trackString(tr), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+type writerFunc func(midi.Message) error
+
+func (w writerFunc) Write(msg midi.Message) error {
+	return w(msg)
+}
+
+type fakeTime struct {
+	t time.Time
+}
+
+func (f *fakeTime) now() time.Time {
+	return f.t
+}
+
+func (f *fakeTime) sleep(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+func TestCountIn(t *testing.T) {
+	ft := &fakeTime{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	r := New(Resolution(smf.MetricTicks(96)), Clock(ft.now))
+
+	var clicks int
+
+	// the clicks are also sent to the recorder, but not recorded
+	w := writerFunc(func(msg midi.Message) error {
+		clicks++
+		return r.Write(msg)
+	})
+
+	err := r.CountIn(context.Background(), clock.NewInternal(120, clock.Time(ft.now, ft.sleep)), w, nil, 1)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := clicks, 8; got != want {
+		t.Errorf("got %v clicks; wanted %v", got, want)
+	}
+
+	ft.sleep(500 * time.Millisecond)
+	r.Write(channel.Channel0.NoteOn(60, 100))
+
+	expected := `
+96 channel.NoteOn channel 0 key 60 velocity 100
+end 96
+`
+
+	if got, want := trackString(r.Track()), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package sequencer provides a step/pattern sequencer.
+
+A Pattern holds events at steps (by default 16th notes). The sequencer plays a chain of patterns one after another,
+optionally looping a region of the chain, and writes the events in real time to a midi.Writer. It is driven by a
+clock.Clock: either an internal clock with a tempo or an external clock that follows the MIDI clock of another device.
+The chain, the loop region and the position may be changed while playing, e.g. to queue the next pattern.
+With Follow and a transport.Transport as clock, the sequencer starts, stops and locates with another device.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/apps/sequencer"
+		"github.com/gomidi/midi/clock"
+		"github.com/gomidi/midi/midimessage/channel"
+		"github.com/gomidi/midi/midiwriter"
+	)
+
+	beat := sequencer.NewPattern("beat", 16)
+	for step := 0; step < 16; step += 4 {
+		beat.Note(step, channel.Channel9, 36, 100, 1)
+	}
+
+	fill := sequencer.NewPattern("fill", 16)
+	// ...
+
+	seq := sequencer.New()
+	seq.Chain(beat, beat, beat, fill)
+	seq.Loop(0, seq.Len())
+
+	err := seq.Run(ctx, clock.NewInternal(120), midiwriter.New(out))
+
+*/
+package sequencer
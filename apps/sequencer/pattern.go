@@ -0,0 +1,56 @@
+package sequencer
+
+import (
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// Event is a message at a step of a pattern
+type Event struct {
+	// Step is the position within the pattern in steps (starting with 0)
+	Step int
+
+	// Message is the message
+	Message midi.Message
+
+	// Length is the length of a note in steps. If it is positive and Message is a note on message,
+	// the note off message is written after Length steps (also beyond the end of the pattern).
+	Length int
+}
+
+// Pattern is a sequence of events with a length in steps
+type Pattern struct {
+	// Name is the name of the pattern
+	Name string
+
+	// Length is the length of the pattern in steps
+	Length int
+
+	// Events are the events of the pattern. Events of the same step are written in their order.
+	Events []Event
+}
+
+// NewPattern returns an empty pattern with the given name and length in steps
+func NewPattern(name string, length int) *Pattern {
+	return &Pattern{Name: name, Length: length}
+}
+
+// Add adds a message at the given step
+func (p *Pattern) Add(step int, msg midi.Message) {
+	p.Events = append(p.Events, Event{Step: step, Message: msg})
+}
+
+// Note adds a note at the given step that lasts for length steps
+func (p *Pattern) Note(step int, ch channel.Channel, key, velocity uint8, length int) {
+	p.Events = append(p.Events, Event{Step: step, Message: ch.NoteOn(key, velocity), Length: length})
+}
+
+// at returns the events at the given step
+func (p *Pattern) at(step int) (res []Event) {
+	for _, ev := range p.Events {
+		if ev.Step == step {
+			res = append(res, ev)
+		}
+	}
+	return
+}
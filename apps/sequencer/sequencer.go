@@ -0,0 +1,277 @@
+package sequencer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/clock"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/state"
+	"github.com/gomidi/midi/transport"
+)
+
+// Option is an option for a Sequencer
+type Option func(*Sequencer)
+
+// Resolution is an option that sets the steps per quarter note (default: 4, i.e. a step is a 16th note).
+// It must be a divisor of 24 (the pulses per quarter note of MIDI clock), otherwise it is ignored.
+func Resolution(stepsPerBeat int) Option {
+	return func(s *Sequencer) {
+		if stepsPerBeat > 0 && clock.PPQN%stepsPerBeat == 0 {
+			s.stepsPerBeat = stepsPerBeat
+		}
+	}
+}
+
+// Sequencer plays a chain of patterns, driven by a clock. The chain, the loop region and the position
+// may be changed while playing. The patterns must not be changed while they are played; use Chain to replace them.
+type Sequencer struct {
+	mx           sync.Mutex
+	stepsPerBeat int
+	chain        []*Pattern
+	loopFrom     int
+	loopTo       int
+	pos          int
+
+	// phase is the pulse within the step Run continues with, if resync is set
+	phase  int
+	resync bool
+}
+
+// New returns a new sequencer with an empty chain
+func New(opts ...Option) *Sequencer {
+	s := &Sequencer{stepsPerBeat: 4}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Chain sets the chain of patterns that are played one after another. A pattern may be given multiple times.
+// The position is kept.
+func (s *Sequencer) Chain(patterns ...*Pattern) {
+	s.mx.Lock()
+	s.chain = append([]*Pattern(nil), patterns...)
+	s.mx.Unlock()
+}
+
+// Append appends the patterns to the chain
+func (s *Sequencer) Append(patterns ...*Pattern) {
+	s.mx.Lock()
+	s.chain = append(s.chain, patterns...)
+	s.mx.Unlock()
+}
+
+// Len returns the length of the chain in steps
+func (s *Sequencer) Len() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.len()
+}
+
+func (s *Sequencer) len() (n int) {
+	for _, p := range s.chain {
+		n += p.Length
+	}
+	return
+}
+
+// Loop sets the loop region in steps of the chain: when the position reaches to, it jumps back to from.
+// If from is not before to, looping is disabled and playing ends at the end of the chain.
+func (s *Sequencer) Loop(from, to int) {
+	s.mx.Lock()
+	s.loopFrom, s.loopTo = from, to
+	s.mx.Unlock()
+}
+
+// Seek sets the position in steps of the chain, where playing continues
+func (s *Sequencer) Seek(step int) {
+	if step < 0 {
+		step = 0
+	}
+
+	s.mx.Lock()
+	s.pos = step
+	s.mx.Unlock()
+}
+
+// Follow lets the sequencer follow the position of the transport: Start plays the chain from the start and a song position
+// pointer moves to the corresponding step. To only play while the transport is playing, the transport is used as clock:
+//
+//	defer seq.Follow(t)()
+//	err := seq.Run(ctx, t, w)
+//
+// Follow returns a function that ends following.
+func (s *Sequencer) Follow(t *transport.Transport) (remove func()) {
+	return t.OnChange(func(c transport.Change, st transport.State) {
+		if c == transport.Started || c == transport.Located {
+			s.locate(st.Pulses())
+		}
+	})
+}
+
+// locate moves to the position in pulses: the next pulse is played at that position
+func (s *Sequencer) locate(pulses int) {
+	perStep := clock.PPQN / s.stepsPerBeat
+
+	s.mx.Lock()
+	s.pos = (pulses + perStep - 1) / perStep
+	s.phase = pulses % perStep
+	s.resync = true
+	s.mx.Unlock()
+}
+
+// takePhase returns the pulse within the step set by locate and whether it was set
+func (s *Sequencer) takePhase() (phase int, ok bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	phase, ok = s.phase, s.resync
+	s.resync = false
+	return
+}
+
+// Position returns the position in steps of the chain of the next step to be played
+func (s *Sequencer) Position() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.pos
+}
+
+// Pattern returns the pattern at the given position in steps of the chain and the step within the pattern.
+// It returns nil, if the position is beyond the chain.
+func (s *Sequencer) Pattern(step int) (p *Pattern, patternStep int) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.pattern(step)
+}
+
+func (s *Sequencer) pattern(step int) (*Pattern, int) {
+	for _, p := range s.chain {
+		if step < p.Length {
+			return p, step
+		}
+		step -= p.Length
+	}
+	return nil, 0
+}
+
+// next returns the events at the current position and moves to the next step.
+// It returns false at the end of the chain.
+func (s *Sequencer) next() ([]Event, bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.loopFrom < s.loopTo && s.pos == s.loopTo {
+		s.pos = s.loopFrom
+	}
+
+	p, step := s.pattern(s.pos)
+
+	if p == nil {
+		return nil, false
+	}
+
+	s.pos++
+	return p.at(step), true
+}
+
+// noteOff is a note off message that is due at a step
+type noteOff struct {
+	step int
+	msg  midi.Message
+}
+
+// Run plays the chain from the current position, writing the events of each step to w at the pulses of clk.
+// It returns nil at the end of the chain, ctx.Err() when ctx is done or the error of a failed write.
+// When Run returns, note offs are written for the notes that are still sounding.
+func (s *Sequencer) Run(ctx context.Context, clk clock.Clock, w midi.Writer) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		notes   = state.NewNotes()
+		perStep = clock.PPQN / s.stepsPerBeat
+		pulses  int
+		played  int
+		offs    []noteOff
+		done    bool
+	)
+
+	write := func(msg midi.Message) {
+		if err == nil {
+			err = w.Write(msg)
+			notes.Track(msg)
+		}
+	}
+
+	clkErr := clk.Run(ctx, func() {
+		if done || err != nil {
+			return
+		}
+
+		if phase, ok := s.takePhase(); ok {
+			pulses = phase
+		}
+
+		defer func() { pulses++ }()
+
+		if pulses%perStep != 0 {
+			return
+		}
+
+		// the note offs that are due are written before the events of the step
+		var pending []noteOff
+		for _, off := range offs {
+			if off.step <= played {
+				write(off.msg)
+			} else {
+				pending = append(pending, off)
+			}
+		}
+		offs = pending
+
+		events, ok := s.next()
+
+		if !ok {
+			done = true
+			cancel()
+			return
+		}
+
+		for _, ev := range events {
+			write(ev.Message)
+
+			if on, is := ev.Message.(channel.NoteOn); is && ev.Length > 0 && on.Velocity() > 0 {
+				offs = append(offs, noteOff{played + ev.Length, channel.Channel(on.Channel()).NoteOff(on.Key())})
+			}
+		}
+
+		played++
+
+		if err != nil {
+			cancel()
+		}
+	})
+
+	for _, off := range offs {
+		w.Write(off.msg)
+		notes.Track(off.msg)
+	}
+
+	for _, msg := range notes.NoteOffs() {
+		w.Write(msg)
+	}
+
+	switch {
+	case err != nil:
+		return err
+	case done:
+		return nil
+	default:
+		return clkErr
+	}
+}
@@ -0,0 +1,210 @@
+package sequencer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/clock"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/transport"
+)
+
+type fakeTime struct {
+	t time.Time
+}
+
+func (f *fakeTime) now() time.Time {
+	return f.t
+}
+
+func (f *fakeTime) sleep(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+type logWriter struct {
+	bf    *bytes.Buffer
+	ft    *fakeTime
+	start time.Time
+}
+
+func (l logWriter) Write(msg midi.Message) error {
+	fmt.Fprintf(l.bf, "%s %s\n", l.ft.t.Sub(l.start), msg)
+	return nil
+}
+
+// mkPatterns returns a pattern A of 4 steps with a kick and a snare and a pattern B of 2 steps
+// with a hihat that lasts beyond the end of the pattern
+func mkPatterns() (a, b *Pattern) {
+	a = NewPattern("A", 4)
+	a.Note(0, channel.Channel9, 36, 100, 1)
+	a.Note(2, channel.Channel9, 38, 100, 2)
+
+	b = NewPattern("B", 2)
+	b.Note(0, channel.Channel9, 42, 80, 4)
+	return
+}
+
+func TestSequencer(t *testing.T) {
+	a, b := mkPatterns()
+
+	tests := []struct {
+		chain    []*Pattern
+		loop     [2]int
+		seek     int
+		stopAt   time.Duration
+		expected string
+	}{
+		// steps are 16th notes of 125ms at 120 BPM
+		{[]*Pattern{a, b}, [2]int{}, 0, 0, `
+0s channel.NoteOn channel 9 key 36 velocity 100
+125ms channel.NoteOff channel 9 key 36
+250ms channel.NoteOn channel 9 key 38 velocity 100
+500ms channel.NoteOff channel 9 key 38
+500ms channel.NoteOn channel 9 key 42 velocity 80
+750ms channel.NoteOff channel 9 key 42
+`},
+		// loops B and the second half of A
+		{[]*Pattern{a, b}, [2]int{2, 6}, 4, time.Second, `
+0s channel.NoteOn channel 9 key 42 velocity 80
+250ms channel.NoteOn channel 9 key 38 velocity 100
+500ms channel.NoteOff channel 9 key 42
+500ms channel.NoteOff channel 9 key 38
+500ms channel.NoteOn channel 9 key 42 velocity 80
+750ms channel.NoteOn channel 9 key 38 velocity 100
+1s channel.NoteOff channel 9 key 42
+1s channel.NoteOff channel 9 key 38
+`},
+	}
+
+	for i, test := range tests {
+		ft := &fakeTime{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+		start := ft.t
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		sleep := func(d time.Duration) {
+			ft.sleep(d)
+			if test.stopAt > 0 && ft.t.Sub(start) >= test.stopAt {
+				cancel()
+			}
+		}
+
+		seq := New()
+		seq.Chain(test.chain...)
+		seq.Loop(test.loop[0], test.loop[1])
+		seq.Seek(test.seek)
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		err := seq.Run(ctx, clock.NewInternal(120, clock.Time(ft.now, sleep)), logWriter{&out, ft, start})
+		cancel()
+
+		if test.stopAt > 0 && err != context.Canceled {
+			t.Errorf("[%v] expected context.Canceled, got %v", i, err)
+		}
+
+		if test.stopAt == 0 && err != nil {
+			t.Errorf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestPattern(t *testing.T) {
+	a, b := mkPatterns()
+
+	seq := New(Resolution(2))
+	seq.Chain(a, b)
+	seq.Append(a)
+
+	if got, want := seq.Len(), 10; got != want {
+		t.Errorf("Len() = %v; wanted %v", got, want)
+	}
+
+	tests := []struct {
+		step        int
+		pattern     *Pattern
+		patternStep int
+	}{
+		{0, a, 0},
+		{5, b, 1},
+		{7, a, 1},
+		{10, nil, 0},
+	}
+
+	for i, test := range tests {
+		p, step := seq.Pattern(test.step)
+
+		if p != test.pattern || step != test.patternStep {
+			t.Errorf("[%v] Pattern(%v) = %v, %v; wanted %v, %v", i, test.step, p, step, test.pattern, test.patternStep)
+		}
+	}
+}
+
+type writerFunc func(midi.Message) error
+
+func (w writerFunc) Write(msg midi.Message) error {
+	return w(msg)
+}
+
+// scriptClock is a clock that calls the script with the pulse function
+type scriptClock func(pulse func())
+
+func (s scriptClock) Run(ctx context.Context, pulse func()) error {
+	s(pulse)
+	return ctx.Err()
+}
+
+func TestFollow(t *testing.T) {
+	a, _ := mkPatterns()
+
+	// steps are 8th notes of 12 pulses
+	seq := New(Resolution(2))
+	seq.Chain(a)
+
+	tr := transport.New()
+	defer seq.Follow(tr)()
+
+	var (
+		out   bytes.Buffer
+		count int
+	)
+
+	out.WriteString("\n")
+
+	clk := scriptClock(func(pulse func()) {
+		// the third 16th note is in the middle of the second step
+		tr.Locate(3)
+		tr.Continue()
+
+		for count = 0; count < 48; count++ {
+			pulse()
+		}
+	})
+
+	w := writerFunc(func(msg midi.Message) error {
+		fmt.Fprintf(&out, "%v %s\n", count, msg)
+		return nil
+	})
+
+	if err := seq.Run(context.Background(), clk, w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+6 channel.NoteOn channel 9 key 38 velocity 100
+30 channel.NoteOff channel 9 key 38
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
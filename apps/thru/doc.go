@@ -0,0 +1,28 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package thru provides a MIDI thru box application that passes the messages of an input
+to an output, filtering and transforming them on the way.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/apps/thru"
+		"github.com/gomidi/midi/midimessage/channel"
+		"github.com/gomidi/midi/router"
+		"github.com/gomidi/midi/transform"
+	)
+
+	in, _ := os.Open("/dev/snd/midiC1D0")
+	out, _ := os.OpenFile("/dev/snd/midiC2D0", os.O_WRONLY, 0)
+
+	// passes channel 1 and 2 transposed by an octave, until ctx is done or the input is closed
+	err := thru.Run(ctx, in, out,
+		thru.Filter(router.Channels(0, 1)),
+		thru.Transform(transform.Transpose(12)),
+	)
+
+*/
+package thru
@@ -0,0 +1,123 @@
+package thru
+
+import (
+	"context"
+	"io"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+	"github.com/gomidi/midi/router"
+)
+
+type config struct {
+	filters    []router.Filter
+	transforms []midi.Transform
+	noRealtime bool
+	writer     []midiwriter.Option
+}
+
+// Option is an option for Run
+type Option func(*config)
+
+// Filter is an option that only passes the messages that match all of the given filters.
+// The filters also apply to realtime messages (router.Channels blocks them, for instance).
+func Filter(filters ...router.Filter) Option {
+	return func(c *config) {
+		c.filters = append(c.filters, filters...)
+	}
+}
+
+// Transform is an option that applies the given transforms (in the given order) to the passed messages.
+// Transforms that are midi.Flushers are flushed when Run returns.
+func Transform(transforms ...midi.Transform) Option {
+	return func(c *config) {
+		c.transforms = append(c.transforms, transforms...)
+	}
+}
+
+// NoRealtime is an option that blocks all realtime messages (e.g. the timing clock)
+func NoRealtime() Option {
+	return func(c *config) {
+		c.noRealtime = true
+	}
+}
+
+// WriterOptions is an option that passes the given options to the writer of the output
+func WriterOptions(opts ...midiwriter.Option) Option {
+	return func(c *config) {
+		c.writer = append(c.writer, opts...)
+	}
+}
+
+// Run passes the messages of in to out until ctx is done or in returns an error.
+// Realtime messages are passed as soon as they arrive, before the message they interrupt.
+// It returns nil for io.EOF and ctx.Err() when ctx is done.
+func Run(ctx context.Context, in io.Reader, out io.Writer, opts ...Option) error {
+	var c config
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	transforms := c.transforms
+	if len(c.filters) > 0 {
+		transforms = append([]midi.Transform{filter(c.filters)}, transforms...)
+	}
+
+	tw := midi.TransformWriter(midiwriter.New(out, c.writer...), transforms...)
+
+	var werr error
+
+	// the realtime handler is called by the reading goroutine, so no locking is needed
+	rthandler := func(msg realtime.Message) {
+		if c.noRealtime || werr != nil {
+			return
+		}
+		werr = tw.Write(msg)
+	}
+
+	rd := midireader.New(in, rthandler).(midireader.ContextReader)
+
+	for {
+		msg, err := rd.ReadContext(ctx)
+
+		if err == nil {
+			err = werr
+		}
+
+		if err == nil {
+			err = tw.Write(msg)
+		}
+
+		if err != nil {
+			if ferr := tw.Close(); ferr != nil && err == io.EOF {
+				return ferr
+			}
+
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// filter is a transform that drops the messages that don't match all filters
+type filter []router.Filter
+
+// Transform passes msg if it matches all filters
+func (f filter) Transform(msg midi.Message) []midi.Message {
+	for _, fn := range f {
+		if !fn(msg) {
+			return nil
+		}
+	}
+	return []midi.Message{msg}
+}
+
+// Name returns the name of the transform
+func (f filter) Name() string {
+	return "filter"
+}
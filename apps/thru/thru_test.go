@@ -0,0 +1,56 @@
+package thru
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midiwriter"
+	"github.com/gomidi/midi/router"
+	"github.com/gomidi/midi/transform"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		opts     []Option
+		expected string
+	}{
+		{
+			nil,
+			"90 3C 64 F8 91 3E 64 90 3C 00",
+		},
+		{
+			[]Option{Filter(router.Channels(0)), Transform(transform.Transpose{Semitones: 12})},
+			// the channel filter also blocks the realtime messages
+			"90 48 64 48 00",
+		},
+		{
+			[]Option{NoRealtime(), WriterOptions(midiwriter.NoRunningStatus())},
+			"90 3C 64 91 3E 64 90 3C 00",
+		},
+	}
+
+	for i, test := range tests {
+		var in bytes.Buffer
+		wr := midiwriter.New(&in, midiwriter.NoRunningStatus())
+		wr.Write(channel.Channel0.NoteOn(60, 100))
+		wr.Write(realtime.TimingClock)
+		wr.Write(channel.Channel1.NoteOn(62, 100))
+		wr.Write(channel.Channel0.NoteOff(60))
+
+		var out bytes.Buffer
+
+		err := Run(context.Background(), &in, &out, test.opts...)
+
+		if err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := fmt.Sprintf("% X", out.Bytes()), test.expected; got != want {
+			t.Errorf("[%v] got %q; wanted %q", i, got, want)
+		}
+	}
+}
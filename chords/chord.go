@@ -0,0 +1,163 @@
+package chords
+
+import (
+	"sort"
+
+	"github.com/gomidi/midi/state"
+)
+
+// Quality is the quality of a chord
+type Quality uint8
+
+const (
+	Major Quality = iota
+	Minor
+	Diminished
+	Augmented
+	Sus2
+	Sus4
+	Power
+	Dominant7
+	Major7
+	Minor7
+	HalfDiminished7
+	Diminished7
+	MinorMajor7
+	Major6
+	Minor6
+	Add9
+	Dominant7Sus4
+)
+
+// qualities defines the intervals above the root of each quality, ordered by the inversions
+var qualities = [...]struct {
+	symbol    string
+	intervals []uint8
+}{
+	Major:           {"", []uint8{0, 4, 7}},
+	Minor:           {"m", []uint8{0, 3, 7}},
+	Diminished:      {"dim", []uint8{0, 3, 6}},
+	Augmented:       {"aug", []uint8{0, 4, 8}},
+	Sus2:            {"sus2", []uint8{0, 2, 7}},
+	Sus4:            {"sus4", []uint8{0, 5, 7}},
+	Power:           {"5", []uint8{0, 7}},
+	Dominant7:       {"7", []uint8{0, 4, 7, 10}},
+	Major7:          {"maj7", []uint8{0, 4, 7, 11}},
+	Minor7:          {"m7", []uint8{0, 3, 7, 10}},
+	HalfDiminished7: {"m7b5", []uint8{0, 3, 6, 10}},
+	Diminished7:     {"dim7", []uint8{0, 3, 6, 9}},
+	MinorMajor7:     {"mMaj7", []uint8{0, 3, 7, 11}},
+	Major6:          {"6", []uint8{0, 4, 7, 9}},
+	Minor6:          {"m6", []uint8{0, 3, 7, 9}},
+	Add9:            {"add9", []uint8{0, 4, 7, 2}},
+	Dominant7Sus4:   {"7sus4", []uint8{0, 5, 7, 10}},
+}
+
+// String returns the symbol of the quality, as it follows the root in a chord name (empty for major)
+func (q Quality) String() string {
+	if int(q) >= len(qualities) {
+		return "?"
+	}
+	return qualities[q].symbol
+}
+
+// Intervals returns the intervals of the chord tones above the root in semitones, ordered by the inversions
+// (root, third, fifth, seventh)
+func (q Quality) Intervals() []uint8 {
+	if int(q) >= len(qualities) {
+		return nil
+	}
+	return append([]uint8(nil), qualities[q].intervals...)
+}
+
+var pitchClasses = [12]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// Chord is a recognized chord
+type Chord struct {
+	// Root is the pitch class of the root (0 = C, 11 = B)
+	Root uint8
+
+	// Bass is the pitch class of the lowest key
+	Bass uint8
+
+	Quality Quality
+
+	// Keys are the keys the chord has been recognized from, ordered from low to high
+	Keys []uint8
+}
+
+// Inversion returns the inversion of the chord: 0 if the root is in the bass, 1 if the third is in the bass etc.
+func (c Chord) Inversion() int {
+	for i, iv := range qualities[c.Quality].intervals {
+		if (c.Root+iv)%12 == c.Bass {
+			return i
+		}
+	}
+	return 0
+}
+
+// String returns the name of the chord, e.g. "Am7" or "C/E" for the first inversion of C major
+func (c Chord) String() string {
+	s := pitchClasses[c.Root%12] + c.Quality.String()
+	if c.Bass != c.Root {
+		s += "/" + pitchClasses[c.Bass%12]
+	}
+	return s
+}
+
+// Equal returns true, if both chords have the same root, bass and quality, no matter which keys are sounding
+func (c Chord) Equal(o Chord) bool {
+	return c.Root == o.Root && c.Bass == o.Bass && c.Quality == o.Quality
+}
+
+// Recognize recognizes the chord that is formed by the given keys. The octaves and doublings of the keys don't matter,
+// but every pitch class must be a chord tone. If there is more than one interpretation (e.g. C6 and Am7),
+// the one with the root in the bass is preferred.
+// ok is false, if the keys form no known chord.
+func Recognize(keys ...uint8) (c Chord, ok bool) {
+	if len(keys) == 0 {
+		return
+	}
+
+	keys = append([]uint8(nil), keys...)
+	sort.Slice(keys, func(a, b int) bool { return keys[a] < keys[b] })
+
+	var set uint16
+	for _, k := range keys {
+		set |= 1 << (k % 12)
+	}
+
+	bass := keys[0] % 12
+
+	// try the bass as root first, then the other pitch classes upwards
+	for i := uint8(0); i < 12; i++ {
+		root := (bass + i) % 12
+		if set&(1<<root) == 0 {
+			continue
+		}
+
+		for q, quality := range qualities {
+			if pitchClassSet(root, quality.intervals) == set {
+				return Chord{Root: root, Bass: bass, Quality: Quality(q), Keys: keys}, true
+			}
+		}
+	}
+
+	return
+}
+
+func pitchClassSet(root uint8, intervals []uint8) (set uint16) {
+	for _, iv := range intervals {
+		set |= 1 << ((root + iv) % 12)
+	}
+	return
+}
+
+// FromNotes recognizes the chord that is formed by the given notes, e.g. the result of state.Notes.Sounding.
+func FromNotes(notes []state.Note) (c Chord, ok bool) {
+	keys := make([]uint8, len(notes))
+	for i, n := range notes {
+		keys[i] = n.Key
+	}
+	return Recognize(keys...)
+}
@@ -0,0 +1,149 @@
+package chords
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+func TestRecognize(t *testing.T) {
+
+	tests := []struct {
+		keys      []uint8
+		expected  string
+		inversion int
+	}{
+		{[]uint8{60, 64, 67}, "C", 0},
+		{[]uint8{64, 67, 72}, "C/E", 1},
+		{[]uint8{55, 60, 64}, "C/G", 2},
+		{[]uint8{57, 60, 64, 69}, "Am", 0},
+		{[]uint8{59, 62, 65}, "Bdim", 0},
+		{[]uint8{60, 64, 68}, "Caug", 0},
+		{[]uint8{64, 68, 72}, "Eaug", 0},
+		{[]uint8{55, 59, 62, 65}, "G7", 0},
+		{[]uint8{53, 55, 59, 62}, "G7/F", 3},
+		{[]uint8{60, 64, 67, 71}, "Cmaj7", 0},
+		{[]uint8{57, 60, 64, 67}, "Am7", 0},
+		{[]uint8{60, 64, 67, 69}, "C6", 0},
+		{[]uint8{59, 62, 65, 69}, "Bm7b5", 0},
+		{[]uint8{59, 62, 65, 68}, "Bdim7", 0},
+		{[]uint8{60, 62, 67}, "Csus2", 0},
+		{[]uint8{60, 65, 67}, "Csus4", 0},
+		{[]uint8{36, 43, 48}, "C5", 0},
+		{[]uint8{60, 62, 64, 67}, "Cadd9", 0},
+		{[]uint8{61, 65, 68}, "C#", 0},
+	}
+
+	for i, test := range tests {
+		c, ok := Recognize(test.keys...)
+
+		if !ok {
+			t.Errorf("[%v] not recognized: %v", i, test.keys)
+			continue
+		}
+
+		if got := c.String(); got != test.expected {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, test.expected)
+		}
+
+		if got := c.Inversion(); got != test.inversion {
+			t.Errorf("[%v] inversion got: %v wanted: %v", i, got, test.inversion)
+		}
+	}
+
+	for _, keys := range [][]uint8{nil, {60}, {60, 61, 62}, {60, 64, 67, 70, 71}} {
+		if c, ok := Recognize(keys...); ok {
+			t.Errorf("%v should not be recognized, but is %s", keys, c)
+		}
+	}
+}
+
+func TestTracker(t *testing.T) {
+	var got []string
+
+	start := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := start
+	tr := NewTracker(func(c *Chord, at time.Time) {
+		got = append(got, fmt.Sprintf("%s %v", c, at.Sub(start)))
+	}, Clock(func() time.Time { return clock }))
+
+	step := func() { clock = clock.Add(time.Second) }
+
+	tr.Write(channel.Channel0.NoteOn(60, 100))
+	tr.Write(channel.Channel0.NoteOn(64, 100))
+	tr.Write(channel.Channel9.NoteOn(61, 100))
+	tr.Write(channel.Channel1.NoteOn(67, 100))
+	step()
+	// sustained notes still form the chord
+	tr.Write(channel.Channel0.ControlChange(64, 127))
+	tr.Write(channel.Channel0.NoteOff(60))
+	tr.Write(channel.Channel0.NoteOn(72, 100))
+	step()
+	tr.Write(channel.Channel0.ControlChange(64, 0))
+	step()
+	tr.Write(channel.Channel1.NoteOn(69, 100))
+	tr.Write(channel.Channel0.NoteOff(64))
+	tr.Write(channel.Channel0.NoteOff(72))
+	tr.Write(channel.Channel1.NoteOff(67))
+	tr.Write(channel.Channel1.NoteOff(69))
+
+	c, ok := tr.Chord()
+	if ok {
+		t.Errorf("no chord expected, got %s", c)
+	}
+
+	expected := "[C 0s C/E 2s Am7/E 3s <nil> 3s]"
+	if g := fmt.Sprint(got); g != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", g, expected)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	var bf bytes.Buffer
+	wr := smfwriter.New(&bf, smfwriter.NumTracks(2))
+
+	chord := func(delta uint32, length uint32, keys ...uint8) {
+		wr.SetDelta(delta)
+		for _, k := range keys {
+			wr.Write(channel.Channel0.NoteOn(k, 100))
+		}
+		wr.SetDelta(length)
+		for _, k := range keys {
+			wr.Write(channel.Channel0.NoteOff(k))
+		}
+	}
+
+	// the bass in the second track
+	chord(0, 960, 60, 64, 67)
+	chord(0, 960, 57, 60, 64)
+	chord(0, 960, 57, 60, 64)
+	chord(480, 480, 55, 59, 62, 65)
+	wr.Write(meta.EndOfTrack)
+
+	chord(0, 960, 48)
+	wr.Write(channel.Channel9.NoteOn(37, 100))
+	wr.SetDelta(960)
+	wr.Write(channel.Channel9.NoteOff(37))
+	wr.Write(meta.EndOfTrack)
+
+	events, err := Detect(bytes.NewReader(bf.Bytes()))
+
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	var got []string
+	for _, ev := range events {
+		got = append(got, fmt.Sprintf("%s@%d+%d", ev.Chord, ev.Start, ev.Duration))
+	}
+
+	expected := "[C@0+960 Am@960+1920 G7@3360+480]"
+	if g := fmt.Sprint(got); g != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", g, expected)
+	}
+}
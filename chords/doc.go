@@ -0,0 +1,31 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package chords recognizes chords in sounding notes: the common qualities (triads, sixth and seventh chords,
+suspended chords) and their inversions.
+
+Recognize works on a set of keys, FromNotes on the notes of a state.Notes tracker.
+A Tracker reports the chord changes of a live stream and Detect reports the chords of an SMF file
+together with their start and duration in ticks.
+
+Usage
+
+	c, ok := chords.Recognize(64, 67, 72)
+	// c.String() == "C/E", c.Inversion() == 1
+
+	tr := chords.NewTracker(func(c *chords.Chord, at time.Time) {
+		if c == nil {
+			fmt.Println("no chord")
+			return
+		}
+		fmt.Println(c)
+	})
+
+	go midi.Pipe(src, dst, tr)
+
+	events, err := chords.Detect(file)
+
+*/
+package chords
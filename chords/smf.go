@@ -0,0 +1,125 @@
+package chords
+
+import (
+	"io"
+	"sort"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+// Event is a chord within an SMF file
+type Event struct {
+	// Start is the absolute time of the start in ticks
+	Start uint64
+
+	// Duration is the duration in ticks
+	Duration uint64
+
+	Chord Chord
+}
+
+type noteEvent struct {
+	time    uint64
+	channel uint8
+	key     uint8
+	on      bool
+}
+
+// Detect returns the chords of the given SMF file, ordered by time. The notes of all tracks and all channels
+// but the percussion channel 9 are combined. Times when the sounding notes form no known chord are skipped.
+// The options are passed to the reader.
+func Detect(src io.Reader, options ...smfreader.Option) ([]Event, error) {
+	rd := smfreader.New(src, options...)
+
+	var notes []noteEvent
+	var time uint64
+	track := int16(-1)
+
+	for {
+		msg, err := rd.Read()
+
+		if err == smf.ErrFinished {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if rd.Track() != track {
+			track = rd.Track()
+			time = 0
+		}
+
+		time += uint64(rd.Delta())
+
+		switch m := msg.(type) {
+		case channel.NoteOn:
+			if m.Channel() != percussionChannel {
+				notes = append(notes, noteEvent{time, m.Channel(), m.Key(), m.Velocity() > 0})
+			}
+		case channel.NoteOff:
+			if m.Channel() != percussionChannel {
+				notes = append(notes, noteEvent{time, m.Channel(), m.Key(), false})
+			}
+		case channel.NoteOffVelocity:
+			if m.Channel() != percussionChannel {
+				notes = append(notes, noteEvent{time, m.Channel(), m.Key(), false})
+			}
+		}
+	}
+
+	// note offs before note ons at the same time, so that a key that is struck again keeps sounding
+	sort.SliceStable(notes, func(a, b int) bool {
+		if notes[a].time != notes[b].time {
+			return notes[a].time < notes[b].time
+		}
+		return !notes[a].on && notes[b].on
+	})
+
+	var res []Event
+	var sounding [16][128]int
+	var current *Event
+
+	for i := 0; i < len(notes); {
+		t := notes[i].time
+
+		// apply all note events of the same time before recognizing
+		for ; i < len(notes) && notes[i].time == t; i++ {
+			n := notes[i]
+			if n.on {
+				sounding[n.channel&0x0F][n.key&0x7F]++
+			} else if sounding[n.channel&0x0F][n.key&0x7F] > 0 {
+				sounding[n.channel&0x0F][n.key&0x7F]--
+			}
+		}
+
+		var keys []uint8
+		for ch := range sounding {
+			for key, count := range sounding[ch] {
+				if count > 0 {
+					keys = append(keys, uint8(key))
+				}
+			}
+		}
+
+		c, ok := Recognize(keys...)
+
+		if current != nil {
+			if ok && c.Equal(current.Chord) {
+				continue
+			}
+			current.Duration = t - current.Start
+			res = append(res, *current)
+			current = nil
+		}
+
+		if ok {
+			current = &Event{Start: t, Chord: c}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,121 @@
+package chords
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/state"
+)
+
+// percussionChannel is the channel of the General MIDI percussion (channel 10), whose notes have no pitch
+const percussionChannel = 9
+
+// Option is an option for the Tracker
+type Option func(*Tracker)
+
+// Clock is an option that sets the function that returns the current time (default: time.Now).
+func Clock(now func() time.Time) Option {
+	return func(t *Tracker) {
+		t.now = now
+	}
+}
+
+// Channels is an option that sets the channels whose notes form the chords (default: all but the percussion channel 9).
+func Channels(channels ...uint8) Option {
+	return func(t *Tracker) {
+		t.channels = channels
+	}
+}
+
+// Tracker tracks the sounding notes of a live stream (including sustained notes) and calls a function
+// whenever the recognized chord changes. Tracker is safe for concurrent use.
+type Tracker struct {
+	mx       sync.Mutex
+	notes    *state.Notes
+	now      func() time.Time
+	channels []uint8
+	current  *Chord
+	onChange func(c *Chord, at time.Time)
+}
+
+// NewTracker returns a Tracker that calls onChange with the new chord and the time of the change.
+// If the sounding notes form no known chord (or no note is sounding), onChange is called with a nil chord.
+func NewTracker(onChange func(c *Chord, at time.Time), opts ...Option) *Tracker {
+	t := &Tracker{
+		now:      time.Now,
+		onChange: onChange,
+	}
+
+	for ch := uint8(0); ch < 16; ch++ {
+		if ch != percussionChannel {
+			t.channels = append(t.channels, ch)
+		}
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	t.notes = state.NewNotes(state.Clock(t.now))
+	return t
+}
+
+// Track updates the state with the given message
+func (t *Tracker) Track(msg midi.Message) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.notes.Track(msg)
+
+	var notes []state.Note
+	for _, ch := range t.channels {
+		notes = append(notes, t.notes.Sounding(ch)...)
+	}
+
+	var next *Chord
+	if c, ok := FromNotes(notes); ok {
+		next = &c
+	}
+
+	if next == nil && t.current == nil {
+		return
+	}
+
+	if next != nil && t.current != nil && next.Equal(*t.current) {
+		return
+	}
+
+	t.current = next
+	if t.onChange != nil {
+		t.onChange(next, t.now())
+	}
+}
+
+// Chord returns the current chord. ok is false, if the sounding notes form no known chord.
+func (t *Tracker) Chord() (c Chord, ok bool) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if t.current == nil {
+		return
+	}
+	return *t.current, true
+}
+
+// Transform tracks the message and passes it unchanged, so that the tracker can be used inside a pipe
+func (t *Tracker) Transform(msg midi.Message) []midi.Message {
+	t.Track(msg)
+	return []midi.Message{msg}
+}
+
+// Name returns the name of the transform
+func (t *Tracker) Name() string {
+	return "chords"
+}
+
+// Write tracks the message, so that the tracker can be used as midi.Writer
+func (t *Tracker) Write(msg midi.Message) error {
+	t.Track(msg)
+	return nil
+}
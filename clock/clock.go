@@ -0,0 +1,188 @@
+package clock
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// PPQN are the pulses per quarter note of MIDI clock
+const PPQN = 24
+
+// Clock is a source of MIDI clock pulses
+type Clock interface {
+	// Run calls pulse for each pulse (24 per quarter note), until ctx is done. It returns ctx.Err().
+	Run(ctx context.Context, pulse func()) error
+}
+
+var (
+	_ Clock = &Internal{}
+	_ Clock = &External{}
+)
+
+// Option is an option for an Internal clock
+type Option func(*Internal)
+
+// Time is an option that sets the functions that return the current time and that sleep (default: time.Now and a timer).
+// A sleep that is set this way can't be interrupted by the context.
+func Time(now func() time.Time, sleep func(time.Duration)) Option {
+	return func(c *Internal) {
+		c.now = now
+		c.sleep = sleep
+	}
+}
+
+// Internal is a clock that generates the pulses at a tempo. It is safe for concurrent use.
+type Internal struct {
+	mx    sync.Mutex
+	bpm   float64
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewInternal returns an internal clock with the given tempo in beats (quarter notes) per minute.
+// If bpm is not positive, the tempo is 120 BPM.
+func NewInternal(bpm float64, opts ...Option) *Internal {
+	if bpm <= 0 {
+		bpm = 120
+	}
+
+	c := &Internal{bpm: bpm, now: time.Now}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// SetTempo sets the tempo in beats per minute. It takes effect with the next pulse.
+// Tempos that are not positive are ignored.
+func (c *Internal) SetTempo(bpm float64) {
+	if bpm <= 0 {
+		return
+	}
+
+	c.mx.Lock()
+	c.bpm = bpm
+	c.mx.Unlock()
+}
+
+// Tempo returns the tempo in beats per minute
+func (c *Internal) Tempo() float64 {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	return c.bpm
+}
+
+// Interval returns the duration between two pulses at the current tempo
+func (c *Internal) Interval() time.Duration {
+	return Interval(c.Tempo())
+}
+
+// Interval returns the duration between two pulses at the given tempo in beats per minute
+func Interval(bpm float64) time.Duration {
+	if bpm <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Minute) / (bpm * PPQN))
+}
+
+// Run calls pulse for each pulse, starting immediately. The times of the pulses are computed from the start,
+// so that the delays of pulse and of the scheduler don't accumulate.
+func (c *Internal) Run(ctx context.Context, pulse func()) error {
+	var (
+		start = c.now()
+		// the time of the next pulse in nanoseconds since the start, without rounding errors
+		next float64
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		pulse()
+
+		next += float64(time.Minute) / (c.Tempo() * PPQN)
+
+		if err := c.wait(ctx, start.Add(time.Duration(math.Round(next))).Sub(c.now())); err != nil {
+			return err
+		}
+	}
+}
+
+// wait waits for the duration d or until ctx is done
+func (c *Internal) wait(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if d <= 0 {
+		return nil
+	}
+
+	if c.sleep != nil {
+		c.sleep(d)
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// External is a clock that follows the timing clock messages of another device.
+// The messages are passed via Receive (e.g. as realtime handler of a midireader) or Write.
+type External struct {
+	pulses chan struct{}
+}
+
+// NewExternal returns an external clock
+func NewExternal() *External {
+	// buffers a quarter note, if the receiver of the pulses is slow
+	return &External{pulses: make(chan struct{}, PPQN)}
+}
+
+// Receive receives a realtime message. It can be passed as realtime handler to midireader.New.
+// Messages other than realtime.TimingClock are ignored. If the buffer is full, the pulse is dropped.
+func (e *External) Receive(msg realtime.Message) {
+	if msg != realtime.TimingClock {
+		return
+	}
+
+	select {
+	case e.pulses <- struct{}{}:
+	default:
+	}
+}
+
+// Write receives a message like Receive. It allows to use the clock as midi.Writer (e.g. as output of a router).
+func (e *External) Write(msg midi.Message) error {
+	if rt, is := msg.(realtime.Message); is {
+		e.Receive(rt)
+	}
+	return nil
+}
+
+// Run calls pulse for each received timing clock message, until ctx is done
+func (e *External) Run(ctx context.Context, pulse func()) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-e.pulses:
+			pulse()
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package clock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+type fakeTime struct {
+	t time.Time
+}
+
+func (f *fakeTime) now() time.Time {
+	return f.t
+}
+
+func (f *fakeTime) sleep(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+func TestInternal(t *testing.T) {
+	ft := &fakeTime{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+	start := ft.t
+
+	clk := NewInternal(120, Time(ft.now, ft.sleep))
+
+	if got, want := clk.Interval(), time.Second/48; got != want {
+		t.Errorf("Interval() = %v; wanted %v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var times []time.Duration
+
+	err := clk.Run(ctx, func() {
+		times = append(times, ft.t.Sub(start))
+
+		switch len(times) {
+		case PPQN + 1:
+			// a quarter note at 120 BPM, then at 60 BPM
+			clk.SetTempo(60)
+		case 2 * PPQN:
+			cancel()
+		}
+	})
+
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if got, want := len(times), 2*PPQN; got != want {
+		t.Fatalf("got %v pulses; wanted %v", got, want)
+	}
+
+	tests := []struct {
+		pulse    int
+		expected time.Duration
+	}{
+		{0, 0},
+		{1, 20833333},
+		{PPQN, 500 * time.Millisecond},
+		{PPQN + 1, 541666667},
+		{2*PPQN - 1, 1458333333},
+	}
+
+	for i, test := range tests {
+		if got, want := times[test.pulse], test.expected; got != want {
+			t.Errorf("[%v] pulse %v at %v; wanted %v", i, test.pulse, got, want)
+		}
+	}
+}
+
+func TestExternal(t *testing.T) {
+	ext := NewExternal()
+
+	ext.Receive(realtime.TimingClock)
+	ext.Receive(realtime.Start)
+	ext.Write(channel.Channel0.NoteOn(60, 100))
+	ext.Write(realtime.TimingClock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var pulses int
+
+	err := ext.Run(ctx, func() {
+		pulses++
+		if pulses == 2 {
+			cancel()
+		}
+	})
+
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if pulses != 2 {
+		t.Errorf("got %v pulses; wanted 2", pulses)
+	}
+}
+
+type timeWriter struct {
+	name  string
+	bf    *bytes.Buffer
+	cur   *time.Time
+	start time.Time
+}
+
+func (w timeWriter) Write(msg midi.Message) error {
+	fmt.Fprintf(w.bf, "%s %s %s\n", w.cur.Sub(w.start), w.name, msg)
+	return nil
+}
+
+func TestDivider(t *testing.T) {
+	var (
+		bf    bytes.Buffer
+		start = time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+		cur   = start
+		d     = NewDivider()
+	)
+
+	bf.WriteString("\n")
+
+	d.Add(timeWriter{"half", &bf, &cur, start}, 1, 2)
+	d.Add(timeWriter{"3:4", &bf, &cur, start}, 3, 4)
+	d.Add(timeWriter{"double", &bf, &cur, start}, 2, 1)
+	d.Add(timeWriter{"invalid", &bf, &cur, start}, 0, 1)
+
+	// 5 pulses of the clock, 30ms apart; the second pulse of double-time is written late with the second pulse
+	// of the clock, since the interval is not known before
+	for i := 0; i < 5; i++ {
+		at := start.Add(time.Duration(i) * 30 * time.Millisecond)
+
+		for {
+			next, ok := d.next()
+			if !ok || next.After(at) {
+				break
+			}
+			cur = next
+			d.advance(cur)
+		}
+
+		cur = at
+		d.pulse(at)
+	}
+
+	expected := `
+0s half TimingClock
+0s 3:4 TimingClock
+0s double TimingClock
+30ms double TimingClock
+30ms double TimingClock
+40ms 3:4 TimingClock
+45ms double TimingClock
+60ms half TimingClock
+60ms double TimingClock
+75ms double TimingClock
+80ms 3:4 TimingClock
+90ms double TimingClock
+105ms double TimingClock
+120ms half TimingClock
+120ms 3:4 TimingClock
+120ms double TimingClock
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+type writerFunc func(midi.Message) error
+
+func (w writerFunc) Write(msg midi.Message) error {
+	return w(msg)
+}
+
+// count is a clock that calls pulse the given number of times
+type count int
+
+func (c count) Run(ctx context.Context, pulse func()) error {
+	for i := 0; i < int(c); i++ {
+		pulse()
+	}
+	return nil
+}
+
+func TestDividerRun(t *testing.T) {
+	var n int
+
+	d := NewDivider()
+	d.Add(writerFunc(func(msg midi.Message) error {
+		n++
+		return nil
+	}), 1, 6)
+
+	if err := d.Run(context.Background(), count(24)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := n, 4; got != want {
+		t.Errorf("pulses = %v; wanted %v", got, want)
+	}
+}
+
+func TestTap(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		// the intervals between the taps
+		intervals []time.Duration
+		expected  float64
+	}{
+		{nil, 0},
+		{[]time.Duration{500 * ms}, 120},
+		{[]time.Duration{500 * ms, 480 * ms, 520 * ms}, 120},
+		// the window averages the last 4 intervals
+		{[]time.Duration{1000 * ms, 500 * ms, 500 * ms, 500 * ms, 500 * ms}, 120},
+		// outliers are ignored
+		{[]time.Duration{500 * ms, 500 * ms, 500 * ms, 250 * ms, 500 * ms}, 120},
+		// two outliers in a row are a new tempo
+		{[]time.Duration{500 * ms, 500 * ms, 500 * ms, 300 * ms, 300 * ms}, 200},
+		// a pause starts a new series
+		{[]time.Duration{500 * ms, 500 * ms, 3000 * ms, 600 * ms}, 100},
+		{[]time.Duration{500 * ms, 500 * ms, 3000 * ms}, 120},
+	}
+
+	for i, test := range tests {
+		ft := &fakeTime{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+		clk := NewInternal(90)
+		tap := NewTap(TapTime(ft.now), TapClock(clk))
+
+		tap.Write(channel.Channel0.NoteOn(60, 100))
+
+		for _, iv := range test.intervals {
+			ft.sleep(iv)
+			// note offs are no taps
+			tap.Write(channel.Channel0.NoteOff(60))
+			tap.Write(channel.Channel0.NoteOn(60, 100))
+		}
+
+		if got, want := tap.Tempo(), test.expected; math.Abs(got-want) > 0.001 {
+			t.Errorf("[%v] Tempo() = %v; wanted %v", i, got, want)
+		}
+
+		if test.expected > 0 {
+			if got, want := clk.Tempo(), test.expected; math.Abs(got-want) > 0.001 {
+				t.Errorf("[%v] clock tempo = %v; wanted %v", i, got, want)
+			}
+		}
+
+		tap.Reset()
+
+		if _, ok := tap.Tap(); ok || tap.Tempo() != 0 {
+			t.Errorf("[%v] expected no tempo after Reset", i)
+		}
+	}
+}
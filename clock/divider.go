@@ -0,0 +1,208 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// divided is an output of a Divider
+type divided struct {
+	w        midi.Writer
+	num, den int64
+
+	// next is the number of the next pulse of the output
+	next int64
+}
+
+// Divider derives clocks with other rates from the pulses of a clock, e.g. half-time or a 3:4 polyrhythm,
+// and writes them as timing clock messages to writers (e.g. to sync vintage gear at different rates).
+// The first pulses of all outputs are written with the first pulse of the clock.
+// Pulses between the pulses of the clock (for multiplied or non-integer rates) are placed using the interval
+// between the last two pulses of the clock. It is safe for concurrent use.
+type Divider struct {
+	mx      sync.Mutex
+	now     func() time.Time
+	outputs []*divided
+
+	// n is the number of the last pulse of the clock (-1 before the first)
+	n int64
+
+	// last is the time of the last pulse of the clock and interval the duration since the pulse before
+	last     time.Time
+	interval time.Duration
+}
+
+// NewDivider returns a divider without outputs
+func NewDivider() *Divider {
+	return &Divider{now: time.Now, n: -1}
+}
+
+// Add adds an output that receives num pulses per den pulses of the clock, e.g. 1, 2 for half-time and 2, 1 for double-time.
+// Outputs with num or den below 1 are ignored.
+func (d *Divider) Add(w midi.Writer, num, den int) {
+	if num < 1 || den < 1 {
+		return
+	}
+
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	o := &divided{w: w, num: int64(num), den: int64(den)}
+
+	// a late output starts with the next pulse of the clock
+	if d.n >= 0 {
+		o.next = ((d.n+1)*o.num + o.den - 1) / o.den
+	}
+
+	d.outputs = append(d.outputs, o)
+}
+
+// Reset aligns the outputs again: their next pulses are written with the next pulse of the clock.
+// It is meant to be called on Start.
+func (d *Divider) Reset() {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	d.n, d.interval = -1, 0
+
+	for _, o := range d.outputs {
+		o.next = 0
+	}
+}
+
+// Run writes the pulses of the outputs following the pulses of clk, until ctx is done, clk.Run returns or a write fails.
+// It returns the error of the write or of clk.Run.
+func (d *Divider) Run(ctx context.Context, clk Clock) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		// buffers a quarter note, if writing is slow
+		pulses = make(chan time.Time, PPQN)
+		done   = make(chan error, 1)
+	)
+
+	go func() {
+		done <- clk.Run(ctx, func() {
+			select {
+			case pulses <- d.now():
+			default:
+			}
+		})
+	}()
+
+	for {
+		var (
+			t    *time.Timer
+			wake <-chan time.Time
+			err  error
+		)
+
+		if next, ok := d.next(); ok {
+			t = time.NewTimer(next.Sub(d.now()))
+			wake = t.C
+		}
+
+		select {
+		case err = <-done:
+			// the pulses that are still buffered
+			for {
+				select {
+				case at := <-pulses:
+					if werr := d.pulse(at); werr != nil {
+						return werr
+					}
+				default:
+					return err
+				}
+			}
+		case at := <-pulses:
+			err = d.pulse(at)
+		case <-wake:
+			err = d.advance(d.now())
+		}
+
+		if t != nil {
+			t.Stop()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// pulse writes the pulses of the outputs that are due with a pulse of the clock at the given time,
+// including the ones that were scheduled before and have not been written yet
+func (d *Divider) pulse(at time.Time) error {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	if d.n >= 0 {
+		d.interval = at.Sub(d.last)
+	}
+
+	d.n++
+	d.last = at
+
+	for _, o := range d.outputs {
+		for o.next*o.den <= d.n*o.num {
+			o.next++
+			if err := o.w.Write(realtime.TimingClock); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// due returns the time of the next pulse of the output, if it is before the next pulse of the clock
+func (d *Divider) due(o *divided) (time.Time, bool) {
+	if d.n < 0 || d.interval <= 0 || o.next*o.den >= (d.n+1)*o.num {
+		return time.Time{}, false
+	}
+
+	frac := float64(o.next*o.den-d.n*o.num) / float64(o.num)
+	return d.last.Add(time.Duration(frac * float64(d.interval))), true
+}
+
+// next returns the time of the next pulse of an output between the pulses of the clock
+func (d *Divider) next() (next time.Time, ok bool) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	for _, o := range d.outputs {
+		if t, is := d.due(o); is && (!ok || t.Before(next)) {
+			next, ok = t, true
+		}
+	}
+
+	return
+}
+
+// advance writes the pulses of the outputs between the pulses of the clock that are due at the given time
+func (d *Divider) advance(now time.Time) error {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	for _, o := range d.outputs {
+		for {
+			t, is := d.due(o)
+			if !is || t.After(now) {
+				break
+			}
+
+			o.next++
+			if err := o.w.Write(realtime.TimingClock); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
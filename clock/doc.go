@@ -0,0 +1,51 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package clock provides clocks that drive sequencers and other components that are synchronized to musical time.
+
+A Clock calls a function for each MIDI clock pulse (24 per quarter note). An Internal clock generates the pulses
+at a tempo that may be changed while running. An External clock follows the MIDI timing clock messages of another
+device (e.g. a drum machine). A transport.Transport is a clock that also follows Start, Stop and song position pointers.
+
+A Divider derives clocks with other rates from a clock and writes them as timing clock messages, e.g. to sync
+vintage gear at half-time or in a 3:4 polyrhythm.
+
+A Tap converts taps (key presses, notes or a footswitch) into a tempo, averaging the last intervals and ignoring
+outliers, and may set the tempo of an internal clock.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/clock"
+		"github.com/gomidi/midi/midireader"
+		"github.com/gomidi/midi/midiwriter"
+	)
+
+	// internal clock at 120 BPM
+	clk := clock.NewInternal(120)
+
+	go clk.Run(ctx, func() {
+		// called 24 times per quarter note
+	})
+
+	clk.SetTempo(140)
+
+	// external clock, receiving the realtime messages of the reader
+	ext := clock.NewExternal()
+	rd := midireader.New(input, ext.Receive)
+
+	// half-time to the first and double-time to the second output
+	div := clock.NewDivider()
+	div.Add(midiwriter.New(out1), 1, 2)
+	div.Add(midiwriter.New(out2), 2, 1)
+
+	go div.Run(ctx, ext)
+
+	// tap tempo with the notes of a controller
+	tap := clock.NewTap(clock.TapClock(clk))
+	tap.Write(msg)
+
+*/
+package clock
@@ -0,0 +1,219 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// TapOption is an option for a Tap
+type TapOption func(*Tap)
+
+// TapWindow is an option that sets the number of intervals between the taps that are averaged (default: 4)
+func TapWindow(n int) TapOption {
+	return func(t *Tap) {
+		if n > 0 {
+			t.window = n
+		}
+	}
+}
+
+// TapTolerance is an option that sets the deviation from the median of the intervals, beyond which an interval
+// is rejected as outlier, as a fraction of the median (default: 0.25). Two outliers in a row that agree with each other
+// are taken as a new tempo.
+func TapTolerance(f float64) TapOption {
+	return func(t *Tap) {
+		if f > 0 {
+			t.tolerance = f
+		}
+	}
+}
+
+// TapTimeout is an option that sets the pause after which a tap starts a new series of taps (default: 2s)
+func TapTimeout(d time.Duration) TapOption {
+	return func(t *Tap) {
+		if d > 0 {
+			t.timeout = d
+		}
+	}
+}
+
+// TapFilter is an option that sets the function that decides which messages passed to Write are taps.
+// By default, note ons and control changes with a value of at least 64 (e.g. a pressed footswitch) are taps.
+func TapFilter(fn func(midi.Message) bool) TapOption {
+	return func(t *Tap) {
+		t.filter = fn
+	}
+}
+
+// TapTime is an option that sets the function that returns the current time (default: time.Now)
+func TapTime(now func() time.Time) TapOption {
+	return func(t *Tap) {
+		t.now = now
+	}
+}
+
+// TapClock is an option that sets the tempo of the clock each time a tap results in a tempo
+func TapClock(clk *Internal) TapOption {
+	return func(t *Tap) {
+		t.clk = clk
+	}
+}
+
+// Tap converts taps (e.g. key presses or incoming notes) into a tempo: the average of the last intervals
+// between the taps, ignoring outliers. It is safe for concurrent use.
+type Tap struct {
+	mx        sync.Mutex
+	now       func() time.Time
+	window    int
+	tolerance float64
+	timeout   time.Duration
+	filter    func(midi.Message) bool
+	clk       *Internal
+
+	last      time.Time
+	intervals []time.Duration
+	outliers  []time.Duration
+	bpm       float64
+}
+
+// NewTap returns a new Tap without tempo
+func NewTap(opts ...TapOption) *Tap {
+	t := &Tap{
+		now:       time.Now,
+		window:    4,
+		tolerance: 0.25,
+		timeout:   2 * time.Second,
+		filter:    isTap,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// isTap is the default filter of the messages
+func isTap(msg midi.Message) bool {
+	switch m := msg.(type) {
+	case channel.NoteOn:
+		return m.Velocity() > 0
+	case channel.ControlChange:
+		return m.Value() >= 64
+	default:
+		return false
+	}
+}
+
+// Tap registers a tap at the current time and returns the tempo in beats per minute and whether it is known
+// (it is known after the second tap).
+func (t *Tap) Tap() (bpm float64, ok bool) {
+	t.mx.Lock()
+	bpm = t.tap(t.now())
+	clk := t.clk
+	t.mx.Unlock()
+
+	if bpm > 0 && clk != nil {
+		clk.SetTempo(bpm)
+	}
+
+	return bpm, bpm > 0
+}
+
+// Write registers a tap, if the message is a tap (see TapFilter). It allows to tap with a MIDI controller,
+// e.g. as output of a router. It always returns nil.
+func (t *Tap) Write(msg midi.Message) error {
+	if t.filter(msg) {
+		t.Tap()
+	}
+	return nil
+}
+
+// Tempo returns the tempo in beats per minute, or 0 if it is not known
+func (t *Tap) Tempo() float64 {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	return t.bpm
+}
+
+// Reset forgets the taps and the tempo
+func (t *Tap) Reset() {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.last = time.Time{}
+	t.intervals, t.outliers = nil, nil
+	t.bpm = 0
+}
+
+// tap registers a tap at the given time and returns the tempo
+func (t *Tap) tap(at time.Time) float64 {
+	last := t.last
+	t.last = at
+
+	if last.IsZero() {
+		return t.bpm
+	}
+
+	d := at.Sub(last)
+
+	if d <= 0 {
+		return t.bpm
+	}
+
+	// a new series keeps the last tempo until the next tap
+	if d > t.timeout {
+		t.intervals, t.outliers = nil, nil
+		return t.bpm
+	}
+
+	switch {
+	case len(t.intervals) < 2 || t.fits(d, median(t.intervals)):
+		t.intervals = append(t.intervals, d)
+		t.outliers = nil
+	case len(t.outliers) > 0 && t.fits(d, t.outliers[len(t.outliers)-1]):
+		// the tempo has changed
+		t.intervals = append(t.outliers, d)
+		t.outliers = nil
+	default:
+		t.outliers = append(t.outliers[:0], d)
+		return t.bpm
+	}
+
+	if len(t.intervals) > t.window {
+		t.intervals = t.intervals[len(t.intervals)-t.window:]
+	}
+
+	var sum time.Duration
+	for _, iv := range t.intervals {
+		sum += iv
+	}
+
+	t.bpm = float64(time.Minute) * float64(len(t.intervals)) / float64(sum)
+	return t.bpm
+}
+
+// fits returns whether the interval d does not deviate more than the tolerance from ref
+func (t *Tap) fits(d, ref time.Duration) bool {
+	diff := d - ref
+	if diff < 0 {
+		diff = -diff
+	}
+	return float64(diff) <= t.tolerance*float64(ref)
+}
+
+// median returns the median of the durations
+func median(ds []time.Duration) time.Duration {
+	s := append([]time.Duration(nil), ds...)
+	sort.Slice(s, func(a, b int) bool { return s[a] < s[b] })
+
+	if len(s)%2 == 1 {
+		return s[len(s)/2]
+	}
+
+	return (s[len(s)/2-1] + s[len(s)/2]) / 2
+}
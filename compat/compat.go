@@ -0,0 +1,195 @@
+package compat
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/apps/player"
+	"github.com/gomidi/midi/apps/recorder"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+	"github.com/gomidi/midi/router"
+	"github.com/gomidi/midi/smf"
+)
+
+// APIVersion is the semantic version of the interfaces of this package
+const APIVersion = "2.0.0"
+
+// Reader is the stable interface of a live MIDI reader
+type Reader interface {
+	midi.NamedReader
+	midireader.ContextReader
+	midireader.Timestamped
+}
+
+// Writer is the stable interface of a live MIDI writer
+type Writer interface {
+	midi.ProvenanceWriter
+	midiwriter.RealtimeWriter
+
+	// Close flushes pending messages; it makes no attempt to close the underlying output
+	Close() error
+}
+
+// SMFReader is the stable interface of a reader of Standard MIDI Files
+type SMFReader = smf.Reader
+
+// SMFWriter is the stable interface of a writer of Standard MIDI Files
+type SMFWriter = smf.Writer
+
+// Router is the stable interface of a router (see router.Router)
+type Router interface {
+	AddOutput(name string, wr midi.Writer)
+	RemoveOutput(name string)
+	Connect(from, to string, filters ...router.Filter)
+	ConnectTransform(from, to string, t midi.Transform, filters ...router.Filter)
+	Disconnect(from, to string)
+	Routes() []router.Route
+	Route(input string, msg midi.Message) error
+	Input(name string) midi.ProvenanceWriter
+	ReadFrom(input string, rd midi.Reader) error
+	SendReset() error
+	ReleaseStuck() error
+}
+
+// Player is the stable interface of a SMF player (see apps/player)
+type Player interface {
+	Header() smf.Header
+	Duration() time.Duration
+	Play(ctx context.Context, w midi.Writer) error
+}
+
+// Recorder is the stable interface of a SMF recorder (see apps/recorder)
+type Recorder interface {
+	midi.Writer
+	Start()
+	Record(ctx context.Context, rd midi.Reader) error
+	Len() int
+	Reset()
+	WriteSMF(w io.Writer) error
+}
+
+var (
+	_ Router   = &router.Router{}
+	_ Player   = &player.Player{}
+	_ Recorder = &recorder.Recorder{}
+	_ Reader   = &reader{}
+	_ Writer   = &writer{}
+)
+
+// NewReader returns a Reader that reads from rd. If rd already is a Reader, it is returned.
+// Missing capabilities are added:
+//   - the name is the name of rd, if it is a midi.NamedReader
+//   - reading with a context lets a pending read of rd continue in the background; its result is returned by the next read
+//   - the time of arrival is the time when rd returned the message, if rd does not record it
+//
+// Like the readers of midireader, the returned reader is not safe for concurrent use.
+func NewReader(rd midi.Reader) Reader {
+	if r, ok := rd.(Reader); ok {
+		return r
+	}
+	return &reader{rd: rd, now: time.Now}
+}
+
+type result struct {
+	msg midi.Message
+	err error
+}
+
+type reader struct {
+	rd      midi.Reader
+	now     func() time.Time
+	pending chan result
+	time    time.Time
+}
+
+// Name returns the name of the underlying reader
+func (r *reader) Name() string {
+	return midi.SourceName(r.rd)
+}
+
+// Read reads the next message
+func (r *reader) Read() (midi.Message, error) {
+	return r.ReadContext(context.Background())
+}
+
+// ReadContext reads the next message and returns ctx.Err() as soon as ctx is done
+func (r *reader) ReadContext(ctx context.Context) (msg midi.Message, err error) {
+	if cr, ok := r.rd.(midireader.ContextReader); ok {
+		msg, err = cr.ReadContext(ctx)
+		r.stamp(err)
+		return
+	}
+
+	if r.pending == nil {
+		ch := make(chan result, 1)
+		r.pending = ch
+		go func() {
+			msg, err := r.rd.Read()
+			ch <- result{msg, err}
+		}()
+	}
+
+	select {
+	case res := <-r.pending:
+		r.pending = nil
+		r.stamp(res.err)
+		return res.msg, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Time returns the time of arrival of the last message
+func (r *reader) Time() time.Time {
+	if ts, ok := r.rd.(midireader.Timestamped); ok {
+		if t := ts.Time(); !t.IsZero() {
+			return t
+		}
+	}
+	return r.time
+}
+
+// stamp records the time of arrival of a successfully read message
+func (r *reader) stamp(err error) {
+	if err == nil {
+		r.time = r.now()
+	}
+}
+
+// NewWriter returns a Writer that writes to w. If w already is a Writer, it is returned.
+// Missing capabilities are added:
+//   - the provenance is passed to w, if it is a midi.ProvenanceWriter
+//   - writes are serialized, so that realtime messages may be written from other goroutines
+//   - Close closes w, if it is a midi.WriteCloser (e.g. a midi.TransformWriter that must be flushed)
+func NewWriter(w midi.Writer) Writer {
+	if wr, ok := w.(Writer); ok {
+		return wr
+	}
+	return &writer{SyncWriter: midi.NewSyncWriter(w), dst: w}
+}
+
+type writer struct {
+	*midi.SyncWriter
+	dst midi.Writer
+}
+
+// WriteRealtime writes the realtime message. If the underlying writer is a midiwriter.RealtimeWriter,
+// the message is passed to it immediately.
+func (w *writer) WriteRealtime(msg realtime.Message) error {
+	if rt, ok := w.dst.(midiwriter.RealtimeWriter); ok {
+		return rt.WriteRealtime(msg)
+	}
+	return w.Write(msg)
+}
+
+// Close closes the underlying writer, if it is a midi.WriteCloser
+func (w *writer) Close() error {
+	if c, ok := w.dst.(midi.WriteCloser); ok {
+		return c.Close()
+	}
+	return nil
+}
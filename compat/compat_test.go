@@ -0,0 +1,112 @@
+package compat
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// blockingReader is a plain midi.Reader that returns its messages when they are sent to the channel
+type blockingReader chan midi.Message
+
+func (b blockingReader) Read() (midi.Message, error) {
+	msg, ok := <-b
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func TestReader(t *testing.T) {
+	in := make(blockingReader, 1)
+	rd := NewReader(midi.Name(in, "keys"))
+
+	if got, want := rd.Name(), "keys"; got != want {
+		t.Errorf("Name() = %q; wanted %q", got, want)
+	}
+
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	rd.(*reader).now = func() time.Time { return now }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := rd.ReadContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// the pending read is not lost
+	in <- channel.Channel1.NoteOn(60, 100)
+
+	msg, err := rd.Read()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := msg.String(), "channel.NoteOn channel 1 key 60 velocity 100"; got != want {
+		t.Errorf("got %q; wanted %q", got, want)
+	}
+
+	if got, want := rd.Time(), now; !got.Equal(want) {
+		t.Errorf("Time() = %v; wanted %v", got, want)
+	}
+
+	if NewReader(rd) != rd {
+		t.Errorf("expected a Reader to be returned unchanged")
+	}
+
+	close(in)
+
+	if _, err := rd.Read(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+type logWriter struct {
+	bf *bytes.Buffer
+}
+
+func (l logWriter) Write(msg midi.Message) error {
+	fmt.Fprintf(l.bf, "%s\n", msg)
+	return nil
+}
+
+func TestWriter(t *testing.T) {
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+
+	tw := midi.TransformWriter(logWriter{&bf}, midi.TransformFunc(func(msg midi.Message) []midi.Message {
+		return []midi.Message{msg}
+	}))
+
+	wr := NewWriter(tw)
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+	wr.WriteRealtime(realtime.Start)
+	wr.WriteProvenance(channel.Channel1.NoteOff(60), midi.Provenance{Source: "keys"})
+
+	if err := wr.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+channel.NoteOn channel 1 key 60 velocity 100
+Start
+channel.NoteOff channel 1 key 60
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if NewWriter(wr) != wr {
+		t.Errorf("expected a Writer to be returned unchanged")
+	}
+}
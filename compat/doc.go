@@ -0,0 +1,36 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package compat defines the stable interfaces of the wire-facing API and provides shims that
+let existing readers and writers use the newer capabilities.
+
+The interfaces of this package are versioned by APIVersion according to semantic versioning:
+they only change with a new major version. New capabilities are added as optional interfaces
+in the packages that provide them (e.g. midireader.ContextReader, midi.ProvenanceWriter,
+midiwriter.RealtimeWriter) and join the interfaces of this package with the next major version.
+The basic interfaces midi.Reader and midi.Writer are never changed, so code that is written against them
+keeps compiling.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/compat"
+	)
+
+	// rd is some midi.Reader, e.g. from an older driver package
+	r := compat.NewReader(rd)
+
+	// now it can be read with a context and knows the time of arrival
+	msg, err := r.ReadContext(ctx)
+	fmt.Println(r.Time(), msg)
+
+	// w is some midi.Writer
+	wr := compat.NewWriter(w)
+
+	// writing the clock from another goroutine is safe now
+	go wr.WriteRealtime(realtime.TimingClock)
+
+*/
+package compat
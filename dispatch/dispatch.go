@@ -0,0 +1,228 @@
+package dispatch
+
+import (
+	"io"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
+	"github.com/gomidi/midi/smf"
+)
+
+// Dispatcher calls the registered callbacks for each dispatched message.
+// Several callbacks may be registered for the same kind of message; they are called in the order of registration.
+// All callbacks must be registered before the dispatching starts.
+type Dispatcher struct {
+	message         []func(midi.Message)
+	noteOn          []func(channel.NoteOn)
+	noteOff         []func(channel.NoteOff)
+	noteOffVelocity []func(channel.NoteOffVelocity)
+	polyAftertouch  []func(channel.PolyAftertouch)
+	controlChange   []func(channel.ControlChange)
+	programChange   []func(channel.ProgramChange)
+	aftertouch      []func(channel.Aftertouch)
+	pitchbend       []func(channel.Pitchbend)
+	channelMode     []func(channel.ChannelMode)
+	patchChange     []func(channel.PatchChange)
+	sysEx           []func(sysex.Message)
+	sysCommon       []func(syscommon.Message)
+	realtime        []func(realtime.Message)
+	meta            []func(meta.Message)
+	unknown         []func(midi.Message)
+	closers         []func()
+}
+
+// New returns a new Dispatcher
+func New() *Dispatcher {
+	return &Dispatcher{}
+}
+
+// OnMessage registers a callback that is called for every message (before any specific callback).
+func (d *Dispatcher) OnMessage(fn func(midi.Message)) {
+	d.message = append(d.message, fn)
+}
+
+// OnNoteOn registers a callback for note-on messages
+func (d *Dispatcher) OnNoteOn(fn func(channel.NoteOn)) {
+	d.noteOn = append(d.noteOn, fn)
+}
+
+// OnNoteOff registers a callback for note-off messages.
+// If no callback for NoteOffVelocity messages is registered, it is also called for them.
+func (d *Dispatcher) OnNoteOff(fn func(channel.NoteOff)) {
+	d.noteOff = append(d.noteOff, fn)
+}
+
+// OnNoteOffVelocity registers a callback for note-off messages with velocity
+func (d *Dispatcher) OnNoteOffVelocity(fn func(channel.NoteOffVelocity)) {
+	d.noteOffVelocity = append(d.noteOffVelocity, fn)
+}
+
+// OnPolyAftertouch registers a callback for polyphonic aftertouch messages
+func (d *Dispatcher) OnPolyAftertouch(fn func(channel.PolyAftertouch)) {
+	d.polyAftertouch = append(d.polyAftertouch, fn)
+}
+
+// OnControlChange registers a callback for control change messages
+func (d *Dispatcher) OnControlChange(fn func(channel.ControlChange)) {
+	d.controlChange = append(d.controlChange, fn)
+}
+
+// OnProgramChange registers a callback for program change messages
+func (d *Dispatcher) OnProgramChange(fn func(channel.ProgramChange)) {
+	d.programChange = append(d.programChange, fn)
+}
+
+// OnAftertouch registers a callback for aftertouch messages
+func (d *Dispatcher) OnAftertouch(fn func(channel.Aftertouch)) {
+	d.aftertouch = append(d.aftertouch, fn)
+}
+
+// OnPitchbend registers a callback for pitch bend messages
+func (d *Dispatcher) OnPitchbend(fn func(channel.Pitchbend)) {
+	d.pitchbend = append(d.pitchbend, fn)
+}
+
+// OnChannelMode registers a callback for channel mode messages (see midireader.ChannelModes)
+func (d *Dispatcher) OnChannelMode(fn func(channel.ChannelMode)) {
+	d.channelMode = append(d.channelMode, fn)
+}
+
+// OnPatchChange registers a callback for patch changes (see midireader.PatchChanges)
+func (d *Dispatcher) OnPatchChange(fn func(channel.PatchChange)) {
+	d.patchChange = append(d.patchChange, fn)
+}
+
+// OnSysEx registers a callback for system exclusive messages
+func (d *Dispatcher) OnSysEx(fn func(sysex.Message)) {
+	d.sysEx = append(d.sysEx, fn)
+}
+
+// OnSysCommon registers a callback for system common messages
+func (d *Dispatcher) OnSysCommon(fn func(syscommon.Message)) {
+	d.sysCommon = append(d.sysCommon, fn)
+}
+
+// OnRealtime registers a callback for system realtime messages.
+// Since the live reader passes realtime messages to a callback, pass Realtime
+// as callback to the reader.
+func (d *Dispatcher) OnRealtime(fn func(realtime.Message)) {
+	d.realtime = append(d.realtime, fn)
+}
+
+// OnMeta registers a callback for meta messages (SMF only)
+func (d *Dispatcher) OnMeta(fn func(meta.Message)) {
+	d.meta = append(d.meta, fn)
+}
+
+// OnUnknown registers a callback for messages that are not of a known kind
+func (d *Dispatcher) OnUnknown(fn func(midi.Message)) {
+	d.unknown = append(d.unknown, fn)
+}
+
+// Realtime dispatches the given realtime message. It can be passed as callback to midireader.New.
+func (d *Dispatcher) Realtime(msg realtime.Message) {
+	d.Dispatch(msg)
+}
+
+// Dispatch calls the callbacks that are registered for the given message
+func (d *Dispatcher) Dispatch(msg midi.Message) {
+	for _, fn := range d.message {
+		fn(msg)
+	}
+
+	switch m := msg.(type) {
+	case channel.NoteOn:
+		for _, fn := range d.noteOn {
+			fn(m)
+		}
+	case channel.NoteOff:
+		for _, fn := range d.noteOff {
+			fn(m)
+		}
+	case channel.NoteOffVelocity:
+		if len(d.noteOffVelocity) == 0 {
+			for _, fn := range d.noteOff {
+				fn(m.NoteOff)
+			}
+		}
+		for _, fn := range d.noteOffVelocity {
+			fn(m)
+		}
+	case channel.PolyAftertouch:
+		for _, fn := range d.polyAftertouch {
+			fn(m)
+		}
+	case channel.ControlChange:
+		for _, fn := range d.controlChange {
+			fn(m)
+		}
+	case channel.ProgramChange:
+		for _, fn := range d.programChange {
+			fn(m)
+		}
+	case channel.Aftertouch:
+		for _, fn := range d.aftertouch {
+			fn(m)
+		}
+	case channel.Pitchbend:
+		for _, fn := range d.pitchbend {
+			fn(m)
+		}
+	case channel.ChannelMode:
+		for _, fn := range d.channelMode {
+			fn(m)
+		}
+	case channel.PatchChange:
+		for _, fn := range d.patchChange {
+			fn(m)
+		}
+	case sysex.Message:
+		for _, fn := range d.sysEx {
+			fn(m)
+		}
+	case syscommon.Message:
+		for _, fn := range d.sysCommon {
+			fn(m)
+		}
+	case realtime.Message:
+		for _, fn := range d.realtime {
+			fn(m)
+		}
+	case meta.Message:
+		for _, fn := range d.meta {
+			fn(m)
+		}
+	default:
+		for _, fn := range d.unknown {
+			fn(m)
+		}
+	}
+}
+
+// Read reads all messages from rd and dispatches them, until rd returns an error.
+// If the error is io.EOF or smf.ErrFinished, nil is returned.
+func (d *Dispatcher) Read(rd midi.Reader) error {
+	for {
+		msg, err := rd.Read()
+
+		if err == io.EOF || err == smf.ErrFinished {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		d.Dispatch(msg)
+	}
+}
+
+// Write dispatches the given message. It allows to use the dispatcher as midi.Writer.
+func (d *Dispatcher) Write(msg midi.Message) error {
+	d.Dispatch(msg)
+	return nil
+}
@@ -0,0 +1,73 @@
+package dispatch
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/sysex"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+func TestDispatcher(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel1.NoteOn(65, 100))
+	wr.Write(realtime.Start)
+	wr.Write(channel.Channel1.ControlChange(7, 100))
+	wr.Write(sysex.SysEx([]byte{0x41, 0x10}))
+	wr.Write(channel.Channel1.NoteOffVelocity(65, 20))
+	wr.Write(channel.Channel2.Pitchbend(300))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	count := 0
+
+	d := New()
+	d.OnMessage(func(midi.Message) {
+		count++
+	})
+	d.OnNoteOn(func(m channel.NoteOn) {
+		fmt.Fprintf(&out, "NoteOn %v %v %v\n", m.Channel(), m.Key(), m.Velocity())
+	})
+	d.OnNoteOff(func(m channel.NoteOff) {
+		fmt.Fprintf(&out, "NoteOff %v %v\n", m.Channel(), m.Key())
+	})
+	d.OnControlChange(func(m channel.ControlChange) {
+		fmt.Fprintf(&out, "ControlChange %v %v %v\n", m.Channel(), m.Controller(), m.Value())
+	})
+	d.OnSysEx(func(m sysex.Message) {
+		fmt.Fprintf(&out, "SysEx % X\n", m.Data())
+	})
+	d.OnRealtime(func(m realtime.Message) {
+		fmt.Fprintf(&out, "Realtime %s\n", m)
+	})
+
+	err := d.Read(midireader.New(&in, d.Realtime, midireader.NoteOffVelocity()))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+NoteOn 1 65 100
+Realtime Start
+ControlChange 1 7 100
+SysEx 41 10
+NoteOff 1 65
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if got, want := count, 6; got != want {
+		t.Errorf("OnMessage called %v times; wanted %v", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package dispatch provides a dispatcher that calls registered callbacks per kind of MIDI message.
+
+It replaces large type switches in user code.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/dispatch"
+		"github.com/gomidi/midi/midireader"
+		. "github.com/gomidi/midi/midimessage/channel"
+	)
+
+	d := dispatch.New()
+
+	d.OnNoteOn(func(msg NoteOn) {
+		fmt.Printf("NoteOn at channel %v: key %v velocity %v\n", msg.Channel(), msg.Key(), msg.Velocity())
+	})
+
+	d.OnControlChange(func(msg ControlChange) {
+		...
+	})
+
+	// realtime messages are passed via the callback of the reader
+	err := d.Read(midireader.New(input, d.Realtime))
+
+Alternatively, a Visitor (usually embedding NopVisitor) gets the messages via Visit:
+
+	type counter struct {
+		dispatch.NopVisitor
+		notes int
+	}
+
+	func (c *counter) NoteOn(msg NoteOn) {
+		c.notes++
+	}
+
+	var c counter
+	dispatch.Visit(msg, &c)
+
+For single checks, there are the generic helpers midi.Is and midi.As.
+
+*/
+package dispatch
@@ -0,0 +1,36 @@
+package dispatch
+
+import (
+	"github.com/gomidi/midi"
+)
+
+// SubscriptionBuffer is the buffer size of the channels returned by Subscribe
+const SubscriptionBuffer = 64
+
+// Subscribe returns a channel that receives every dispatched message of type T,
+// e.g. Subscribe[channel.NoteOn](d) returns a <-chan channel.NoteOn.
+// T may also be an interface, e.g. Subscribe[channel.Message](d) receives all channel messages.
+//
+// The channel has a buffer of SubscriptionBuffer messages. When it is full, dispatching blocks until
+// the subscriber has received a message, so no message gets lost.
+// The channel is closed by Close. Like the callbacks, subscriptions must be made before the dispatching starts.
+func Subscribe[T midi.Message](d *Dispatcher) <-chan T {
+	ch := make(chan T, SubscriptionBuffer)
+
+	d.OnMessage(func(msg midi.Message) {
+		if m, is := msg.(T); is {
+			ch <- m
+		}
+	})
+
+	d.closers = append(d.closers, func() { close(ch) })
+	return ch
+}
+
+// Close closes the channels returned by Subscribe. The dispatcher must not be used afterwards.
+func (d *Dispatcher) Close() {
+	for _, c := range d.closers {
+		c()
+	}
+	d.closers = nil
+}
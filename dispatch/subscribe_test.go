@@ -0,0 +1,48 @@
+package dispatch
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+func TestSubscribe(t *testing.T) {
+	d := New()
+
+	notes := Subscribe[channel.NoteOn](d)
+	all := Subscribe[channel.Message](d)
+
+	// the channels are buffered
+	d.Write(channel.Channel1.NoteOn(60, 100))
+	d.Write(realtime.Start)
+	d.Write(channel.Channel2.ControlChange(1, 100))
+	d.Write(channel.Channel1.NoteOn(62, 100))
+	d.Close()
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for n := range notes {
+		// no type assertion needed
+		fmt.Fprintf(&out, "note key %v\n", n.Key())
+	}
+
+	for m := range all {
+		fmt.Fprintf(&out, "channel %v\n", m.Channel())
+	}
+
+	expected := `
+note key 60
+note key 62
+channel 1
+channel 2
+channel 1
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
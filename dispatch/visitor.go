@@ -0,0 +1,119 @@
+package dispatch
+
+import (
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+// Visitor has a method for each kind of message. Visit calls the method that matches the message.
+// Embed NopVisitor to implement only the methods of interest.
+// Unlike the Dispatcher, a Visitor is a single value that keeps its own state.
+type Visitor interface {
+	NoteOn(channel.NoteOn)
+	NoteOff(channel.NoteOff)
+	NoteOffVelocity(channel.NoteOffVelocity)
+	PolyAftertouch(channel.PolyAftertouch)
+	ControlChange(channel.ControlChange)
+	ProgramChange(channel.ProgramChange)
+	Aftertouch(channel.Aftertouch)
+	Pitchbend(channel.Pitchbend)
+	ChannelMode(channel.ChannelMode)
+	PatchChange(channel.PatchChange)
+	SysEx(sysex.Message)
+	SysCommon(syscommon.Message)
+	Realtime(realtime.Message)
+	Meta(meta.Message)
+
+	// Unknown is called for all other messages
+	Unknown(midi.Message)
+}
+
+// Visit calls the method of v that matches the type of msg
+func Visit(msg midi.Message, v Visitor) {
+	switch m := msg.(type) {
+	case channel.NoteOn:
+		v.NoteOn(m)
+	case channel.NoteOff:
+		v.NoteOff(m)
+	case channel.NoteOffVelocity:
+		v.NoteOffVelocity(m)
+	case channel.PolyAftertouch:
+		v.PolyAftertouch(m)
+	case channel.ControlChange:
+		v.ControlChange(m)
+	case channel.ProgramChange:
+		v.ProgramChange(m)
+	case channel.Aftertouch:
+		v.Aftertouch(m)
+	case channel.Pitchbend:
+		v.Pitchbend(m)
+	case channel.ChannelMode:
+		v.ChannelMode(m)
+	case channel.PatchChange:
+		v.PatchChange(m)
+	case sysex.Message:
+		v.SysEx(m)
+	case syscommon.Message:
+		v.SysCommon(m)
+	case realtime.Message:
+		v.Realtime(m)
+	case meta.Message:
+		v.Meta(m)
+	default:
+		v.Unknown(m)
+	}
+}
+
+// NopVisitor is a Visitor that ignores all messages. It is meant to be embedded.
+type NopVisitor struct{}
+
+var _ Visitor = NopVisitor{}
+
+// NoteOn does nothing
+func (NopVisitor) NoteOn(channel.NoteOn) {}
+
+// NoteOff does nothing
+func (NopVisitor) NoteOff(channel.NoteOff) {}
+
+// NoteOffVelocity does nothing
+func (NopVisitor) NoteOffVelocity(channel.NoteOffVelocity) {}
+
+// PolyAftertouch does nothing
+func (NopVisitor) PolyAftertouch(channel.PolyAftertouch) {}
+
+// ControlChange does nothing
+func (NopVisitor) ControlChange(channel.ControlChange) {}
+
+// ProgramChange does nothing
+func (NopVisitor) ProgramChange(channel.ProgramChange) {}
+
+// Aftertouch does nothing
+func (NopVisitor) Aftertouch(channel.Aftertouch) {}
+
+// Pitchbend does nothing
+func (NopVisitor) Pitchbend(channel.Pitchbend) {}
+
+// ChannelMode does nothing
+func (NopVisitor) ChannelMode(channel.ChannelMode) {}
+
+// PatchChange does nothing
+func (NopVisitor) PatchChange(channel.PatchChange) {}
+
+// SysEx does nothing
+func (NopVisitor) SysEx(sysex.Message) {}
+
+// SysCommon does nothing
+func (NopVisitor) SysCommon(syscommon.Message) {}
+
+// Realtime does nothing
+func (NopVisitor) Realtime(realtime.Message) {}
+
+// Meta does nothing
+func (NopVisitor) Meta(meta.Message) {}
+
+// Unknown does nothing
+func (NopVisitor) Unknown(midi.Message) {}
@@ -0,0 +1,64 @@
+package dispatch
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+type noteCounter struct {
+	NopVisitor
+	out   *bytes.Buffer
+	notes int
+}
+
+func (n *noteCounter) NoteOn(m channel.NoteOn) {
+	n.notes++
+	fmt.Fprintf(n.out, "NoteOn %v\n", m.Key())
+}
+
+func (n *noteCounter) ChannelMode(m channel.ChannelMode) {
+	fmt.Fprintf(n.out, "ChannelMode %s\n", m.Mode())
+}
+
+func (n *noteCounter) Meta(m meta.Message) {
+	fmt.Fprintf(n.out, "Meta %s\n", m)
+}
+
+func TestVisit(t *testing.T) {
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	v := &noteCounter{out: &out}
+
+	for _, msg := range []midi.Message{
+		channel.Channel0.NoteOn(60, 100),
+		channel.Channel0.ControlChange(7, 100),
+		realtime.Start,
+		channel.Channel0.AllNotesOff(),
+		meta.Text("hello"),
+		channel.Channel0.NoteOn(64, 100),
+	} {
+		Visit(msg, v)
+	}
+
+	expected := `
+NoteOn 60
+ChannelMode All Notes Off
+Meta meta.Text: "hello"
+NoteOn 64
+`
+
+	if got := out.String(); got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+
+	if v.notes != 2 {
+		t.Errorf("got %v notes, wanted 2", v.notes)
+	}
+}
@@ -42,5 +42,23 @@ System Realtime and System Common Messages are restricted to "over the wire",
 while Meta Messages are restricted to SMF files. However System Realtime and System Common Messages
 can be saved inside a SMF file which the help of SysEx escaping (F7).
 
+All messages implement encoding.BinaryMarshaler and their pointers encoding.BinaryUnmarshaler, so that single
+messages can be stored or sent over queues. Parse returns the message for the raw bytes without the need for a reader.
+
+Messages returns an iterator over the messages of a reader, smf.Events one over the events of a SMF file:
+
+	for msg, err := range midi.Messages(rd) {
+		...
+	}
+
+Embedded devices
+
+The live core (this package, midireader, midiwriter and the packages below midimessage except meta)
+avoids reflection and the fmt package. For microcontrollers (e.g. USB-MIDI gadgets) there is a tiny build profile
+that is selected automatically when compiling with TinyGo (build tag tinygo) or explicitly with the build tag miditiny.
+The tiny profile leaves out the controller names in the String method of control change messages,
+the spooling of sysex to files and the type names of transforms in provenances.
+The SMF packages are not part of the tiny profile.
+
 */
 package midi
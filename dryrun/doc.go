@@ -0,0 +1,29 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package dryrun provides the offline simulation of a MIDI processing setup.
+
+The real outputs (e.g. hardware ports) are replaced by sinks of a report and the input is
+read from a capture file (raw MIDI bytes read via midireader) or from a SMF file.
+After the run, the report tells what each sink would have received
+(counts per message type, first and last message with their position) and how often
+each rule has been hit, which allows to validate a configuration before a show.
+
+Usage
+
+	rep := dryrun.New()
+
+	f, _ := os.Open("capture.mid")
+	src := rep.Source(smfreader.New(f))
+
+	// the setup under test, writing to the sinks instead of the real outputs
+	var setup midi.Writer = mySetup(rep.Sink("synth"), rep.Sink("drums"))
+
+	err := dryrun.Run(src, setup)
+
+	rep.WriteTo(os.Stdout)
+
+*/
+package dryrun
@@ -0,0 +1,265 @@
+package dryrun
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/smf"
+)
+
+// Position is the position of a message within the input
+type Position struct {
+	// Input is the number of the input message (starting with 0) that was read last,
+	// when the message arrived at the sink.
+	Input int
+
+	// Track is the track of the input message for SMF input (-1 otherwise)
+	Track int16
+
+	// Tick is the absolute position of the input message in ticks within its track for SMF input
+	Tick uint64
+}
+
+// String returns the position as string
+func (p Position) String() string {
+	if p.Track < 0 {
+		return fmt.Sprintf("#%v", p.Input)
+	}
+	return fmt.Sprintf("#%v track %v tick %v", p.Input, p.Track, p.Tick)
+}
+
+// Event is a message that arrived at a sink at a position
+type Event struct {
+	Position
+	Message midi.Message
+}
+
+// Report collects what the sinks received and how often each rule has been hit.
+// It is safe for concurrent use.
+type Report struct {
+	mx    sync.Mutex
+	pos   Position
+	sinks []*Sink
+	hits  map[string]int
+	rules []string
+}
+
+// New returns a new Report
+func New() *Report {
+	return &Report{
+		hits: map[string]int{},
+		pos:  Position{Input: -1, Track: -1},
+	}
+}
+
+// Source returns a reader that reads from src and keeps track of the position of the input.
+// If src is a smf.Reader, the track and absolute tick are tracked too.
+func (r *Report) Source(src midi.Reader) midi.Reader {
+	return &source{report: r, src: src}
+}
+
+// Sink returns the sink with the given name. The sink is created if it does not exist.
+func (r *Report) Sink(name string) *Sink {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	for _, s := range r.sinks {
+		if s.name == name {
+			return s
+		}
+	}
+
+	s := &Sink{report: r, name: name, kinds: map[string]int{}}
+	r.sinks = append(r.sinks, s)
+	return s
+}
+
+// Sinks returns all sinks in the order of creation
+func (r *Report) Sinks() []*Sink {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return append([]*Sink(nil), r.sinks...)
+}
+
+// Hit counts a hit of the rule with the given name
+func (r *Report) Hit(rule string) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if _, has := r.hits[rule]; !has {
+		r.rules = append(r.rules, rule)
+	}
+	r.hits[rule]++
+}
+
+// Hits returns the number of hits of the rule with the given name
+func (r *Report) Hits(rule string) int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return r.hits[rule]
+}
+
+func (r *Report) position() Position {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return r.pos
+}
+
+// WriteTo writes the report in a human readable form to w
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var bf strings.Builder
+
+	for _, s := range r.Sinks() {
+		bf.WriteString(s.String())
+	}
+
+	r.mx.Lock()
+	for _, rule := range r.rules {
+		fmt.Fprintf(&bf, "rule %q: %v hits\n", rule, r.hits[rule])
+	}
+	r.mx.Unlock()
+
+	n, err := io.WriteString(w, bf.String())
+	return int64(n), err
+}
+
+// Run reads all messages from src (which should have been returned by Source) and writes them to dst,
+// which is the entry point of the setup under test.
+// It returns nil when src returns io.EOF or smf.ErrFinished.
+func Run(src midi.Reader, dst midi.Writer) error {
+	for {
+		msg, err := src.Read()
+
+		if err == io.EOF || err == smf.ErrFinished {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = dst.Write(msg)
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type source struct {
+	report *Report
+	src    midi.Reader
+}
+
+// Read reads the next message and updates the position
+func (s *source) Read() (midi.Message, error) {
+	msg, err := s.src.Read()
+
+	if err != nil {
+		return msg, err
+	}
+
+	s.report.mx.Lock()
+	s.report.pos.Input++
+	if rd, ok := s.src.(smf.Reader); ok {
+		if rd.Track() != s.report.pos.Track {
+			s.report.pos.Track = rd.Track()
+			s.report.pos.Tick = 0
+		}
+		s.report.pos.Tick += uint64(rd.Delta())
+	}
+	s.report.mx.Unlock()
+
+	return msg, nil
+}
+
+// Sink is a midi.Writer that records what it would have received
+type Sink struct {
+	report *Report
+	name   string
+	mx     sync.Mutex
+	count  int
+	kinds  map[string]int
+	first  *Event
+	last   *Event
+}
+
+// Write records the message
+func (s *Sink) Write(msg midi.Message) error {
+	ev := &Event{Position: s.report.position(), Message: msg}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.count++
+	s.kinds[fmt.Sprintf("%T", msg)]++
+
+	if s.first == nil {
+		s.first = ev
+	}
+	s.last = ev
+	return nil
+}
+
+// Name returns the name of the sink
+func (s *Sink) Name() string {
+	return s.name
+}
+
+// Count returns the number of messages the sink received
+func (s *Sink) Count() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.count
+}
+
+// CountOf returns the number of messages of the same type as the given message the sink received
+func (s *Sink) CountOf(msg midi.Message) int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.kinds[fmt.Sprintf("%T", msg)]
+}
+
+// First returns the first message the sink received (nil if there was none)
+func (s *Sink) First() *Event {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.first
+}
+
+// Last returns the last message the sink received (nil if there was none)
+func (s *Sink) Last() *Event {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.last
+}
+
+// String returns the summary of the sink
+func (s *Sink) String() string {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	var kinds []string
+	for k, n := range s.kinds {
+		kinds = append(kinds, fmt.Sprintf("%s: %v", k, n))
+	}
+	sort.Strings(kinds)
+
+	var bf strings.Builder
+	fmt.Fprintf(&bf, "sink %q: %v messages", s.name, s.count)
+	if len(kinds) > 0 {
+		fmt.Fprintf(&bf, " (%s)", strings.Join(kinds, ", "))
+	}
+	bf.WriteString("\n")
+
+	if s.first != nil {
+		fmt.Fprintf(&bf, "  first: [%s] %s\n", s.first.Position, s.first.Message)
+		fmt.Fprintf(&bf, "  last: [%s] %s\n", s.last.Position, s.last.Message)
+	}
+
+	return bf.String()
+}
@@ -0,0 +1,65 @@
+package dryrun
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/internal/examples"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+type setup struct {
+	report *Report
+	a, b   midi.Writer
+}
+
+func (s *setup) Write(msg midi.Message) error {
+	cm, ok := msg.(channel.Message)
+	if !ok {
+		return nil
+	}
+
+	if cm.Channel() == 0 {
+		s.report.Hit("channel 0 to a")
+		return s.a.Write(msg)
+	}
+
+	s.report.Hit("others to b")
+	return s.b.Write(msg)
+}
+
+func TestReport(t *testing.T) {
+	rep := New()
+	src := rep.Source(smfreader.New(bytes.NewReader(examples.SpecSMF1)))
+
+	err := Run(src, &setup{rep, rep.Sink("a"), rep.Sink("b")})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+	rep.WriteTo(&out)
+
+	expected := `
+sink "a": 3 messages (channel.NoteOff: 1, channel.NoteOn: 1, channel.ProgramChange: 1)
+  first: [#3 track 1 tick 0] channel.ProgramChange channel 0 program 5
+  last: [#5 track 1 tick 384] channel.NoteOff channel 0 key 76
+sink "b": 8 messages (channel.NoteOff: 3, channel.NoteOn: 3, channel.ProgramChange: 2)
+  first: [#7 track 2 tick 0] channel.ProgramChange channel 1 program 46
+  last: [#15 track 3 tick 384] channel.NoteOff channel 2 key 60
+rule "channel 0 to a": 3 hits
+rule "others to b": 8 hits
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if got, want := rep.Sink("b").CountOf(channel.NoteOn{}), 3; got != want {
+		t.Errorf("CountOf(NoteOn) = %v; wanted %v", got, want)
+	}
+}
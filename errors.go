@@ -0,0 +1,64 @@
+package midi
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gomidi/midi/internal/midilib"
+)
+
+var (
+	// ErrInvalidStatus is returned, when a status byte is found at a position where it is not allowed
+	// (e.g. a system common message inside a SMF file).
+	ErrInvalidStatus = errors.New("invalid status byte")
+
+	// ErrDataWithoutStatus is returned, when data bytes are found outside of a message.
+	ErrDataWithoutStatus = errors.New("data byte without status")
+
+	// ErrSysExTooLarge is returned, when a sysex exceeds the maximal size of the reader.
+	ErrSysExTooLarge = errors.New("sysex too large")
+
+	// ErrInvalidMessage is returned, when bytes can't be parsed as a MIDI message (see Parse and the UnmarshalBinary methods of the messages).
+	ErrInvalidMessage = midilib.ErrInvalidMessage
+)
+
+// ReadError is an error of a reader that carries the position of the error in the stream.
+// Use errors.Is to check for the cause, e.g. errors.Is(err, midi.ErrUnexpectedEOF).
+type ReadError struct {
+	// Err is the cause: ErrUnexpectedEOF, ErrInvalidStatus, ErrDataWithoutStatus, ErrSysExTooLarge or an error of the specific reader
+	Err error
+
+	// Offset is the offset of the offending byte from the start of the stream, or the offset of the end of the stream
+	// for ErrUnexpectedEOF
+	Offset int64
+
+	// Byte is the offending byte, 0 if there is none (e.g. for ErrUnexpectedEOF)
+	Byte byte
+
+	// Track is the number of the track of a SMF file (starting with 0), -1 for live streams and the header of SMF files
+	Track int16
+
+	// Fatal is true, if the reader can't continue after the error. Otherwise the next read returns the next message.
+	Fatal bool
+}
+
+// Error returns the error message with the position
+func (e *ReadError) Error() string {
+	const digits = "0123456789ABCDEF"
+	s := e.Err.Error() + " at offset " + strconv.FormatInt(e.Offset, 10)
+
+	if e.Byte != 0 {
+		s += " (byte 0x" + string([]byte{digits[e.Byte>>4], digits[e.Byte&0x0F]}) + ")"
+	}
+
+	if e.Track >= 0 {
+		s += " in track " + strconv.Itoa(int(e.Track))
+	}
+
+	return s
+}
+
+// Unwrap returns the cause
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
@@ -1 +1,3 @@
 module github.com/gomidi/midi
+
+go 1.23
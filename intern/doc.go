@@ -0,0 +1,29 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package intern provides a table of shared message instances for frequently repeated messages
+(e.g. the same control change or note over and over again).
+
+Returning a message as midi.Message needs an allocation for most message types.
+Messages that are found in the table are returned as the shared instance instead, which saves the allocation.
+Since all messages are immutable values, sharing them is safe.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/intern"
+		"github.com/gomidi/midi/midireader"
+	)
+
+	table := intern.New(0)
+
+	// midireader uses its own table by default; readers may share one instead
+	rd := midireader.New(src, nil, midireader.Intern(table))
+
+	// messages from other sources can be interned explicitly
+	msg = table.Intern(msg)
+
+*/
+package intern
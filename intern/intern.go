@@ -0,0 +1,100 @@
+package intern
+
+import (
+	"sync"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/syscommon"
+)
+
+// DefaultMax is the maximum number of messages of a table created with New(0)
+const DefaultMax = 4096
+
+// Table is a table of shared message instances for channel messages and system common messages
+// with up to two data bytes. It is safe for concurrent use.
+// When the table is full, further messages are not added, so that the messages that came first stay in the table.
+type Table struct {
+	mx   sync.RWMutex
+	msgs map[uint32]midi.Message
+	max  int
+}
+
+// New returns a new table for at most max messages. If max is 0, DefaultMax is used.
+func New(max int) *Table {
+	if max <= 0 {
+		max = DefaultMax
+	}
+	return &Table{
+		msgs: map[uint32]midi.Message{},
+		max:  max,
+	}
+}
+
+func key(status, arg1, arg2 byte) uint32 {
+	return uint32(status)<<16 | uint32(arg1)<<8 | uint32(arg2)
+}
+
+// Lookup returns the message for the given status byte and data bytes, if it is in the table.
+// For messages with one data byte, arg2 must be 0.
+func (t *Table) Lookup(status, arg1, arg2 byte) (msg midi.Message, found bool) {
+	t.mx.RLock()
+	msg, found = t.msgs[key(status, arg1, arg2)]
+	t.mx.RUnlock()
+	return
+}
+
+// Store adds the message for the given status byte and data bytes to the table, unless the table is full.
+// For messages with one data byte, arg2 must be 0.
+func (t *Table) Store(status, arg1, arg2 byte, msg midi.Message) {
+	t.mx.Lock()
+	if len(t.msgs) < t.max {
+		t.msgs[key(status, arg1, arg2)] = msg
+	}
+	t.mx.Unlock()
+}
+
+// Len returns the number of messages in the table
+func (t *Table) Len() int {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+	return len(t.msgs)
+}
+
+// internable returns true for the channel and system common messages with up to two data bytes.
+// They are comparable, so that they can be compared to the shared instances.
+func internable(msg midi.Message) bool {
+	switch msg.(type) {
+	case channel.NoteOn, channel.NoteOff, channel.NoteOffVelocity, channel.PolyAftertouch, channel.ControlChange,
+		channel.ChannelMode, channel.ProgramChange, channel.Aftertouch, channel.Pitchbend,
+		syscommon.MTC, syscommon.SongSelect, syscommon.SPP:
+		return true
+	}
+	return false
+}
+
+// Intern returns the shared instance of the given message, if it is in the table, otherwise
+// it adds the message to the table and returns it. Messages that can't be interned (e.g. sysex or syscommon.Unknown) are returned unchanged.
+func (t *Table) Intern(msg midi.Message) midi.Message {
+	if !internable(msg) {
+		return msg
+	}
+
+	raw := msg.Raw()
+
+	var arg2 byte
+	if len(raw) == 3 {
+		arg2 = raw[2]
+	}
+
+	// messages of different types might have the same bytes (e.g. a note on with velocity 0 and a note off)
+	if shared, found := t.Lookup(raw[0], raw[1], arg2); found {
+		if shared == msg {
+			return shared
+		}
+		return msg
+	}
+
+	t.Store(raw[0], raw[1], arg2, msg)
+	return msg
+}
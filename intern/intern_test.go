@@ -0,0 +1,54 @@
+package intern
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+func TestIntern(t *testing.T) {
+	table := New(2)
+
+	a := table.Intern(channel.Channel1.ControlChange(7, 100))
+	b := table.Intern(channel.Channel1.ControlChange(7, 100))
+
+	if a != b {
+		t.Errorf("expected equal messages")
+	}
+
+	// same bytes, other type
+	off := table.Intern(channel.Channel1.NoteOff(60))
+	on := table.Intern(channel.Channel1.NoteOn(60, 0))
+
+	if _, is := on.(channel.NoteOn); !is {
+		t.Errorf("expected NoteOn, got %T", on)
+	}
+
+	if _, is := off.(channel.NoteOff); !is {
+		t.Errorf("expected NoteOff, got %T", off)
+	}
+
+	// table is full
+	table.Intern(channel.Channel1.ProgramChange(3))
+
+	if got, want := table.Len(), 2; got != want {
+		t.Errorf("Len() = %v; wanted %v", got, want)
+	}
+
+	var sx midi.Message = sysex.SysEx([]byte{0x43})
+	if got := table.Intern(sx); got.String() != sx.String() {
+		t.Errorf("sysex should be returned unchanged, got %v", got)
+	}
+
+	// not comparable
+	table = New(0)
+	for i := 0; i < 2; i++ {
+		var u midi.Message = syscommon.Unknown{Status: 0xF1, Data: []byte{0x12}}
+		if got := table.Intern(u); got.String() != u.String() {
+			t.Errorf("unknown message should be returned unchanged, got %v", got)
+		}
+	}
+}
@@ -1,11 +1,14 @@
 package midilib
 
 import (
+	"errors"
 	"io"
-
-	"github.com/gomidi/midi"
 )
 
+// ErrUnexpectedEOF is returned, when an unexspected end of file is reached.
+// It is exported as midi.ErrUnexpectedEOF.
+var ErrUnexpectedEOF = errors.New("Unexpected End of File found.")
+
 /*
 This file contains functions that are modifications of the functions found
 in the github.com/afandian/go-midi package of Joe Wass.
@@ -97,7 +100,7 @@ func ReadVarLength(reader io.Reader) (uint32, error) {
 	}
 
 	if num == 0 && !first {
-		return result, midi.ErrUnexpectedEOF
+		return result, ErrUnexpectedEOF
 	}
 
 	return result, nil
@@ -120,7 +123,7 @@ func ReadVarLengthData(reader io.Reader) ([]byte, error) {
 
 	// If we couldn't read the entire expected-length buffer, that's a problem.
 	if num != int(length) {
-		return []byte{}, midi.ErrUnexpectedEOF
+		return []byte{}, ErrUnexpectedEOF
 	}
 
 	// If there was some other problem, that's also a problem.
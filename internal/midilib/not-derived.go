@@ -5,9 +5,40 @@ functions in this file are _not_ derived from the work of Joe Wass.
 */
 
 import (
+	"errors"
 	"io"
 )
 
+// ErrInvalidMessage is returned, when bytes can't be parsed as the expected MIDI message.
+// It is exported as midi.ErrInvalidMessage.
+var ErrInvalidMessage = errors.New("invalid MIDI message")
+
+// InvalidMessage returns an error with the cause ErrInvalidMessage, telling that data is no valid message of the given kind
+func InvalidMessage(data []byte, kind string) error {
+	return &invalidMessage{data: data, kind: kind}
+}
+
+// invalidMessage is the error returned by InvalidMessage. It avoids the fmt package for the tiny profile.
+type invalidMessage struct {
+	data []byte
+	kind string
+}
+
+func (e *invalidMessage) Error() string {
+	const digits = "0123456789ABCDEF"
+	s := []byte(ErrInvalidMessage.Error() + ":")
+
+	for _, b := range e.data {
+		s = append(s, ' ', digits[b>>4], digits[b&0x0F])
+	}
+
+	return string(append(s, " is no "+e.kind...))
+}
+
+func (e *invalidMessage) Unwrap() error {
+	return ErrInvalidMessage
+}
+
 func clearBitU16(n uint16, pos uint16) uint16 {
 	mask := ^(uint16(1) << pos)
 	n &= mask
@@ -77,8 +108,13 @@ func ReadNBytes(n int, rd io.Reader) ([]byte, error) {
 	return b, err
 }
 
-// ReadByte reads a byte from the reader
+// ReadByte reads a byte from the reader.
+// If the reader is an io.ByteReader, its ReadByte method is used, which avoids the allocation of a buffer.
 func ReadByte(rd io.Reader) (byte, error) {
+	if br, ok := rd.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+
 	b, err := ReadNBytes(1, rd)
 
 	if err != nil {
@@ -87,3 +123,25 @@ func ReadByte(rd io.Reader) (byte, error) {
 
 	return b[0], nil
 }
+
+// CountingReader is an io.Reader that counts the bytes that have been read
+type CountingReader struct {
+	R io.Reader
+	N int64
+}
+
+// Read reads from R and counts the bytes
+func (c *CountingReader) Read(p []byte) (n int, err error) {
+	n, err = c.R.Read(p)
+	c.N += int64(n)
+	return
+}
+
+// ReadByte reads a single byte from R and counts it
+func (c *CountingReader) ReadByte() (b byte, err error) {
+	b, err = ReadByte(c.R)
+	if err == nil {
+		c.N++
+	}
+	return
+}
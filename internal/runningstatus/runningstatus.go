@@ -12,12 +12,19 @@ type Reader interface {
 	// Read reads the status byte off the canary and returns
 	// if it has changed compared to the previous read
 	Read(canary byte) (status byte, changed bool)
+
+	// Reset clears the running status (e.g. after a System Reset)
+	Reset()
 }
 
 type reader struct {
 	status byte
 }
 
+func (r *reader) Reset() {
+	r.status = 0
+}
+
 func (r *reader) read(canary byte) (status byte, changed bool) {
 
 	// channel/Voice Category Status
@@ -155,6 +162,12 @@ type liveWriter struct {
 // Write writes the given message with running status
 func (w *liveWriter) Write(msg []byte) (int, error) {
 	// fmt.Printf("should write % X\n", msg)
+	// a system reset clears the running status of the receiver
+	if msg[0] == 0xFF {
+		w.status = 0
+		return w.write(msg)
+	}
+
 	// for realtime system messages, don't affect status and write the whole message
 	if msg[0] > 0xF7 {
 		return w.write(msg)
@@ -0,0 +1,40 @@
+package midi
+
+import (
+	"io"
+	"iter"
+)
+
+// Messages returns an iterator over the messages that are read from rd, e.g.
+//
+//	for msg, err := range midi.Messages(rd) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(msg)
+//	}
+//
+// The iteration ends, when rd returns io.EOF (which is not passed) or after the first error that is not
+// a *ReadError with Fatal set to false. Non-fatal read errors are passed and the iteration goes on.
+// Breaking out of the loop stops the reading. For SMF files, see smf.Events.
+func Messages(rd Reader) iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		for {
+			msg, err := rd.Read()
+
+			if err == io.EOF {
+				return
+			}
+
+			if !yield(msg, err) {
+				return
+			}
+
+			if err != nil {
+				if re, ok := err.(*ReadError); !ok || re.Fatal {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package midi_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midireader"
+)
+
+func TestMessages(t *testing.T) {
+	in := []byte{
+		0x90, 0x3C, 0x64,
+		0xF0, 0x01, 0x02, 0x03, 0x04, 0xF7, // too large
+		0x80, 0x3C, 0x00,
+		0x91, 0x3C, 0x64,
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	rd := midireader.New(bytes.NewReader(in), nil, midireader.MaxSysEx(2))
+
+	for msg, err := range midi.Messages(rd) {
+		if err != nil {
+			if !errors.Is(err, midi.ErrSysExTooLarge) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			out.WriteString("sysex too large\n")
+			continue
+		}
+
+		fmt.Fprintf(&out, "%s\n", msg)
+
+		// early exit
+		if _, is := msg.(channel.NoteOff); is {
+			break
+		}
+	}
+
+	expected := `
+channel.NoteOn channel 0 key 60 velocity 100
+sysex too large
+channel.NoteOff channel 0 key 60
+`
+
+	if got := out.String(); got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+}
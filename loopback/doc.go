@@ -0,0 +1,28 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package loopback verifies MIDI connections by sending a known pattern to an output and
+checking that it is received unchanged on an input that is physically looped back to the output
+(e.g. by a MIDI cable connecting MIDI out with MIDI in).
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/loopback"
+		"github.com/gomidi/midi/midireader"
+		"github.com/gomidi/midi/midiwriter"
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := loopback.Check(ctx, midiwriter.New(out), midireader.New(in, nil))
+
+	if d, is := err.(*loopback.Divergence); is {
+		fmt.Printf("the connection is broken at message #%v\n", d.Index)
+	}
+
+*/
+package loopback
@@ -0,0 +1,162 @@
+package loopback
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+// Divergence is returned by Check, if a message was not received as it has been sent
+type Divergence struct {
+	// Index is the position of the message within the pattern (starting with 0)
+	Index int
+
+	// Sent is the message that has been sent
+	Sent midi.Message
+
+	// Received is the message that has been received instead, nil if Err is set
+	Received midi.Message
+
+	// Err is the error that happened while reading the message, if any
+	Err error
+}
+
+// Error returns the description of the divergence
+func (d *Divergence) Error() string {
+	if d.Err != nil {
+		return fmt.Sprintf("message #%v: sent %s, got error: %v", d.Index, d.Sent, d.Err)
+	}
+	return fmt.Sprintf("message #%v: sent %s, received %s", d.Index, d.Sent, d.Received)
+}
+
+// Option is an option for Check
+type Option func(*config)
+
+type config struct {
+	pattern []midi.Message
+}
+
+// Pattern replaces the default pattern by the given messages
+func Pattern(msgs ...midi.Message) Option {
+	return func(c *config) {
+		c.pattern = msgs
+	}
+}
+
+// DefaultPattern returns the pattern that is sent by default. It consists of
+// notes on every channel, running status sequences of all channel message types and
+// SysEx messages of increasing sizes.
+func DefaultPattern() []midi.Message {
+	var msgs []midi.Message
+
+	for ch := uint8(0); ch < 16; ch++ {
+		c := channel.Channel(ch)
+		msgs = append(msgs, c.NoteOn(60+ch, 100), c.NoteOff(60+ch))
+	}
+
+	// running status sequences
+	c := channel.Channel0
+	for i := uint8(0); i < 8; i++ {
+		msgs = append(msgs, c.NoteOn(i*16, 127-i*16))
+	}
+	for i := uint8(0); i < 8; i++ {
+		msgs = append(msgs, c.ControlChange(i, i*16))
+	}
+	for i := uint8(0); i < 8; i++ {
+		msgs = append(msgs, c.ProgramChange(i*16))
+	}
+	for i := int16(0); i < 8; i++ {
+		msgs = append(msgs, c.Pitchbend(-8192+i*2048))
+	}
+	for i := uint8(0); i < 8; i++ {
+		msgs = append(msgs, c.PolyAftertouch(i, i*16))
+	}
+	for i := uint8(0); i < 8; i++ {
+		msgs = append(msgs, c.Aftertouch(i*16))
+	}
+
+	for _, size := range []int{1, 16, 128, 1024, 4096} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i % 128)
+		}
+		msgs = append(msgs, sysex.SysEx(data))
+	}
+
+	return msgs
+}
+
+type contextReader interface {
+	ReadContext(ctx context.Context) (midi.Message, error)
+}
+
+// Check writes the pattern to wr and verifies that it is received unchanged by rd.
+// The pattern is written in a separate goroutine, while rd is read in the calling goroutine.
+// If rd has a ReadContext method (like the reader returned by midireader.New), reading is aborted
+// when ctx is done. Realtime messages are not part of the pattern and should be handled by the realtime
+// callback of the reader.
+// The first message that was not received as sent is reported as *Divergence.
+// Errors while writing are returned as they are.
+func Check(ctx context.Context, wr midi.Writer, rd midi.Reader, options ...Option) error {
+	c := &config{pattern: DefaultPattern()}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writeErr := make(chan error, 1)
+
+	go func() {
+		for i, msg := range c.pattern {
+			if ctx.Err() != nil {
+				writeErr <- ctx.Err()
+				return
+			}
+
+			if err := wr.Write(msg); err != nil {
+				writeErr <- fmt.Errorf("can't write message #%v: %v", i, err)
+				return
+			}
+		}
+		writeErr <- nil
+	}()
+
+	cr, hasContext := rd.(contextReader)
+
+	for i, sent := range c.pattern {
+		var (
+			got midi.Message
+			err error
+		)
+
+		if hasContext {
+			got, err = cr.ReadContext(ctx)
+		} else {
+			got, err = rd.Read()
+		}
+
+		if err != nil {
+			select {
+			case werr := <-writeErr:
+				if werr != nil {
+					return werr
+				}
+			default:
+			}
+			return &Divergence{Index: i, Sent: sent, Err: err}
+		}
+
+		if !bytes.Equal(got.Raw(), sent.Raw()) {
+			return &Divergence{Index: i, Sent: sent, Received: got}
+		}
+	}
+
+	return <-writeErr
+}
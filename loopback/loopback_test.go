@@ -0,0 +1,75 @@
+package loopback
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+func TestCheck(t *testing.T) {
+	pr, pw := io.Pipe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := Check(ctx, midiwriter.New(pw), midireader.New(pr, nil))
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckDivergence(t *testing.T) {
+	pr, pw := io.Pipe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// a broken cable that changes the velocity of the note on channel 4
+	broken := midi.TransformFunc(func(msg midi.Message) []midi.Message {
+		if msg == channel.Channel3.NoteOn(63, 100) {
+			return []midi.Message{channel.Channel3.NoteOn(63, 90)}
+		}
+		return []midi.Message{msg}
+	})
+
+	err := Check(ctx, midi.TransformWriter(midiwriter.New(pw), broken), midireader.New(pr, nil))
+
+	d, is := err.(*Divergence)
+
+	if !is {
+		t.Fatalf("expected *Divergence, got %v", err)
+	}
+
+	expected := "message #6: sent channel.NoteOn channel 3 key 63 velocity 100, received channel.NoteOn channel 3 key 63 velocity 90"
+
+	if got, want := d.Error(), expected; got != want {
+		t.Errorf("got: %q; wanted %q", got, want)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	pr, _ := io.Pipe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// nothing is looped back
+	err := Check(ctx, midi.ProvenanceFunc(func(midi.Message, midi.Provenance) error { return nil }), midireader.New(pr, nil))
+
+	d, is := err.(*Divergence)
+
+	if !is {
+		t.Fatalf("expected *Divergence, got %v", err)
+	}
+
+	if d.Index != 0 || d.Err != context.DeadlineExceeded {
+		t.Errorf("unexpected divergence: %v", d)
+	}
+}
@@ -0,0 +1,20 @@
+package midi
+
+// Is returns true, if msg is of type T. T may also be an interface, e.g.
+//
+//	midi.Is[channel.NoteOn](msg)
+//	midi.Is[channel.Message](msg) // any channel message
+func Is[T Message](msg Message) bool {
+	_, is := msg.(T)
+	return is
+}
+
+// As returns msg as type T and true, if msg is of type T. Otherwise the zero value of T and false are returned. T may also be an interface, e.g.
+//
+//	if on, ok := midi.As[channel.NoteOn](msg); ok {
+//		fmt.Println(on.Key())
+//	}
+func As[T Message](msg Message) (m T, ok bool) {
+	m, ok = msg.(T)
+	return
+}
@@ -0,0 +1,37 @@
+package midi_test
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+func TestIsAs(t *testing.T) {
+	var msg midi.Message = channel.Channel2.NoteOn(60, 100)
+
+	if !midi.Is[channel.NoteOn](msg) {
+		t.Errorf("NoteOn should be a channel.NoteOn")
+	}
+
+	if !midi.Is[channel.Message](msg) {
+		t.Errorf("NoteOn should be a channel.Message")
+	}
+
+	if midi.Is[channel.NoteOff](msg) || midi.Is[realtime.Message](msg) {
+		t.Errorf("NoteOn should be neither a channel.NoteOff nor a realtime.Message")
+	}
+
+	if on, ok := midi.As[channel.NoteOn](msg); !ok || on.Key() != 60 {
+		t.Errorf("As[channel.NoteOn] = %v, %v", on, ok)
+	}
+
+	if cm, ok := midi.As[channel.Message](msg); !ok || cm.Channel() != 2 {
+		t.Errorf("As[channel.Message] = %v, %v", cm, ok)
+	}
+
+	if off, ok := midi.As[channel.NoteOff](msg); ok || off != (channel.NoteOff{}) {
+		t.Errorf("As[channel.NoteOff] = %v, %v", off, ok)
+	}
+}
@@ -0,0 +1,66 @@
+package midi
+
+import (
+	"io"
+)
+
+// Merge reads the messages of all readers concurrently and writes them to w in the order of their arrival.
+// Since the readers return complete messages, a message is never interrupted by a message of another reader (e.g. SysEx).
+// Running status is handled by each reader for its own source and by w for the merged stream.
+// If w is a ProvenanceWriter, it receives the provenance of each message, where the source is the name of the reader,
+// if it is a NamedReader.
+//
+// Merge returns when all readers have returned an error. It returns the first error that is not io.EOF.
+// When writing fails, Merge returns the write error immediately while the pending reads of the readers
+// are finished in the background.
+// Realtime messages of live readers are passed to the realtime handler of the readers and not merged.
+func Merge(w Writer, readers ...Reader) error {
+	type result struct {
+		msg Message
+		p   Provenance
+		err error
+	}
+
+	results := make(chan result)
+	done := make(chan struct{})
+	defer close(done)
+
+	for _, rd := range readers {
+		go func(rd Reader) {
+			p := Provenance{Source: SourceName(rd)}
+			for {
+				msg, err := rd.Read()
+
+				select {
+				case results <- result{msg, p, err}:
+				case <-done:
+					return
+				}
+
+				if err != nil {
+					return
+				}
+			}
+		}(rd)
+	}
+
+	var firstErr error
+
+	for active := len(readers); active > 0; {
+		res := <-results
+
+		if res.err != nil {
+			active--
+			if res.err != io.EOF && firstErr == nil {
+				firstErr = res.err
+			}
+			continue
+		}
+
+		if err := WriteProvenance(w, res.msg, res.p); err != nil {
+			return err
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,77 @@
+package midi_test
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/sysex"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+func TestMerge(t *testing.T) {
+	var a, b bytes.Buffer
+
+	wa := midiwriter.New(&a)
+	wa.Write(channel.Channel1.NoteOn(60, 100))
+	wa.Write(channel.Channel1.NoteOn(64, 100))
+	wa.Write(sysex.SysEx([]byte{0x43, 0x10, 0x4C, 0x00}))
+	wa.Write(channel.Channel1.NoteOff(60))
+
+	wb := midiwriter.New(&b)
+	wb.Write(channel.Channel2.ProgramChange(3))
+	wb.Write(channel.Channel2.ProgramChange(4))
+	wb.Write(channel.Channel2.NoteOn(40, 20))
+
+	pr, pw := io.Pipe()
+
+	var err error
+	done := make(chan bool)
+
+	go func() {
+		err = midi.Merge(
+			midiwriter.New(pw),
+			midi.Name(midireader.New(&a, nil), "a"),
+			midi.Name(midireader.New(&b, nil), "b"),
+		)
+		pw.Close()
+		done <- true
+	}()
+
+	var lines []string
+	rd := midireader.New(pr, nil)
+
+	for {
+		msg, rerr := rd.Read()
+		if rerr != nil {
+			break
+		}
+		lines = append(lines, msg.String())
+	}
+
+	<-done
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the interleaving is not deterministic
+	sort.Strings(lines)
+
+	expected := `channel.NoteOff channel 1 key 60
+channel.NoteOn channel 1 key 60 velocity 100
+channel.NoteOn channel 1 key 64 velocity 100
+channel.NoteOn channel 2 key 40 velocity 20
+channel.ProgramChange channel 2 program 3
+channel.ProgramChange channel 2 program 4
+sysex.SysEx len: 4`
+
+	if got, want := strings.Join(lines, "\n"), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package midijson provides a canonical JSON representation of MIDI messages and SMF files,
+e.g. for web APIs and tools that describe MIDI content declaratively in configuration files.
+
+A message is represented by an object with the type of the message (the Go type, as in the String method)
+and its properties, e.g.
+
+	{"channel":2,"key":65,"type":"channel.NoteOn","velocity":90}
+	{"bpm":120,"type":"meta.Tempo"}
+	{"data":"41 10 42 12","type":"sysex.SysEx"}
+	{"type":"realtime.Start"}
+
+Channels are 0-based (0-15), like in the channel package. Sysex and other binary data is written as hexadecimal
+bytes, separated by spaces. Unknown types and properties are rejected when unmarshaling.
+Checked and spooled sysex (see midireader) are written as sysex.SysEx.
+
+The package uses reflection (encoding/json) and is therefore not part of the tiny profile.
+
+Usage
+
+	import (
+		"encoding/json"
+		"github.com/gomidi/midi/midijson"
+		. "github.com/gomidi/midi/midimessage/channel"
+	)
+
+	// a single message
+	b, err := midijson.Marshal(Channel2.NoteOn(65, 90))
+	msg, err := midijson.Unmarshal(b)
+
+	// messages inside own structures
+	var config struct {
+		Init []midijson.Message `json:"init"`
+	}
+
+	err = json.Unmarshal(data, &config)
+
+	for _, m := range config.Init {
+		wr.Write(m.Message)
+	}
+
+	// SMF files
+	var file midijson.SMF
+	err = json.Unmarshal(data, &file)
+	err = midijson.WriteSMF(output, &file)
+
+	f, err := midijson.ReadSMF(input)
+	b, err = json.MarshalIndent(f, "", "  ")
+
+*/
+package midijson
@@ -0,0 +1,486 @@
+package midijson
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+// Message wraps a midi.Message to marshal and unmarshal it as JSON, e.g. as part of own structures.
+type Message struct {
+	midi.Message
+}
+
+// MarshalJSON returns the JSON representation of the message. It implements json.Marshaler.
+func (m Message) MarshalJSON() ([]byte, error) {
+	return Marshal(m.Message)
+}
+
+// UnmarshalJSON sets the message to the message described by data. It implements json.Unmarshaler.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	msg, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	m.Message = msg
+	return nil
+}
+
+// Marshal returns the JSON representation of the given message.
+// The object keys are sorted, so that the same message always results in the same bytes.
+func Marshal(msg midi.Message) ([]byte, error) {
+	typ, props, err := encode(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+
+	props["type"] = typ
+	return json.Marshal(props)
+}
+
+// Unmarshal returns the message that is described by the given JSON object.
+func Unmarshal(data []byte) (midi.Message, error) {
+	var f fields
+
+	if err := json.Unmarshal(data, &f.props); err != nil {
+		return nil, err
+	}
+
+	var typ string
+	f.get("type", &typ)
+
+	dec, has := decoders[typ]
+	if !has {
+		return nil, fmt.Errorf("unknown message type %q", typ)
+	}
+
+	msg := dec(&f)
+
+	if f.err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", typ, f.err)
+	}
+
+	if unused := f.unused(); len(unused) > 0 {
+		return nil, fmt.Errorf("invalid %s: unknown properties %s", typ, strings.Join(unused, ", "))
+	}
+
+	return msg, nil
+}
+
+// props are the properties of a message, apart from the type
+type props map[string]interface{}
+
+// encode returns the type and the properties of the given message
+func encode(msg midi.Message) (typ string, p props, err error) {
+	switch msg {
+	case meta.EndOfTrack:
+		return "meta.EndOfTrack", nil, nil
+	case syscommon.Tune:
+		return "syscommon.Tune", nil, nil
+	}
+
+	switch m := msg.(type) {
+
+	// channel messages
+	case channel.NoteOn:
+		return "channel.NoteOn", props{"channel": m.Channel(), "key": m.Key(), "velocity": m.Velocity()}, nil
+	case channel.NoteOff:
+		return "channel.NoteOff", props{"channel": m.Channel(), "key": m.Key()}, nil
+	case channel.NoteOffVelocity:
+		return "channel.NoteOffVelocity", props{"channel": m.Channel(), "key": m.Key(), "velocity": m.Velocity()}, nil
+	case channel.PolyAftertouch:
+		return "channel.PolyAftertouch", props{"channel": m.Channel(), "key": m.Key(), "pressure": m.Pressure()}, nil
+	case channel.ControlChange:
+		return "channel.ControlChange", props{"channel": m.Channel(), "controller": m.Controller(), "value": m.Value()}, nil
+	case channel.ProgramChange:
+		return "channel.ProgramChange", props{"channel": m.Channel(), "program": m.Program()}, nil
+	case channel.Aftertouch:
+		return "channel.Aftertouch", props{"channel": m.Channel(), "pressure": m.Pressure()}, nil
+	case channel.Pitchbend:
+		return "channel.Pitchbend", props{"channel": m.Channel(), "value": m.Value()}, nil
+	case channel.ChannelMode:
+		return "channel.ChannelMode", props{"channel": m.Channel(), "mode": uint8(m.Mode()), "value": m.Value()}, nil
+	case channel.PatchChange:
+		p := props{"channel": m.Channel(), "program": m.Program()}
+		if msb, ok := m.BankMSB(); ok {
+			p["bankMSB"] = msb
+		}
+		if lsb, ok := m.BankLSB(); ok {
+			p["bankLSB"] = lsb
+		}
+		return "channel.PatchChange", p, nil
+
+	// system messages
+	case realtime.Message:
+		return "realtime." + m.String(), nil, nil
+	case syscommon.MTC:
+		return "syscommon.MTC", props{"quarterFrame": m.QuarterFrame()}, nil
+	case syscommon.SongSelect:
+		return "syscommon.SongSelect", props{"number": m.Number()}, nil
+	case syscommon.SPP:
+		return "syscommon.SPP", props{"number": m.Number()}, nil
+	case syscommon.Unknown:
+		return "syscommon.Unknown", props{"status": m.Status, "data": hexData(m.Data)}, nil
+	case sysex.SysEx:
+		return "sysex.SysEx", props{"data": hexData(m)}, nil
+	case sysex.Checked:
+		// the result of the validation is not kept
+		return "sysex.SysEx", props{"data": hexData(m.SysEx)}, nil
+	case sysex.Start:
+		return "sysex.Start", props{"data": hexData(m)}, nil
+	case sysex.Continue:
+		return "sysex.Continue", props{"data": hexData(m)}, nil
+	case sysex.End:
+		return "sysex.End", props{"data": hexData(m)}, nil
+	case sysex.Escape:
+		return "sysex.Escape", props{"data": hexData(m)}, nil
+
+	// meta messages
+	case meta.Text:
+		return "meta.Text", props{"text": m.Text()}, nil
+	case meta.Copyright:
+		return "meta.Copyright", props{"text": m.Text()}, nil
+	case meta.Sequence:
+		return "meta.Sequence", props{"text": m.Text()}, nil
+	case meta.Track:
+		return "meta.Track", props{"text": m.Text()}, nil
+	case meta.Lyric:
+		return "meta.Lyric", props{"text": m.Text()}, nil
+	case meta.Marker:
+		return "meta.Marker", props{"text": m.Text()}, nil
+	case meta.Cuepoint:
+		return "meta.Cuepoint", props{"text": m.Text()}, nil
+	case meta.Device:
+		return "meta.Device", props{"text": m.Text()}, nil
+	case meta.Program:
+		return "meta.Program", props{"text": m.Text()}, nil
+	case meta.Tempo:
+		return "meta.Tempo", props{"bpm": m.FractionalBPM()}, nil
+	case meta.TimeSig:
+		return "meta.TimeSig", props{
+			"numerator":                m.Numerator,
+			"denominator":              m.Denominator,
+			"clocksPerClick":           m.ClocksPerClick,
+			"demiSemiQuaverPerQuarter": m.DemiSemiQuaverPerQuarter,
+		}, nil
+	case meta.Key:
+		return "meta.Key", props{"key": m.Key, "isMajor": m.IsMajor, "num": m.Num, "isFlat": m.IsFlat}, nil
+	case meta.SMPTE:
+		return "meta.SMPTE", props{
+			"hour":            m.Hour,
+			"minute":          m.Minute,
+			"second":          m.Second,
+			"frame":           m.Frame,
+			"fractionalFrame": m.FractionalFrame,
+		}, nil
+	case meta.SequenceNo:
+		return "meta.SequenceNo", props{"number": m.Number()}, nil
+	case meta.Channel:
+		return "meta.Channel", props{"number": m.Number()}, nil
+	case meta.Port:
+		return "meta.Port", props{"number": m.Number()}, nil
+	case meta.SequencerData:
+		return "meta.SequencerData", props{"data": hexData(m)}, nil
+	case meta.Undefined:
+		return "meta.Undefined", props{"metaType": m.Typ, "data": hexData(m.Data)}, nil
+
+	default:
+		if data, is, err := spooledData(msg); is {
+			if err != nil {
+				return "", nil, err
+			}
+			return "sysex.SysEx", props{"data": hexData(data)}, nil
+		}
+		return "", nil, fmt.Errorf("can't marshal message of type %T", msg)
+	}
+}
+
+// decoders return the message of the type for the given fields
+var decoders = map[string]func(f *fields) midi.Message{
+	"channel.NoteOn": func(f *fields) midi.Message {
+		return f.channel().NoteOn(f.uint7("key"), f.uint7("velocity"))
+	},
+	"channel.NoteOff": func(f *fields) midi.Message {
+		return f.channel().NoteOff(f.uint7("key"))
+	},
+	"channel.NoteOffVelocity": func(f *fields) midi.Message {
+		return f.channel().NoteOffVelocity(f.uint7("key"), f.uint7("velocity"))
+	},
+	"channel.PolyAftertouch": func(f *fields) midi.Message {
+		return f.channel().PolyAftertouch(f.uint7("key"), f.uint7("pressure"))
+	},
+	"channel.ControlChange": func(f *fields) midi.Message {
+		return f.channel().ControlChange(f.uint7("controller"), f.uint7("value"))
+	},
+	"channel.ProgramChange": func(f *fields) midi.Message {
+		return f.channel().ProgramChange(f.uint7("program"))
+	},
+	"channel.Aftertouch": func(f *fields) midi.Message {
+		return f.channel().Aftertouch(f.uint7("pressure"))
+	},
+	"channel.Pitchbend": func(f *fields) midi.Message {
+		var value int16
+		f.get("value", &value)
+		if value < channel.PitchLowest || value > channel.PitchHighest {
+			f.fail("value %v out of range", value)
+		}
+		return f.channel().Pitchbend(value)
+	},
+	"channel.ChannelMode": func(f *fields) midi.Message {
+		ch, mode, value := f.channel(), f.uint7("mode"), f.uint7("value")
+		m, ok := ch.ControlChange(mode, value).ChannelMode()
+		if !ok {
+			f.fail("mode %v out of range", mode)
+		}
+		return m
+	},
+	"channel.PatchChange": func(f *fields) midi.Message {
+		c := f.channel()
+		var banks []channel.ControlChange
+		if _, has := f.props["bankMSB"]; has {
+			banks = append(banks, c.ControlChange(0, f.uint7("bankMSB")))
+		}
+		if _, has := f.props["bankLSB"]; has {
+			banks = append(banks, c.ControlChange(32, f.uint7("bankLSB")))
+		}
+		return c.ProgramChange(f.uint7("program")).WithBank(banks...)
+	},
+
+	"syscommon.MTC": func(f *fields) midi.Message {
+		return syscommon.MTC(f.uint7("quarterFrame"))
+	},
+	"syscommon.SongSelect": func(f *fields) midi.Message {
+		return syscommon.SongSelect(f.uint7("number"))
+	},
+	"syscommon.SPP": func(f *fields) midi.Message {
+		var number uint16
+		f.get("number", &number)
+		if number > 0x3FFF {
+			f.fail("number %v out of range", number)
+		}
+		return syscommon.SPP(number)
+	},
+	"syscommon.Tune": func(f *fields) midi.Message {
+		return syscommon.Tune
+	},
+	"syscommon.Unknown": func(f *fields) midi.Message {
+		var status uint8
+		f.get("status", &status)
+		return syscommon.Unknown{Status: status, Data: f.data("data")}
+	},
+
+	"sysex.SysEx": func(f *fields) midi.Message {
+		return sysex.SysEx(f.data("data"))
+	},
+	"sysex.Start": func(f *fields) midi.Message {
+		return sysex.Start(f.data("data"))
+	},
+	"sysex.Continue": func(f *fields) midi.Message {
+		return sysex.Continue(f.data("data"))
+	},
+	"sysex.End": func(f *fields) midi.Message {
+		return sysex.End(f.data("data"))
+	},
+	"sysex.Escape": func(f *fields) midi.Message {
+		return sysex.Escape(f.data("data"))
+	},
+
+	"meta.Text": func(f *fields) midi.Message {
+		return meta.Text(f.text())
+	},
+	"meta.Copyright": func(f *fields) midi.Message {
+		return meta.Copyright(f.text())
+	},
+	"meta.Sequence": func(f *fields) midi.Message {
+		return meta.Sequence(f.text())
+	},
+	"meta.Track": func(f *fields) midi.Message {
+		return meta.Track(f.text())
+	},
+	"meta.Lyric": func(f *fields) midi.Message {
+		return meta.Lyric(f.text())
+	},
+	"meta.Marker": func(f *fields) midi.Message {
+		return meta.Marker(f.text())
+	},
+	"meta.Cuepoint": func(f *fields) midi.Message {
+		return meta.Cuepoint(f.text())
+	},
+	"meta.Device": func(f *fields) midi.Message {
+		return meta.Device(f.text())
+	},
+	"meta.Program": func(f *fields) midi.Message {
+		return meta.Program(f.text())
+	},
+	"meta.Tempo": func(f *fields) midi.Message {
+		var bpm float64
+		f.get("bpm", &bpm)
+		if bpm <= 0 {
+			f.fail("bpm %v out of range", bpm)
+			return meta.Tempo(0)
+		}
+		return meta.FractionalBPM(bpm)
+	},
+	"meta.TimeSig": func(f *fields) midi.Message {
+		var m meta.TimeSig
+		f.get("numerator", &m.Numerator)
+		f.get("denominator", &m.Denominator)
+		f.get("clocksPerClick", &m.ClocksPerClick)
+		f.get("demiSemiQuaverPerQuarter", &m.DemiSemiQuaverPerQuarter)
+		return m
+	},
+	"meta.Key": func(f *fields) midi.Message {
+		var m meta.Key
+		f.get("key", &m.Key)
+		f.get("isMajor", &m.IsMajor)
+		f.get("num", &m.Num)
+		f.get("isFlat", &m.IsFlat)
+		return m
+	},
+	"meta.SMPTE": func(f *fields) midi.Message {
+		var m meta.SMPTE
+		f.get("hour", &m.Hour)
+		f.get("minute", &m.Minute)
+		f.get("second", &m.Second)
+		f.get("frame", &m.Frame)
+		f.get("fractionalFrame", &m.FractionalFrame)
+		return m
+	},
+	"meta.SequenceNo": func(f *fields) midi.Message {
+		var number uint16
+		f.get("number", &number)
+		return meta.SequenceNo(number)
+	},
+	"meta.Channel": func(f *fields) midi.Message {
+		var number uint8
+		f.get("number", &number)
+		return meta.Channel(number)
+	},
+	"meta.Port": func(f *fields) midi.Message {
+		var number uint8
+		f.get("number", &number)
+		return meta.Port(number)
+	},
+	"meta.SequencerData": func(f *fields) midi.Message {
+		return meta.SequencerData(f.data("data"))
+	},
+	"meta.Undefined": func(f *fields) midi.Message {
+		var typ uint8
+		f.get("metaType", &typ)
+		return meta.Undefined{Typ: typ, Data: f.data("data")}
+	},
+	"meta.EndOfTrack": func(f *fields) midi.Message {
+		return meta.EndOfTrack
+	},
+}
+
+func init() {
+	for b := 0xF8; b <= 0xFF; b++ {
+		msg, err := realtime.ParseMessage([]byte{byte(b)})
+		if err != nil {
+			continue
+		}
+		decoders["realtime."+msg.String()] = func(*fields) midi.Message {
+			return msg
+		}
+	}
+}
+
+// hexData returns the data as hexadecimal bytes, separated by spaces
+func hexData(data []byte) string {
+	return fmt.Sprintf("% X", data)
+}
+
+// fields are the properties of a JSON object that is decoded. The first error is kept.
+type fields struct {
+	props map[string]json.RawMessage
+	used  map[string]bool
+	err   error
+}
+
+// get decodes the property with the given name into v. A missing property leaves v untouched.
+func (f *fields) get(name string, v interface{}) {
+	if f.used == nil {
+		f.used = map[string]bool{}
+	}
+	f.used[name] = true
+
+	raw, has := f.props[name]
+	if !has || f.err != nil {
+		return
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		f.fail("property %s: %v", name, err)
+	}
+}
+
+// fail sets the error, if there is none
+func (f *fields) fail(format string, vals ...interface{}) {
+	if f.err == nil {
+		f.err = fmt.Errorf(format, vals...)
+	}
+}
+
+// unused returns the sorted names of the properties that were not decoded
+func (f *fields) unused() (names []string) {
+	for name := range f.props {
+		if !f.used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// channel returns the channel of the "channel" property
+func (f *fields) channel() channel.Channel {
+	var ch uint8
+	f.get("channel", &ch)
+	if ch > 15 {
+		f.fail("channel %v out of range", ch)
+	}
+	return channel.Channel(ch)
+}
+
+// uint7 returns the value of a property that must be a data byte (0-127)
+func (f *fields) uint7(name string) uint8 {
+	var v uint8
+	f.get(name, &v)
+	if v > 127 {
+		f.fail("%s %v out of range", name, v)
+	}
+	return v
+}
+
+// text returns the value of the "text" property
+func (f *fields) text() (s string) {
+	f.get("text", &s)
+	return
+}
+
+// data returns the bytes of the hexadecimal property with the given name
+func (f *fields) data(name string) []byte {
+	var s string
+	f.get(name, &s)
+
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		f.fail("property %s: %v", name, err)
+	}
+
+	return b
+}
@@ -0,0 +1,190 @@
+package midijson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
+	"github.com/gomidi/midi/smf"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		msg      midi.Message
+		expected string
+	}{
+		{channel.Channel2.NoteOn(65, 90), `{"channel":2,"key":65,"type":"channel.NoteOn","velocity":90}`},
+		{channel.Channel2.NoteOff(65), `{"channel":2,"key":65,"type":"channel.NoteOff"}`},
+		{channel.Channel2.NoteOffVelocity(65, 20), `{"channel":2,"key":65,"type":"channel.NoteOffVelocity","velocity":20}`},
+		{channel.Channel0.PolyAftertouch(60, 3), `{"channel":0,"key":60,"pressure":3,"type":"channel.PolyAftertouch"}`},
+		{channel.Channel15.ControlChange(7, 100), `{"channel":15,"controller":7,"type":"channel.ControlChange","value":100}`},
+		{channel.Channel1.ProgramChange(12), `{"channel":1,"program":12,"type":"channel.ProgramChange"}`},
+		{channel.Channel1.Aftertouch(30), `{"channel":1,"pressure":30,"type":"channel.Aftertouch"}`},
+		{channel.Channel1.Pitchbend(-200), `{"channel":1,"type":"channel.Pitchbend","value":-200}`},
+		{channel.Channel3.AllNotesOff(), `{"channel":3,"mode":123,"type":"channel.ChannelMode","value":0}`},
+		{channel.Channel3.MonoOn(4), `{"channel":3,"mode":126,"type":"channel.ChannelMode","value":4}`},
+		{channel.Channel3.PatchChange(1, 2, 5), `{"bankLSB":2,"bankMSB":1,"channel":3,"program":5,"type":"channel.PatchChange"}`},
+		{channel.Channel3.ProgramChange(5).WithBank(channel.Channel3.ControlChange(0, 1)), `{"bankMSB":1,"channel":3,"program":5,"type":"channel.PatchChange"}`},
+		{realtime.Start, `{"type":"realtime.Start"}`},
+		{realtime.TimingClock, `{"type":"realtime.TimingClock"}`},
+		{syscommon.MTC(3), `{"quarterFrame":3,"type":"syscommon.MTC"}`},
+		{syscommon.SongSelect(3), `{"number":3,"type":"syscommon.SongSelect"}`},
+		{syscommon.SPP(300), `{"number":300,"type":"syscommon.SPP"}`},
+		{syscommon.Tune, `{"type":"syscommon.Tune"}`},
+		{syscommon.Unknown{Status: 0xF4, Data: []byte{0x12}}, `{"data":"12","status":244,"type":"syscommon.Unknown"}`},
+		{sysex.SysEx{0x41, 0x10, 0x42}, `{"data":"41 10 42","type":"sysex.SysEx"}`},
+		{sysex.Start{0x41}, `{"data":"41","type":"sysex.Start"}`},
+		{sysex.Continue{0x10}, `{"data":"10","type":"sysex.Continue"}`},
+		{sysex.End{0x42}, `{"data":"42","type":"sysex.End"}`},
+		{sysex.Escape{0xF2, 0x01, 0x02}, `{"data":"F2 01 02","type":"sysex.Escape"}`},
+		{meta.Text("hello"), `{"text":"hello","type":"meta.Text"}`},
+		{meta.Copyright("me"), `{"text":"me","type":"meta.Copyright"}`},
+		{meta.Sequence("song"), `{"text":"song","type":"meta.Sequence"}`},
+		{meta.Track("piano"), `{"text":"piano","type":"meta.Track"}`},
+		{meta.Lyric("la"), `{"text":"la","type":"meta.Lyric"}`},
+		{meta.Marker("A"), `{"text":"A","type":"meta.Marker"}`},
+		{meta.Cuepoint("go"), `{"text":"go","type":"meta.Cuepoint"}`},
+		{meta.Device("synth"), `{"text":"synth","type":"meta.Device"}`},
+		{meta.Program("organ"), `{"text":"organ","type":"meta.Program"}`},
+		{meta.BPM(120), `{"bpm":120,"type":"meta.Tempo"}`},
+		{meta.TimeSig{Numerator: 3, Denominator: 4, ClocksPerClick: 24, DemiSemiQuaverPerQuarter: 8}, `{"clocksPerClick":24,"demiSemiQuaverPerQuarter":8,"denominator":4,"numerator":3,"type":"meta.TimeSig"}`},
+		{meta.Key{Key: 7, IsMajor: true, Num: 1}, `{"isFlat":false,"isMajor":true,"key":7,"num":1,"type":"meta.Key"}`},
+		{meta.SMPTE{Hour: 1, Minute: 2, Second: 3, Frame: 4, FractionalFrame: 5}, `{"fractionalFrame":5,"frame":4,"hour":1,"minute":2,"second":3,"type":"meta.SMPTE"}`},
+		{meta.SequenceNo(2), `{"number":2,"type":"meta.SequenceNo"}`},
+		{meta.Channel(3), `{"number":3,"type":"meta.Channel"}`},
+		{meta.Port(4), `{"number":4,"type":"meta.Port"}`},
+		{meta.SequencerData{0x01, 0x02}, `{"data":"01 02","type":"meta.SequencerData"}`},
+		{meta.Undefined{Typ: 0x60, Data: []byte{0x01}}, `{"data":"01","metaType":96,"type":"meta.Undefined"}`},
+		{meta.EndOfTrack, `{"type":"meta.EndOfTrack"}`},
+	}
+
+	for i, test := range tests {
+		b, err := Marshal(test.msg)
+		if err != nil {
+			t.Errorf("[%v] Error: %v", i, err)
+			continue
+		}
+
+		if got, want := string(b), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+
+		msg, err := Unmarshal(b)
+		if err != nil {
+			t.Errorf("[%v] Error: %v", i, err)
+			continue
+		}
+
+		if got, want := fmt.Sprintf("%s % X", msg, msg.Raw()), fmt.Sprintf("%s % X", test.msg, test.msg.Raw()); got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"type":"channel.NoteOn","channel":16,"key":60}`, "invalid channel.NoteOn: channel 16 out of range"},
+		{`{"type":"channel.NoteOn","channel":1,"key":128}`, "invalid channel.NoteOn: key 128 out of range"},
+		{`{"type":"channel.NoteOn","channel":1,"kye":60}`, "invalid channel.NoteOn: unknown properties kye"},
+		{`{"type":"channel.NoteOn","channel":"1"}`, "invalid channel.NoteOn: property channel: json: cannot unmarshal string into Go value of type uint8"},
+		{`{"type":"sysex.SysEx","data":"4"}`, "invalid sysex.SysEx: property data: encoding/hex: odd length hex string"},
+		{`{"type":"meta.Tempo","bpm":0}`, "invalid meta.Tempo: bpm 0 out of range"},
+		{`{"type":"channel.ChannelMode","channel":1,"mode":7,"value":0}`, "invalid channel.ChannelMode: mode 7 out of range"},
+		{`{"type":"NoteOn"}`, `unknown message type "NoteOn"`},
+	}
+
+	for i, test := range tests {
+		_, err := Unmarshal([]byte(test.input))
+
+		if err == nil {
+			t.Errorf("[%v] expected error", i)
+			continue
+		}
+
+		if got, want := err.Error(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestSMF(t *testing.T) {
+	input := `{
+  "format": 1,
+  "ticksPerQuarter": 96,
+  "tracks": [
+    [
+      {"delta": 0, "message": {"type": "meta.Tempo", "bpm": 120}}
+    ],
+    [
+      {"delta": 0, "message": {"type": "channel.NoteOn", "channel": 0, "key": 60, "velocity": 100}},
+      {"delta": 96, "message": {"type": "channel.NoteOff", "channel": 0, "key": 60}},
+      {"delta": 96, "message": {"type": "meta.EndOfTrack"}}
+    ]
+  ]
+}`
+
+	var s SMF
+
+	if err := json.Unmarshal([]byte(input), &s); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	var bf bytes.Buffer
+
+	if err := WriteSMF(&bf, &s); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	read, err := ReadSMF(&bf)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	b, err := json.Marshal(read)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := `{"format":1,"ticksPerQuarter":96,"tracks":[` +
+		`[{"delta":0,"message":{"bpm":120,"type":"meta.Tempo"}},{"delta":0,"message":{"type":"meta.EndOfTrack"}}],` +
+		`[{"delta":0,"message":{"channel":0,"key":60,"type":"channel.NoteOn","velocity":100}},` +
+		`{"delta":96,"message":{"channel":0,"key":60,"type":"channel.NoteOff"}},` +
+		`{"delta":96,"message":{"type":"meta.EndOfTrack"}}]]}`
+
+	if got, want := string(b), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if got, want := read.timeFormat(), smf.MetricTicks(96); got != want {
+		t.Errorf("got: %v wanted: %v", got, want)
+	}
+}
+
+func TestMarshalSysExVariants(t *testing.T) {
+	tests := []midi.Message{
+		sysex.Checked{SysEx: sysex.SysEx{0x41, 0x10, 0x42}},
+		sysex.Checked{SysEx: sysex.SysEx{0x41, 0x10, 0x42}, Err: fmt.Errorf("corrupt")},
+	}
+
+	for i, msg := range tests {
+		b, err := Marshal(msg)
+		if err != nil {
+			t.Errorf("[%v] Error: %v", i, err)
+			continue
+		}
+
+		if got, want := string(b), `{"data":"41 10 42","type":"sysex.SysEx"}`; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package midijson
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+// SMF is the JSON representation of a SMF file
+type SMF struct {
+	// Format is the SMF format (0, 1 or 2)
+	Format uint16 `json:"format"`
+
+	// TicksPerQuarter is the metric time format (ticks per quarter note). It is 0 if TimeCode is set.
+	TicksPerQuarter uint16 `json:"ticksPerQuarter,omitempty"`
+
+	// TimeCode is the SMPTE time format, if the file does not use metric ticks.
+	TimeCode *TimeCode `json:"timeCode,omitempty"`
+
+	// Tracks are the tracks of the file
+	Tracks []Track `json:"tracks"`
+}
+
+// TimeCode is the JSON representation of the smf.TimeCode time format
+type TimeCode struct {
+	FramesPerSecond uint8 `json:"framesPerSecond"`
+	SubFrames       uint8 `json:"subFrames"`
+}
+
+// Track is a track of a SMF file
+type Track []Event
+
+// Event is a message within a track
+type Event struct {
+	// Delta is the time distance to the previous event in ticks
+	Delta uint32 `json:"delta"`
+
+	// Message is the message
+	Message Message `json:"message"`
+}
+
+// timeFormat returns the time format of the file
+func (s *SMF) timeFormat() smf.TimeFormat {
+	if s.TimeCode != nil {
+		return smf.TimeCode{FramesPerSecond: s.TimeCode.FramesPerSecond, SubFrames: s.TimeCode.SubFrames}
+	}
+	return smf.MetricTicks(s.TicksPerQuarter)
+}
+
+// ReadSMF reads a SMF file from src.
+// The end of track messages are kept, since their delta determines the length of the tracks.
+func ReadSMF(src io.Reader, options ...smfreader.Option) (*SMF, error) {
+	rd := smfreader.New(src, options...)
+
+	if err := rd.ReadHeader(); err != nil {
+		return nil, err
+	}
+
+	h := rd.Header()
+	s := &SMF{Format: h.Format.Type(), Tracks: []Track{}}
+
+	switch tf := h.TimeFormat.(type) {
+	case smf.MetricTicks:
+		s.TicksPerQuarter = tf.Number()
+	case smf.TimeCode:
+		s.TimeCode = &TimeCode{FramesPerSecond: tf.FramesPerSecond, SubFrames: tf.SubFrames}
+	}
+
+	for {
+		msg, err := rd.Read()
+
+		if err == smf.ErrFinished {
+			return s, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for int(rd.Track()) >= len(s.Tracks) {
+			s.Tracks = append(s.Tracks, Track{})
+		}
+
+		tr := &s.Tracks[rd.Track()]
+		*tr = append(*tr, Event{Delta: rd.Delta(), Message: Message{msg}})
+	}
+}
+
+// WriteSMF writes the given SMF file to dest. The given options are passed to the writer after the options
+// for the format, the time format and the number of tracks of the file.
+// A missing end of track message is added at the end of each track.
+func WriteSMF(dest io.Writer, s *SMF, options ...smfwriter.Option) error {
+	if s.Format > 2 {
+		return fmt.Errorf("invalid SMF format %v", s.Format)
+	}
+
+	tracks := s.Tracks
+	if len(tracks) == 0 {
+		tracks = []Track{{}}
+	}
+
+	formats := []smf.Format{smf.SMF0, smf.SMF1, smf.SMF2}
+
+	opts := []smfwriter.Option{
+		smfwriter.Format(formats[s.Format]),
+		smfwriter.TimeFormat(s.timeFormat()),
+		smfwriter.NumTracks(uint16(len(tracks))),
+	}
+
+	wr := smfwriter.New(dest, append(opts, options...)...)
+
+	for i, tr := range tracks {
+		for j, ev := range tr {
+			switch {
+			case ev.Message.Message == nil:
+				return fmt.Errorf("track %v event %v: missing message", i, j)
+			case ev.Message.Message == meta.EndOfTrack && j < len(tr)-1:
+				return fmt.Errorf("track %v event %v: end of track before the last event", i, j)
+			}
+
+			wr.SetDelta(ev.Delta)
+
+			// the writer returns smf.ErrFinished after the last track
+			if err := wr.Write(ev.Message.Message); err != nil && err != smf.ErrFinished {
+				return err
+			}
+		}
+
+		if len(tr) == 0 || tr[len(tr)-1].Message.Message != meta.EndOfTrack {
+			if err := wr.Write(meta.EndOfTrack); err != nil && err != smf.ErrFinished {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
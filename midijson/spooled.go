@@ -0,0 +1,21 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midijson
+
+import (
+	"io/ioutil"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+// spooledData returns the data of a sysex.Spooled from its file
+func spooledData(msg midi.Message) (data []byte, is bool, err error) {
+	m, is := msg.(sysex.Spooled)
+	if !is {
+		return nil, false, nil
+	}
+	data, err = ioutil.ReadFile(m.Path)
+	return data, true, err
+}
@@ -0,0 +1,31 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midijson
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+func TestMarshalSpooled(t *testing.T) {
+	var spooled sysex.Spooled
+	if err := spooled.UnmarshalBinary([]byte{0xF0, 0x41, 0x10, 0x42, 0xF7}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	defer spooled.Remove()
+
+	b, err := Marshal(spooled)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if got, want := string(b), `{"data":"41 10 42","type":"sysex.SysEx"}`; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if _, err := Marshal(sysex.Spooled{Path: spooled.Path + ".missing"}); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,13 @@
+//go:build tinygo || miditiny
+// +build tinygo miditiny
+
+package midijson
+
+import (
+	"github.com/gomidi/midi"
+)
+
+// spooledData reports no message as spooled, since the tiny profile has no sysex.Spooled
+func spooledData(msg midi.Message) (data []byte, is bool, err error) {
+	return nil, false, nil
+}
@@ -1,7 +1,7 @@
 package channel
 
 import (
-	"fmt"
+	"strconv"
 
 	"github.com/gomidi/midi/internal/midilib"
 )
@@ -29,7 +29,7 @@ func (a Aftertouch) Raw() []byte {
 
 // String returns human readable information about the aftertouch message.
 func (a Aftertouch) String() string {
-	return fmt.Sprintf("%T channel %v pressure %v", a, a.Channel(), a.Pressure())
+	return "channel.Aftertouch channel " + strconv.Itoa(int(a.Channel())) + " pressure " + strconv.Itoa(int(a.Pressure()))
 }
 
 // set returns a new aftertouch message that is set to the parsed arguments
@@ -0,0 +1,222 @@
+package channel
+
+import (
+	"bytes"
+
+	"github.com/gomidi/midi/internal/midilib"
+)
+
+// ParseMessage parses the raw bytes of a single channel message, as returned by Raw.
+// Like the readers, it returns a NoteOff for a noteoff message (type 8) and for a noteon message with velocity of 0,
+// unless the ReadNoteOffVelocity option is passed.
+// The error has the cause midi.ErrInvalidMessage, if data is no complete channel message (running status is not supported).
+func ParseMessage(data []byte, options ...ReaderOption) (Message, error) {
+	if len(data) < 2 || data[0] < 0x80 || data[0] > 0xEF {
+		return nil, midilib.InvalidMessage(data, "channel message")
+	}
+
+	rd := bytes.NewReader(data[2:])
+	msg, err := NewReader(rd, options...).Read(data[0], data[1])
+
+	if err != nil || rd.Len() > 0 || data[1] > 0x7F || (len(data) > 2 && data[2] > 0x7F) {
+		return nil, midilib.InvalidMessage(data, "channel message")
+	}
+
+	return msg, nil
+}
+
+// unmarshal parses data and passes the message to set that returns false, if it has the wrong type
+func unmarshal(data []byte, kind string, set func(Message) bool, options ...ReaderOption) error {
+	msg, err := ParseMessage(data, options...)
+	if err != nil {
+		return err
+	}
+
+	if !set(msg) {
+		return midilib.InvalidMessage(data, kind)
+	}
+
+	return nil
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (n NoteOn) MarshalBinary() ([]byte, error) {
+	return n.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the note-on message in data. It implements encoding.BinaryUnmarshaler.
+func (n *NoteOn) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "channel.NoteOn", func(msg Message) bool {
+		v, ok := msg.(NoteOn)
+		if ok {
+			*n = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (n NoteOff) MarshalBinary() ([]byte, error) {
+	return n.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the note-off message in data (a noteon message with velocity of 0 or a noteoff message).
+// It implements encoding.BinaryUnmarshaler.
+func (n *NoteOff) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "channel.NoteOff", func(msg Message) bool {
+		v, ok := msg.(NoteOff)
+		if ok {
+			*n = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (n NoteOffVelocity) MarshalBinary() ([]byte, error) {
+	return n.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the noteoff message (type 8) in data. It implements encoding.BinaryUnmarshaler.
+func (n *NoteOffVelocity) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "channel.NoteOffVelocity", func(msg Message) bool {
+		v, ok := msg.(NoteOffVelocity)
+		if ok {
+			*n = v
+		}
+		return ok
+	}, ReadNoteOffVelocity())
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (p PolyAftertouch) MarshalBinary() ([]byte, error) {
+	return p.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the polyphonic aftertouch message in data. It implements encoding.BinaryUnmarshaler.
+func (p *PolyAftertouch) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "channel.PolyAftertouch", func(msg Message) bool {
+		v, ok := msg.(PolyAftertouch)
+		if ok {
+			*p = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (c ControlChange) MarshalBinary() ([]byte, error) {
+	return c.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the control change message in data. It implements encoding.BinaryUnmarshaler.
+func (c *ControlChange) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "channel.ControlChange", func(msg Message) bool {
+		v, ok := msg.(ControlChange)
+		if ok {
+			*c = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m ChannelMode) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the channel mode message (controllers 120-127) in data. It implements encoding.BinaryUnmarshaler.
+func (m *ChannelMode) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "channel.ChannelMode", func(msg Message) bool {
+		v, ok := msg.(ChannelMode)
+		if ok {
+			*m = v
+		}
+		return ok
+	}, ReadChannelModes())
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (p ProgramChange) MarshalBinary() ([]byte, error) {
+	return p.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the program change message in data. It implements encoding.BinaryUnmarshaler.
+func (p *ProgramChange) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "channel.ProgramChange", func(msg Message) bool {
+		v, ok := msg.(ProgramChange)
+		if ok {
+			*p = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (a Aftertouch) MarshalBinary() ([]byte, error) {
+	return a.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the aftertouch message in data. It implements encoding.BinaryUnmarshaler.
+func (a *Aftertouch) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "channel.Aftertouch", func(msg Message) bool {
+		v, ok := msg.(Aftertouch)
+		if ok {
+			*a = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (p Pitchbend) MarshalBinary() ([]byte, error) {
+	return p.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the pitchbend message in data. It implements encoding.BinaryUnmarshaler.
+func (p *Pitchbend) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "channel.Pitchbend", func(msg Message) bool {
+		v, ok := msg.(Pitchbend)
+		if ok {
+			*p = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the messages of the patch change. It implements encoding.BinaryMarshaler.
+func (p PatchChange) MarshalBinary() ([]byte, error) {
+	return p.Raw(), nil
+}
+
+// UnmarshalBinary sets the patch change to the bank select controllers (0 and 32) followed by the program change in data,
+// all on the same channel and without running status. It implements encoding.BinaryUnmarshaler.
+func (p *PatchChange) UnmarshalBinary(data []byte) error {
+	var (
+		banks []ControlChange
+		rest  = data
+	)
+
+	for len(rest) >= 3 && rest[0]&0xF0 == 0xB0 && len(banks) < 2 {
+		msg, err := ParseMessage(rest[:3])
+		if err != nil {
+			return midilib.InvalidMessage(data, "channel.PatchChange")
+		}
+
+		cc := msg.(ControlChange)
+		if (cc.Controller() != 0 && cc.Controller() != 32) || (len(banks) > 0 && cc.Channel() != banks[0].Channel()) {
+			return midilib.InvalidMessage(data, "channel.PatchChange")
+		}
+
+		banks = append(banks, cc)
+		rest = rest[3:]
+	}
+
+	var prog ProgramChange
+	if err := prog.UnmarshalBinary(rest); err != nil || (len(banks) > 0 && prog.Channel() != banks[0].Channel()) {
+		return midilib.InvalidMessage(data, "channel.PatchChange")
+	}
+
+	*p = prog.WithBank(banks...)
+	return nil
+}
@@ -0,0 +1,32 @@
+package channel
+
+// Ch returns the MIDI channel with the given number (0-15) as starting point for building messages, e.g.
+//
+//	Ch(3).NoteOn(60, 100)
+//	Ch(3).CC(74, 32)
+//	Ch(3).PitchBend(2048)
+//
+// It panics for numbers above 15, like SetChannel. Use the validating constructors (e.g. NewNoteOn)
+// for channels that are not known at compile time.
+func Ch(number uint8) Channel {
+	if number > 15 {
+		panic("invalid channel number")
+	}
+	return Channel(number)
+}
+
+// CC creates a control change message on the channel (short for ControlChange)
+func (c Channel) CC(controller, value uint8) ControlChange {
+	return c.ControlChange(controller, value)
+}
+
+// PitchBend creates a pitch bend message on the channel (an alias for Pitchbend).
+// The value is relative to the center (0) and clipped to PitchLowest and PitchHighest.
+func (c Channel) PitchBend(value int16) Pitchbend {
+	return c.Pitchbend(value)
+}
+
+// Program creates a program change message on the channel (short for ProgramChange)
+func (c Channel) Program(program uint8) ProgramChange {
+	return c.ProgramChange(program)
+}
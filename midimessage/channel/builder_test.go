@@ -0,0 +1,33 @@
+package channel_test
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		got, expected midi.Message
+	}{
+		{channel.Ch(3).NoteOn(60, 100), channel.Channel3.NoteOn(60, 100)},
+		{channel.Ch(3).CC(74, 32), channel.Channel3.ControlChange(74, 32)},
+		{channel.Ch(15).PitchBend(2048), channel.Channel15.Pitchbend(2048)},
+		{channel.Ch(0).Program(5), channel.Channel0.ProgramChange(5)},
+	}
+
+	for i, test := range tests {
+		if test.got != test.expected {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, test.got, test.expected)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Ch(16) should panic")
+		}
+	}()
+
+	channel.Ch(16)
+}
@@ -0,0 +1,7 @@
+//go:build tinygo || miditiny
+// +build tinygo miditiny
+
+package channel
+
+// the names of the controllers are not part of the tiny profile
+var ccControllers map[uint8]string
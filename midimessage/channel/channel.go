@@ -51,6 +51,7 @@ const (
 	Channel15 = Channel(15)
 )
 
+// SetChannel returns a copy of msg on the channel ch. Messages of unknown types are returned unchanged.
 func SetChannel(msg Message, ch uint8) Message {
 	if ch > 15 {
 		panic("invalid channel number")
@@ -75,9 +76,15 @@ func SetChannel(msg Message, ch uint8) Message {
 		return c.PolyAftertouch(v.Key(), v.Pressure())
 	case ProgramChange:
 		return c.ProgramChange(v.Program())
+	case ChannelMode:
+		v.channel = c.Channel()
+		return v
+	case PatchChange:
+		v.channel = c.Channel()
+		return v
 	}
 
-	panic("unreachable")
+	return msg
 }
 
 // Channel represents a MIDI channel
@@ -86,7 +86,7 @@ func TestMessagesString(t *testing.T) {
 
 		bf.WriteString(test.input.String())
 
-		if got, want := bf.String(), test.expected; got != want {
+		if got, want := bf.String(), named(test.expected); got != want {
 			t.Errorf("got: %#v; wanted %#v", got, want)
 		}
 	}
@@ -146,6 +146,8 @@ func TestMessagesRaw(t *testing.T) {
 
 }
 
+type wrapped struct{ NoteOn }
+
 func TestSetChannel(t *testing.T) {
 
 	tests := []struct {
@@ -193,6 +195,12 @@ func TestSetChannel(t *testing.T) {
 			0,
 			"channel.ProgramChange channel 0 program 83",
 		},
+		{
+			// unknown types are passed through
+			wrapped{Channel4.NoteOn(60, 100)},
+			2,
+			"channel.NoteOn channel 4 key 60 velocity 100",
+		},
 	}
 
 	for _, test := range tests {
@@ -203,7 +211,7 @@ func TestSetChannel(t *testing.T) {
 
 		bf.WriteString(msg.String())
 
-		if got, want := bf.String(), test.expected; got != want {
+		if got, want := bf.String(), named(test.expected); got != want {
 			t.Errorf("got: %#v; wanted %#v", got, want)
 		}
 	}
@@ -0,0 +1,170 @@
+package channel
+
+import (
+	"strconv"
+)
+
+// Mode is the kind of a channel mode message. Its value is the controller number that is used for the mode.
+type Mode uint8
+
+const (
+	// ModeAllSoundOff mutes all sounding notes immediately, without release
+	ModeAllSoundOff Mode = 120
+
+	// ModeResetAllControllers resets the controllers to their default values
+	ModeResetAllControllers Mode = 121
+
+	// ModeLocalControl connects (value 127) or disconnects (value 0) the keyboard of the device from its sound generator
+	ModeLocalControl Mode = 122
+
+	// ModeAllNotesOff releases all notes (while the sustain pedal keeps them sounding)
+	ModeAllNotesOff Mode = 123
+
+	// ModeOmniOff lets the device respond to its basic channel only (and turns all notes off)
+	ModeOmniOff Mode = 124
+
+	// ModeOmniOn lets the device respond to all channels (and turns all notes off)
+	ModeOmniOn Mode = 125
+
+	// ModeMonoOn switches the device to monophonic operation; the value is the number of channels (0 = as many as voices)
+	ModeMonoOn Mode = 126
+
+	// ModePolyOn switches the device to polyphonic operation (and turns all notes off)
+	ModePolyOn Mode = 127
+)
+
+var modeNames = [...]string{
+	"All Sound Off",
+	"Reset All Controllers",
+	"Local Control",
+	"All Notes Off",
+	"Omni Off",
+	"Omni On",
+	"Mono On",
+	"Poly On",
+}
+
+// String returns the name of the mode
+func (m Mode) String() string {
+	if m < ModeAllSoundOff || m > ModePolyOn {
+		return "Mode " + strconv.Itoa(int(m))
+	}
+	return modeNames[m-ModeAllSoundOff]
+}
+
+// ChannelMode represents a channel mode message, i.e. a control change message for one of the controllers 120-127,
+// which don't control a parameter of the sound but the mode of operation of the receiving device.
+// The reader of the channel package returns them as ChannelMode messages (instead of ControlChange)
+// if the ReadChannelModes option is set.
+type ChannelMode struct {
+	channel uint8
+	mode    Mode
+	value   uint8
+}
+
+// AllSoundOff creates an All Sound Off (controller 120) message on the channel
+func (c Channel) AllSoundOff() ChannelMode {
+	return ChannelMode{channel: c.Channel(), mode: ModeAllSoundOff}
+}
+
+// ResetAllControllers creates a Reset All Controllers (controller 121) message on the channel
+func (c Channel) ResetAllControllers() ChannelMode {
+	return ChannelMode{channel: c.Channel(), mode: ModeResetAllControllers}
+}
+
+// LocalControl creates a Local Control (controller 122) message on the channel
+func (c Channel) LocalControl(on bool) ChannelMode {
+	m := ChannelMode{channel: c.Channel(), mode: ModeLocalControl}
+	if on {
+		m.value = 127
+	}
+	return m
+}
+
+// AllNotesOff creates an All Notes Off (controller 123) message on the channel
+func (c Channel) AllNotesOff() ChannelMode {
+	return ChannelMode{channel: c.Channel(), mode: ModeAllNotesOff}
+}
+
+// OmniOff creates an Omni Mode Off (controller 124) message on the channel
+func (c Channel) OmniOff() ChannelMode {
+	return ChannelMode{channel: c.Channel(), mode: ModeOmniOff}
+}
+
+// OmniOn creates an Omni Mode On (controller 125) message on the channel
+func (c Channel) OmniOn() ChannelMode {
+	return ChannelMode{channel: c.Channel(), mode: ModeOmniOn}
+}
+
+// MonoOn creates a Mono Mode On (controller 126) message on the channel for the given number of channels
+// (0 for as many channels as the device has voices)
+func (c Channel) MonoOn(channels uint8) ChannelMode {
+	return ChannelMode{channel: c.Channel(), mode: ModeMonoOn, value: channels & 0x7F}
+}
+
+// PolyOn creates a Poly Mode On (controller 127) message on the channel
+func (c Channel) PolyOn() ChannelMode {
+	return ChannelMode{channel: c.Channel(), mode: ModePolyOn}
+}
+
+// Channel returns the MIDI channel of the channel mode message
+func (m ChannelMode) Channel() uint8 {
+	return m.channel
+}
+
+// Mode returns the mode of the channel mode message
+func (m ChannelMode) Mode() Mode {
+	return m.mode
+}
+
+// Value returns the value of the channel mode message. It is only relevant for ModeLocalControl
+// (127 for on, 0 for off) and ModeMonoOn (the number of channels).
+func (m ChannelMode) Value() uint8 {
+	return m.value
+}
+
+// On returns true, if the message is a Local Control message that connects the keyboard
+func (m ChannelMode) On() bool {
+	return m.mode == ModeLocalControl && m.value >= 64
+}
+
+// ControlChange returns the channel mode message as control change message
+func (m ChannelMode) ControlChange() ControlChange {
+	return ControlChange{channel: m.channel, controller: uint8(m.mode), value: m.value}
+}
+
+// Raw returns the raw bytes of the channel mode message.
+func (m ChannelMode) Raw() []byte {
+	return channelMessage2(m.channel, 11, uint8(m.mode), m.value)
+}
+
+// String returns human readable information about the channel mode message.
+func (m ChannelMode) String() string {
+	s := "channel.ChannelMode channel " + strconv.Itoa(int(m.channel)) + " " + m.mode.String()
+
+	switch m.mode {
+	case ModeLocalControl:
+		if m.On() {
+			return s + " on"
+		}
+		return s + " off"
+	case ModeMonoOn:
+		return s + " channels " + strconv.Itoa(int(m.value))
+	}
+
+	return s
+}
+
+// set returns a new channel mode message that is set to the parsed arguments
+func (ChannelMode) set(channel uint8, firstArg, secondArg uint8) setter2 {
+	cc := ControlChange{}.set(channel, firstArg, secondArg).(ControlChange)
+	return ChannelMode{channel: channel, mode: Mode(cc.controller), value: cc.value}
+}
+
+// ChannelMode returns the control change message as channel mode message, if its controller is one of the controllers 120-127
+func (c ControlChange) ChannelMode() (m ChannelMode, ok bool) {
+	if c.controller < uint8(ModeAllSoundOff) {
+		return m, false
+	}
+	return ChannelMode{channel: c.channel, mode: Mode(c.controller), value: c.value}, true
+}
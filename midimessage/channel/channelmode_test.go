@@ -0,0 +1,90 @@
+package channel_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestChannelMode(t *testing.T) {
+	tests := []struct {
+		msg      channel.ChannelMode
+		raw      string
+		expected string
+	}{
+		{channel.Channel0.AllSoundOff(), "B0 78 00", "channel.ChannelMode channel 0 All Sound Off"},
+		{channel.Channel1.ResetAllControllers(), "B1 79 00", "channel.ChannelMode channel 1 Reset All Controllers"},
+		{channel.Channel2.LocalControl(true), "B2 7A 7F", "channel.ChannelMode channel 2 Local Control on"},
+		{channel.Channel2.LocalControl(false), "B2 7A 00", "channel.ChannelMode channel 2 Local Control off"},
+		{channel.Channel3.AllNotesOff(), "B3 7B 00", "channel.ChannelMode channel 3 All Notes Off"},
+		{channel.Channel4.OmniOff(), "B4 7C 00", "channel.ChannelMode channel 4 Omni Off"},
+		{channel.Channel5.OmniOn(), "B5 7D 00", "channel.ChannelMode channel 5 Omni On"},
+		{channel.Channel6.MonoOn(4), "B6 7E 04", "channel.ChannelMode channel 6 Mono On channels 4"},
+		{channel.Channel7.PolyOn(), "B7 7F 00", "channel.ChannelMode channel 7 Poly On"},
+	}
+
+	for i, test := range tests {
+		if got := fmt.Sprintf("% X", test.msg.Raw()); got != test.raw {
+			t.Errorf("[%v] raw got: %s wanted: %s", i, got, test.raw)
+		}
+
+		if got := test.msg.String(); got != test.expected {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, test.expected)
+		}
+
+		// by default, channel mode messages are read as control changes
+		msg, err := channel.ParseMessage(test.msg.Raw())
+		if err != nil {
+			t.Fatalf("[%v] error: %v", i, err)
+		}
+
+		cc, is := msg.(channel.ControlChange)
+		if !is {
+			t.Fatalf("[%v] got %T, wanted channel.ControlChange", i, msg)
+		}
+
+		if m, ok := cc.ChannelMode(); !ok || m != test.msg {
+			t.Errorf("[%v] ChannelMode() = %v, %v; wanted %v", i, m, ok, test.msg)
+		}
+
+		msg, err = channel.ParseMessage(test.msg.Raw(), channel.ReadChannelModes())
+		if err != nil || msg != test.msg {
+			t.Errorf("[%v] with ReadChannelModes got: %v, %v wanted: %v", i, msg, err, test.msg)
+		}
+
+		var m channel.ChannelMode
+		if err := m.UnmarshalBinary(test.msg.Raw()); err != nil || m != test.msg {
+			t.Errorf("[%v] UnmarshalBinary got: %v, %v wanted: %v", i, m, err, test.msg)
+		}
+	}
+
+	// ordinary controllers are not affected
+	msg, _ := channel.ParseMessage(channel.Channel0.ControlChange(7, 100).Raw(), channel.ReadChannelModes())
+	if _, is := msg.(channel.ControlChange); !is {
+		t.Errorf("got %T, wanted channel.ControlChange", msg)
+	}
+
+	if _, ok := channel.Channel0.ControlChange(7, 100).ChannelMode(); ok {
+		t.Errorf("controller 7 should be no channel mode")
+	}
+}
+
+func TestModeString(t *testing.T) {
+	tests := []struct {
+		mode     channel.Mode
+		expected string
+	}{
+		{channel.ModeAllSoundOff, "All Sound Off"},
+		{channel.ModePolyOn, "Poly On"},
+		{channel.Mode(7), "Mode 7"},
+		{channel.Mode(128), "Mode 128"},
+		{channel.Mode(255), "Mode 255"},
+	}
+
+	for i, test := range tests {
+		if got, want := test.mode.String(), test.expected; got != want {
+			t.Errorf("[%v] got: %q wanted: %q", i, got, want)
+		}
+	}
+}
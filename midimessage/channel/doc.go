@@ -2,5 +2,21 @@
 // Use of this source code is governed by a MIT
 // license that can be found in the LICENSE file.
 
-// Package channel provides MIDI Channel Messages
+/*
+Package channel provides MIDI Channel Messages
+
+The messages are created by the methods of a Channel, which is either one of the constants Channel0 to Channel15
+or returned by Ch for a channel number.
+
+Usage
+
+	wr.Write(channel.Ch(3).NoteOn(60, 100))
+	wr.Write(channel.Ch(3).CC(74, 32))
+	wr.Write(channel.Ch(3).PitchBend(2048))
+	wr.Write(channel.Ch(3).NoteOff(60))
+
+	// the same with the channel constant
+	wr.Write(channel.Channel3.NoteOn(60, 100))
+
+*/
 package channel
@@ -1,12 +1,25 @@
 package channel
 
 // Message represents a channel message
+// It can only be implemented by the message types of this package.
 type Message interface {
 	String() string
 	Raw() []byte
 	Channel() uint8
+	channelMessage()
 }
 
+func (NoteOff) channelMessage()         {}
+func (NoteOffVelocity) channelMessage() {}
+func (NoteOn) channelMessage()          {}
+func (PolyAftertouch) channelMessage()  {}
+func (ControlChange) channelMessage()   {}
+func (ProgramChange) channelMessage()   {}
+func (Aftertouch) channelMessage()      {}
+func (Pitchbend) channelMessage()       {}
+func (ChannelMode) channelMessage()     {}
+func (PatchChange) channelMessage()     {}
+
 var (
 	_ Message = NoteOff{}
 	_ Message = NoteOffVelocity{}
@@ -16,6 +29,8 @@ var (
 	_ Message = ProgramChange{}
 	_ Message = Aftertouch{}
 	_ Message = Pitchbend{}
+	_ Message = ChannelMode{}
+	_ Message = PatchChange{}
 
 	_ setter2 = NoteOff{}
 	_ setter2 = NoteOffVelocity{}
@@ -23,6 +38,7 @@ var (
 	_ setter2 = PolyAftertouch{}
 	_ setter2 = ControlChange{}
 	_ setter2 = Pitchbend{}
+	_ setter2 = ChannelMode{}
 
 	_ setter1 = ProgramChange{}
 	_ setter1 = Aftertouch{}
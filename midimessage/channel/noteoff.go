@@ -1,8 +1,8 @@
 package channel
 
 import (
-	"fmt"
 	"github.com/gomidi/midi/internal/midilib"
+	"strconv"
 )
 
 // NoteOffVelocity is offered as an alternative to NoteOff for
@@ -34,7 +34,7 @@ func (n NoteOffVelocity) Raw() []byte {
 
 // String returns human readable information about the note-off message that includes velocity.
 func (n NoteOffVelocity) String() string {
-	return fmt.Sprintf("%T channel %v key %v velocity %v", n, n.Channel(), n.Key(), n.Velocity())
+	return "channel.NoteOffVelocity channel " + strconv.Itoa(int(n.Channel())) + " key " + strconv.Itoa(int(n.Key())) + " velocity " + strconv.Itoa(int(n.Velocity()))
 }
 
 // NoteOff represents a note-off message by a note-on message with velocity of 0 (helps for running status).
@@ -63,7 +63,7 @@ func (n NoteOff) Channel() uint8 {
 
 // String returns human readable information about the note-off message.
 func (n NoteOff) String() string {
-	return fmt.Sprintf("%T channel %v key %v", n, n.Channel(), n.Key())
+	return "channel.NoteOff channel " + strconv.Itoa(int(n.Channel())) + " key " + strconv.Itoa(int(n.Key()))
 }
 
 // set returns a new note-off message that is set to the parsed arguments
@@ -1,8 +1,8 @@
 package channel
 
 import (
-	"fmt"
 	"github.com/gomidi/midi/internal/midilib"
+	"strconv"
 )
 
 // NoteOn represents a note-on message
@@ -34,7 +34,7 @@ func (n NoteOn) Raw() []byte {
 
 // String returns human readable information about the note-on message.
 func (n NoteOn) String() string {
-	return fmt.Sprintf("%T channel %v key %v velocity %v", n, n.Channel(), n.Key(), n.Velocity())
+	return "channel.NoteOn channel " + strconv.Itoa(int(n.Channel())) + " key " + strconv.Itoa(int(n.Key())) + " velocity " + strconv.Itoa(int(n.Velocity()))
 }
 
 // set returns a new note-on message that is set to the parsed arguments
@@ -0,0 +1,119 @@
+package channel
+
+import (
+	"strconv"
+)
+
+// PatchChange represents the selection of a patch: the Bank Select controllers (0 for the MSB and 32 for the LSB)
+// followed by a program change. It is no MIDI message on its own, but the three messages that are
+// meant together. Either of the bank select controllers may be missing.
+//
+// The writers of midiwriter and smfwriter write the messages of a PatchChange (see Messages) and
+// the midireader returns PatchChange messages when the PatchChanges option is set.
+type PatchChange struct {
+	channel uint8
+	bankMSB uint8
+	bankLSB uint8
+	hasMSB  bool
+	hasLSB  bool
+	program uint8
+}
+
+// PatchChange creates a patch change for the given bank (MSB and LSB) and program on the channel
+func (c Channel) PatchChange(bankMSB, bankLSB, program uint8) PatchChange {
+	return PatchChange{
+		channel: c.Channel(),
+		bankMSB: bankMSB & 0x7F,
+		bankLSB: bankLSB & 0x7F,
+		hasMSB:  true,
+		hasLSB:  true,
+		program: program & 0x7F,
+	}
+}
+
+// WithBank returns the patch change that consists of the given bank select controllers and the program change.
+// Controllers other than the bank select controllers and controllers of other channels are ignored.
+// If a controller is given more than once, the last one wins.
+func (p ProgramChange) WithBank(bankSelect ...ControlChange) PatchChange {
+	pc := PatchChange{channel: p.channel, program: p.program}
+
+	for _, cc := range bankSelect {
+		if cc.Channel() != p.channel {
+			continue
+		}
+
+		switch cc.Controller() {
+		case 0:
+			pc.bankMSB, pc.hasMSB = cc.Value(), true
+		case 32:
+			pc.bankLSB, pc.hasLSB = cc.Value(), true
+		}
+	}
+
+	return pc
+}
+
+// Channel returns the channel of the patch change
+func (p PatchChange) Channel() uint8 {
+	return p.channel
+}
+
+// Program returns the program of the patch change
+func (p PatchChange) Program() uint8 {
+	return p.program
+}
+
+// BankMSB returns the MSB of the bank (controller 0) and whether it is selected
+func (p PatchChange) BankMSB() (msb uint8, ok bool) {
+	return p.bankMSB, p.hasMSB
+}
+
+// BankLSB returns the LSB of the bank (controller 32) and whether it is selected
+func (p PatchChange) BankLSB() (lsb uint8, ok bool) {
+	return p.bankLSB, p.hasLSB
+}
+
+// Bank returns the 14bit bank number (MSB * 128 + LSB). Missing parts count as 0.
+func (p PatchChange) Bank() uint16 {
+	return uint16(p.bankMSB)<<7 | uint16(p.bankLSB)
+}
+
+// Messages returns the bank select controllers (if selected) and the program change
+func (p PatchChange) Messages() []Message {
+	c := Channel(p.channel)
+	res := make([]Message, 0, 3)
+
+	if p.hasMSB {
+		res = append(res, c.ControlChange(0, p.bankMSB))
+	}
+
+	if p.hasLSB {
+		res = append(res, c.ControlChange(32, p.bankLSB))
+	}
+
+	return append(res, c.ProgramChange(p.program))
+}
+
+// Raw returns the raw bytes of all messages of the patch change (without running status)
+func (p PatchChange) Raw() []byte {
+	var b []byte
+	for _, msg := range p.Messages() {
+		b = append(b, msg.Raw()...)
+	}
+	return b
+}
+
+// String returns human readable information about the patch change
+func (p PatchChange) String() string {
+	s := "channel.PatchChange channel " + strconv.Itoa(int(p.channel))
+
+	if p.hasMSB {
+		s += " bankMSB " + strconv.Itoa(int(p.bankMSB))
+	}
+
+	if p.hasLSB {
+		s += " bankLSB " + strconv.Itoa(int(p.bankLSB))
+	}
+
+	return s + " program " + strconv.Itoa(int(p.program))
+}
@@ -0,0 +1,51 @@
+package channel_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestPatchChangeBinary(t *testing.T) {
+	tests := []struct {
+		msg channel.PatchChange
+		raw string
+	}{
+		{channel.Channel2.PatchChange(1, 2, 3), "B2 00 01 B2 20 02 C2 03"},
+		{channel.Channel2.ProgramChange(3).WithBank(channel.Channel2.ControlChange(0, 1)), "B2 00 01 C2 03"},
+		{channel.Channel2.ProgramChange(3).WithBank(channel.Channel2.ControlChange(32, 2)), "B2 20 02 C2 03"},
+		{channel.Channel2.ProgramChange(3).WithBank(), "C2 03"},
+	}
+
+	for i, test := range tests {
+		data, err := test.msg.MarshalBinary()
+		if got := fmt.Sprintf("% X", data); err != nil || got != test.raw {
+			t.Errorf("[%v] MarshalBinary got: %s, %v wanted: %s", i, got, err, test.raw)
+		}
+
+		var m channel.PatchChange
+		if err := m.UnmarshalBinary(data); err != nil || m != test.msg {
+			t.Errorf("[%v] UnmarshalBinary got: %v, %v wanted: %v", i, m, err, test.msg)
+		}
+	}
+
+	invalid := [][]byte{
+		nil,
+		{0xB2, 0x00, 0x01},
+		{0xB2, 0x07, 0x01, 0xC2, 0x03},
+		{0xB2, 0x00, 0x01, 0xC3, 0x03},
+		{0xB2, 0x00, 0x01, 0xB3, 0x20, 0x02, 0xC2, 0x03},
+		{0xB2, 0x00, 0x01, 0xB2, 0x20, 0x02, 0xB2, 0x00, 0x01, 0xC2, 0x03},
+		{0xC2, 0x03, 0xC2, 0x04},
+	}
+
+	for i, data := range invalid {
+		var m channel.PatchChange
+		if err := m.UnmarshalBinary(data); !errors.Is(err, midi.ErrInvalidMessage) {
+			t.Errorf("[%v] got %v, %v; wanted ErrInvalidMessage", i, m, err)
+		}
+	}
+}
@@ -1,8 +1,7 @@
 package channel
 
 import (
-	"encoding/binary"
-	"fmt"
+	"strconv"
 
 	"github.com/gomidi/midi/internal/midilib"
 )
@@ -59,16 +58,12 @@ func (p Pitchbend) Channel() uint8 {
 // Raw returns the raw bytes for the message
 func (p Pitchbend) Raw() []byte {
 	r := midilib.MsbLsbSigned(p.value)
-
-	var b = make([]byte, 2)
-
-	binary.BigEndian.PutUint16(b, r)
-	return channelMessage2(p.channel, 14, b[0], b[1])
+	return channelMessage2(p.channel, 14, byte(r>>8), byte(r))
 }
 
 // String represents the MIDI pitch bend message as a string (for debugging)
 func (p Pitchbend) String() string {
-	return fmt.Sprintf("%T channel %v value %v absValue %v", p, p.Channel(), p.Value(), p.AbsValue())
+	return "channel.Pitchbend channel " + strconv.Itoa(int(p.Channel())) + " value " + strconv.Itoa(int(p.Value())) + " absValue " + strconv.Itoa(int(p.AbsValue()))
 }
 
 func (Pitchbend) set(channel uint8, firstArg, secondArg uint8) setter2 {
@@ -78,3 +73,56 @@ func (Pitchbend) set(channel uint8, firstArg, secondArg uint8) setter2 {
 	m.value, m.absValue = midilib.ParsePitchWheelVals(firstArg, secondArg)
 	return m
 }
+
+// DefaultBendRange is the pitch bend sensitivity in semitones of a General MIDI device after power on or reset
+const DefaultBendRange = 2
+
+// PitchbendSemitones returns a pitch bend message that bends by the given semitones (may be fractional, e.g. 0.25 for 25 cents),
+// assuming that the device bends by bendRange semitones for the maximum value (see PitchbendSensitivity).
+// Bends beyond the range are clipped to PitchLowest or PitchHighest.
+// For a bendRange that is not positive, the pitch bend is centered (value 0).
+func (c Channel) PitchbendSemitones(semitones, bendRange float64) Pitchbend {
+	// also catches NaN
+	if !(bendRange > 0) {
+		return c.Pitchbend(0)
+	}
+
+	v := semitones / bendRange * 8192
+
+	// round half away from zero
+	if v < 0 {
+		v -= 0.5
+	} else {
+		v += 0.5
+	}
+
+	switch {
+	case v <= PitchLowest:
+		v = PitchLowest
+	case v >= PitchHighest:
+		v = PitchHighest
+	}
+
+	return c.Pitchbend(int16(v))
+}
+
+// Semitones returns the bend in semitones (fractional), assuming that the device bends by bendRange semitones
+// for the maximum value (see PitchbendSensitivity).
+func (p Pitchbend) Semitones(bendRange float64) float64 {
+	return float64(p.value) / 8192 * bendRange
+}
+
+// PitchbendSensitivity returns the messages that set the pitch bend range of the channel
+// to the given semitones and cents by means of the registered parameter number (RPN) 0:
+// the selection of RPN 0 (controllers 101 and 100), the data entry (controllers 6 and 38) and
+// the deselection by the null RPN (127, 127), which prevents later data entries from changing the range.
+func (c Channel) PitchbendSensitivity(semitones, cents uint8) []Message {
+	return []Message{
+		c.ControlChange(101, 0),
+		c.ControlChange(100, 0),
+		c.ControlChange(6, semitones),
+		c.ControlChange(38, cents),
+		c.ControlChange(101, 127),
+		c.ControlChange(100, 127),
+	}
+}
@@ -45,3 +45,55 @@ func TestPitchbend(t *testing.T) {
 		}
 	}
 }
+
+func TestPitchbendSemitones(t *testing.T) {
+	tests := []struct {
+		semitones float64
+		bendRange float64
+		expected  int16
+	}{
+		{0, 2, 0},
+		{1, 2, 4096},
+		{-1, 2, -4096},
+		{2, 2, channel.PitchHighest},
+		{-2, 2, channel.PitchLowest},
+		{5, 2, channel.PitchHighest},
+		{-5, 2, channel.PitchLowest},
+		{0.5, 12, 341},
+		{-0.5, 12, -341},
+		{2, 0, 0},
+		{-2, -12, 0},
+	}
+
+	for i, test := range tests {
+		p := channel.Channel0.PitchbendSemitones(test.semitones, test.bendRange)
+
+		if got := p.Value(); got != test.expected {
+			t.Errorf("[%v] got: %v wanted: %v", i, got, test.expected)
+		}
+	}
+
+	if got := channel.Channel0.Pitchbend(-4096).Semitones(12); got != -6 {
+		t.Errorf("got: %v wanted: %v", got, -6)
+	}
+}
+
+func TestPitchbendSensitivity(t *testing.T) {
+	var bf bytes.Buffer
+
+	for _, msg := range channel.Channel2.PitchbendSensitivity(12, 0) {
+		bf.WriteString(msg.String() + "\n")
+	}
+
+	expected := `channel.ControlChange channel 2 controller 101 ("Registered Parameter (MSB)") value 0
+channel.ControlChange channel 2 controller 100 ("Registered Parameter (LSB)") value 0
+channel.ControlChange channel 2 controller 6 ("Data Entry (MSB)") value 12
+channel.ControlChange channel 2 controller 38 ("Data Entry (LSB)") value 0
+channel.ControlChange channel 2 controller 101 ("Registered Parameter (MSB)") value 127
+channel.ControlChange channel 2 controller 100 ("Registered Parameter (LSB)") value 127
+`
+
+	if got, want := bf.String(), channel.Named(expected); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
@@ -1,7 +1,7 @@
 package channel
 
 import (
-	"fmt"
+	"strconv"
 
 	"github.com/gomidi/midi/internal/midilib"
 )
@@ -30,7 +30,7 @@ func (p PolyAftertouch) Channel() uint8 {
 
 // String returns human readable information about the polyphonic aftertouch message.
 func (p PolyAftertouch) String() string {
-	return fmt.Sprintf("%T channel %v key %v pressure %v", p, p.Channel(), p.Key(), p.Pressure())
+	return "channel.PolyAftertouch channel " + strconv.Itoa(int(p.Channel())) + " key " + strconv.Itoa(int(p.Key())) + " pressure " + strconv.Itoa(int(p.Pressure()))
 }
 
 // Raw returns the raw bytes of the polyphonic aftertouch message.
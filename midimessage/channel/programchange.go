@@ -1,8 +1,8 @@
 package channel
 
 import (
-	"fmt"
 	"github.com/gomidi/midi/internal/midilib"
+	"strconv"
 )
 
 // ProgramChange represents a MIDI program change message
@@ -28,7 +28,7 @@ func (p ProgramChange) Raw() []byte {
 
 // String returns human readable information about the program change message.
 func (p ProgramChange) String() string {
-	return fmt.Sprintf("%T channel %v program %v", p, p.Channel(), p.Program())
+	return "channel.ProgramChange channel " + strconv.Itoa(int(p.Channel())) + " program " + strconv.Itoa(int(p.Program()))
 }
 
 // set returns a new program change message that is set to the parsed arguments
@@ -1,8 +1,8 @@
 package channel
 
 import (
-	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/gomidi/midi/internal/midilib"
 )
@@ -40,9 +40,17 @@ func ReadNoteOffVelocity() ReaderOption {
 	}
 }
 
+// ReadChannelModes lets the reader return control change messages of the controllers 120-127 as ChannelMode messages.
+// If this option is not set, they are returned as ControlChange messages (default).
+func ReadChannelModes() ReaderOption {
+	return func(rd *reader) {
+		rd.readChannelModes = true
+	}
+}
+
 // NewReader returns a reader
 func NewReader(input io.Reader, options ...ReaderOption) Reader {
-	rd := &reader{input, false}
+	rd := &reader{input: input}
 
 	for _, opt := range options {
 		opt(rd)
@@ -54,6 +62,7 @@ func NewReader(input io.Reader, options ...ReaderOption) Reader {
 type reader struct {
 	input               io.Reader
 	readNoteOffPedantic bool
+	readChannelModes    bool
 }
 
 // Read reads a channel message
@@ -94,7 +103,7 @@ func (r *reader) getMsg1(typ uint8, channel uint8, arg uint8) (msg setter1) {
 	case byteChannelPressure:
 		msg = Aftertouch{}
 	default:
-		panic(fmt.Sprintf("must not happen (typ % X is not an channel message with one argument)", typ))
+		panic("must not happen (typ " + strconv.FormatUint(uint64(typ), 16) + " is not an channel message with one argument)")
 	}
 
 	msg = msg.set(channel, arg)
@@ -111,22 +120,26 @@ func (r *reader) getMsg2(typ uint8, channel uint8, arg1 uint8, arg2 uint8) (msg
 			msg = NoteOff{}
 		}
 	case byteNoteOn:
-		msg = NoteOn{}
+		// handle noteOn messages with velocity of 0 as note offs
+		if arg2&0x7F == 0 {
+			msg = NoteOff{}
+		} else {
+			msg = NoteOn{}
+		}
 	case bytePolyphonicKeyPressure:
 		msg = PolyAftertouch{}
 	case byteControlChange:
-		msg = ControlChange{}
+		if r.readChannelModes && arg1 >= uint8(ModeAllSoundOff) {
+			msg = ChannelMode{}
+		} else {
+			msg = ControlChange{}
+		}
 	case bytePitchWheel:
 		msg = Pitchbend{}
 	default:
-		panic(fmt.Sprintf("must not happen (typ % X is not an channel message with two arguments)", typ))
+		panic("must not happen (typ " + strconv.FormatUint(uint64(typ), 16) + " is not an channel message with two arguments)")
 	}
 
 	msg = msg.set(channel, arg1, arg2)
-
-	// handle noteOn messages with velocity of 0 as note offs
-	if noteOn, is := msg.(NoteOn); is && noteOn.velocity == 0 {
-		msg = (NoteOff{}).set(channel, arg1, 0)
-	}
 	return
 }
@@ -0,0 +1,166 @@
+package channel
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrOutOfRange is the cause of the errors that are returned by the validating constructors and by Validate,
+// if a value is out of range
+var ErrOutOfRange = errors.New("value out of range")
+
+// rangeError tells which value of which message is out of range. It avoids the fmt package for the tiny profile.
+type rangeError struct {
+	kind     string
+	field    string
+	value    int
+	min, max int
+}
+
+func (e *rangeError) Error() string {
+	return e.kind + ": " + e.field + " " + strconv.Itoa(e.value) + " out of range (" +
+		strconv.Itoa(e.min) + ".." + strconv.Itoa(e.max) + ")"
+}
+
+func (e *rangeError) Unwrap() error {
+	return ErrOutOfRange
+}
+
+// check returns a rangeError, if value is not within min and max
+func check(kind, field string, value, min, max int) error {
+	if value < min || value > max {
+		return &rangeError{kind: kind, field: field, value: value, min: min, max: max}
+	}
+	return nil
+}
+
+// checkAll returns the first error that is not nil
+func checkAll(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkChannel(kind string, ch uint8) error {
+	return check(kind, "channel", int(ch), 0, 15)
+}
+
+func check7(kind, field string, value uint8) error {
+	return check(kind, field, int(value), 0, 127)
+}
+
+// NewNoteOn returns a note-on message or an error with the cause ErrOutOfRange, if the channel, the key or the velocity is out of range.
+// Unlike Channel.NoteOn, it does not truncate the values.
+func NewNoteOn(ch, key, velocity uint8) (NoteOn, error) {
+	const kind = "channel.NoteOn"
+	if err := checkAll(checkChannel(kind, ch), check7(kind, "key", key), check7(kind, "velocity", velocity)); err != nil {
+		return NoteOn{}, err
+	}
+	return Channel(ch).NoteOn(key, velocity), nil
+}
+
+// NewNoteOff returns a note-off message or an error with the cause ErrOutOfRange, if the channel or the key is out of range.
+// Unlike Channel.NoteOff, it does not truncate the values.
+func NewNoteOff(ch, key uint8) (NoteOff, error) {
+	const kind = "channel.NoteOff"
+	if err := checkAll(checkChannel(kind, ch), check7(kind, "key", key)); err != nil {
+		return NoteOff{}, err
+	}
+	return Channel(ch).NoteOff(key), nil
+}
+
+// NewNoteOffVelocity returns a note-off message with velocity or an error with the cause ErrOutOfRange,
+// if the channel, the key or the velocity is out of range.
+// Unlike Channel.NoteOffVelocity, it does not truncate the values.
+func NewNoteOffVelocity(ch, key, velocity uint8) (NoteOffVelocity, error) {
+	const kind = "channel.NoteOffVelocity"
+	if err := checkAll(checkChannel(kind, ch), check7(kind, "key", key), check7(kind, "velocity", velocity)); err != nil {
+		return NoteOffVelocity{}, err
+	}
+	return Channel(ch).NoteOffVelocity(key, velocity), nil
+}
+
+// NewPolyAftertouch returns a polyphonic aftertouch message or an error with the cause ErrOutOfRange,
+// if the channel, the key or the pressure is out of range.
+// Unlike Channel.PolyAftertouch, it does not truncate the values.
+func NewPolyAftertouch(ch, key, pressure uint8) (PolyAftertouch, error) {
+	const kind = "channel.PolyAftertouch"
+	if err := checkAll(checkChannel(kind, ch), check7(kind, "key", key), check7(kind, "pressure", pressure)); err != nil {
+		return PolyAftertouch{}, err
+	}
+	return Channel(ch).PolyAftertouch(key, pressure), nil
+}
+
+// NewControlChange returns a control change message or an error with the cause ErrOutOfRange,
+// if the channel, the controller or the value is out of range.
+// Unlike Channel.ControlChange, it does not truncate the values.
+func NewControlChange(ch, controller, value uint8) (ControlChange, error) {
+	const kind = "channel.ControlChange"
+	if err := checkAll(checkChannel(kind, ch), check7(kind, "controller", controller), check7(kind, "value", value)); err != nil {
+		return ControlChange{}, err
+	}
+	return Channel(ch).ControlChange(controller, value), nil
+}
+
+// NewProgramChange returns a program change message or an error with the cause ErrOutOfRange, if the channel or the program is out of range.
+// Unlike Channel.ProgramChange, it does not truncate the values.
+func NewProgramChange(ch, program uint8) (ProgramChange, error) {
+	const kind = "channel.ProgramChange"
+	if err := checkAll(checkChannel(kind, ch), check7(kind, "program", program)); err != nil {
+		return ProgramChange{}, err
+	}
+	return Channel(ch).ProgramChange(program), nil
+}
+
+// NewAftertouch returns an aftertouch message or an error with the cause ErrOutOfRange, if the channel or the pressure is out of range.
+// Unlike Channel.Aftertouch, it does not truncate the values.
+func NewAftertouch(ch, pressure uint8) (Aftertouch, error) {
+	const kind = "channel.Aftertouch"
+	if err := checkAll(checkChannel(kind, ch), check7(kind, "pressure", pressure)); err != nil {
+		return Aftertouch{}, err
+	}
+	return Channel(ch).Aftertouch(pressure), nil
+}
+
+// NewPitchbend returns a pitch bend message or an error with the cause ErrOutOfRange, if the channel or the value is out of range.
+// Unlike Channel.Pitchbend, it does not clip the value.
+func NewPitchbend(ch uint8, value int16) (Pitchbend, error) {
+	const kind = "channel.Pitchbend"
+	if err := checkAll(checkChannel(kind, ch), check(kind, "value", int(value), PitchLowest, PitchHighest)); err != nil {
+		return Pitchbend{}, err
+	}
+	return Channel(ch).Pitchbend(value), nil
+}
+
+// Validate returns an error with the cause ErrOutOfRange, if a value of the given channel message is out of range.
+// Since the constructors of Channel truncate the values, this is mostly the channel, e.g. for Channel(16).NoteOn(60, 100).
+// Messages that are not defined in this package are not validated.
+func Validate(msg Message) (err error) {
+	switch v := msg.(type) {
+	case NoteOn:
+		_, err = NewNoteOn(v.channel, v.key, v.velocity)
+	case NoteOff:
+		_, err = NewNoteOff(v.channel, v.key)
+	case NoteOffVelocity:
+		_, err = NewNoteOffVelocity(v.channel, v.key, v.velocity)
+	case PolyAftertouch:
+		_, err = NewPolyAftertouch(v.channel, v.key, v.pressure)
+	case ControlChange:
+		_, err = NewControlChange(v.channel, v.controller, v.value)
+	case ProgramChange:
+		_, err = NewProgramChange(v.channel, v.program)
+	case Aftertouch:
+		_, err = NewAftertouch(v.channel, v.pressure)
+	case Pitchbend:
+		_, err = NewPitchbend(v.channel, v.value)
+	case ChannelMode:
+		const kind = "channel.ChannelMode"
+		err = checkAll(checkChannel(kind, v.channel), check(kind, "mode", int(v.mode), int(ModeAllSoundOff), int(ModePolyOn)), check7(kind, "value", v.value))
+	case PatchChange:
+		err = checkChannel("channel.PatchChange", v.channel)
+	}
+	return
+}
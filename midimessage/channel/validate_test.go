@@ -0,0 +1,56 @@
+package channel_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestValidatingConstructors(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{second(channel.NewNoteOn(16, 60, 100)), "channel.NoteOn: channel 16 out of range (0..15)"},
+		{second(channel.NewNoteOn(0, 128, 100)), "channel.NoteOn: key 128 out of range (0..127)"},
+		{second(channel.NewNoteOn(0, 60, 200)), "channel.NoteOn: velocity 200 out of range (0..127)"},
+		{second(channel.NewNoteOff(0, 255)), "channel.NoteOff: key 255 out of range (0..127)"},
+		{second(channel.NewNoteOffVelocity(0, 60, 128)), "channel.NoteOffVelocity: velocity 128 out of range (0..127)"},
+		{second(channel.NewPolyAftertouch(0, 60, 128)), "channel.PolyAftertouch: pressure 128 out of range (0..127)"},
+		{second(channel.NewControlChange(0, 128, 0)), "channel.ControlChange: controller 128 out of range (0..127)"},
+		{second(channel.NewProgramChange(20, 0)), "channel.ProgramChange: channel 20 out of range (0..15)"},
+		{second(channel.NewAftertouch(0, 130)), "channel.Aftertouch: pressure 130 out of range (0..127)"},
+		{second(channel.NewPitchbend(0, 9000)), "channel.Pitchbend: value 9000 out of range (-8192..8191)"},
+		{channel.Validate(channel.Channel(16).NoteOn(60, 100)), "channel.NoteOn: channel 16 out of range (0..15)"},
+		{channel.Validate(channel.Channel(17).AllNotesOff()), "channel.ChannelMode: channel 17 out of range (0..15)"},
+	}
+
+	for i, test := range tests {
+		if test.err == nil {
+			t.Errorf("[%v] expected error %q", i, test.expected)
+			continue
+		}
+
+		if got := test.err.Error(); got != test.expected {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, test.expected)
+		}
+
+		if !errors.Is(test.err, channel.ErrOutOfRange) {
+			t.Errorf("[%v] error is not ErrOutOfRange", i)
+		}
+	}
+
+	msg, err := channel.NewNoteOn(15, 127, 127)
+	if err != nil || msg != channel.Channel15.NoteOn(127, 127) {
+		t.Errorf("got: %v, %v", msg, err)
+	}
+
+	if err := channel.Validate(channel.Channel15.Pitchbend(channel.PitchLowest)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func second(_ interface{}, err error) error {
+	return err
+}
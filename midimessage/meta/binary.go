@@ -0,0 +1,348 @@
+package meta
+
+import (
+	"bytes"
+
+	"github.com/gomidi/midi/internal/midilib"
+	"github.com/gomidi/midi/internal/vlq"
+)
+
+// ParseMessage parses the raw bytes of a single meta message, as returned by Raw (0xFF, the type, the length and the data).
+// The error has the cause midi.ErrInvalidMessage, if data is no complete meta message.
+func ParseMessage(data []byte) (Message, error) {
+	if len(data) < 3 || data[0] != 0xFF || data[1] > 0x7F {
+		return nil, midilib.InvalidMessage(data, "meta message")
+	}
+
+	// the length must match the data
+	if length, err := midilib.ReadVarLength(bytes.NewReader(data[2:])); err != nil || length != uint32(len(data)-2-len(vlq.Encode(length))) {
+		return nil, midilib.InvalidMessage(data, "meta message")
+	}
+
+	rd := bytes.NewReader(data[2:])
+	msg, err := NewReader(rd, data[1]).Read()
+
+	if err != nil || rd.Len() > 0 {
+		return nil, midilib.InvalidMessage(data, "meta message")
+	}
+
+	return msg, nil
+}
+
+// unmarshal parses data and passes the message to set that returns false, if it has the wrong type
+func unmarshal(data []byte, kind string, set func(Message) bool) error {
+	msg, err := ParseMessage(data)
+	if err != nil {
+		return err
+	}
+
+	if !set(msg) {
+		return midilib.InvalidMessage(data, kind)
+	}
+
+	return nil
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Channel) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the MIDI channel meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Channel) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Channel", func(msg Message) bool {
+		v, ok := msg.(Channel)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Copyright) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the copyright meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Copyright) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Copyright", func(msg Message) bool {
+		v, ok := msg.(Copyright)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Cuepoint) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the cuepoint meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Cuepoint) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Cuepoint", func(msg Message) bool {
+		v, ok := msg.(Cuepoint)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Device) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the device meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Device) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Device", func(msg Message) bool {
+		v, ok := msg.(Device)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Key) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the key signature meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Key) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Key", func(msg Message) bool {
+		v, ok := msg.(Key)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Lyric) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the lyric meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Lyric) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Lyric", func(msg Message) bool {
+		v, ok := msg.(Lyric)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Marker) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the marker meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Marker) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Marker", func(msg Message) bool {
+		v, ok := msg.(Marker)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Port) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the MIDI port meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Port) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Port", func(msg Message) bool {
+		v, ok := msg.(Port)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (p Program) MarshalBinary() ([]byte, error) {
+	return p.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the program name meta message in data. It implements encoding.BinaryUnmarshaler.
+func (p *Program) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Program", func(msg Message) bool {
+		v, ok := msg.(Program)
+		if ok {
+			*p = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Sequence) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the sequence name meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Sequence) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Sequence", func(msg Message) bool {
+		v, ok := msg.(Sequence)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (s SequenceNo) MarshalBinary() ([]byte, error) {
+	return s.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the sequence number meta message in data. It implements encoding.BinaryUnmarshaler.
+func (s *SequenceNo) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.SequenceNo", func(msg Message) bool {
+		v, ok := msg.(SequenceNo)
+		if ok {
+			*s = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (s SequencerData) MarshalBinary() ([]byte, error) {
+	return s.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the sequencer specific meta message in data. It implements encoding.BinaryUnmarshaler.
+func (s *SequencerData) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.SequencerData", func(msg Message) bool {
+		v, ok := msg.(SequencerData)
+		if ok {
+			*s = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (s SMPTE) MarshalBinary() ([]byte, error) {
+	return s.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the SMPTE offset meta message in data. It implements encoding.BinaryUnmarshaler.
+func (s *SMPTE) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.SMPTE", func(msg Message) bool {
+		v, ok := msg.(SMPTE)
+		if ok {
+			*s = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Tempo) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the tempo meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Tempo) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Tempo", func(msg Message) bool {
+		v, ok := msg.(Tempo)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Text) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the text meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Text) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Text", func(msg Message) bool {
+		v, ok := msg.(Text)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m TimeSig) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the time signature meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *TimeSig) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.TimeSig", func(msg Message) bool {
+		v, ok := msg.(TimeSig)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Track) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the track name meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Track) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Track", func(msg Message) bool {
+		v, ok := msg.(Track)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Undefined) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the undefined meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *Undefined) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.Undefined", func(msg Message) bool {
+		v, ok := msg.(Undefined)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m endOfTrack) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the end of track meta message in data. It implements encoding.BinaryUnmarshaler.
+func (m *endOfTrack) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "meta.EndOfTrack", func(msg Message) bool {
+		v, ok := msg.(endOfTrack)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
@@ -41,7 +41,7 @@ func (m Channel) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
-	if length != 1 {
+	if length < 1 {
 		return nil, unexpectedMessageLengthError("Midi Channel Message expected length 1")
 	}
 
@@ -52,6 +52,10 @@ func (m Channel) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
+	if err = skipExtra(rd, length, 1); err != nil {
+		return nil, err
+	}
+
 	return Channel(ch), nil
 
 }
@@ -2,5 +2,13 @@
 // Use of this source code is governed by a MIT
 // license that can be found in the LICENSE file.
 
-// Package meta provides MIDI Meta Messages
+/*
+Package meta provides MIDI Meta Messages
+
+The texts of text messages (e.g. Lyric) are plain bytes in SMF files. An Encoding converts them from and to
+Go strings, if they are not UTF-8. Latin1 is provided, other encodings (e.g. Shift-JIS from golang.org/x/text)
+can be used via EncodingFuncs:
+
+	lyric, err := meta.Decode(msg, meta.Latin1)
+*/
 package meta
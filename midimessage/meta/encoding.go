@@ -0,0 +1,144 @@
+package meta
+
+import (
+	"fmt"
+)
+
+// Encoding converts the texts of text messages (Text, Copyright, Sequence, Track, Lyric, Marker, Cuepoint, Device
+// and Program) between their encoding in the file and Go strings (UTF-8).
+// The SMF standard does not define an encoding; files in the wild often use Latin-1 or Shift-JIS.
+type Encoding interface {
+	// Decode converts the bytes of a text in the file to a string
+	Decode(b []byte) (string, error)
+
+	// Encode converts a string to the bytes of a text in the file
+	Encode(s string) ([]byte, error)
+}
+
+// Latin1 is the ISO 8859-1 encoding
+var Latin1 Encoding = latin1{}
+
+type latin1 struct{}
+
+// Decode converts Latin-1 bytes to a string
+func (latin1) Decode(b []byte) (string, error) {
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r), nil
+}
+
+// Encode converts a string to Latin-1 bytes. It returns an error for characters beyond Latin-1.
+func (latin1) Encode(s string) ([]byte, error) {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xFF {
+			return nil, fmt.Errorf("character %q can't be encoded as Latin-1", r)
+		}
+		b = append(b, byte(r))
+	}
+	return b, nil
+}
+
+// EncodingFuncs returns an Encoding based on the given conversion functions between the bytes of the file and UTF-8.
+// It allows to use the encodings of golang.org/x/text, e.g.
+//
+//	meta.EncodingFuncs(japanese.ShiftJIS.NewDecoder().Bytes, japanese.ShiftJIS.NewEncoder().Bytes)
+func EncodingFuncs(decode, encode func([]byte) ([]byte, error)) Encoding {
+	return encodingFuncs{decode, encode}
+}
+
+type encodingFuncs struct {
+	decode, encode func([]byte) ([]byte, error)
+}
+
+// Decode converts the bytes to a string with the decode function
+func (e encodingFuncs) Decode(b []byte) (string, error) {
+	res, err := e.decode(b)
+	return string(res), err
+}
+
+// Encode converts the string to bytes with the encode function
+func (e encodingFuncs) Encode(s string) ([]byte, error) {
+	return e.encode([]byte(s))
+}
+
+// Decode returns the text message with its text decoded by enc. Other messages and plain ASCII texts
+// are returned unchanged.
+func Decode(msg Message, enc Encoding) (Message, error) {
+	return convert(msg, func(s string) (string, error) {
+		if isASCII(s) {
+			return s, nil
+		}
+		return enc.Decode([]byte(s))
+	})
+}
+
+// Encode returns the text message with its text encoded by enc. Other messages are returned unchanged.
+func Encode(msg Message, enc Encoding) (Message, error) {
+	return convert(msg, func(s string) (string, error) {
+		if isASCII(s) {
+			return s, nil
+		}
+		b, err := enc.Encode(s)
+		return string(b), err
+	})
+}
+
+// convert converts the text of text messages
+func convert(msg Message, fn func(string) (string, error)) (Message, error) {
+	var (
+		res Message
+		err error
+		s   string
+	)
+
+	switch m := msg.(type) {
+	case Text:
+		s, err = fn(string(m))
+		res = Text(s)
+	case Copyright:
+		s, err = fn(string(m))
+		res = Copyright(s)
+	case Sequence:
+		s, err = fn(string(m))
+		res = Sequence(s)
+	case Track:
+		s, err = fn(string(m))
+		res = Track(s)
+	case Lyric:
+		s, err = fn(string(m))
+		res = Lyric(s)
+	case Marker:
+		s, err = fn(string(m))
+		res = Marker(s)
+	case Cuepoint:
+		s, err = fn(string(m))
+		res = Cuepoint(s)
+	case Device:
+		s, err = fn(string(m))
+		res = Device(s)
+	case Program:
+		s, err = fn(string(m))
+		res = Program(s)
+	default:
+		return msg, nil
+	}
+
+	if err != nil {
+		return msg, err
+	}
+
+	return res, nil
+}
+
+// isASCII returns whether the string only consists of ASCII characters, which are the same in all supported encodings
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
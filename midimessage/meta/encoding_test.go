@@ -0,0 +1,49 @@
+package meta
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncoding(t *testing.T) {
+	upper := func(b []byte) ([]byte, error) { return bytes.ToUpper(b), nil }
+	lower := func(b []byte) ([]byte, error) { return bytes.ToLower(b), nil }
+
+	tests := []struct {
+		enc     Encoding
+		msg     Message
+		encoded Message
+		decoded Message
+	}{
+		{Latin1, Lyric("Grüße"), Lyric("Gr\xfc\xdfe"), Lyric("Grüße")},
+		{Latin1, Track("piano"), Track("piano"), Track("piano")},
+		{Latin1, Tempo(120), Tempo(120), Tempo(120)},
+		{EncodingFuncs(lower, upper), Marker("Ära"), Marker("ÄRA"), Marker("ära")},
+	}
+
+	for i, test := range tests {
+		enc, err := Encode(test.msg, test.enc)
+
+		if err != nil {
+			t.Fatalf("[%v] can't encode: %v", i, err)
+		}
+
+		if got, want := enc, test.encoded; got != want {
+			t.Errorf("[%v] got:\n%#v\n\nwanted:\n%#v\n\n", i, got, want)
+		}
+
+		dec, err := Decode(enc, test.enc)
+
+		if err != nil {
+			t.Fatalf("[%v] can't decode: %v", i, err)
+		}
+
+		if got, want := dec, test.decoded; got != want {
+			t.Errorf("[%v] got:\n%#v\n\nwanted:\n%#v\n\n", i, got, want)
+		}
+	}
+
+	if _, err := Encode(Text("日本"), Latin1); err == nil {
+		t.Errorf("expected error for text that can't be encoded as Latin-1")
+	}
+}
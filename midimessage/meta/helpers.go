@@ -25,6 +25,17 @@ func (m *metaMessage) Bytes() []byte {
 	return b
 }
 
+// skipExtra skips the bytes of a message of the given length that follow the expected n bytes.
+// Meta messages may be extended by appending data in future versions of the SMF specification,
+// that must be ignored by readers.
+func skipExtra(rd io.Reader, length uint32, n uint32) error {
+	if length <= n {
+		return nil
+	}
+	_, err := midilib.ReadNBytes(int(length-n), rd)
+	return err
+}
+
 func readText(rd io.Reader) (string, error) {
 	b, err := midilib.ReadVarLengthData(rd)
 
@@ -144,7 +144,7 @@ func (m Key) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
-	if length != 2 {
+	if length < 2 {
 		err = unexpectedMessageLengthError("KeySignature expected length 2")
 		return nil, err
 	}
@@ -166,6 +166,10 @@ func (m Key) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
+	if err = skipExtra(rd, length, 2); err != nil {
+		return nil, err
+	}
+
 	num := sharpsOrFlats
 	if num < 0 {
 		num = num * (-1)
@@ -0,0 +1,234 @@
+package key
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+// the key profiles of Krumhansl and Kessler, starting with the tonic
+var (
+	majorProfile = [12]float64{6.35, 2.23, 3.48, 2.33, 4.38, 4.09, 2.52, 5.19, 2.39, 3.66, 2.29, 2.88}
+	minorProfile = [12]float64{6.33, 2.68, 3.52, 5.38, 2.60, 3.53, 2.54, 4.75, 3.98, 2.69, 3.34, 3.17}
+)
+
+// majorKeys and minorKeys are the key signatures for the tonics C to B.
+// For six accidentals the sharp keys are used.
+var (
+	majorKeys = [12]func() meta.Key{CMaj, DFlatMaj, DMaj, EFlatMaj, EMaj, FMaj, FSharpMaj, GMaj, AFlatMaj, AMaj, BFlatMaj, BMaj}
+	minorKeys = [12]func() meta.Key{CMin, CSharpMin, DMin, DSharpMin, EMin, FMin, FSharpMin, GMin, GSharpMin, AMin, BFlatMin, BMin}
+)
+
+// percussionChannel is the channel of the General MIDI percussion (channel 10), whose notes have no pitch
+const percussionChannel = 9
+
+// Analyzer estimates the key of notes with the Krumhansl-Schmuckler algorithm, i.e. by correlating
+// the distribution of the pitch classes with the key profiles of Krumhansl and Kessler.
+// It is a midi.Writer, so that it may be fed with a captured note stream, where each note-on message counts once.
+// Notes on the percussion channel (channel 10, i.e. channel.Channel9) are ignored.
+// The zero value is ready to use. It is not safe for concurrent use.
+type Analyzer struct {
+	weights [12]float64
+}
+
+var _ midi.Writer = &Analyzer{}
+
+// Add adds the given key (note number) with the given weight, e.g. the duration of the note.
+func (a *Analyzer) Add(key uint8, weight float64) {
+	a.weights[key%12] += weight
+}
+
+// Write adds the key of a note-on message with a weight of 1. Other messages are ignored.
+func (a *Analyzer) Write(msg midi.Message) error {
+	if on, is := msg.(channel.NoteOn); is && on.Channel() != percussionChannel && on.Velocity() > 0 {
+		a.Add(on.Key(), 1)
+	}
+	return nil
+}
+
+// Reset removes all added notes
+func (a *Analyzer) Reset() {
+	a.weights = [12]float64{}
+}
+
+// Key returns the estimated key and the correlation of the notes with the profile of the key (between -1 and 1).
+// The higher the correlation, the more reliable the estimation is.
+// Without notes, C major with a correlation of 0 is returned.
+func (a *Analyzer) Key() (k meta.Key, correlation float64) {
+	k, correlation = CMaj(), math.Inf(-1)
+
+	for tonic := 0; tonic < 12; tonic++ {
+		if c := correlate(a.weights, majorProfile, tonic); c > correlation {
+			k, correlation = majorKeys[tonic](), c
+		}
+
+		if c := correlate(a.weights, minorProfile, tonic); c > correlation {
+			k, correlation = minorKeys[tonic](), c
+		}
+	}
+
+	if math.IsNaN(correlation) || math.IsInf(correlation, -1) {
+		return CMaj(), 0
+	}
+
+	return
+}
+
+// correlate returns the Pearson correlation of the weights with the profile that is moved to the given tonic
+func correlate(weights, profile [12]float64, tonic int) float64 {
+	var meanW, meanP float64
+
+	for i := 0; i < 12; i++ {
+		meanW += weights[i] / 12
+		meanP += profile[i] / 12
+	}
+
+	var cov, varW, varP float64
+
+	for i := 0; i < 12; i++ {
+		w := weights[i] - meanW
+		p := profile[(i-tonic+12)%12] - meanP
+		cov += w * p
+		varW += w * w
+		varP += p * p
+	}
+
+	if varW == 0 {
+		return math.NaN()
+	}
+
+	return cov / math.Sqrt(varW*varP)
+}
+
+// Detect estimates the key of the notes in the given SMF file, weighted by their duration in ticks.
+// The options are passed to the reader.
+func Detect(src io.Reader, options ...smfreader.Option) (k meta.Key, correlation float64, err error) {
+	var a Analyzer
+
+	rd := smfreader.New(src, options...)
+
+	// the start times of the sounding notes by track, channel and key
+	type note struct {
+		track   int16
+		channel uint8
+		key     uint8
+	}
+
+	starts := map[note]uint64{}
+	var time uint64
+	track := int16(-1)
+
+	for {
+		msg, err := rd.Read()
+
+		if err == smf.ErrFinished {
+			break
+		}
+
+		if err != nil {
+			return k, 0, err
+		}
+
+		if rd.Track() != track {
+			track = rd.Track()
+			time = 0
+		}
+
+		time += uint64(rd.Delta())
+
+		switch m := msg.(type) {
+		case channel.NoteOn:
+			if m.Channel() != percussionChannel {
+				starts[note{track, m.Channel(), m.Key()}] = time
+			}
+		case channel.NoteOff:
+			n := note{track, m.Channel(), m.Key()}
+			if start, has := starts[n]; has {
+				a.Add(m.Key(), float64(time-start))
+				delete(starts, n)
+			}
+		}
+	}
+
+	k, correlation = a.Key()
+	return k, correlation, nil
+}
+
+// Correct detects the key of the SMF file src (see Detect) and writes the file to dest with the detected
+// key signature at the start of the first track. Any other key signature is removed.
+// The options are passed to the writer after the options for the header.
+func Correct(dest io.Writer, src io.Reader, options ...smfwriter.Option) (k meta.Key, correlation float64, err error) {
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		return k, 0, err
+	}
+
+	k, correlation, err = Detect(bytes.NewReader(data))
+	if err != nil {
+		return k, 0, err
+	}
+
+	rd := smfreader.New(bytes.NewReader(data))
+
+	if err = rd.ReadHeader(); err != nil {
+		return k, 0, err
+	}
+
+	h := rd.Header()
+	opts := []smfwriter.Option{
+		smfwriter.Format(h.Format),
+		smfwriter.NumTracks(h.NumTracks),
+		smfwriter.TimeFormat(h.TimeFormat),
+	}
+
+	wr := smfwriter.New(dest, append(opts, options...)...)
+
+	// the delta of removed key signatures is added to the next message
+	var delta uint32
+	track := int16(-1)
+
+	for {
+		msg, err := rd.Read()
+
+		if err == smf.ErrFinished {
+			return k, correlation, nil
+		}
+
+		if err != nil {
+			return k, 0, err
+		}
+
+		if rd.Track() != track {
+			track = rd.Track()
+			delta = 0
+
+			if track == 0 {
+				if err = wr.Write(k); err != nil {
+					return k, 0, err
+				}
+			}
+		}
+
+		delta += rd.Delta()
+
+		if _, is := msg.(meta.Key); is {
+			continue
+		}
+
+		wr.SetDelta(delta)
+		delta = 0
+
+		// the writer returns smf.ErrFinished after the last track
+		if err = wr.Write(msg); err != nil && err != smf.ErrFinished {
+			return k, 0, err
+		}
+	}
+}
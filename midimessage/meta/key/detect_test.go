@@ -0,0 +1,114 @@
+package key
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+func TestAnalyzer(t *testing.T) {
+
+	tests := []struct {
+		keys     []uint8
+		expected string
+	}{
+		// C major scale with emphasis on the triad
+		{[]uint8{60, 62, 64, 65, 67, 69, 71, 72, 60, 64, 67}, "C maj."},
+		// A harmonic minor with emphasis on the triad
+		{[]uint8{57, 59, 60, 62, 64, 65, 68, 69, 57, 60, 64}, "A min."},
+		// E♭ major triad and scale
+		{[]uint8{63, 65, 67, 68, 70, 72, 74, 63, 67, 70}, "E♭ maj."},
+		// no notes
+		{nil, "C maj."},
+	}
+
+	for i, test := range tests {
+		var a Analyzer
+
+		for _, k := range test.keys {
+			a.Write(channel.Channel0.NoteOn(k, 100))
+			// percussion is ignored
+			a.Write(channel.Channel9.NoteOn(k+1, 100))
+		}
+
+		got, _ := a.Key()
+
+		if got.Text() != test.expected {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got.Text(), test.expected)
+		}
+	}
+}
+
+func writeNotes(keys ...uint8) []byte {
+	var bf bytes.Buffer
+	wr := smfwriter.New(&bf, smfwriter.NumTracks(1))
+	wr.Write(EMin())
+
+	for _, k := range keys {
+		wr.Write(channel.Channel0.NoteOn(k, 100))
+		wr.SetDelta(480)
+		wr.Write(channel.Channel0.NoteOff(k))
+	}
+
+	wr.Write(meta.EndOfTrack)
+	return bf.Bytes()
+}
+
+func TestCorrect(t *testing.T) {
+	data := writeNotes(65, 67, 69, 70, 72, 74, 76, 77, 65, 69, 72)
+
+	k, _, err := Detect(bytes.NewReader(data))
+
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if got, expected := k.Text(), "F maj."; got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+
+	var bf bytes.Buffer
+	_, _, err = Correct(&bf, bytes.NewReader(data))
+
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	var keys []string
+	var notes int
+	rd := smfreader.New(&bf)
+
+	for {
+		msg, err := rd.Read()
+		if err == smf.ErrFinished {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+
+		switch m := msg.(type) {
+		case meta.Key:
+			keys = append(keys, m.Text())
+			if rd.Delta() != 0 {
+				t.Errorf("key signature at delta %v", rd.Delta())
+			}
+		case channel.NoteOn:
+			notes++
+		}
+	}
+
+	if len(keys) != 1 || keys[0] != "F maj." {
+		t.Errorf("got:\n%v\n\nwanted:\n%v\n\n", keys, []string{"F maj."})
+	}
+
+	if notes != 11 {
+		t.Errorf("got %v notes, wanted 11", notes)
+	}
+}
@@ -5,5 +5,17 @@
 /*
 Package key provides helper functions for key signature meta messages.
 
+It also estimates the key of notes with the Krumhansl-Schmuckler algorithm.
+An Analyzer collects notes of a live stream (it is a midi.Writer), Detect analyzes an SMF file
+and Correct rewrites an SMF file with the detected key signature.
+
+Usage
+
+	var a key.Analyzer
+	a.Write(channel.Channel0.NoteOn(60, 100))
+	k, correlation := a.Key()
+
+	k, correlation, err := key.Correct(dest, src)
+
 */
 package key
@@ -41,7 +41,7 @@ func (m Port) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
-	if length != 1 {
+	if length < 1 {
 		return nil, unexpectedMessageLengthError("MIDI Port Message expected length 1")
 	}
 
@@ -52,6 +52,10 @@ func (m Port) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
+	if err = skipExtra(rd, length, 1); err != nil {
+		return nil, err
+	}
+
 	return Port(port), nil
 
 }
@@ -143,3 +143,81 @@ func TestRead(t *testing.T) {
 	}
 
 }
+
+func TestReadExtended(t *testing.T) {
+	tests := []struct {
+		raw      []byte
+		expected string
+	}{
+		// additional data of a known meta message must be ignored
+		{[]byte{0xFF, 0x51, 0x04, 0x07, 0xA1, 0x20, 0x99}, "meta.Tempo BPM: 120.00"},
+		{[]byte{0xFF, 0x00, 0x03, 0x00, 0x05, 0x99}, "meta.SequenceNo: 5"},
+		{[]byte{0xFF, 0x00, 0x00}, "meta.SequenceNo: 0"},
+		{[]byte{0xFF, 0x20, 0x02, 0x03, 0x99}, "meta.Channel: 3"},
+		{[]byte{0xFF, 0x21, 0x02, 0x01, 0x99}, "meta.Port: 1"},
+		{[]byte{0xFF, 0x58, 0x05, 0x03, 0x02, 0x18, 0x08, 0x99}, "meta.TimeSig 3/4 clocksperclick 24 dsqpq 8"},
+		{[]byte{0xFF, 0x54, 0x06, 0x61, 0x02, 0x03, 0x04, 0x05, 0x99}, "meta.SMPTE 97:2:3 4.5"},
+		// unknown meta messages are kept
+		{[]byte{0xFF, 0x60, 0x02, 0x01, 0x02}, "meta.Undefined type:  60"},
+	}
+
+	for i, test := range tests {
+		rd := bytes.NewReader(test.raw[2:])
+		m, err := NewReader(rd, test.raw[1]).Read()
+
+		if err != nil {
+			t.Errorf("[%v] Read(% X) returned error: %v", i, test.raw, err)
+			continue
+		}
+
+		if rd.Len() != 0 {
+			t.Errorf("[%v] Read(% X) left %v bytes", i, test.raw, rd.Len())
+		}
+
+		if got, want := m.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+
+	if _, err := NewReader(bytes.NewReader([]byte{0x01, 0x05}), 0x00).Read(); err == nil {
+		t.Errorf("expected error for sequence number of length 1")
+	}
+
+	m, _ := NewReader(bytes.NewReader([]byte{0x02, 0x01, 0x02}), 0x60).Read()
+
+	if got, want := m.Raw(), []byte{0xFF, 0x60, 0x02, 0x01, 0x02}; !bytes.Equal(got, want) {
+		t.Errorf("raw of undefined meta message: got % X; wanted % X", got, want)
+	}
+}
+
+func TestTypedFields(t *testing.T) {
+	s := SMPTE{Hour: 0x61, Minute: 2}
+
+	if got, want := s.FrameRate(), byte(30); got != want {
+		t.Errorf("SMPTE.FrameRate() = %v; wanted %v", got, want)
+	}
+
+	if got, want := s.Hours(), byte(1); got != want {
+		t.Errorf("SMPTE.Hours() = %v; wanted %v", got, want)
+	}
+
+	tests := []struct {
+		data         SequencerData
+		manufacturer []byte
+		payload      []byte
+	}{
+		{SequencerSpecific([]byte{0x43}, []byte{0x7B, 0x00}), []byte{0x43}, []byte{0x7B, 0x00}},
+		{SequencerSpecific([]byte{0x00, 0x20, 0x29}, []byte{0x01}), []byte{0x00, 0x20, 0x29}, []byte{0x01}},
+		{SequencerData(nil), []byte{}, []byte{}},
+	}
+
+	for i, test := range tests {
+		if got, want := test.data.Manufacturer(), test.manufacturer; !bytes.Equal(got, want) {
+			t.Errorf("[%v] Manufacturer() = % X; wanted % X", i, got, want)
+		}
+
+		if got, want := test.data.Payload(), test.payload; !bytes.Equal(got, want) {
+			t.Errorf("[%v] Payload() = % X; wanted % X", i, got, want)
+		}
+	}
+}
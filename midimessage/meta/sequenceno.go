@@ -33,7 +33,7 @@ func (s SequenceNo) Raw() []byte {
 }
 
 func (s SequenceNo) readFrom(rd io.Reader) (Message, error) {
-	length, err := midilib.ReadByte(rd)
+	length, err := midilib.ReadVarLength(rd)
 
 	if err != nil {
 		return nil, err
@@ -45,6 +45,10 @@ func (s SequenceNo) readFrom(rd io.Reader) (Message, error) {
 	}
 
 	// Otherwise length will be 2 to hold the uint16.
+	if length < 2 {
+		return nil, unexpectedMessageLengthError("SequenceNumber expected length 2")
+	}
+
 	var sequenceNumber uint16
 	sequenceNumber, err = midilib.ReadUint16(rd)
 
@@ -52,6 +56,10 @@ func (s SequenceNo) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
+	if err = skipExtra(rd, length, 2); err != nil {
+		return nil, err
+	}
+
 	return SequenceNo(sequenceNumber), nil
 }
 
@@ -19,11 +19,43 @@ formats should probably stick to the standard features when using this format.
 // SequencerData is a sequencer specific meta message
 type SequencerData []byte
 
-// Data returns the sequencer specific data
+// SequencerSpecific returns a sequencer specific meta message for the given manufacturer ID (one byte or three bytes, starting with 0)
+// and data.
+func SequencerSpecific(manufacturer []byte, data []byte) SequencerData {
+	s := make(SequencerData, 0, len(manufacturer)+len(data))
+	s = append(s, manufacturer...)
+	return append(s, data...)
+}
+
+// Data returns the sequencer specific data (including the manufacturer ID)
 func (s SequencerData) Data() []byte {
 	return []byte(s)
 }
 
+// Manufacturer returns the manufacturer ID: the first byte, or the first three bytes if the first byte is 0
+func (s SequencerData) Manufacturer() []byte {
+	n := s.manufacturerLen()
+	return []byte(s[:n])
+}
+
+// Payload returns the data after the manufacturer ID
+func (s SequencerData) Payload() []byte {
+	return []byte(s[s.manufacturerLen():])
+}
+
+func (s SequencerData) manufacturerLen() int {
+	switch {
+	case len(s) == 0:
+		return 0
+	case s[0] == 0 && len(s) >= 3:
+		return 3
+	case s[0] == 0:
+		return len(s)
+	default:
+		return 1
+	}
+}
+
 // Raw returns the raw MIDI data
 func (s SequencerData) Raw() []byte {
 	return (&metaMessage{
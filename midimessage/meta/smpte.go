@@ -26,6 +26,7 @@ a certain tempo.
 
 // SMPTE represents a smpte offset MIDI meta message
 type SMPTE struct {
+	// Hour is the hour in the SMPTE format: the bits 5 and 6 are the frame rate (see FrameRate), the bits 0-4 the hour (see Hours)
 	Hour            byte
 	Minute          byte
 	Second          byte
@@ -33,6 +34,25 @@ type SMPTE struct {
 	FractionalFrame byte
 }
 
+// Hours returns the hour without the frame rate bits
+func (s SMPTE) Hours() byte {
+	return s.Hour & 0x1F
+}
+
+// FrameRate returns the frames per second that are encoded in the hour: 24, 25, 29 (29.97 drop frame) or 30
+func (s SMPTE) FrameRate() byte {
+	switch (s.Hour >> 5) & 0x03 {
+	case 0:
+		return 24
+	case 1:
+		return 25
+	case 2:
+		return 29
+	default:
+		return 30
+	}
+}
+
 // Raw returns the raw bytes for the message
 func (s SMPTE) Raw() []byte {
 	return (&metaMessage{
@@ -53,12 +73,12 @@ func (s SMPTE) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
-	if length != 5 {
+	if length < 5 {
 		err = unexpectedMessageLengthError("SMPTEOffset expected length 5")
 		return nil, err
 	}
 
-	bt, err := midilib.ReadNBytes(5, rd)
+	bt, err := midilib.ReadNBytes(int(length), rd)
 
 	if err != nil {
 		return nil, err
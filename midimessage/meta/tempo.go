@@ -82,7 +82,7 @@ func (m Tempo) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
-	if length != 3 {
+	if length < 3 {
 		err = unexpectedMessageLengthError("Tempo expected length 3")
 		return nil, err
 	}
@@ -93,5 +93,9 @@ func (m Tempo) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
+	if err = skipExtra(rd, length, 3); err != nil {
+		return nil, err
+	}
+
 	return Tempo(microsecondsPerCrotchet), nil
 }
@@ -100,7 +100,7 @@ func (m TimeSig) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
-	if length != 4 {
+	if length < 4 {
 		err = unexpectedMessageLengthError("TimeSignature expected length 4")
 		return nil, err
 	}
@@ -133,6 +133,10 @@ func (m TimeSig) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
+	if err = skipExtra(rd, length, 4); err != nil {
+		return nil, err
+	}
+
 	m.DemiSemiQuaverPerQuarter = demiSemiQuaverPerQuarter
 	m.ClocksPerClick = clocksPerClick
 	m.Numerator = numerator
@@ -0,0 +1,35 @@
+package realtime
+
+import (
+	"github.com/gomidi/midi/internal/midilib"
+)
+
+// ParseMessage parses the raw byte of a realtime message, as returned by Raw.
+// The error has the cause midi.ErrInvalidMessage, if data is no realtime message.
+func ParseMessage(data []byte) (Message, error) {
+	if len(data) != 1 {
+		return nil, midilib.InvalidMessage(data, "realtime message")
+	}
+
+	msg := dispatch(data[0])
+	if msg == nil {
+		return nil, midilib.InvalidMessage(data, "realtime message")
+	}
+
+	return msg, nil
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m msg) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the realtime message in data. It implements encoding.BinaryUnmarshaler.
+func (m *msg) UnmarshalBinary(data []byte) error {
+	v, err := ParseMessage(data)
+	if err != nil {
+		return err
+	}
+	*m = v.(msg)
+	return nil
+}
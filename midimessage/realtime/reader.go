@@ -16,36 +16,42 @@ type Reader interface {
 // The Reader does no buffering and makes no attempt to close input.
 func NewReader(input io.Reader, rthandler func(Message)) Reader {
 	if rthandler == nil {
-		return &discardReader{input}
+		return &discardReader{input: input}
 	}
-	return &reader{input, rthandler}
+	return &reader{input: input, handler: rthandler}
 }
 
 func (r *reader) Read(target []byte) (n int, err error) {
-	var bf = make([]byte, 1)
+	for n < len(target) {
+		target[n], err = r.ReadByte()
 
-	for {
-		if n == len(target) {
+		if err != nil {
 			return
-
 		}
 
+		n++
+	}
+	return
+}
+
+// ReadByte reads the next byte that is not a realtime message.
+// It allows to read without the allocation of a buffer.
+func (r *reader) ReadByte() (byte, error) {
+	for {
 		// error needed here to be able to interrupt the reading from the callback (handler)
 		// then an io.EOF error is returned and propagated to midireader.read()
-		_, err = r.input.Read(bf)
+		_, err := io.ReadFull(r.input, r.bf[:])
 
 		if err != nil {
-			return
+			return 0, err
 		}
 
 		// => no realtime message
-		if bf[0] < 0xF8 {
-			target[n] = bf[0]
-			n++
-			continue
+		if r.bf[0] < 0xF8 {
+			return r.bf[0], nil
 		}
 
-		if m := dispatch(bf[0]); m != nil {
+		if m := dispatch(r.bf[0]); m != nil {
 			// we know that r.handler is not nil (otherwise we would be inside discardReader)
 			r.handler(m)
 		}
@@ -70,6 +76,7 @@ For more information about RealTime, read the sections Running Status, Ignoring
 type reader struct {
 	input   io.Reader
 	handler func(Message)
+	bf      [1]byte
 }
 
 func (r *reader) realtime() {}
@@ -77,37 +84,39 @@ func (r *reader) realtime() {}
 // discardReader is an optimized reader that discards realtime messages
 type discardReader struct {
 	input io.Reader
+	bf    [1]byte
 }
 
 func (r *discardReader) realtime() {}
 
 func (r *discardReader) Read(target []byte) (n int, err error) {
-	var bf []byte
+	for n < len(target) {
+		target[n], err = r.ReadByte()
 
-	for {
-		if n == len(target) {
+		if err != nil {
 			return
-
 		}
-		bf = make([]byte, 1)
 
-		_, err = r.input.Read(bf)
+		n++
+	}
+	return
+}
+
+// ReadByte reads the next byte that is not a realtime message.
+// It allows to read without the allocation of a buffer.
+func (r *discardReader) ReadByte() (byte, error) {
+	for {
+		_, err := io.ReadFull(r.input, r.bf[:])
 
 		if err != nil {
-			return
+			return 0, err
 		}
 
-		// => no realtime message
-		if bf[0] < 0xF8 {
-			target[n] = bf[0]
-			n++
-			continue
+		// => no realtime message; realtime messages are not handled, so do nothing with them
+		if r.bf[0] < 0xF8 {
+			return r.bf[0], nil
 		}
-
-		// don't handle realtime messages, so do nothing here
-
 	}
-
 }
 
 func dispatch(b byte) Message {
@@ -0,0 +1,148 @@
+package syscommon
+
+import (
+	"bytes"
+
+	"github.com/gomidi/midi/internal/midilib"
+)
+
+// ParseMessage parses the raw bytes of a single system common message, as returned by Raw.
+// The undefined messages 0xF4 and 0xF5 are returned as Unknown.
+// The error has the cause midi.ErrInvalidMessage, if data is no complete system common message.
+func ParseMessage(data []byte) (Message, error) {
+	if len(data) == 0 || data[0] < 0xF1 || data[0] > 0xF6 || !isData(data[1:]) {
+		return nil, midilib.InvalidMessage(data, "system common message")
+	}
+
+	if data[0] == 0xF4 || data[0] == 0xF5 {
+		return Unknown{Status: data[0], Data: append([]byte(nil), data[1:]...)}, nil
+	}
+
+	if len(data) != messageLengths[data[0]] {
+		return nil, midilib.InvalidMessage(data, "system common message")
+	}
+
+	rd := bytes.NewReader(data[1:])
+	msg, err := NewReader(rd, data[0]).Read()
+
+	if err != nil || rd.Len() > 0 {
+		return nil, midilib.InvalidMessage(data, "system common message")
+	}
+
+	return msg, nil
+}
+
+// messageLengths are the lengths of the defined system common messages, including the status byte
+var messageLengths = map[byte]int{
+	byteMIDITimingCodeMessage:  2,
+	byteSysSongPositionPointer: 3,
+	byteSysSongSelect:          2,
+	byteSysTuneRequest:         1,
+}
+
+// isData returns whether all bytes are data bytes
+func isData(data []byte) bool {
+	for _, b := range data {
+		if b > 0x7F {
+			return false
+		}
+	}
+	return true
+}
+
+// unmarshal parses data and passes the message to set that returns false, if it has the wrong type
+func unmarshal(data []byte, kind string, set func(Message) bool) error {
+	msg, err := ParseMessage(data)
+	if err != nil {
+		return err
+	}
+
+	if !set(msg) {
+		return midilib.InvalidMessage(data, kind)
+	}
+
+	return nil
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m MTC) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the MIDI timing code message in data. It implements encoding.BinaryUnmarshaler.
+func (m *MTC) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "syscommon.MTC", func(msg Message) bool {
+		v, ok := msg.(MTC)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m SongSelect) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the song select message in data. It implements encoding.BinaryUnmarshaler.
+func (m *SongSelect) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "syscommon.SongSelect", func(msg Message) bool {
+		v, ok := msg.(SongSelect)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m SPP) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the song position pointer message in data. It implements encoding.BinaryUnmarshaler.
+func (m *SPP) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "syscommon.SPP", func(msg Message) bool {
+		v, ok := msg.(SPP)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m tune) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the tune request message in data. It implements encoding.BinaryUnmarshaler.
+func (m *tune) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "syscommon.Tune", func(msg Message) bool {
+		v, ok := msg.(tune)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Unknown) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the undefined message (0xF4 or 0xF5) or the data bytes without status in data.
+// It implements encoding.BinaryUnmarshaler.
+func (m *Unknown) UnmarshalBinary(data []byte) error {
+	switch {
+	case len(data) > 0 && isData(data):
+		*m = Unknown{Data: append([]byte(nil), data...)}
+	case len(data) > 0 && (data[0] == 0xF4 || data[0] == 0xF5) && isData(data[1:]):
+		*m = Unknown{Status: data[0], Data: append([]byte(nil), data[1:]...)}
+	default:
+		return midilib.InvalidMessage(data, "syscommon.Unknown")
+	}
+	return nil
+}
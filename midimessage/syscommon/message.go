@@ -28,4 +28,5 @@ var (
 	//	_ Message = Undefined4(0)
 	//	_ Message = Undefined5(0)
 	_ Message = MTC(0)
+	_ Message = Unknown{}
 )
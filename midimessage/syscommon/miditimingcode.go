@@ -1,8 +1,8 @@
 package syscommon
 
 import (
-	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/gomidi/midi/internal/midilib"
 )
@@ -35,7 +35,7 @@ type MTC uint8
 
 // String represents the MIDI timing code message as a string (for debugging)
 func (m MTC) String() string {
-	return fmt.Sprintf("%T: %v", m, m.QuarterFrame())
+	return "syscommon.MTC: " + strconv.Itoa(int(m.QuarterFrame()))
 }
 
 // Raw returns the raw bytes for the message
@@ -85,3 +85,16 @@ func TestRead(t *testing.T) {
 	}
 
 }
+
+func TestReadSPP(t *testing.T) {
+	// the LSB comes first
+	m, err := NewReader(bytes.NewReader([]byte{0x2C, 0x02}), 0xF2).Read()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := m.String(), "syscommon.SPP: 300"; got != want {
+		t.Errorf("got: %#v; wanted %#v", got, want)
+	}
+}
@@ -1,35 +1,43 @@
 package syscommon
 
 import (
-	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/gomidi/midi/internal/midilib"
 )
 
 // Raw returns the raw bytes for the message
-// TODO Test
 func (m SongSelect) Raw() []byte {
-	// TODO check - it is a guess
-	return []byte{byte(0xF3), byte(m)}
+	return []byte{byte(0xF3), m.Number()}
 }
 
 // SongSelect represents the MIDI song select system message
 type SongSelect uint8
 
-// Number returns the number of the song
+// MaxSong is the maximal number of a song that can be selected
+const MaxSong = 0x7F
+
+// SelectSong returns the song select message for the song (0-127). Numbers beyond MaxSong are set to MaxSong.
+func SelectSong(song uint8) SongSelect {
+	if song > MaxSong {
+		song = MaxSong
+	}
+	return SongSelect(song)
+}
+
+// Number returns the number of the song (0-127)
 func (m SongSelect) Number() uint8 {
-	return uint8(m)
+	return uint8(m) & MaxSong
 }
 
 // String represents the MIDI song select message as a string (for debugging)
 func (m SongSelect) String() string {
-	return fmt.Sprintf("%T: %v", m, m.Number())
+	return "syscommon.SongSelect: " + strconv.Itoa(int(m.Number()))
 }
 
 func (m SongSelect) sysCommon() {}
 
-// TODO: check
 func (m SongSelect) readFrom(rd io.Reader) (Message, error) {
 
 	b, err := midilib.ReadByte(rd)
@@ -38,5 +46,5 @@ func (m SongSelect) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
-	return SongSelect(b), nil
+	return SongSelect(b & MaxSong), nil
 }
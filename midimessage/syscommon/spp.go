@@ -2,8 +2,8 @@ package syscommon
 
 import (
 	// "encoding/binary"
-	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/gomidi/midi/internal/midilib"
 )
@@ -37,34 +37,86 @@ func (m SPP) readFrom(rd io.Reader) (Message, error) {
 		return nil, err
 	}
 
-	_, abs := midilib.ParsePitchWheelVals(bt[1], bt[0])
+	// the LSB comes first
+	_, abs := midilib.ParsePitchWheelVals(bt[0], bt[1])
 	return SPP(abs), nil
 }
 
 // SPP represents the MIDI song position pointer (SPP)
 type SPP uint16
 
+// MaxSPP is the maximal position of a song position pointer in 16th notes
+const MaxSPP = 0x3FFF
+
+// SPPAtSixteenth returns the song position pointer for the position in 16th notes (MIDI beats) since the start of the song.
+// Positions beyond MaxSPP are set to MaxSPP.
+func SPPAtSixteenth(n uint32) SPP {
+	if n > MaxSPP {
+		n = MaxSPP
+	}
+	return SPP(n)
+}
+
+// SPPAtBeat returns the song position pointer for the position in beats (quarter notes) since the start of the song,
+// rounded down to a 16th note. Negative positions are set to 0.
+func SPPAtBeat(beat float64) SPP {
+	if beat < 0 {
+		return 0
+	}
+
+	n := beat * 4
+	if n > MaxSPP {
+		return MaxSPP
+	}
+
+	return SPP(n)
+}
+
+// SPPAtBar returns the song position pointer for the start of the bar (starting with 1) in the given time signature,
+// e.g. 6/8. Time signature changes are not taken into account, denominators greater than 16 are treated as 16.
+func SPPAtBar(bar uint32, numerator, denominator uint8) SPP {
+	if bar == 0 || denominator == 0 {
+		return 0
+	}
+
+	if denominator > 16 {
+		denominator = 16
+	}
+
+	return SPPAtSixteenth((bar - 1) * uint32(numerator) * (16 / uint32(denominator)))
+}
+
 // Number returns the number of the song position pointer
 func (m SPP) Number() uint16 {
 	return uint16(m)
 }
 
+// Sixteenths returns the position in 16th notes (MIDI beats) since the start of the song
+func (m SPP) Sixteenths() uint16 {
+	return uint16(m) & MaxSPP
+}
+
+// Beats returns the position in beats (quarter notes) since the start of the song
+func (m SPP) Beats() float64 {
+	return float64(m.Sixteenths()) / 4
+}
+
+// Pulses returns the position in MIDI clock pulses (24 per quarter note) since the start of the song
+func (m SPP) Pulses() int {
+	return int(m.Sixteenths()) * 6
+}
+
 // String represents the MIDI song position pointer message as a string (for debugging)
 func (m SPP) String() string {
-	return fmt.Sprintf("%T: %v", m, m.Number())
+	return "syscommon.SPP: " + strconv.Itoa(int(m.Number()))
 }
 
 // Raw returns the raw bytes for the message
 func (m SPP) Raw() []byte {
-	var b = make([]byte, 2)
-	b[1] = byte(uint16(m) & 0x7F)
-	b[0] = byte((uint16(m) >> 7) & 0x7F)
-
-	// r := msbLsbUnsigned(uint16(m))
-	// binary.BigEndian.PutUint16(b, r)
-
-	//return []byte{0xF2, b[1], b[0]}
+	lsb := byte(uint16(m) & 0x7F)
+	msb := byte((uint16(m) >> 7) & 0x7F)
 
-	return []byte{0xF2, b[0], b[1]}
+	// the LSB comes first
+	return []byte{0xF2, lsb, msb}
 }
 func (m SPP) sysCommon() {}
@@ -28,6 +28,18 @@ func TestMessagesString(t *testing.T) {
 			Tune,
 			"syscommon.Tune",
 		},
+		{
+			Unknown{Status: 0xF5},
+			"syscommon.Unknown: F5",
+		},
+		{
+			Unknown{Status: 0xF4, Data: []byte{0x12, 0x13}},
+			"syscommon.Unknown: F4 12 13",
+		},
+		{
+			Unknown{Data: []byte{0x14}},
+			"syscommon.Unknown: 14",
+		},
 	}
 
 	for _, test := range tests {
@@ -81,7 +93,11 @@ func TestMessagesRaw(t *testing.T) {
 		},
 		{
 			SPP(8),
-			"F2 00 08",
+			"F2 08 00",
+		},
+		{
+			SPP(300),
+			"F2 2C 02",
 		},
 		{
 			SongSelect(2),
@@ -105,3 +121,45 @@ func TestMessagesRaw(t *testing.T) {
 	}
 
 }
+
+func TestSPPPosition(t *testing.T) {
+
+	tests := []struct {
+		input      SPP
+		sixteenths uint16
+		beats      float64
+		pulses     int
+	}{
+		{SPPAtSixteenth(6), 6, 1.5, 36},
+		{SPPAtSixteenth(20000), MaxSPP, 4095.75, 98298},
+		{SPPAtBeat(2.3), 9, 2.25, 54},
+		{SPPAtBeat(-1), 0, 0, 0},
+		{SPPAtBar(3, 4, 4), 32, 8, 192},
+		{SPPAtBar(2, 6, 8), 12, 3, 72},
+		{SPPAtBar(0, 4, 4), 0, 0, 0},
+	}
+
+	for i, test := range tests {
+		if got, want := test.input.Sixteenths(), test.sixteenths; got != want {
+			t.Errorf("[%v] Sixteenths() = %v; wanted %v", i, got, want)
+		}
+
+		if got, want := test.input.Beats(), test.beats; got != want {
+			t.Errorf("[%v] Beats() = %v; wanted %v", i, got, want)
+		}
+
+		if got, want := test.input.Pulses(), test.pulses; got != want {
+			t.Errorf("[%v] Pulses() = %v; wanted %v", i, got, want)
+		}
+	}
+}
+
+func TestSelectSong(t *testing.T) {
+	if got, want := SelectSong(5).Number(), uint8(5); got != want {
+		t.Errorf("SelectSong(5).Number() = %v; wanted %v", got, want)
+	}
+
+	if got, want := SelectSong(200).Number(), uint8(MaxSong); got != want {
+		t.Errorf("SelectSong(200).Number() = %v; wanted %v", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package syscommon
+
+import (
+	"io"
+)
+
+// Unknown represents an undefined system common message (status 0xF4 or 0xF5) or data bytes without status.
+// Such messages are discarded by the readers by default; some non-conformant devices send them nevertheless.
+type Unknown struct {
+	// Status is the status byte, 0 for data bytes without status
+	Status byte
+
+	// Data are the data bytes that followed the status
+	Data []byte
+}
+
+// String represents the undefined message as a string (for debugging)
+func (m Unknown) String() string {
+	const digits = "0123456789ABCDEF"
+	s := []byte("syscommon.Unknown:")
+
+	for _, b := range m.Raw() {
+		s = append(s, ' ', digits[b>>4], digits[b&0x0F])
+	}
+
+	return string(s)
+}
+
+// Raw returns the raw bytes for the message
+func (m Unknown) Raw() []byte {
+	if m.Status == 0 {
+		return append([]byte(nil), m.Data...)
+	}
+	return append([]byte{m.Status}, m.Data...)
+}
+
+func (m Unknown) sysCommon() {}
+
+func (m Unknown) readFrom(rd io.Reader) (Message, error) {
+	return m, nil
+}
@@ -0,0 +1,133 @@
+package sysex
+
+import (
+	"github.com/gomidi/midi/internal/midilib"
+)
+
+// ParseMessage parses the raw bytes of a single sysex message, as returned by Raw.
+// Data starting with 0xF0 is returned as SysEx, if it ends with 0xF7, and as Start otherwise.
+// Data starting with 0xF7 is returned as End, if it ends with 0xF7, and as Continue otherwise
+// (use the UnmarshalBinary method of Escape for escape sequences).
+// The error has the cause midi.ErrInvalidMessage, if data does not start with 0xF0 or 0xF7.
+func ParseMessage(data []byte) (Message, error) {
+	if len(data) == 0 {
+		return nil, midilib.InvalidMessage(data, "sysex message")
+	}
+
+	ended := len(data) > 1 && data[len(data)-1] == byteSysExEnd
+
+	switch {
+	case data[0] == byteSysExStart && ended:
+		return SysEx(inner(data, true)), nil
+	case data[0] == byteSysExStart:
+		return Start(inner(data, false)), nil
+	case data[0] == byteSysExEnd && ended:
+		return End(inner(data, true)), nil
+	case data[0] == byteSysExEnd:
+		return Continue(inner(data, false)), nil
+	default:
+		return nil, midilib.InvalidMessage(data, "sysex message")
+	}
+}
+
+// inner returns a copy of data without the first byte and, if ended, the last byte
+func inner(data []byte, ended bool) []byte {
+	end := len(data)
+	if ended {
+		end--
+	}
+	return append([]byte{}, data[1:end]...)
+}
+
+// unmarshal parses data and passes the message to set that returns false, if it has the wrong type
+func unmarshal(data []byte, kind string, set func(Message) bool) error {
+	msg, err := ParseMessage(data)
+	if err != nil {
+		return err
+	}
+
+	if !set(msg) {
+		return midilib.InvalidMessage(data, kind)
+	}
+
+	return nil
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m SysEx) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the complete sysex in data. It implements encoding.BinaryUnmarshaler.
+func (m *SysEx) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "sysex.SysEx", func(msg Message) bool {
+		v, ok := msg.(SysEx)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Start) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the incomplete sysex start in data. It implements encoding.BinaryUnmarshaler.
+func (m *Start) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "sysex.Start", func(msg Message) bool {
+		v, ok := msg.(Start)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Continue) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the sysex continuation in data. It implements encoding.BinaryUnmarshaler.
+func (m *Continue) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "sysex.Continue", func(msg Message) bool {
+		v, ok := msg.(Continue)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m End) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the sysex end in data. It implements encoding.BinaryUnmarshaler.
+func (m *End) UnmarshalBinary(data []byte) error {
+	return unmarshal(data, "sysex.End", func(msg Message) bool {
+		v, ok := msg.(End)
+		if ok {
+			*m = v
+		}
+		return ok
+	})
+}
+
+// MarshalBinary returns the raw bytes of the message. It implements encoding.BinaryMarshaler.
+func (m Escape) MarshalBinary() ([]byte, error) {
+	return m.Raw(), nil
+}
+
+// UnmarshalBinary sets the message to the escape sequence in data (0xF7 followed by the escaped bytes).
+// It implements encoding.BinaryUnmarshaler.
+func (m *Escape) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != byteSysExEnd {
+		return midilib.InvalidMessage(data, "sysex.Escape")
+	}
+	*m = Escape(inner(data, false))
+	return nil
+}
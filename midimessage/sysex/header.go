@@ -0,0 +1,89 @@
+package sysex
+
+import (
+	"strconv"
+)
+
+// Header is the beginning of the data of a sysex that identifies its manufacturer
+// and, in case of universal sysex, its device and type.
+type Header struct {
+	// Manufacturer is the manufacturer ID, either a single byte or three bytes starting with 0x00.
+	// Universal sysex have the IDs 0x7E (non-realtime) and 0x7F (realtime).
+	Manufacturer []byte
+
+	// Device is the device ID of universal sysex
+	Device uint8
+
+	// SubID1 is the first sub-ID of universal sysex
+	SubID1 uint8
+
+	// SubID2 is the second sub-ID of universal sysex
+	SubID2 uint8
+}
+
+// Universal returns true, if the header belongs to a universal sysex
+func (h Header) Universal() bool {
+	return len(h.Manufacturer) == 1 && (h.Manufacturer[0] == 0x7E || h.Manufacturer[0] == 0x7F)
+}
+
+// Realtime returns true, if the header belongs to a universal realtime sysex
+func (h Header) Realtime() bool {
+	return h.Universal() && h.Manufacturer[0] == 0x7F
+}
+
+// String represents the header as a string (for debugging)
+func (h Header) String() string {
+	if h.Universal() {
+		return "sysex.Header manufacturer: " + hex(h.Manufacturer) + " device: " + strconv.Itoa(int(h.Device)) +
+			" subID1: " + strconv.Itoa(int(h.SubID1)) + " subID2: " + strconv.Itoa(int(h.SubID2))
+	}
+	return "sysex.Header manufacturer: " + hex(h.Manufacturer)
+}
+
+// hex returns the bytes in uppercase hexadecimal notation, separated by spaces
+func hex(b []byte) string {
+	const digits = "0123456789ABCDEF"
+	s := make([]byte, 0, len(b)*3)
+	for i, c := range b {
+		if i > 0 {
+			s = append(s, ' ')
+		}
+		s = append(s, digits[c>>4], digits[c&0x0F])
+	}
+	return string(s)
+}
+
+// ParseHeader parses the header from the beginning of the inner data of a sysex (without 0xF0).
+// complete is false if the data is too short to contain the whole header; the returned
+// header then contains what could be parsed so far.
+func ParseHeader(data []byte) (h Header, complete bool) {
+	if len(data) == 0 {
+		return
+	}
+
+	switch data[0] {
+	case 0x00:
+		if len(data) < 3 {
+			h.Manufacturer = data
+			return
+		}
+		h.Manufacturer = data[:3]
+		return h, true
+	case 0x7E, 0x7F:
+		h.Manufacturer = data[:1]
+		if len(data) > 1 {
+			h.Device = data[1]
+		}
+		if len(data) > 2 {
+			h.SubID1 = data[2]
+		}
+		if len(data) > 3 {
+			h.SubID2 = data[3]
+			complete = true
+		}
+		return
+	default:
+		h.Manufacturer = data[:1]
+		return h, true
+	}
+}
@@ -0,0 +1,98 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package sysex
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+// Spooled is a complete sysex whose inner data (without 0xF0 and 0xF7) has been spooled to a
+// file instead of being held in memory.
+// The file is owned by the receiver of the message who is responsible to remove it.
+type Spooled struct {
+	// Path is the path of the file containing the data
+	Path string
+
+	// Size is the size of the data in bytes
+	Size int
+}
+
+// Reader opens the file containing the data for reading
+func (m Spooled) Reader() (io.ReadCloser, error) {
+	return os.Open(m.Path)
+}
+
+// Remove removes the file containing the data
+func (m Spooled) Remove() error {
+	return os.Remove(m.Path)
+}
+
+// Data reads the whole data into memory. It returns nil if the file can't be read.
+func (m Spooled) Data() []byte {
+	b, err := ioutil.ReadFile(m.Path)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+var _ Message = Spooled{}
+
+func (m Spooled) sysex() {}
+
+// String represents the spooled sysex message as a string (for debugging)
+func (m Spooled) String() string {
+	return "sysex.Spooled len: " + strconv.Itoa(m.Len())
+}
+
+// Len returns the length of the sysex data
+func (m Spooled) Len() int {
+	return m.Size
+}
+
+// Raw reads the whole data into memory and returns it with the prefixed 0xF0 and
+// the postfix 0xF7
+func (m Spooled) Raw() []byte {
+	return SysEx(m.Data()).Raw()
+}
+
+// MarshalBinary reads the whole data into memory and returns the raw bytes of the sysex.
+// It implements encoding.BinaryMarshaler.
+func (m Spooled) MarshalBinary() ([]byte, error) {
+	b, err := ioutil.ReadFile(m.Path)
+	if err != nil {
+		return nil, err
+	}
+	return SysEx(b).Raw(), nil
+}
+
+// UnmarshalBinary spools the data of the complete sysex in data into a new file inside the default directory
+// for temporary files. The caller is responsible to remove it. It implements encoding.BinaryUnmarshaler.
+func (m *Spooled) UnmarshalBinary(data []byte) error {
+	var sys SysEx
+	if err := sys.UnmarshalBinary(data); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile("", "sysex")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(sys.Data())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	*m = Spooled{Path: f.Name(), Size: len(sys.Data())}
+	return nil
+}
@@ -0,0 +1,39 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package sysex
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/internal/midilib"
+)
+
+func TestSpooledBinary(t *testing.T) {
+	data := []byte{0xF0, 0x41, 0x10, 0x42, 0xF7}
+
+	var m Spooled
+	if err := m.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	defer m.Remove()
+
+	if got, want := m.String(), "sysex.Spooled len: 3"; got != want {
+		t.Errorf("got: %#v; wanted %#v", got, want)
+	}
+
+	b, err := m.MarshalBinary()
+	if got, want := fmt.Sprintf("% X", b), fmt.Sprintf("% X", data); err != nil || got != want {
+		t.Errorf("MarshalBinary got: %s, %v; wanted %s", got, err, want)
+	}
+
+	if err := m.UnmarshalBinary([]byte{0xF0, 0x41}); !errors.Is(err, midilib.ErrInvalidMessage) {
+		t.Errorf("expected ErrInvalidMessage for an incomplete sysex, got %v", err)
+	}
+
+	if _, err := (Spooled{Path: m.Path + ".missing"}).MarshalBinary(); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
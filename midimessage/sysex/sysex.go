@@ -1,7 +1,7 @@
 package sysex
 
 import (
-	"fmt"
+	"strconv"
 )
 
 const (
@@ -20,7 +20,7 @@ func (m Escape) Data() []byte {
 
 // String represents the sysex.Escape as a string (for debugging)
 func (m Escape) String() string {
-	return fmt.Sprintf("%T len: %v", m, m.Len())
+	return "sysex.Escape len: " + strconv.Itoa(m.Len())
 }
 
 // Raw returns the data with the escape prefix 0xF7
@@ -65,7 +65,7 @@ func (m Start) sysex() {}
 
 // String represents the sysex.Start as a string (for debugging)
 func (m Start) String() string {
-	return fmt.Sprintf("%T len: %v", m, m.Len())
+	return "sysex.Start len: " + strconv.Itoa(m.Len())
 }
 
 // Continue is an incomplete sysex that is following Start or SysExContinue but not ending it.
@@ -84,7 +84,7 @@ func (m Continue) sysex() {}
 
 // String represents the sysex.Continue as a string (for debugging)
 func (m Continue) String() string {
-	return fmt.Sprintf("%T len: %v", m, m.Len())
+	return "sysex.Continue len: " + strconv.Itoa(m.Len())
 }
 
 // Raw returns the data with the prefix 0xF7
@@ -113,7 +113,7 @@ func (m End) Data() []byte {
 
 // String represents the sysex.End as a string (for debugging)
 func (m End) String() string {
-	return fmt.Sprintf("%T len: %v", m, m.Len())
+	return "sysex.End len: " + strconv.Itoa(m.Len())
 }
 
 // Len returns the length of the sysex data
@@ -161,7 +161,7 @@ func (m SysEx) sysex() {}
 
 // String represents the sysex message as a string (for debugging)
 func (m SysEx) String() string {
-	return fmt.Sprintf("%T len: %v", m, m.Len())
+	return "sysex.SysEx len: " + strconv.Itoa(m.Len())
 }
 
 // Len returns the length of the sysex data
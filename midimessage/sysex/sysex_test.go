@@ -167,3 +167,23 @@ func TestMessagesString(t *testing.T) {
 	}
 
 }
+
+func TestValidators(t *testing.T) {
+	var v Validators
+	v.Register([]byte{0x00, 0x20, 0x33}, Length(4, 6))
+
+	tests := []struct {
+		input    SysEx
+		expected string
+	}{
+		{SysEx([]byte{0x00, 0x20, 0x33, 0x01}), "sysex.Checked len: 4 verified"},
+		{SysEx([]byte{0x00, 0x20, 0x33, 0x01, 0x02, 0x03, 0x04}), "sysex.Checked len: 7 corrupt: invalid length 7, expected 4 - 6"},
+		{SysEx([]byte{0x00, 0x20, 0x34, 0x01, 0x02, 0x03, 0x04}), "sysex.SysEx len: 7"},
+	}
+
+	for _, test := range tests {
+		if got, want := v.Check(test.input).String(), test.expected; got != want {
+			t.Errorf("Check(% X) = %q; wanted %q", []byte(test.input), got, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package sysex
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// Validator validates the inner data of a sysex (without 0xF0 and 0xF7), e.g. by verifying a checksum.
+// It returns an error describing the corruption, or nil if the data is valid.
+type Validator func(data []byte) error
+
+// Validators is a registry of validators per manufacturer.
+// It can be used concurrently. The zero value is an empty registry.
+type Validators struct {
+	mx         sync.RWMutex
+	validators []registeredValidator
+}
+
+type registeredValidator struct {
+	manufacturer []byte
+	validate     Validator
+}
+
+// Register registers a validator for the given manufacturer ID (one byte or three bytes starting with 0x00).
+// The validator is called for each sysex whose data starts with the manufacturer ID.
+// Multiple validators may be registered for the same manufacturer. They run in the order of registration.
+func (v *Validators) Register(manufacturer []byte, validate Validator) {
+	v.mx.Lock()
+	v.validators = append(v.validators, registeredValidator{manufacturer: manufacturer, validate: validate})
+	v.mx.Unlock()
+}
+
+// Validate runs all validators that are registered for the manufacturer of the given data.
+// It returns the first error and whether any validator has been registered for the manufacturer.
+func (v *Validators) Validate(data []byte) (validated bool, err error) {
+	v.mx.RLock()
+	defer v.mx.RUnlock()
+
+	for _, rv := range v.validators {
+		if !bytes.HasPrefix(data, rv.manufacturer) {
+			continue
+		}
+
+		validated = true
+		if err = rv.validate(data); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Check returns a Checked message if validators are registered for the manufacturer of the sysex,
+// otherwise the sysex is returned unchanged.
+func (v *Validators) Check(msg SysEx) Message {
+	validated, err := v.Validate(msg.Data())
+
+	if !validated {
+		return msg
+	}
+
+	return Checked{SysEx: msg, Err: err}
+}
+
+// Checked is a complete sysex that has been validated by the validators of its manufacturer.
+type Checked struct {
+	SysEx
+
+	// Err is the error returned by the failing validator, nil if the sysex is verified.
+	Err error
+}
+
+// Verified returns true if all validators accepted the sysex.
+func (m Checked) Verified() bool {
+	return m.Err == nil
+}
+
+// String represents the checked sysex message as a string (for debugging)
+func (m Checked) String() string {
+	if m.Err != nil {
+		return "sysex.Checked len: " + strconv.Itoa(m.Len()) + " corrupt: " + m.Err.Error()
+	}
+	return "sysex.Checked len: " + strconv.Itoa(m.Len()) + " verified"
+}
+
+// Length returns a validator that checks that the length of the data is between min and max (including).
+func Length(min, max int) Validator {
+	return func(data []byte) error {
+		if len(data) < min || len(data) > max {
+			return errors.New("invalid length " + strconv.Itoa(len(data)) + ", expected " + strconv.Itoa(min) + " - " + strconv.Itoa(max))
+		}
+		return nil
+	}
+}
+
+// Checksum returns a validator for the checksum scheme used by Roland (and others), where the
+// last byte of the data is a checksum, such that the lower 7 bits of the sum of the
+// bytes from the given offset up to and including the checksum are 0.
+func Checksum(offset int) Validator {
+	return func(data []byte) error {
+		if len(data) <= offset {
+			return errors.New("data too short for checksum at offset " + strconv.Itoa(offset))
+		}
+
+		var sum byte
+		for _, b := range data[offset:] {
+			sum += b
+		}
+
+		if sum&0x7F != 0 {
+			return errors.New("checksum mismatch: 0x" + hex(data[len(data)-1:]))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package midireader
+
+import (
+	"io"
+	"sync"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// Async reads live MIDI data inside a goroutine and delivers the messages via a channel.
+type Async struct {
+	messages chan midi.Message
+	results  chan readResult
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+	err      error
+}
+
+// NewAsync starts reading from src in a goroutine. The read messages are delivered on the channel
+// returned by Messages. Realtime messages are passed to rthandler (if it is not nil) from within the
+// reading goroutine. The options are the same as for New.
+//
+// Reading stops, if src returns an error (e.g. io.EOF) or if Close is called. In both cases
+// the channel returned by Messages is closed.
+func NewAsync(src io.Reader, rthandler func(realtime.Message), options ...Option) *Async {
+	a := &Async{
+		messages: make(chan midi.Message),
+		results:  make(chan readResult),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go a.read(New(src, rthandler, options...))
+	go a.forward()
+	return a
+}
+
+// read reads from rd until an error happens or Close is called
+func (a *Async) read(rd midi.Reader) {
+	for {
+		msg, err := rd.Read()
+
+		select {
+		case a.results <- readResult{msg, err}:
+		case <-a.stop:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// forward passes the read messages to the messages channel
+func (a *Async) forward() {
+	defer close(a.done)
+	defer close(a.messages)
+
+	for {
+		select {
+		case res := <-a.results:
+			if res.err != nil {
+				a.err = res.err
+				return
+			}
+
+			select {
+			case a.messages <- res.msg:
+			case <-a.stop:
+				return
+			}
+		case <-a.stop:
+			return
+		}
+	}
+}
+
+// Messages returns the channel on which the read messages are delivered.
+// It is closed when the reading stops.
+func (a *Async) Messages() <-chan midi.Message {
+	return a.messages
+}
+
+// Err returns the error that stopped the reading (e.g. io.EOF). It returns nil
+// if the reading has been stopped by Close.
+// Err blocks until the channel returned by Messages has been closed.
+func (a *Async) Err() error {
+	<-a.done
+	return a.err
+}
+
+// Close stops the delivery of messages and closes the channel returned by Messages.
+// Since a blocking read of the underlying io.Reader can't be interrupted, the reading goroutine
+// keeps running until the io.Reader returns, but its result is discarded.
+// Close makes no attempt to close the underlying io.Reader.
+func (a *Async) Close() error {
+	a.once.Do(func() {
+		close(a.stop)
+	})
+	<-a.done
+	return nil
+}
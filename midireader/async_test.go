@@ -0,0 +1,72 @@
+package midireader
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+func TestAsync(t *testing.T) {
+	var (
+		bf   bytes.Buffer
+		rt   []realtime.Message
+		rtMx sync.Mutex
+	)
+
+	rtCallBack := func(m realtime.Message) {
+		rtMx.Lock()
+		rt = append(rt, m)
+		rtMx.Unlock()
+	}
+
+	a := NewAsync(mkMIDI(), rtCallBack)
+
+	bf.WriteString("\n")
+
+	for msg := range a.Messages() {
+		bf.WriteString(msg.String() + "\n")
+	}
+
+	if err := a.Err(); err != io.EOF {
+		t.Errorf("Err() = %v; wanted io.EOF", err)
+	}
+
+	expected := `
+channel.NoteOn channel 1 key 65 velocity 100
+sysex.SysEx len: 1
+channel.NoteOff channel 1 key 65
+syscommon.Tune
+channel.NoteOn channel 2 key 62 velocity 30
+sysex.SysEx len: 2
+channel.NoteOff channel 2 key 62
+`
+	if got, wanted := bf.String(), expected; got != wanted {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, wanted)
+	}
+
+	rtMx.Lock()
+	defer rtMx.Unlock()
+
+	if len(rt) != 1 || rt[0] != realtime.Start {
+		t.Errorf("got realtime messages %v; wanted [Start]", rt)
+	}
+}
+
+func TestAsyncClose(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	a := NewAsync(pr, nil)
+	a.Close()
+
+	if _, open := <-a.Messages(); open {
+		t.Errorf("expected closed channel")
+	}
+
+	if err := a.Err(); err != nil {
+		t.Errorf("Err() = %v; wanted nil", err)
+	}
+}
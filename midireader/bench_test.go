@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/sysex"
 	"github.com/gomidi/midi/midiwriter"
 )
 
@@ -117,3 +118,67 @@ func BenchmarkNoteOnOffAlternatingChannel1000(b *testing.B) {
 	}
 
 }
+
+// BenchmarkNoteOnOffNotInterned1000 reads 1000 channel messages per iteration
+// like BenchmarkNoteOnOffAlternatingChannel1000, but with interning switched off.
+func BenchmarkNoteOnOffNotInterned1000(b *testing.B) {
+	b.StopTimer()
+
+	src := alternatingChannel()
+	rd := New(src, nil, Intern(nil))
+
+	var err error
+
+	b.StartTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 1000; j++ {
+			_, err = rd.Read()
+			if err != nil {
+				b.Fatalf("Error: %v", err)
+			}
+		}
+	}
+
+}
+
+func TestZeroAllocs(t *testing.T) {
+	var channelMsgs, sysexMsgs bytes.Buffer
+
+	wr := midiwriter.New(&channelMsgs)
+	wr.Write(channel.Channel1.NoteOn(20, 100))
+	wr.Write(channel.Channel4.NoteOn(23, 70))
+	wr.Write(channel.Channel1.NoteOff(20))
+	wr.Write(channel.Channel4.NoteOff(23))
+
+	midiwriter.New(&sysexMsgs).Write(sysex.SysEx([]byte{0x41, 0x10, 0x42, 0x12, 0x40, 0x00, 0x7F, 0x00, 0x41}))
+
+	tests := []struct {
+		input     []byte
+		n         int
+		maxAllocs float64
+	}{
+		{channelMsgs.Bytes(), 4, 0},
+		// the sysex data is not copied, but the sysex.SysEx is boxed into a midi.Message
+		{sysexMsgs.Bytes(), 1, 1},
+	}
+
+	for i, test := range tests {
+		rd := New(&testreader{0, test.input}, nil, ReuseSysEx())
+
+		read := func() {
+			for j := 0; j < test.n; j++ {
+				if _, err := rd.Read(); err != nil {
+					t.Fatalf("Error: %v", err)
+				}
+			}
+		}
+
+		// fill the intern table and the sysex buffer
+		read()
+
+		if allocs := testing.AllocsPerRun(100, read); allocs > test.maxAllocs {
+			t.Errorf("[%v] got %v allocations; wanted at most %v", i, allocs, test.maxAllocs)
+		}
+	}
+}
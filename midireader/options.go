@@ -1,5 +1,12 @@
 package midireader
 
+import (
+	"time"
+
+	"github.com/gomidi/midi/intern"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
 // Option is a configuration option for a reader
 type Option func(rd *reader)
 
@@ -15,3 +22,148 @@ func NoteOffVelocity() Option {
 		rd.readNoteOffPedantic = true
 	}
 }
+
+// ChannelModes is an option for the reader that returns the control change messages of the controllers 120-127
+// (All Sound Off, Reset All Controllers, Local Control, All Notes Off and the Omni/Mono/Poly modes) as channel.ChannelMode messages.
+// If this option is not set, they are returned as channel.ControlChange messages (default).
+func ChannelModes() Option {
+	return func(rd *reader) {
+		rd.readChannelModes = true
+	}
+}
+
+// Timestamps is an option for the reader that lets it capture the time when the first byte
+// of a message (the status byte or the first data byte in case of running status) arrived.
+// The captured time can be retrieved after each call of Read via the Time method of the
+// Timestamped interface. Since time.Now is used, the captured time contains a monotonic clock reading
+// that can be used to calculate accurate durations between messages.
+func Timestamps() Option {
+	return func(rd *reader) {
+		rd.now = time.Now
+	}
+}
+
+// ValidateSysEx is an option for the reader that runs the validators registered for the manufacturer
+// of each received complete sysex. Such sysex are returned as sysex.Checked messages that
+// tell if they are verified or corrupt. Sysex of manufacturers without validators are returned as sysex.SysEx.
+func ValidateSysEx(v *sysex.Validators) Option {
+	return func(rd *reader) {
+		rd.sysexValidators = v
+	}
+}
+
+// SysExHeader is an option for the reader that calls fn as soon as the header of a sysex
+// (the manufacturer ID and for universal sysex the device and the sub-IDs) has been received, so that
+// the application may decide early whether to keep it.
+// If fn returns false, the rest of the sysex is discarded without buffering and Read continues with the next message.
+// For sysex that end before their header is complete, fn is called with the partial header at the end.
+func SysExHeader(fn func(h sysex.Header) (keep bool)) Option {
+	return func(rd *reader) {
+		rd.sysexHeader = fn
+	}
+}
+
+// Intern is an option for the reader that returns the shared instances of the given table for channel messages
+// that are in the table, which saves allocations for frequently repeated messages. Other channel messages are added to the table.
+// Without this option, each reader uses its own table of intern.DefaultMax messages. A table may be shared by several readers,
+// if they use the same NoteOffVelocity and ChannelModes options.
+// Intern(nil) switches interning off, so that every channel message is allocated.
+func Intern(t *intern.Table) Option {
+	return func(rd *reader) {
+		rd.intern = t
+		rd.internSet = true
+	}
+}
+
+// Coalesce is an option for the reader that protects slow consumers from floods of pitch bend and aftertouch (channel pressure) messages:
+// When such a message has been read and the source has more data buffered, the following messages of the same type and channel
+// are skipped and only the latest is returned. The number of skipped messages can be retrieved via the Skipped method of the
+// Coalescing interface.
+// The source must report its buffered data via a Buffered method (like *bufio.Reader) or a Len method (like *bytes.Buffer),
+// otherwise no message is skipped. Since the buffered data is checked before a message is read, Read might wait for
+// the next message if only realtime messages are buffered.
+func Coalesce() Option {
+	return func(rd *reader) {
+		rd.coalesce = true
+	}
+}
+
+// PatchChanges is an option for the reader that returns the Bank Select controllers (0 and 32) that are followed by a
+// program change of the same channel as one channel.PatchChange message. Either of the bank select controllers may be missing,
+// a program change without bank select is returned as channel.ProgramChange.
+// Since the reader has to wait for the next message to know if a bank select belongs to a patch change,
+// a bank select that is not followed by a program change is returned when the next message arrives.
+// The time of a patch change is the time of its first message and its wire bytes are those of all of its messages.
+func PatchChanges() Option {
+	return func(rd *reader) {
+		rd.patchChanges = true
+	}
+}
+
+// ResetState is an option for the reader that clears the running status when a System Reset (0xFF) is received
+// and calls the given functions, e.g. the Reset methods of the trackers of the state package that must not survive a reset.
+// The functions are called before the Reset message is passed to the realtime handler.
+func ResetState(fns ...func()) Option {
+	return func(rd *reader) {
+		rd.resetState = true
+		rd.onReset = append(rd.onReset, fns...)
+	}
+}
+
+// Undefined is an option for the reader that returns the undefined system common messages (status 0xF4 and 0xF5, and 0xF7 without sysex)
+// and data bytes without status as syscommon.Unknown messages with all their data bytes, instead of discarding them.
+// This is meant for the interoperation with non-conformant devices and for diagnostic tools.
+// Since the data bytes are read until the next status byte, the message is returned when that byte arrives.
+// The Parser returns the undefined system common messages without data bytes and ignores data bytes without status.
+// The undefined realtime messages 0xF9 and 0xFD are always passed to the realtime handler (as realtime.Tick and realtime.Undefined4).
+func Undefined() Option {
+	return func(rd *reader) {
+		rd.undefined = true
+	}
+}
+
+// WireBytes is an option for the reader that records the bytes of each message as they were received.
+// They are returned by the WireBytes method (see WireReader).
+func WireBytes() Option {
+	return func(rd *reader) {
+		rd.wire = &wireRecorder{}
+	}
+}
+
+// MaxSysEx is an option for the reader that limits the size of sysex messages to the given number of data bytes.
+// The data of larger sysex is discarded and a *midi.ReadError with the cause midi.ErrSysExTooLarge and the offset of the
+// start of the sysex is returned when the sysex ends. The error is not fatal: the next read returns the next message.
+func MaxSysEx(size int) Option {
+	return func(rd *reader) {
+		rd.maxSysEx = size
+	}
+}
+
+// Strict is an option for the reader that returns a *midi.ReadError for data bytes without status
+// (cause midi.ErrDataWithoutStatus) and for undefined status bytes, including an 0xF7 without sysex (cause midi.ErrInvalidStatus, unless the Undefined option is set),
+// instead of skipping them. The error is not fatal: the next read returns the next message.
+// Use it for debugging hardware, when every deviation matters.
+func Strict() Option {
+	return func(rd *reader) {
+		rd.strict = true
+	}
+}
+
+// OnSkip is an option for the reader that calls fn with the bytes that are skipped in the default (tolerant) mode
+// and the offset of their first byte in the stream, so that skipping is visible without interrupting the reading.
+// Realtime messages between the skipped bytes are passed to the realtime handler as usual.
+func OnSkip(fn func(skipped []byte, offset int64)) Option {
+	return func(rd *reader) {
+		rd.onSkip = fn
+	}
+}
+
+// ReuseSysEx is an option for the reader that reads all sysex messages into the same buffer, which saves the
+// allocations for each sysex. The data of a returned sysex.SysEx is only valid until the next read; copy it to keep it.
+// Together with the Intern option, reading channel and sysex messages performs no allocations once the table
+// and the buffer have grown to their working size.
+func ReuseSysEx() Option {
+	return func(rd *reader) {
+		rd.reuseSysEx = true
+	}
+}
@@ -0,0 +1,320 @@
+package midireader
+
+import (
+	"io"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/internal/midilib"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+// Parser is a push parser that processes one byte per call without blocking.
+// It is intended for interrupt driven code (e.g. the receive interrupt of an UART) or select loops,
+// where the pull semantics of io.Reader don't fit.
+// The parser handles running status and returns realtime messages as soon as their byte arrives, without
+// disturbing the message that is currently received.
+// It must not be used concurrently.
+type Parser struct {
+	status byte // the current status, 0 if none
+	need   int  // number of data bytes that are needed for the message of status
+	n      int  // number of data bytes that have been received
+	data   [2]byte
+
+	inSysEx    bool
+	discard    bool // the sysex is discarded by the sysex header callback
+	headerDone bool
+	sysex      []byte
+
+	channelReader channel.Reader
+	src           dataSource
+	cfg           reader
+
+	// pending is a second message that has been completed by the last byte (a message that aborted a sysex)
+	pending midi.Message
+
+	// queue holds the completed messages that have not yet been returned by Feed
+	queue []midi.Message
+
+	// copySysEx is set while FeedBytes collects the messages, so that the sysex buffer is not shared by them
+	copySysEx bool
+}
+
+// NewParser returns a new push parser.
+// Of the options, NoteOffVelocity, ChannelModes, ValidateSysEx, SysExHeader, ResetState, Undefined, MaxSysEx and ReuseSysEx are supported,
+// while the others are ignored.
+// With ResetState, a System Reset also discards a partially received message.
+// With MaxSysEx, the buffer for the sysex data is allocated once with the given size and larger sysex are discarded.
+// With ReuseSysEx, the data of a sysex returned by Feed is only valid until the next call of Feed (FeedBytes returns copies).
+// Both options together let the parser receive sysex without allocating their data.
+func NewParser(options ...Option) *Parser {
+	p := &Parser{}
+
+	for _, opt := range options {
+		opt(&p.cfg)
+	}
+
+	if p.cfg.maxSysEx > 0 {
+		p.sysex = make([]byte, 0, p.cfg.maxSysEx)
+	}
+
+	p.channelReader = channel.NewReader(&p.src, p.cfg.channelOptions()...)
+
+	return p
+}
+
+// Feed processes the next byte. It returns the message and true, if the byte completed a message.
+// Bytes that don't belong to any message (e.g. data bytes without status) are ignored.
+// If a byte completes two messages (e.g. a tune request that aborts a sysex), the second one is kept and returned by Pending
+// or else by the next call of Feed, before the message that is completed by that call.
+func (p *Parser) Feed(b byte) (msg midi.Message, ok bool) {
+	if m, completed := p.feed(b); completed {
+		p.queue = append(p.queue, m)
+	}
+
+	if p.pending != nil {
+		p.queue = append(p.queue, p.pending)
+		p.pending = nil
+	}
+
+	return p.Pending()
+}
+
+// Pending returns the next message that has been completed but not yet returned by Feed and true, or false if there is none.
+// To get every message as soon as it is completed, call Pending after each Feed that returned a message until it returns false:
+//
+//	for msg, ok := p.Feed(b); ok; msg, ok = p.Pending() {
+//		...
+//	}
+func (p *Parser) Pending() (msg midi.Message, ok bool) {
+	if len(p.queue) == 0 {
+		return nil, false
+	}
+
+	msg = p.queue[0]
+	n := copy(p.queue, p.queue[1:])
+	p.queue[n] = nil
+	p.queue = p.queue[:n]
+	return msg, true
+}
+
+// FeedBytes processes the given bytes and returns the messages that have been completed by them, in the order of completion.
+// The bytes may start and end anywhere within a message, since the state is kept between the calls. That allows to pass
+// whatever a non-blocking source (e.g. a serial port or a network socket) returns.
+func (p *Parser) FeedBytes(data []byte) (msgs []midi.Message) {
+	msgs = append(msgs, p.queue...)
+	for i := range p.queue {
+		p.queue[i] = nil
+	}
+	p.queue = p.queue[:0]
+
+	p.copySysEx = true
+	defer func() { p.copySysEx = false }()
+
+	for _, b := range data {
+		if msg, ok := p.feed(b); ok {
+			msgs = append(msgs, msg)
+		}
+
+		if p.pending != nil {
+			msgs = append(msgs, p.pending)
+			p.pending = nil
+		}
+	}
+	return
+}
+
+func (p *Parser) feed(b byte) (msg midi.Message, ok bool) {
+	// realtime messages may appear anywhere
+	if b >= 0xF8 {
+		if b == 0xFF && p.cfg.resetState {
+			p.status, p.n, p.need = 0, 0, 0
+			p.inSysEx, p.discard, p.sysex = false, false, p.sysex[:0]
+			p.cfg.reset()
+		}
+		if rt := realtimeMessage(b); rt != nil {
+			return rt, true
+		}
+		return nil, false
+	}
+
+	if !midilib.IsStatusByte(b) {
+		return p.feedData(b)
+	}
+
+	// any status byte ends a sysex
+	if p.inSysEx {
+		p.inSysEx = false
+		msg, ok = p.sysexMessage()
+	}
+
+	p.status, p.n, p.need = 0, 0, 0
+
+	switch {
+	case b == 0xF0:
+		p.inSysEx = true
+		p.discard = false
+		p.headerDone = p.cfg.sysexHeader == nil
+		p.sysex = p.sysex[:0]
+	case b == 0xF6:
+		if ok {
+			p.pending = syscommon.Tune
+			return
+		}
+		return syscommon.Tune, true
+	case b == 0xF1 || b == 0xF3:
+		p.status, p.need = b, 1
+	case b == 0xF2:
+		p.status, p.need = b, 2
+	case b < 0xF0:
+		p.status, p.need = b, 2
+		if typ := b >> 4; typ == 0xC || typ == 0xD {
+			p.need = 1
+		}
+	}
+
+	// the undefined 0xF4 and 0xF5 are returned with the Undefined option
+	if (b == 0xF4 || b == 0xF5) && p.cfg.undefined {
+		if ok {
+			p.pending = syscommon.Unknown{Status: b}
+			return
+		}
+		return syscommon.Unknown{Status: b}, true
+	}
+
+	// 0xF7 and the undefined 0xF4 and 0xF5 just end the running status
+	return
+}
+
+func (p *Parser) feedData(b byte) (msg midi.Message, ok bool) {
+	if p.inSysEx {
+		if p.discard {
+			return
+		}
+
+		// the sysex is too large for the buffer of the given size
+		if p.cfg.maxSysEx > 0 && len(p.sysex) == p.cfg.maxSysEx {
+			p.discard, p.headerDone = true, true
+			p.sysex = p.sysex[:0]
+			return
+		}
+
+		p.sysex = append(p.sysex, b)
+
+		if !p.headerDone {
+			if h, complete := sysex.ParseHeader(p.sysex); complete {
+				p.headerDone = true
+				p.discard = !p.cfg.sysexHeader(h)
+			}
+		}
+		return
+	}
+
+	// no status: ignore
+	if p.status == 0 {
+		return
+	}
+
+	p.data[p.n] = b
+	p.n++
+
+	if p.n < p.need {
+		return
+	}
+
+	p.n = 0
+
+	var err error
+
+	if p.status >= 0xF0 {
+		// system common messages cancel the running status
+		p.src.set(p.data[:p.need])
+		msg, err = syscommon.NewReader(&p.src, p.status).Read()
+		p.status = 0
+	} else {
+		// the channel reader gets the first data byte and reads the second one from src
+		p.src.set(p.data[1:p.need])
+		msg, err = p.channelReader.Read(p.status, p.data[0])
+	}
+
+	if err != nil || msg == nil {
+		return nil, false
+	}
+
+	return msg, true
+}
+
+// sysexMessage returns the sysex that has been received
+func (p *Parser) sysexMessage() (midi.Message, bool) {
+	if !p.headerDone {
+		h, _ := sysex.ParseHeader(p.sysex)
+		p.discard = !p.cfg.sysexHeader(h)
+	}
+
+	if p.discard {
+		return nil, false
+	}
+
+	s := sysex.SysEx(p.sysex)
+
+	if !p.cfg.reuseSysEx || p.copySysEx {
+		s = append(sysex.SysEx(nil), p.sysex...)
+	}
+
+	if p.cfg.sysexValidators != nil {
+		return p.cfg.sysexValidators.Check(s), true
+	}
+	return s, true
+}
+
+func realtimeMessage(b byte) midi.Message {
+	switch b {
+	case 0xF8:
+		return realtime.TimingClock
+	case 0xF9:
+		return realtime.Tick
+	case 0xFA:
+		return realtime.Start
+	case 0xFB:
+		return realtime.Continue
+	case 0xFC:
+		return realtime.Stop
+	case 0xFD:
+		return realtime.Undefined4
+	case 0xFE:
+		return realtime.Activesense
+	case 0xFF:
+		return realtime.Reset
+	}
+	return nil
+}
+
+// dataSource is an io.Reader that returns the data bytes of a message that has been received by the parser
+type dataSource struct {
+	data []byte
+}
+
+func (d *dataSource) set(data []byte) {
+	d.data = data
+}
+
+// ReadByte implements io.ByteReader
+func (d *dataSource) ReadByte() (b byte, err error) {
+	if len(d.data) == 0 {
+		return 0, io.EOF
+	}
+	b, d.data = d.data[0], d.data[1:]
+	return
+}
+
+// Read implements io.Reader
+func (d *dataSource) Read(b []byte) (n int, err error) {
+	n = copy(b, d.data)
+	d.data = d.data[n:]
+	if n == 0 && len(b) > 0 {
+		return 0, io.EOF
+	}
+	return
+}
@@ -0,0 +1,216 @@
+package midireader
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+func TestParser(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel1.NoteOn(65, 100))
+	// running status, interrupted by a realtime message
+	in.Write([]byte{66, 0xF8, 100})
+	wr.Write(channel.Channel1.NoteOffVelocity(65, 64))
+	wr.Write(channel.Channel1.ProgramChange(3))
+	in.Write([]byte{4})
+	wr.Write(syscommon.SPP(200))
+	// data byte without status
+	in.Write([]byte{20})
+	wr.Write(sysex.SysEx([]byte{0x43, 0x10, 0x4C}))
+	wr.Write(syscommon.Tune)
+	// undefined
+	in.Write([]byte{0xF5, 0x20})
+	wr.Write(channel.Channel2.Pitchbend(-20))
+	// sysex aborted by a status
+	in.Write([]byte{0xF0, 0x41, 0x10})
+	wr.Write(channel.Channel2.NoteOn(62, 0))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	p := NewParser(NoteOffVelocity())
+
+	for _, b := range in.Bytes() {
+		msg, ok := p.Feed(b)
+		if ok {
+			out.WriteString(msg.String() + "\n")
+		}
+	}
+
+	expected := `
+channel.NoteOn channel 1 key 65 velocity 100
+TimingClock
+channel.NoteOn channel 1 key 66 velocity 100
+channel.NoteOffVelocity channel 1 key 65 velocity 64
+channel.ProgramChange channel 1 program 3
+channel.ProgramChange channel 1 program 4
+syscommon.SPP: 200
+sysex.SysEx len: 3
+syscommon.Tune
+channel.Pitchbend channel 2 value -20 absValue 8172
+sysex.SysEx len: 2
+channel.NoteOff channel 2 key 62
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestParserRealtime(t *testing.T) {
+	p := NewParser()
+
+	if msg, ok := p.Feed(0xFA); !ok || msg != realtime.Start {
+		t.Errorf("Feed(0xFA) = %v, %v; wanted %v, true", msg, ok, realtime.Start)
+	}
+}
+
+func TestParserResetState(t *testing.T) {
+	resets := 0
+	p := NewParser(ResetState(func() { resets++ }))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for _, b := range []byte{0x91, 0x3C, 0xFF, 0x64, 0x3E, 0x64, 0x91, 0x40, 0x64} {
+		if msg, ok := p.Feed(b); ok {
+			fmt.Fprintf(&out, "%s\n", msg)
+		}
+	}
+
+	expected := `
+Reset
+channel.NoteOn channel 1 key 64 velocity 100
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if resets != 1 {
+		t.Errorf("resets = %v; wanted 1", resets)
+	}
+}
+
+func TestParserFeedBytes(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel1.NoteOn(65, 100))
+	in.Write([]byte{66, 0xF8, 100})
+	// sysex aborted by a tune request
+	in.Write([]byte{0xF0, 0x41, 0x10, 0xF6})
+	wr.Write(channel.Channel2.Pitchbend(-20))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	p := NewParser()
+	data := in.Bytes()
+
+	// arbitrary boundaries, as they are returned by non-blocking reads
+	for _, chunk := range [][]byte{data[:2], data[2:4], data[4:9], data[9:]} {
+		for _, msg := range p.FeedBytes(chunk) {
+			out.WriteString(msg.String() + "\n")
+		}
+	}
+
+	expected := `
+channel.NoteOn channel 1 key 65 velocity 100
+TimingClock
+channel.NoteOn channel 1 key 66 velocity 100
+sysex.SysEx len: 2
+syscommon.Tune
+channel.Pitchbend channel 2 value -20 absValue 8172
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestParserSecondMessage(t *testing.T) {
+	p := NewParser()
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	// sysex aborted by a tune request, followed by a realtime message
+	in := []byte{0xF0, 0x41, 0x10, 0xF6, 0xF8, 0xFA}
+
+	// without Pending, the tune request delays the following messages
+	for _, b := range in {
+		if msg, ok := p.Feed(b); ok {
+			fmt.Fprintf(&out, "%s\n", msg)
+		}
+	}
+
+	out.WriteString("--\n")
+
+	for _, b := range in {
+		for msg, ok := p.Feed(b); ok; msg, ok = p.Pending() {
+			fmt.Fprintf(&out, "%s\n", msg)
+		}
+	}
+
+	expected := `
+sysex.SysEx len: 2
+syscommon.Tune
+TimingClock
+--
+Start
+sysex.SysEx len: 2
+syscommon.Tune
+TimingClock
+Start
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestParserSysExBuffer(t *testing.T) {
+	p := NewParser(MaxSysEx(4), ReuseSysEx())
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	// the second sysex is too large
+	for _, b := range []byte{0xF0, 0x41, 0x10, 0x42, 0xF7, 0xF0, 0x41, 0x10, 0x42, 0x12, 0x40, 0xF7, 0xF0, 0x43, 0xF7} {
+		if msg, ok := p.Feed(b); ok {
+			fmt.Fprintf(&out, "% X\n", msg.Raw())
+		}
+	}
+
+	expected := `
+F0 41 10 42 F7
+F0 43 F7
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	in := []byte{0xF0, 0x41, 0x10, 0x42, 0x12, 0xF7}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		for _, b := range in {
+			p.Feed(b)
+		}
+	})
+
+	// the sysex is returned within an interface, which needs an allocation
+	if allocs > 1 {
+		t.Errorf("got %v allocations; wanted at most 1", allocs)
+	}
+}
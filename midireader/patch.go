@@ -0,0 +1,83 @@
+package midireader
+
+import (
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// readPatchChange reads the next message and combines bank select controllers with a following program change (see PatchChanges)
+func (r *reader) readPatchChange() (msg midi.Message, err error) {
+	msg, err = r.readQueued()
+
+	first, is := bankSelect(msg)
+	if err != nil || !is {
+		return
+	}
+
+	held := []lookahead{r.current(msg, err)}
+	bank := []channel.ControlChange{first}
+
+	for {
+		next, nerr := r.readQueued()
+
+		if nerr == nil {
+			if cc, is := bankSelect(next); is && cc.Channel() == first.Channel() && len(bank) == 1 && cc.Controller() != first.Controller() {
+				held = append(held, r.current(next, nerr))
+				bank = append(bank, cc)
+				continue
+			}
+
+			if pc, is := next.(channel.ProgramChange); is && pc.Channel() == first.Channel() {
+				if r.wire != nil {
+					var wire []byte
+					for _, h := range held {
+						wire = append(wire, h.wire...)
+					}
+					r.wireBytes = append(wire, r.wireBytes...)
+				}
+				r.time = held[0].time
+				return pc.WithBank(bank...), nil
+			}
+		}
+
+		// no patch change: return the first message and keep the others for the next reads
+		queue := append([]lookahead(nil), held[1:]...)
+		r.queue = append(append(queue, r.current(next, nerr)), r.queue...)
+		h := held[0]
+		r.time = h.time
+		if r.wire != nil {
+			r.wireBytes = append(r.wireBytes[:0], h.wire...)
+		}
+		return h.msg, h.err
+	}
+}
+
+// readQueued returns the next message that has been read ahead or reads the next message
+func (r *reader) readQueued() (msg midi.Message, err error) {
+	if len(r.queue) == 0 {
+		return r.readCoalesced()
+	}
+
+	q := r.queue[0]
+	r.queue = r.queue[1:]
+	r.time = q.time
+	if r.wire != nil {
+		r.wireBytes = append(r.wireBytes[:0], q.wire...)
+	}
+	return q.msg, q.err
+}
+
+// current returns the given message together with the time and the wire bytes of the message that has just been read
+func (r *reader) current(msg midi.Message, err error) lookahead {
+	l := lookahead{msg: msg, err: err, time: r.time}
+	if r.wire != nil {
+		l.wire = append([]byte(nil), r.wireBytes...)
+	}
+	return l
+}
+
+// bankSelect returns the message as control change, if it is a bank select controller (0 or 32)
+func bankSelect(msg midi.Message) (cc channel.ControlChange, is bool) {
+	cc, is = msg.(channel.ControlChange)
+	return cc, is && (cc.Controller() == 0 || cc.Controller() == 32)
+}
@@ -1,9 +1,12 @@
 package midireader
 
 import (
+	"context"
 	"io"
+	"time"
 
 	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/intern"
 	"github.com/gomidi/midi/internal/midilib"
 	"github.com/gomidi/midi/internal/runningstatus"
 	"github.com/gomidi/midi/midimessage/channel"
@@ -16,50 +19,421 @@ import (
 // When calling Read, any intermediate System Realtime Message will be either ignored (if rthandler is nil)
 // or passed to rthandler (if not) while other MIDI messages will be returned.
 //
+// Channel messages are returned as shared instances of a table of the reader (see the Intern option),
+// so that reading repeated channel messages does not allocate.
+//
 // The Reader does no buffering and makes no attempt to close src.
 // If src.Read returns an io.EOF, the reader stops reading and returns the error.
 func New(src io.Reader, rthandler func(realtime.Message), options ...Option) midi.Reader {
 	rd := &reader{
-		input:         realtime.NewReader(src, rthandler),
+		src:           src,
+		count:         &midilib.CountingReader{R: src},
 		runningStatus: runningstatus.NewLiveReader(),
 	}
 
+	rd.input = realtime.NewReader(rd.count, rthandler)
+
 	for _, opt := range options {
 		opt(rd)
 	}
 
-	if rd.readNoteOffPedantic {
-		rd.channelReader = channel.NewReader(rd.input, channel.ReadNoteOffVelocity())
-	} else {
-		rd.channelReader = channel.NewReader(rd.input)
+	if rd.resetState {
+		rd.input = realtime.NewReader(rd.count, rd.resetHandler(rthandler))
+	}
+
+	if rd.wire != nil {
+		rd.wire.input = rd.input
+		rd.input = rd.wire
+	}
+
+	// channel messages are interned by default, so that reading them does not allocate
+	if !rd.internSet {
+		rd.intern = intern.New(0)
+	}
+
+	chopts := rd.channelOptions()
+	rd.channelReader = channel.NewReader(rd.input, chopts...)
+
+	if rd.intern != nil {
+		rd.internReader = channel.NewReader(&rd.internSrc, chopts...)
 	}
 
 	return rd
 
 }
 
+// Timestamped is a midi.Reader that reports the time of arrival of the last message.
+// The readers returned by New implement it.
+type Timestamped interface {
+	midi.Reader
+
+	// Time returns the time when the first byte of the last message that has been read arrived.
+	// It returns the zero time if the Timestamps option has not been set.
+	Time() time.Time
+}
+
+var _ Timestamped = &reader{}
+
+// ContextReader is a midi.Reader that allows to cancel a pending read via a context.
+// The readers returned by New implement it.
+type ContextReader interface {
+	midi.Reader
+
+	// ReadContext reads the next MIDI message like Read but returns ctx.Err() as soon as ctx is done.
+	// Since the underlying io.Reader can't be interrupted, a pending read continues in the background.
+	// Its result is not lost, but returned by the next call of Read or ReadContext, which wait for it.
+	// Until then, Time, WireBytes and Skipped keep reporting the last message that has been returned.
+	ReadContext(ctx context.Context) (midi.Message, error)
+}
+
+var _ ContextReader = &reader{}
+
+// Coalescing is a midi.Reader that reports how many messages have been skipped because of the Coalesce option.
+// The readers returned by New implement it.
+type Coalescing interface {
+	midi.Reader
+
+	// Skipped returns the number of messages that have been skipped in favour of the last message.
+	Skipped() int
+}
+
+var _ Coalescing = &reader{}
+
+// WireReader is a midi.Reader that returns the exact bytes of the last message as they were received.
+// The readers returned by New implement it; the bytes are only recorded with the WireBytes option.
+type WireReader interface {
+	midi.Reader
+
+	// WireBytes returns the bytes of the last message that has been read as they were received, i.e. without
+	// the status byte for running status and without the realtime messages that were interleaved.
+	// While the Raw method of a message returns the canonical bytes of the message, WireBytes allows byte-perfect thru and
+	// recording and the debugging of decoder discrepancies.
+	// The returned slice is only valid until the next read. It is nil without the WireBytes option.
+	WireBytes() []byte
+}
+
+var _ WireReader = &reader{}
+
+type readResult struct {
+	msg midi.Message
+	err error
+}
+
 type reader struct {
-	input               realtime.Reader
+	src                 io.Reader
+	count               *midilib.CountingReader
+	input               io.Reader
 	runningStatus       runningstatus.Reader
 	channelReader       channel.Reader
 	readNoteOffPedantic bool
+	readChannelModes    bool
+	now                 func() time.Time
+	time                time.Time
+	pending             chan readResult
+	last                lastRead
+	lastWire            []byte
+	sysexValidators     *sysex.Validators
+	sysexHeader         func(sysex.Header) bool
+	spoolDir            string
+	spoolThreshold      int
+	intern              *intern.Table
+	internSet           bool
+	internReader        channel.Reader
+	internSrc           dataSource
+	internBuf           [1]byte
+	coalesce            bool
+	skipped             int
+	lookahead           *lookahead
+	resetState          bool
+	onReset             []func()
+	undefined           bool
+	unread              byte  // a status byte that has been read ahead, 0 if none
+	unreadOffset        int64 // the offset of unread
+	offset              int64 // the offset of the first byte of the current message
+	wire                *wireRecorder
+	wireBytes           []byte
+	wireCarry           byte // a status byte that has been read as part of the last message, but belongs to the next, 0 if none
+	maxSysEx            int
+	strict              bool
+	reuseSysEx          bool
+	sysexBuf            []byte
+	onSkip              func(skipped []byte, offset int64)
+	patchChanges        bool
+	queue               []lookahead // messages that have been read ahead while combining patch changes
+}
+
+// channelOptions returns the options for the channel reader
+func (r *reader) channelOptions() (opts []channel.ReaderOption) {
+	if r.readNoteOffPedantic {
+		opts = append(opts, channel.ReadNoteOffVelocity())
+	}
+	if r.readChannelModes {
+		opts = append(opts, channel.ReadChannelModes())
+	}
+	return
+}
+
+// resetHandler returns a realtime handler that resets the state on a System Reset before calling next (if not nil)
+func (r *reader) resetHandler(next func(realtime.Message)) func(realtime.Message) {
+	return func(msg realtime.Message) {
+		if msg == realtime.Reset {
+			r.runningStatus.Reset()
+			r.reset()
+		}
+		if next != nil {
+			next(msg)
+		}
+	}
+}
+
+// reset calls the functions of the ResetState option
+func (r *reader) reset() {
+	for _, fn := range r.onReset {
+		fn()
+	}
+}
+
+// Time returns the time of arrival of the last message.
+func (r *reader) Time() time.Time {
+	return r.last.time
 }
 
 // Read reads the next MIDI mesage.
+// If a previous call of ReadContext has been canceled, the result of its pending read is returned.
 func (r *reader) Read() (msg midi.Message, err error) {
+	if r.pending != nil {
+		res := <-r.pending
+		r.pending = nil
+		r.publish()
+		return res.msg, res.err
+	}
+	msg, err = r.read()
+	r.publish()
+	return
+}
+
+// ReadContext reads the next MIDI message or returns ctx.Err() if ctx is done before.
+// The pending read of a canceled call continues in a goroutine that owns the state of the reader until it is finished.
+// Meanwhile Time, WireBytes and Skipped report the last message that has been returned.
+func (r *reader) ReadContext(ctx context.Context) (msg midi.Message, err error) {
+	if r.pending == nil {
+		// the goroutine reuses the buffer of the wire bytes
+		if r.last.wire != nil {
+			r.lastWire = append(r.lastWire[:0], r.last.wire...)
+			r.last.wire = r.lastWire
+		}
+
+		r.pending = make(chan readResult, 1)
+		go func(ch chan<- readResult) {
+			m, e := r.read()
+			ch <- readResult{m, e}
+		}(r.pending)
+	}
+
+	select {
+	case res := <-r.pending:
+		r.pending = nil
+		r.publish()
+		return res.msg, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// publish makes the state of the last read available to Time, WireBytes and Skipped.
+// It must only be called, when no read is pending.
+func (r *reader) publish() {
+	r.last = lastRead{time: r.time, wire: r.wireBytes, skipped: r.skipped}
+}
+
+// lastRead is the state of the last message that has been returned
+type lastRead struct {
+	time    time.Time
+	wire    []byte
+	skipped int
+}
+
+// lookahead is a message that has been read ahead while coalescing
+type lookahead struct {
+	msg  midi.Message
+	err  error
+	time time.Time
+	wire []byte
+}
+
+// WireBytes returns the bytes of the last message as they were received (see WireReader)
+func (r *reader) WireBytes() []byte {
+	return r.last.wire
+}
+
+// Skipped returns the number of messages that have been skipped in favour of the last message, because of the Coalesce option.
+func (r *reader) Skipped() int {
+	return r.last.skipped
+}
+
+func (r *reader) read() (msg midi.Message, err error) {
+	if !r.patchChanges {
+		return r.readCoalesced()
+	}
+	return r.readPatchChange()
+}
+
+// readCoalesced reads the next message and coalesces it, if the Coalesce option is set
+func (r *reader) readCoalesced() (msg midi.Message, err error) {
+	if r.lookahead != nil {
+		msg, err, r.time = r.lookahead.msg, r.lookahead.err, r.lookahead.time
+		if r.wire != nil {
+			r.wireBytes = append(r.wireBytes[:0], r.lookahead.wire...)
+		}
+		r.lookahead = nil
+	} else {
+		msg, err = r.readNext()
+		r.keepWire()
+	}
+
+	r.skipped = 0
+
+	if !r.coalesce {
+		return
+	}
+
+	for err == nil && coalescable(msg) && r.buffered() {
+		t := r.time
+		next, nerr := r.readNext()
+
+		if nerr == nil && sameKind(msg, next) {
+			msg = next
+			r.skipped++
+			r.keepWire()
+			continue
+		}
+
+		r.lookahead = &lookahead{msg: next, err: nerr, time: r.time}
+		if r.wire != nil {
+			r.lookahead.wire = append([]byte(nil), r.wire.bytes...)
+		}
+		r.time = t
+		break
+	}
+
+	return
+}
+
+// coalescable returns true for the messages that are coalesced by the Coalesce option
+func coalescable(msg midi.Message) bool {
+	switch msg.(type) {
+	case channel.Pitchbend, channel.Aftertouch:
+		return true
+	}
+	return false
+}
+
+// sameKind returns true, if both messages are of the same type and channel
+func sameKind(a, b midi.Message) bool {
+	switch va := a.(type) {
+	case channel.Pitchbend:
+		vb, is := b.(channel.Pitchbend)
+		return is && va.Channel() == vb.Channel()
+	case channel.Aftertouch:
+		vb, is := b.(channel.Aftertouch)
+		return is && va.Channel() == vb.Channel()
+	}
+	return false
+}
+
+// buffered returns true, if the source reports that it has buffered data
+func (r *reader) buffered() bool {
+	switch b := r.src.(type) {
+	case interface{ Buffered() int }:
+		return b.Buffered() > 0
+	case interface{ Len() int }:
+		return b.Len() > 0
+	}
+	return false
+}
+
+func (r *reader) readNext() (msg midi.Message, err error) {
 	// read the canary in the coal mine to see, if we have a running status byte or a given one
 	var canary byte
-	canary, err = midilib.ReadByte(r.input)
+
+	if r.wire != nil {
+		r.wire.bytes = r.wire.bytes[:0]
+		if r.wireCarry != 0 {
+			r.wire.bytes = append(r.wire.bytes, r.wireCarry)
+			r.wireCarry = 0
+		}
+	}
+
+	if r.unread != 0 {
+		canary, r.unread = r.unread, 0
+		r.offset = r.unreadOffset
+	} else {
+		canary, err = midilib.ReadByte(r.input)
+		r.offset = r.count.N - 1
+	}
 
 	if err != nil {
 		return
 	}
 
+	r.stamp()
 	return r.readMsg(canary)
 }
 
-// discardUntilNextStatus discards every byte until the next status byte
-func (r *reader) discardUntilNextStatus() (canary byte, err error) {
+// unexpected returns a ReadError for the end of the input within a message; other errors are returned unchanged
+func (r *reader) unexpected(err error) error {
+	if err == io.EOF || err == midi.ErrUnexpectedEOF {
+		return &midi.ReadError{Err: midi.ErrUnexpectedEOF, Offset: r.count.N, Track: -1, Fatal: true}
+	}
+	return err
+}
+
+// keepWire keeps the recorded bytes of the message that has just been read
+func (r *reader) keepWire() {
+	if r.wire != nil {
+		r.wireBytes = append(r.wireBytes[:0], r.wire.bytes...)
+	}
+}
+
+// carryWire moves the status byte b, that has been recorded last, to the bytes of the next message
+func (r *reader) carryWire(b byte) {
+	if r.wire != nil && len(r.wire.bytes) > 0 {
+		r.wire.bytes = r.wire.bytes[:len(r.wire.bytes)-1]
+		r.wireCarry = b
+	}
+}
+
+// wireRecorder records the bytes that are read from the input (without realtime messages)
+type wireRecorder struct {
+	input io.Reader
+	bytes []byte
+}
+
+// Read reads from the input and records the bytes
+func (w *wireRecorder) Read(p []byte) (n int, err error) {
+	n, err = w.input.Read(p)
+	w.bytes = append(w.bytes, p[:n]...)
+	return
+}
+
+// ReadByte reads a byte from the input and records it
+func (w *wireRecorder) ReadByte() (b byte, err error) {
+	b, err = midilib.ReadByte(w.input)
+	if err == nil {
+		w.bytes = append(w.bytes, b)
+	}
+	return
+}
+
+// stamp captures the time of arrival, if the Timestamps option is set
+func (r *reader) stamp() {
+	if r.now != nil {
+		r.time = r.now()
+	}
+}
+
+// readUntilStatus appends every byte until the next status byte to bf. The status byte is kept for the next read.
+func (r *reader) readUntilStatus(bf []byte) ([]byte, error) {
 
 	//	A device should be able to "ignore" all MIDI messages that it doesn't use, including currently undefined MIDI messages
 	//	(ie Status is 0xF4, 0xF5, or 0xFD). In other words, a device is expected to be able to deal with all MIDI messages that it
@@ -69,17 +443,63 @@ func (r *reader) discardUntilNextStatus() (canary byte, err error) {
 	//	all data bytes (ie, bit #7 clear) up to the next received, non-RealTime Status byte.
 	//
 	for {
-		canary, err = midilib.ReadByte(r.input)
+		b, err := midilib.ReadByte(r.input)
 
 		if err != nil {
-			return
+			return bf, err
 		}
 
-		if midilib.IsStatusByte(canary) {
-			return
+		if midilib.IsStatusByte(b) {
+			r.unread = b
+			r.unreadOffset = r.count.N - 1
+			r.carryWire(b)
+			return bf, nil
 		}
+
+		bf = append(bf, b)
+	}
+}
+
+// skip skips the undefined message or data bytes without status that started with canary.
+// In strict mode, a ReadError is returned, otherwise the skipped bytes are reported and the next message is returned.
+func (r *reader) skip(canary byte) (midi.Message, error) {
+	offset := r.offset
+	skipped, err := r.readUntilStatus([]byte{canary})
+
+	if r.strict {
+		cause := midi.ErrInvalidStatus
+		if !midilib.IsStatusByte(canary) {
+			cause = midi.ErrDataWithoutStatus
+		}
+		return nil, &midi.ReadError{Err: cause, Offset: offset, Byte: canary, Track: -1}
+	}
+
+	if r.onSkip != nil {
+		r.onSkip(skipped, offset)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
+	// return the next message
+	return r.readNext()
+}
+
+// readUndefined reads an undefined message that started with canary (an undefined status or a data byte without status)
+// and its data bytes until the next status byte, that is kept for the next read.
+// An error while reading the data bytes ends the message; it is returned by the next read.
+func (r *reader) readUndefined(canary byte) (midi.Message, error) {
+	var u syscommon.Unknown
+
+	if midilib.IsStatusByte(canary) {
+		u.Status = canary
+		u.Data, _ = r.readUntilStatus(nil)
+	} else {
+		u.Data, _ = r.readUntilStatus([]byte{canary})
+	}
+
+	return u, nil
 }
 
 /*
@@ -95,9 +515,25 @@ func (r *reader) discardUntilNextStatus() (canary byte, err error) {
 
 // readSysEx reads a sysex
 // here we can ignore incomplete casio style messages (since they are only interrupted in time)
-func (r *reader) readSysEx() (sys sysex.SysEx, status byte, err error) {
+// if the sysex header callback rejects the sysex, the rest of it is discarded and discarded is true
+func (r *reader) readSysEx() (sys sysex.Message, status byte, discarded bool, err error) {
 	var b byte
 	var bf []byte
+	var sp *spool
+
+	if r.reuseSysEx {
+		bf = r.sysexBuf[:0]
+		defer func() {
+			if cap(bf) > cap(r.sysexBuf) {
+				r.sysexBuf = bf
+			}
+		}()
+	}
+
+	var size int
+	var tooLarge bool
+	start := r.count.N - 1
+	headerDone := r.sysexHeader == nil
 
 	// read byte by byte
 	for {
@@ -106,29 +542,117 @@ func (r *reader) readSysEx() (sys sysex.SysEx, status byte, err error) {
 			break
 		}
 
-		// the normal way to terminate
-		if b == byte(0xF7) {
-			sys = sysex.SysEx(bf)
+		// the normal way to terminate is 0xF7,
+		// the not so elegant way to terminate is by sending a new status
+		if b == byte(0xF7) || midilib.IsStatusByte(b) {
+			if !headerDone {
+				h, _ := sysex.ParseHeader(bf)
+				discarded = !r.sysexHeader(h)
+			}
+
+			switch {
+			case tooLarge:
+				err = &midi.ReadError{Err: midi.ErrSysExTooLarge, Offset: start, Byte: 0xF0, Track: -1}
+			case discarded:
+			case sp != nil:
+				sys, err = sp.finish()
+			default:
+				sys = sysex.SysEx(bf)
+			}
+
+			if b != byte(0xF7) {
+				status = b
+			}
 			return
 		}
 
-		// not so elegant way to terminate by sending a new status
-		if midilib.IsStatusByte(b) {
-			sys = sysex.SysEx(bf)
-			status = b
-			return
+		if discarded {
+			continue
+		}
+
+		size++
+
+		if r.maxSysEx > 0 && size > r.maxSysEx {
+			discarded, tooLarge, bf = true, true, nil
+			if sp != nil {
+				sp.remove()
+				sp = nil
+			}
+			continue
+		}
+
+		if sp != nil {
+			if err = sp.WriteByte(b); err != nil {
+				break
+			}
+			continue
 		}
 
 		bf = append(bf, b)
+
+		if !headerDone {
+			if h, complete := sysex.ParseHeader(bf); complete {
+				headerDone = true
+				if !r.sysexHeader(h) {
+					discarded = true
+					bf = nil
+				}
+			}
+		}
+
+		if r.spoolThreshold > 0 && !discarded && len(bf) > r.spoolThreshold {
+			sp, err = newSpool(r.spoolDir, bf)
+			if err != nil {
+				break
+			}
+			bf = nil
+		}
 	}
 
 	// any error, especially io.EOF is considered a failure.
 	// however return the sysex that had been received so far back to the user
 	// and leave him to decide what to do.
+	// A spooled sysex is not returned, since its file would be left behind: the file is removed.
+	if discarded {
+		return
+	}
+	if sp != nil {
+		sp.remove()
+		return
+	}
 	sys = sysex.SysEx(bf)
 	return
 }
 
+// readInterned reads the rest of a channel message and returns the shared instance, if it is in the intern table
+func (r *reader) readInterned(status, arg1 byte) (m midi.Message, err error) {
+	var n int
+	r.internBuf[0] = 0
+
+	// all channel messages but program change and aftertouch have two data bytes
+	if typ := status >> 4; typ != 0xC && typ != 0xD {
+		r.internBuf[0], err = midilib.ReadByte(r.input)
+		if err != nil {
+			return
+		}
+		n = 1
+	}
+
+	arg2 := r.internBuf[0]
+
+	if shared, found := r.intern.Lookup(status, arg1, arg2); found {
+		return shared, nil
+	}
+
+	r.internSrc.set(r.internBuf[:n])
+	m, err = r.internReader.Read(status, arg1)
+
+	if err == nil && m != nil {
+		r.intern.Store(status, arg1, arg2, m)
+	}
+	return
+}
+
 // readMsg reads the next MIDI message that started with canary
 func (r *reader) readMsg(canary byte) (m midi.Message, err error) {
 	status, changed := r.runningStatus.Read(canary)
@@ -144,7 +668,14 @@ func (r *reader) readMsg(canary byte) (m midi.Message, err error) {
 
 		/* start sysex */
 		case 0xF0:
-			m, status, err = r.readSysEx()
+			var sys sysex.Message
+			var discarded bool
+			sys, status, discarded, err = r.readSysEx()
+			m = sys
+
+			if s, is := sys.(sysex.SysEx); is && err == nil && r.sysexValidators != nil {
+				m = r.sysexValidators.Check(s)
+			}
 
 			// TODO check if that works
 			/*
@@ -155,15 +686,29 @@ func (r *reader) readMsg(canary byte) (m midi.Message, err error) {
 			*/
 			if status != 0 {
 				r.runningStatus.Read(status)
+				r.carryWire(status)
+			}
+
+			// the sysex has been rejected by the header callback: return the next message
+			if discarded && err == nil {
+				return r.readNext()
 			}
 
 		case 0xF7:
-			// we should never have a 0xF7 since sysex must already have consumed it
-			panic("unreachable")
+			// a 0xF7 without a preceding sysex (the sysex consumes its own 0xF7) is handled like an undefined status
+			if r.undefined {
+				return r.readUndefined(canary)
+			}
+			return r.skip(canary)
 
 		default:
 			// must be a system common message, but no sysex (0xF0 < canary < 0xF7)
+			// or data bytes without status
 			m, err = syscommon.NewReader(r.input, canary).Read()
+
+			if m == nil && err == nil && r.undefined {
+				return r.readUndefined(canary)
+			}
 		}
 
 	} else {
@@ -175,27 +720,27 @@ func (r *reader) readMsg(canary byte) (m midi.Message, err error) {
 		if changed {
 			arg1, err = midilib.ReadByte(r.input)
 			if err != nil {
+				err = r.unexpected(err)
 				return
 			}
 		}
 
 		// read the channel message
-		m, err = r.channelReader.Read(status, arg1)
+		if r.intern != nil {
+			m, err = r.readInterned(status, arg1)
+		} else {
+			m, err = r.channelReader.Read(status, arg1)
+		}
 	}
 
 	if err != nil {
+		err = r.unexpected(err)
 		return
 	}
 
 	// unknown event: read until next status byte
 	if m == nil {
-
-		canary, err = r.discardUntilNextStatus()
-		if err != nil {
-			return
-		}
-		// return the next message
-		return r.readMsg(canary)
+		return r.skip(canary)
 	}
 
 	return
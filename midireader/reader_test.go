@@ -2,9 +2,16 @@ package midireader
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/intern"
 	"github.com/gomidi/midi/midimessage/channel"
 	"github.com/gomidi/midi/midimessage/realtime"
 	"github.com/gomidi/midi/midimessage/syscommon"
@@ -99,3 +106,755 @@ channel.NoteOff channel 2 key 62
 	}
 
 }
+
+func TestReadTimestamps(t *testing.T) {
+	var bf bytes.Buffer
+
+	wr := midiwriter.New(&bf)
+	wr.Write(channel.Channel(1).NoteOn(65, 100))
+	wr.Write(channel.Channel(1).NoteOff(65))
+
+	rd := New(bytes.NewReader(bf.Bytes()), nil, Timestamps()).(Timestamped)
+
+	if got := rd.Time(); !got.IsZero() {
+		t.Errorf("expected zero time before reading, got %v", got)
+	}
+
+	start := time.Now()
+	var ticks time.Duration
+	rd.(*reader).now = func() time.Time {
+		ticks += time.Millisecond
+		return start.Add(ticks)
+	}
+
+	for i := 1; i <= 2; i++ {
+		_, err := rd.Read()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := rd.Time().Sub(start), time.Duration(i)*time.Millisecond; got != want {
+			t.Errorf("[%v] Time() = start + %v; wanted start + %v", i, got, want)
+		}
+	}
+}
+
+func TestReadContext(t *testing.T) {
+	pr, pw := io.Pipe()
+
+	rd := New(pr, nil).(ContextReader)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := rd.ReadContext(ctx)
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	go midiwriter.New(pw).Write(channel.Channel(1).NoteOn(65, 100))
+
+	msg, err := rd.ReadContext(context.Background())
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := msg.String(), "channel.NoteOn channel 1 key 65 velocity 100"; got != want {
+		t.Errorf("got %#v; wanted %#v", got, want)
+	}
+}
+
+func TestReadContextCancelThenRead(t *testing.T) {
+	pr, pw := io.Pipe()
+
+	rd := New(pr, nil, Timestamps(), WireBytes(), Coalesce())
+
+	wr := midiwriter.New(pw)
+	go wr.Write(channel.Channel(1).NoteOn(60, 100))
+
+	if _, err := rd.Read(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := rd.(Timestamped).Time()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := rd.(ContextReader).ReadContext(ctx); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// the pending read runs while the state of the last message is queried
+	done := make(chan struct{})
+	go func() {
+		wr.Write(channel.Channel(2).NoteOn(65, 100))
+		close(done)
+	}()
+
+	for i := 0; i < 100; i++ {
+		if got := rd.(Timestamped).Time(); !got.Equal(first) {
+			t.Fatalf("Time() = %v; wanted %v", got, first)
+		}
+		if got, want := fmt.Sprintf("% X", rd.(WireReader).WireBytes()), "91 3C 64"; got != want {
+			t.Fatalf("WireBytes() = %v; wanted %v", got, want)
+		}
+		rd.(Coalescing).Skipped()
+	}
+
+	msg, err := rd.Read()
+	<-done
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := msg.String(), "channel.NoteOn channel 2 key 65 velocity 100"; got != want {
+		t.Errorf("got %#v; wanted %#v", got, want)
+	}
+
+	if got, want := fmt.Sprintf("% X", rd.(WireReader).WireBytes()), "92 41 64"; got != want {
+		t.Errorf("WireBytes() = %v; wanted %v", got, want)
+	}
+
+	if rd.(Timestamped).Time().Before(first) {
+		t.Errorf("Time() must not go back")
+	}
+}
+
+func TestReadValidateSysEx(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	// Roland: manufacturer, device, model, command, address, data, checksum
+	wr.Write(sysex.SysEx([]byte{0x41, 0x10, 0x42, 0x12, 0x40, 0x00, 0x7F, 0x00, 0x41}))
+	wr.Write(sysex.SysEx([]byte{0x41, 0x10, 0x42, 0x12, 0x40, 0x00, 0x7F, 0x00, 0x40}))
+	// Yamaha: no validators
+	wr.Write(sysex.SysEx([]byte{0x43, 0x10, 0x4C}))
+
+	var v sysex.Validators
+	v.Register([]byte{0x41}, sysex.Checksum(4))
+
+	rd := New(&in, nil, ValidateSysEx(&v))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		out.WriteString(msg.String() + "\n")
+	}
+
+	expected := `
+sysex.Checked len: 9 verified
+sysex.Checked len: 9 corrupt: checksum mismatch: 0x40
+sysex.SysEx len: 3
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestReadSysExHeader(t *testing.T) {
+	var in bytes.Buffer
+
+	dump := make([]byte, 1000)
+	dump[0] = 0x43
+
+	wr := midiwriter.New(&in)
+	wr.Write(sysex.SysEx(dump))
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+	// universal non-realtime: identity request
+	wr.Write(sysex.SysEx([]byte{0x7E, 0x7F, 0x06, 0x01}))
+	wr.Write(sysex.SysEx([]byte{0x00, 0x20, 0x33, 0x01}))
+	wr.Write(sysex.SysEx([]byte{0x7E, 0x7F}))
+	wr.Write(channel.Channel1.NoteOff(60))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	keepUniversal := func(h sysex.Header) bool {
+		out.WriteString(h.String() + "\n")
+		return h.Universal()
+	}
+
+	rd := New(&in, nil, SysExHeader(keepUniversal))
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		out.WriteString(msg.String() + "\n")
+	}
+
+	expected := `
+sysex.Header manufacturer: 43
+channel.NoteOn channel 1 key 60 velocity 100
+sysex.Header manufacturer: 7E device: 127 subID1: 6 subID2: 1
+sysex.SysEx len: 4
+sysex.Header manufacturer: 00 20 33
+sysex.Header manufacturer: 7E device: 127 subID1: 0 subID2: 0
+sysex.SysEx len: 2
+channel.NoteOff channel 1 key 60
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestReadSysExHeaderCoalesce(t *testing.T) {
+	// a discarded sysex between coalescable messages
+	in := []byte{0xE0, 0x00, 0x40, 0xF0, 0x43, 0x10, 0xF7, 0xD0, 0x20, 0x90, 0x3C, 0x64, 0x80, 0x3C, 0x00}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	rd := New(bytes.NewReader(in), nil, Coalesce(), SysExHeader(func(h sysex.Header) bool { return false }))
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		out.WriteString(msg.String() + "\n")
+	}
+
+	expected := `
+channel.Pitchbend channel 0 value 0 absValue 8192
+channel.Aftertouch channel 0 pressure 32
+channel.NoteOn channel 0 key 60 velocity 100
+channel.NoteOff channel 0 key 60
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestReadIntern(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	for i := 0; i < 100; i++ {
+		wr.Write(channel.Channel1.ControlChange(7, 100))
+		wr.Write(channel.Channel1.ProgramChange(3))
+	}
+
+	data := in.Bytes()
+
+	allocs := func(opts ...Option) float64 {
+		return testing.AllocsPerRun(10, func() {
+			rd := New(bytes.NewReader(data), nil, opts...)
+			for {
+				if _, err := rd.Read(); err != nil {
+					break
+				}
+			}
+		})
+	}
+
+	table := intern.New(0)
+	plain, interned := allocs(Intern(nil)), allocs(Intern(table))
+
+	if interned >= plain {
+		t.Errorf("expected less allocations with interning: %v (interned) vs %v (plain)", interned, plain)
+	}
+
+	rd := New(bytes.NewReader(data), nil, Intern(table))
+	msg, _ := rd.Read()
+
+	if got, want := msg.String(), named(`channel.ControlChange channel 1 controller 7 ("Volume (MSB)") value 100`); got != want {
+		t.Errorf("got %q; wanted %q", got, want)
+	}
+
+	if got, want := table.Len(), 2; got != want {
+		t.Errorf("table.Len() = %v; wanted %v", got, want)
+	}
+}
+
+func TestReadCoalesce(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+	for i := int16(1); i <= 5; i++ {
+		wr.Write(channel.Channel1.Pitchbend(i * 100))
+	}
+	wr.Write(channel.Channel2.Pitchbend(-100))
+	for i := uint8(1); i <= 3; i++ {
+		wr.Write(channel.Channel1.Aftertouch(i * 10))
+	}
+	wr.Write(channel.Channel1.NoteOff(60))
+
+	rd := New(&in, nil, Coalesce())
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		fmt.Fprintf(&out, "%s (skipped %v)\n", msg, rd.(Coalescing).Skipped())
+	}
+
+	expected := `
+channel.NoteOn channel 1 key 60 velocity 100 (skipped 0)
+channel.Pitchbend channel 1 value 500 absValue 8692 (skipped 4)
+channel.Pitchbend channel 2 value -100 absValue 8092 (skipped 0)
+channel.Aftertouch channel 1 pressure 30 (skipped 2)
+channel.NoteOff channel 1 key 60 (skipped 0)
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestReadResetState(t *testing.T) {
+	// the second note uses running status across the reset, which a reset receiver must not accept
+	in := bytes.NewReader([]byte{0x91, 0x3C, 0x64, 0xFF, 0x3E, 0x64, 0x91, 0x40, 0x64})
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	resets := 0
+	rd := New(in, func(m realtime.Message) { fmt.Fprintf(&out, "%s (resets: %v)\n", m, resets) }, ResetState(func() { resets++ }))
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		fmt.Fprintf(&out, "%s\n", msg)
+	}
+
+	expected := `
+channel.NoteOn channel 1 key 60 velocity 100
+Reset (resets: 1)
+channel.NoteOn channel 1 key 64 velocity 100
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestReadUndefined(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+	wr.Write(syscommon.Unknown{Status: 0xF4})
+	in.Write([]byte{0x12, 0x13})
+	wr.Write(realtime.Tick)
+	wr.Write(syscommon.Unknown{Status: 0xF5})
+	wr.Write(realtime.Undefined4)
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+	wr.Write(syscommon.Tune)
+	in.Write([]byte{0x14})
+
+	for _, undefined := range []bool{false, true} {
+		var opts []Option
+		if undefined {
+			opts = append(opts, Undefined())
+		}
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		rd := New(bytes.NewReader(in.Bytes()), func(m realtime.Message) { fmt.Fprintf(&out, "%s\n", m) }, opts...)
+
+		for {
+			msg, err := rd.Read()
+
+			if err != nil {
+				break
+			}
+
+			fmt.Fprintf(&out, "%s\n", msg)
+		}
+
+		expected := `
+channel.NoteOn channel 1 key 60 velocity 100
+Tick
+Undefined4
+channel.NoteOn channel 1 key 60 velocity 100
+syscommon.Tune
+`
+
+		if undefined {
+			expected = `
+channel.NoteOn channel 1 key 60 velocity 100
+Tick
+syscommon.Unknown: F4 12 13
+Undefined4
+syscommon.Unknown: F5
+channel.NoteOn channel 1 key 60 velocity 100
+syscommon.Tune
+syscommon.Unknown: 14
+`
+		}
+
+		if got, want := out.String(), expected; got != want {
+			t.Errorf("[undefined: %v] got:\n%s\n\nwanted:\n%s\n\n", undefined, got, want)
+		}
+	}
+}
+
+func TestWireBytes(t *testing.T) {
+	in := []byte{
+		0x90, 0x3C, 0xF8, 0x64, // note on with interleaved clock
+		0x3C, 0x00, // running status
+		0xF0, 0x01, 0x02, // sysex aborted by a status
+		0x91, 0x3E, 0x64,
+		0xF4, 0x12, // undefined
+		0x3E, 0x00, // no running status after the undefined message: data of the undefined message
+		0x91, 0x3E, 0x00,
+	}
+
+	for _, undefined := range []bool{false, true} {
+		opts := []Option{WireBytes()}
+		if undefined {
+			opts = append(opts, Undefined())
+		}
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		rd := New(bytes.NewReader(in), nil, opts...)
+
+		for {
+			msg, err := rd.Read()
+
+			if err != nil {
+				break
+			}
+
+			fmt.Fprintf(&out, "% X | % X\n", rd.(WireReader).WireBytes(), msg.Raw())
+		}
+
+		expected := `
+90 3C 64 | 90 3C 64
+3C 00 | 90 3C 00
+F0 01 02 | F0 01 02 F7
+91 3E 64 | 91 3E 64
+91 3E 00 | 91 3E 00
+`
+
+		if undefined {
+			expected = `
+90 3C 64 | 90 3C 64
+3C 00 | 90 3C 00
+F0 01 02 | F0 01 02 F7
+91 3E 64 | 91 3E 64
+F4 12 3E 00 | F4 12 3E 00
+91 3E 00 | 91 3E 00
+`
+		}
+
+		if got, want := out.String(), expected; got != want {
+			t.Errorf("[undefined: %v] got:\n%s\n\nwanted:\n%s\n\n", undefined, got, want)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		input    []byte
+		opts     []Option
+		expected string
+	}{
+		{
+			// note on without velocity
+			[]byte{0x90, 0x3C, 0x64, 0x90, 0x3C},
+			nil,
+			`
+channel.NoteOn channel 0 key 60 velocity 100
+error: Unexpected End of File found. at offset 5 (fatal: true)
+`,
+		},
+		{
+			// too large sysex
+			[]byte{0x90, 0x3C, 0x64, 0xF0, 0x01, 0x02, 0x03, 0xF7, 0xF0, 0x01, 0xF7, 0x3C, 0x00},
+			[]Option{MaxSysEx(2)},
+			`
+channel.NoteOn channel 0 key 60 velocity 100
+error: sysex too large at offset 3 (byte 0xF0) (fatal: false)
+sysex.SysEx len: 1
+`,
+		},
+	}
+
+	for i, test := range tests {
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		rd := New(bytes.NewReader(test.input), nil, test.opts...)
+
+		for {
+			msg, err := rd.Read()
+
+			if err == io.EOF {
+				break
+			}
+
+			var rerr *midi.ReadError
+
+			if errors.As(err, &rerr) {
+				fmt.Fprintf(&out, "error: %v (fatal: %v)\n", err, rerr.Fatal)
+				if rerr.Fatal {
+					break
+				}
+				continue
+			}
+
+			if err != nil {
+				t.Fatalf("[%v] unexpected error: %v", i, err)
+			}
+
+			fmt.Fprintf(&out, "%s\n", msg)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	// data bytes after the tune request and an undefined message
+	in := []byte{0x90, 0x3C, 0x64, 0xF6, 0x12, 0x13, 0xF4, 0x15, 0x90, 0x3C, 0x00}
+
+	for _, strict := range []bool{false, true} {
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		opts := []Option{OnSkip(func(skipped []byte, offset int64) {
+			fmt.Fprintf(&out, "skipped % X at offset %v\n", skipped, offset)
+		})}
+
+		if strict {
+			opts = append(opts, Strict())
+		}
+
+		rd := New(bytes.NewReader(in), nil, opts...)
+
+		for {
+			msg, err := rd.Read()
+
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				fmt.Fprintf(&out, "error: %v\n", err)
+				continue
+			}
+
+			fmt.Fprintf(&out, "%s\n", msg)
+		}
+
+		expected := `
+channel.NoteOn channel 0 key 60 velocity 100
+syscommon.Tune
+skipped 12 13 at offset 4
+skipped F4 15 at offset 6
+channel.NoteOff channel 0 key 60
+`
+
+		if strict {
+			expected = `
+channel.NoteOn channel 0 key 60 velocity 100
+syscommon.Tune
+error: data byte without status at offset 4 (byte 0x12)
+error: invalid status byte at offset 6 (byte 0xF4)
+channel.NoteOff channel 0 key 60
+`
+		}
+
+		if got, want := out.String(), expected; got != want {
+			t.Errorf("[strict: %v] got:\n%s\n\nwanted:\n%s\n\n", strict, got, want)
+		}
+	}
+}
+
+func TestStrayEndOfExclusive(t *testing.T) {
+	// an end of exclusive without sysex, followed by a data byte
+	in := []byte{0x90, 0x3C, 0x64, 0xF7, 0x12, 0x90, 0x3C, 0x00}
+
+	tests := []struct {
+		opts     []Option
+		expected string
+	}{
+		{
+			nil,
+			`
+channel.NoteOn channel 0 key 60 velocity 100
+skipped F7 12 at offset 3
+channel.NoteOff channel 0 key 60
+`,
+		},
+		{
+			[]Option{Strict()},
+			`
+channel.NoteOn channel 0 key 60 velocity 100
+error: invalid status byte at offset 3 (byte 0xF7)
+channel.NoteOff channel 0 key 60
+`,
+		},
+		{
+			[]Option{Undefined()},
+			`
+channel.NoteOn channel 0 key 60 velocity 100
+syscommon.Unknown: F7 12
+channel.NoteOff channel 0 key 60
+`,
+		},
+	}
+
+	for i, test := range tests {
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		opts := append([]Option{OnSkip(func(skipped []byte, offset int64) {
+			fmt.Fprintf(&out, "skipped % X at offset %v\n", skipped, offset)
+		})}, test.opts...)
+
+		rd := New(bytes.NewReader(in), nil, opts...)
+
+		for {
+			msg, err := rd.Read()
+
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				fmt.Fprintf(&out, "error: %v\n", err)
+				continue
+			}
+
+			fmt.Fprintf(&out, "%s\n", msg)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestPatchChanges(t *testing.T) {
+	in := []byte{
+		0xB0, 0x00, 0x01, 0x20, 0x02, 0xC0, 0x05, // complete patch change with running status
+		0xB1, 0x00, 0x03, 0xC1, 0x06, // without LSB
+		0xC1, 0x07, // program change without bank select
+		0xB0, 0x00, 0x04, 0xB1, 0x00, 0x05, 0xC1, 0x08, // bank select of another channel in between
+		0xB2, 0x20, 0x09, 0x92, 0x3C, 0x64, // bank select without program change
+		0xB3, 0x00, 0x0A, // bank select at the end
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	rd := New(bytes.NewReader(in), nil, PatchChanges(), WireBytes())
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		fmt.Fprintf(&out, "%s | % X\n", msg, rd.(WireReader).WireBytes())
+	}
+
+	expected := `
+channel.PatchChange channel 0 bankMSB 1 bankLSB 2 program 5 | B0 00 01 20 02 C0 05
+channel.PatchChange channel 1 bankMSB 3 program 6 | B1 00 03 C1 06
+channel.ProgramChange channel 1 program 7 | C1 07
+channel.ControlChange channel 0 controller 0 ("Bank Select (MSB)") value 4 | B0 00 04
+channel.PatchChange channel 1 bankMSB 5 program 8 | B1 00 05 C1 08
+channel.ControlChange channel 2 controller 32 ("Bank Select (LSB)") value 9 | B2 20 09
+channel.NoteOn channel 2 key 60 velocity 100 | 92 3C 64
+channel.ControlChange channel 3 controller 0 ("Bank Select (MSB)") value 10 | B3 00 0A
+`
+
+	if got, want := out.String(), named(expected); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	// the writer writes the messages of a patch change with running status
+	var bf bytes.Buffer
+	midiwriter.New(&bf).Write(channel.Channel0.PatchChange(1, 2, 5))
+
+	if got, want := fmt.Sprintf("% X", bf.Bytes()), "B0 00 01 20 02 C0 05"; got != want {
+		t.Errorf("got: %s wanted: %s", got, want)
+	}
+}
+
+func TestChannelModes(t *testing.T) {
+	in := []byte{0xB0, 0x07, 0x64, 0x7B, 0x00, 0xB1, 0x7A, 0x00}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	rd := New(bytes.NewReader(in), nil, ChannelModes())
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		fmt.Fprintf(&out, "%s\n", msg)
+	}
+
+	expected := `
+channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 100
+channel.ChannelMode channel 0 All Notes Off
+channel.ChannelMode channel 1 Local Control off
+`
+
+	if got, want := out.String(), named(expected); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+// named removes the quoted names of the controllers from s if they are not known, as in the tiny profile
+func named(s string) string {
+	if !strings.Contains(channel.Channel0.ControlChange(0, 0).String(), `("`) {
+		for {
+			start := strings.Index(s, ` ("`)
+			if start < 0 {
+				break
+			}
+			end := strings.Index(s[start:], `")`)
+			if end < 0 {
+				break
+			}
+			s = s[:start] + s[start+end+2:]
+		}
+	}
+	return s
+}
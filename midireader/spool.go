@@ -0,0 +1,81 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midireader
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+// spool is a temporary file, a sysex is spooled into
+type spool struct {
+	file *os.File
+	wr   *bufio.Writer
+	size int
+}
+
+// newSpool creates a temporary file inside dir and writes the data received so far into it
+func newSpool(dir string, data []byte) (*spool, error) {
+	f, err := ioutil.TempFile(dir, "sysex")
+	if err != nil {
+		return nil, err
+	}
+
+	sp := &spool{file: f, wr: bufio.NewWriter(f), size: len(data)}
+
+	if _, err = sp.wr.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	return sp, nil
+}
+
+func (sp *spool) WriteByte(b byte) error {
+	sp.size++
+	return sp.wr.WriteByte(b)
+}
+
+// finish flushes and closes the file and returns the spooled sysex (a sysex.Spooled).
+// If that fails, the file is removed and only the error is returned.
+func (sp *spool) finish() (sysex.Message, error) {
+	err := sp.wr.Flush()
+	if cerr := sp.file.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		os.Remove(sp.file.Name())
+		return nil, err
+	}
+
+	return sysex.Spooled{Path: sp.file.Name(), Size: sp.size}, nil
+}
+
+// remove closes and removes the file of a sysex that is discarded
+func (sp *spool) remove() {
+	sp.file.Close()
+	os.Remove(sp.file.Name())
+}
+
+// SpoolSysEx is an option for the reader that spools the data of sysex that are larger than threshold bytes
+// into a temporary file inside dir (the default directory for temporary files if dir is empty),
+// instead of holding them in memory. Such sysex are returned as sysex.Spooled messages and the receiver is
+// responsible to remove their files. Smaller sysex are returned as sysex.SysEx.
+// Spooled sysex are not passed to the validators of the ValidateSysEx option.
+// If reading fails within a spooled sysex (e.g. at the end of the input), only the error is returned and the file is removed.
+func SpoolSysEx(dir string, threshold int) Option {
+	if threshold < 4 {
+		// the header must be in memory
+		threshold = 4
+	}
+	return func(rd *reader) {
+		rd.spoolDir = dir
+		rd.spoolThreshold = threshold
+	}
+}
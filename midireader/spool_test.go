@@ -0,0 +1,95 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midireader
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/sysex"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+func TestReadSpoolSysEx(t *testing.T) {
+	var in bytes.Buffer
+
+	dump := make([]byte, 10000)
+	for i := range dump {
+		dump[i] = byte(i % 128)
+	}
+
+	wr := midiwriter.New(&in)
+	wr.Write(sysex.SysEx([]byte{0x43, 0x10, 0x4C}))
+	wr.Write(sysex.SysEx(dump))
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+
+	dir, err := ioutil.TempDir("", "midireader")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	rd := New(&in, nil, SpoolSysEx(dir, 1024))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		out.WriteString(msg.String() + "\n")
+
+		if sp, is := msg.(sysex.Spooled); is {
+			if !bytes.Equal(sp.Data(), dump) {
+				t.Errorf("spooled data differs")
+			}
+
+			if err := sp.Remove(); err != nil {
+				t.Errorf("can't remove spooled file: %v", err)
+			}
+		}
+	}
+
+	expected := `
+sysex.SysEx len: 3
+sysex.Spooled len: 10000
+channel.NoteOn channel 1 key 60 velocity 100
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestReadSpoolSysExUnexpectedEOF(t *testing.T) {
+	dir, err := ioutil.TempDir("", "midireader")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	// a sysex without end
+	in := append([]byte{0xF0}, make([]byte, 2000)...)
+
+	rd := New(bytes.NewReader(in), nil, SpoolSysEx(dir, 1024))
+
+	msg, err := rd.Read()
+
+	if err == nil || msg != nil {
+		t.Errorf("got %v, %v; wanted nil and an error", msg, err)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+
+	if got, want := len(files), 0; got != want {
+		t.Errorf("got %v files left; wanted %v", got, want)
+	}
+}
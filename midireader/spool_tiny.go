@@ -0,0 +1,27 @@
+//go:build tinygo || miditiny
+// +build tinygo miditiny
+
+package midireader
+
+import (
+	"errors"
+
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+// spooling sysex to files is not part of the tiny profile, since it needs a file system
+type spool struct{}
+
+func newSpool(dir string, data []byte) (*spool, error) {
+	return nil, errors.New("spooling not supported")
+}
+
+func (sp *spool) WriteByte(b byte) error {
+	return errors.New("spooling not supported")
+}
+
+func (sp *spool) remove() {}
+
+func (sp *spool) finish() (sysex.Message, error) {
+	return nil, errors.New("spooling not supported")
+}
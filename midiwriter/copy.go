@@ -0,0 +1,121 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midiwriter
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+)
+
+// Clock is the clock that CopyFrom uses to write the messages at their time
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// Sleep waits for the given duration
+	Sleep(d time.Duration)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// CopyFrom reads the SMF file from src and writes its messages at their time to dest, respecting the tempo changes.
+// The tracks of SMF0 and SMF1 files are played at the same time, the tracks of SMF2 files one after another.
+// Meta messages are not written. If clock is nil, the real time is used.
+// CopyFrom returns, when all messages have been written or when reading or writing fails.
+func CopyFrom(dest midi.Writer, src smf.Reader, clock Clock) error {
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	f, err := smf.Load(src)
+	if err != nil {
+		return err
+	}
+
+	// the groups of tracks that are played one after another
+	var parts [][]*smf.Track
+
+	if f.Format == smf.SMF2 {
+		for _, t := range f.Tracks {
+			parts = append(parts, []*smf.Track{t})
+		}
+	} else {
+		parts = append(parts, f.Tracks)
+	}
+
+	var (
+		start  = clock.Now()
+		offset time.Duration
+	)
+
+	for _, tracks := range parts {
+		timeOf, err := timing(f.TimeFormat, tracks)
+		if err != nil {
+			return err
+		}
+
+		var (
+			events []smf.Event
+			end    uint64
+		)
+
+		for _, t := range tracks {
+			events = append(events, t.Events...)
+			if t.End > end {
+				end = t.End
+			}
+		}
+
+		// keeps the order of the tracks for events at the same tick
+		sort.SliceStable(events, func(a, b int) bool {
+			return events[a].AbsTicks < events[b].AbsTicks
+		})
+
+		for _, ev := range events {
+			if _, is := ev.Message.(meta.Message); is {
+				continue
+			}
+
+			if d := offset + timeOf(ev.AbsTicks) - clock.Now().Sub(start); d > 0 {
+				clock.Sleep(d)
+			}
+
+			if err = dest.Write(ev.Message); err != nil {
+				return err
+			}
+		}
+
+		offset += timeOf(end)
+	}
+
+	return nil
+}
+
+// timing returns a function that returns the time of an absolute position in ticks within the given tracks
+func timing(tf smf.TimeFormat, tracks []*smf.Track) (func(abs uint64) time.Duration, error) {
+	if tc, is := tf.(smf.TimeCode); is {
+		fps := float64(tc.FramesPerSecond)
+		if tc.FramesPerSecond == 29 {
+			fps = 29.97
+		}
+
+		return func(abs uint64) time.Duration {
+			return time.Duration(float64(abs) * float64(time.Second) / (fps * float64(tc.SubFrames)))
+		}, nil
+	}
+
+	tl, err := smf.NewTimeline(&smf.File{TimeFormat: tf, Tracks: tracks})
+	if err != nil {
+		return nil, err
+	}
+
+	return tl.Time, nil
+}
@@ -0,0 +1,94 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midiwriter
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.t
+}
+
+func (f *fakeClock) Sleep(d time.Duration) {
+	f.t = f.t.Add(d)
+}
+
+type clockWriter struct {
+	bf    *bytes.Buffer
+	clock *fakeClock
+	start time.Time
+}
+
+func (c clockWriter) Write(msg midi.Message) error {
+	fmt.Fprintf(c.bf, "%s %s\n", c.clock.t.Sub(c.start), msg)
+	return nil
+}
+
+func TestCopyFrom(t *testing.T) {
+	tests := []struct {
+		format   smf.Format
+		expected string
+	}{
+		{smf.SMF1, `
+0s channel.NoteOn channel 0 key 60 velocity 100
+0s channel.NoteOn channel 1 key 48 velocity 100
+500ms channel.NoteOff channel 0 key 60
+1.5s channel.NoteOff channel 1 key 48
+`},
+		// the tracks are played one after another, the second with the default tempo
+		{smf.SMF2, `
+0s channel.NoteOn channel 0 key 60 velocity 100
+500ms channel.NoteOff channel 0 key 60
+1.5s channel.NoteOn channel 1 key 48 velocity 100
+2.5s channel.NoteOff channel 1 key 48
+`},
+	}
+
+	for i, test := range tests {
+		var file bytes.Buffer
+
+		wr := smfwriter.New(&file, smfwriter.NumTracks(2), smfwriter.Format(test.format), smfwriter.TimeFormat(smf.MetricTicks(96)))
+
+		// a quarter note at 120 BPM, then a quarter note rest at 60 BPM
+		wr.Write(channel.Channel0.NoteOn(60, 100))
+		wr.SetDelta(96)
+		wr.Write(channel.Channel0.NoteOff(60))
+		wr.Write(meta.FractionalBPM(60))
+		wr.SetDelta(96)
+		wr.Write(meta.EndOfTrack)
+
+		wr.Write(channel.Channel1.NoteOn(48, 100))
+		wr.SetDelta(192)
+		wr.Write(channel.Channel1.NoteOff(48))
+		wr.Write(meta.EndOfTrack)
+
+		clock := &fakeClock{t: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		if err := CopyFrom(clockWriter{&out, clock, clock.t}, smfreader.New(bytes.NewReader(file.Bytes())), clock); err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
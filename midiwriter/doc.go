@@ -35,5 +35,28 @@ Usage
 	// simulates releasing key 65 on MIDI channel 3
 	wr.Write(Channel2.NoteOff(65))
 
+To batch the messages into fewer writes (e.g. for pipes and sockets), use the Buffered option and flush after each burst:
+
+	wr := midiwriter.New(output, midiwriter.Buffered(512))
+	wr.Write(Channel2.NoteOn(65, 90))
+	wr.Write(Channel2.NoteOn(69, 90))
+	wr.(midiwriter.Flusher).Flush()
+
+To let the receiver detect a lost connection, wrap the writer with a KeepAlive that sends Active Sensing while idle:
+
+	ka := midiwriter.NewKeepAlive(midiwriter.New(output), midiwriter.ActiveSensingInterval)
+	stop := ka.Start()
+	defer stop()
+
+	ka.Write(Channel2.NoteOn(65, 90))
+
+To play a SMF file to the output, copy it with CopyFrom, that writes the messages at their time (not in the tiny profile):
+
+	err := midiwriter.CopyFrom(midiwriter.New(output), smfreader.New(file), nil)
+
+To start a slaved drum machine at bar 9 of a 4/4 song, before sending the clock:
+
+	err := midiwriter.StartAt(wr, syscommon.SPPAtBar(9, 4, 4))
+
 */
 package midiwriter
@@ -0,0 +1,47 @@
+package midiwriter
+
+import (
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+)
+
+// Locate moves a slaved device (e.g. a drum machine that follows the MIDI clock) to the song position.
+// It writes Stop and the song position pointer, since devices only accept song position pointers while stopped.
+func Locate(wr midi.Writer, pos syscommon.SPP) error {
+	if err := wr.Write(realtime.Stop); err != nil {
+		return err
+	}
+	return wr.Write(pos)
+}
+
+// StartAt moves a slaved device to the song position (see Locate) and starts playback: with Start at the start of
+// the song and with Continue otherwise. The timing clock messages must follow for the device to play.
+func StartAt(wr midi.Writer, pos syscommon.SPP) error {
+	if pos.Sixteenths() == 0 {
+		if err := wr.Write(realtime.Stop); err != nil {
+			return err
+		}
+		return wr.Write(realtime.Start)
+	}
+
+	if err := Locate(wr, pos); err != nil {
+		return err
+	}
+
+	return wr.Write(realtime.Continue)
+}
+
+// SelectSong selects the song (0-127) of a slaved device and moves it to the start of the song.
+// It writes Stop, the song select message and a song position pointer to the start.
+func SelectSong(wr midi.Writer, song uint8) error {
+	if err := wr.Write(realtime.Stop); err != nil {
+		return err
+	}
+
+	if err := wr.Write(syscommon.SelectSong(song)); err != nil {
+		return err
+	}
+
+	return wr.Write(syscommon.SPP(0))
+}
@@ -1,16 +1,69 @@
 package midiwriter
 
+import (
+	"time"
+)
+
 type config struct {
 	noRunningStatus bool
+	sysexSize       int
+	sysexDelay      time.Duration
+	bufferSize      int
+	validate        bool
+	sleep           func(time.Duration)
 }
 
 // Option is a configuration option for a writer
 type Option func(*config)
 
 // NoRunningStatus is an option for the writer that prevents it from
-// using the running status.
+// using the running status, so that every message is written with its status byte.
+// Use it for interfaces and soft synths that mishandle running status.
 func NoRunningStatus() Option {
 	return func(c *config) {
 		c.noRunningStatus = true
 	}
 }
+
+// RunningStatus is an option for the writer that lets it use the running status (the default).
+// It overrides a NoRunningStatus option that was passed before (e.g. as part of a default option set).
+func RunningStatus() Option {
+	return func(c *config) {
+		c.noRunningStatus = false
+	}
+}
+
+// SysExPacing is an option for the writer that splits outgoing sysex messages that are larger than size bytes
+// into fragments of size bytes and waits for the given delay between the fragments.
+// Many older devices drop bulk dumps that are sent at full speed.
+// The fragments are written directly one after another to the output, so they are still a single sysex
+// for the receiver. A size <= 0 disables the splitting.
+// Realtime messages that are written via WriteRealtime (see RealtimeWriter) during the writing of a sysex are
+// written between the fragments, so the delay may be 0 to just get the fragments.
+func SysExPacing(size int, delay time.Duration) Option {
+	return func(c *config) {
+		c.sysexSize = size
+		c.sysexDelay = delay
+	}
+}
+
+// Buffered is an option for the writer that collects the written messages in a buffer of the given size
+// and writes them with a single Write call to the output, when the buffer is full or when Flush is called
+// (see Flusher). This improves the throughput over pipes and sockets, where each write is a system call.
+// Realtime messages that are written via WriteRealtime (see RealtimeWriter) bypass the buffer and are
+// written immediately. A size <= 0 disables the buffering.
+func Buffered(size int) Option {
+	return func(c *config) {
+		c.bufferSize = size
+	}
+}
+
+// Validate is an option for the writer that lets it refuse channel messages with values out of range
+// (see channel.Validate), e.g. a channel above 15, which would corrupt the status byte.
+// The error of channel.Validate is returned and nothing is written.
+// Without this option, invalid messages are written as they are.
+func Validate() Option {
+	return func(c *config) {
+		c.validate = true
+	}
+}
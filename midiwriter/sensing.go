@@ -0,0 +1,114 @@
+package midiwriter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// ActiveSensingInterval is the maximal time between two messages of a sender that uses Active Sensing.
+const ActiveSensingInterval = 300 * time.Millisecond
+
+// KeepAlive is a midi.Writer that writes Active Sensing messages to the wrapped writer while it is idle,
+// so that the receiver notices when the connection is lost (see monitor.WaitForActiveSensing for the receiving side).
+// Active Sensing is written when no message has been written for three quarters of the interval.
+// It is safe for concurrent use, if the wrapped writer is (which is the case for the writers returned by New).
+type KeepAlive struct {
+	mx       sync.Mutex
+	wr       midi.Writer
+	interval time.Duration
+	now      func() time.Time
+	last     time.Time
+	err      error
+}
+
+// NewKeepAlive returns a new KeepAlive that writes to wr. If interval is <= 0, ActiveSensingInterval is used.
+// If wr is a RealtimeWriter, the Active Sensing messages are written via WriteRealtime, so that they may
+// be written between the fragments of a long sysex (see SysExPacing).
+func NewKeepAlive(wr midi.Writer, interval time.Duration) *KeepAlive {
+	if interval <= 0 {
+		interval = ActiveSensingInterval
+	}
+	return &KeepAlive{wr: wr, interval: interval, now: time.Now}
+}
+
+// Write writes the given message to the wrapped writer
+func (k *KeepAlive) Write(msg midi.Message) error {
+	k.touch()
+	err := k.wr.Write(msg)
+	k.touch()
+	return err
+}
+
+// Check writes an Active Sensing message, if the writer has been idle for three quarters of the interval.
+// It returns whether the message was written.
+func (k *KeepAlive) Check() (sent bool, err error) {
+	k.mx.Lock()
+	idle := k.now().Sub(k.last) >= k.interval-k.interval/4
+	k.mx.Unlock()
+
+	if !idle {
+		return false, nil
+	}
+
+	if rt, ok := k.wr.(RealtimeWriter); ok {
+		err = rt.WriteRealtime(realtime.Activesense)
+	} else {
+		err = k.wr.Write(realtime.Activesense)
+	}
+
+	k.touch()
+	return err == nil, err
+}
+
+// Start writes the first Active Sensing message and starts a goroutine that calls Check every quarter
+// of the interval, so that the time between two messages never exceeds the interval.
+// The goroutine stops at the first error (see Err). The returned function stops the goroutine.
+func (k *KeepAlive) Start() (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
+	k.mx.Lock()
+	k.last = time.Time{}
+	k.mx.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(k.interval / 4)
+		defer ticker.Stop()
+
+		for {
+			if _, err := k.Check(); err != nil {
+				k.mx.Lock()
+				k.err = err
+				k.mx.Unlock()
+				return
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// Err returns the error that stopped the goroutine that was started by Start
+func (k *KeepAlive) Err() error {
+	k.mx.Lock()
+	defer k.mx.Unlock()
+	return k.err
+}
+
+// touch sets the time of the last activity to now
+func (k *KeepAlive) touch() {
+	k.mx.Lock()
+	k.last = k.now()
+	k.mx.Unlock()
+}
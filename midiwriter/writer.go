@@ -1,34 +1,146 @@
 package midiwriter
 
 import (
+	"io"
+	"sync"
+	"time"
+
 	"github.com/gomidi/midi"
 	"github.com/gomidi/midi/internal/runningstatus"
-	"io"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
 )
 
-// New returns a new midi.Writer.
+// RealtimeWriter is a midi.Writer that allows to write realtime messages (e.g. the MIDI clock) while
+// another goroutine is writing. The writers returned by New implement it.
+type RealtimeWriter interface {
+	midi.Writer
+
+	// WriteRealtime writes the given realtime message as soon as possible. It is safe to call it concurrently with Write.
+	// When the SysExPacing option is set, the message is written between two fragments of a sysex that
+	// is written at the same time, as the MIDI specification allows. Otherwise it is written after the sysex.
+	// A System Reset is written like via Write, since it affects the running status.
+	WriteRealtime(msg realtime.Message) error
+}
+
+// Flusher is a midi.Writer that buffers the written messages. The writers returned by New implement it.
+type Flusher interface {
+	midi.Writer
+
+	// Flush writes the buffered messages to the output.
+	Flush() error
+}
+
+// New returns a new midi.Writer (a RealtimeWriter and a Flusher).
 //
-// The Writer does no buffering and makes no attempt to close dest.
+// The Writer does no buffering, unless the Buffered option is passed, and makes no attempt to close dest.
+// It is safe for concurrent use.
 //
 // By default the writer uses running status for efficiency.
 // You can disable that behaviour by passing the NoRunningStatus() option.
 // If you don't know what running status is, keep the default.
-func New(dest io.Writer, opts ...Option) (wr midi.Writer) {
-	var c = &config{}
+func New(dest io.Writer, opts ...Option) midi.Writer {
+	var c = &config{sleep: time.Sleep}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	w := &writer{output: &lockedWriter{output: dest}, validate: c.validate}
+	var out io.Writer = w.output
+
+	if c.bufferSize > 0 {
+		w.buffer = &bufferedWriter{output: out, size: c.bufferSize}
+		out = w.buffer
+	}
+
+	if c.sysexSize > 0 {
+		pw := &pacedWriter{output: out, size: c.sysexSize, delay: c.sysexDelay, sleep: c.sleep}
+		if w.buffer != nil {
+			pw.flush = w.buffer.Flush
+		}
+		out = pw
+	}
+
 	if c.noRunningStatus {
-		wr = &notRunningWriter{output: dest}
+		w.wr = &notRunningWriter{output: out}
 	} else {
-		wr = &runningWriter{
-			runningstatus: runningstatus.NewLiveWriter(dest),
+		w.wr = &runningWriter{
+			runningstatus: runningstatus.NewLiveWriter(out),
 		}
 	}
 
-	return wr
+	return w
+}
+
+// writer serializes the writing of messages, while realtime messages may be written in between
+type writer struct {
+	mx       sync.Mutex
+	wr       midi.Writer
+	output   *lockedWriter
+	buffer   *bufferedWriter
+	validate bool
+}
+
+var (
+	_ RealtimeWriter = &writer{}
+	_ Flusher        = &writer{}
+)
+
+// Write writes a midi.Message to a midi (live) stream.
+// A channel.PatchChange is written as its messages.
+func (w *writer) Write(msg midi.Message) error {
+	if cm, is := msg.(channel.Message); is && w.validate {
+		if err := channel.Validate(cm); err != nil {
+			return err
+		}
+	}
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	if pc, is := msg.(channel.PatchChange); is {
+		for _, m := range pc.Messages() {
+			if err := w.wr.Write(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return w.wr.Write(msg)
+}
+
+// WriteRealtime writes the realtime message, if necessary between the fragments of a sysex.
+func (w *writer) WriteRealtime(msg realtime.Message) (err error) {
+	if msg == realtime.Reset {
+		return w.Write(msg)
+	}
+	_, err = w.output.Write(msg.Raw())
+	return
+}
+
+// Flush writes the buffered messages to the output. Without the Buffered option it does nothing.
+func (w *writer) Flush() error {
+	if w.buffer == nil {
+		return nil
+	}
+	w.mx.Lock()
+	defer w.mx.Unlock()
+	return w.buffer.Flush()
+}
+
+// lockedWriter serializes the writes to the output
+type lockedWriter struct {
+	mx     sync.Mutex
+	output io.Writer
+}
+
+// Write writes b to the output
+func (l *lockedWriter) Write(b []byte) (int, error) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	return l.output.Write(b)
 }
 
 type notRunningWriter struct {
@@ -52,3 +164,85 @@ func (w *runningWriter) Write(msg midi.Message) (err error) {
 	_, err = w.runningstatus.Write(msg.Raw())
 	return
 }
+
+// pacedWriter splits sysex into fragments with a delay between them
+type pacedWriter struct {
+	output io.Writer
+	size   int
+	delay  time.Duration
+	sleep  func(time.Duration)
+
+	// flush is called before waiting, if set, so that the fragments are not held back by a buffer
+	flush func() error
+}
+
+// Write writes the given bytes. Sysex that is larger than the fragment size is written in fragments.
+func (w *pacedWriter) Write(b []byte) (n int, err error) {
+	if len(b) <= w.size || b[0] != 0xF0 {
+		return w.output.Write(b)
+	}
+
+	for n < len(b) {
+		if n > 0 {
+			if w.flush != nil {
+				if err = w.flush(); err != nil {
+					return
+				}
+			}
+			w.sleep(w.delay)
+		}
+
+		end := n + w.size
+		if end > len(b) {
+			end = len(b)
+		}
+
+		var nn int
+		nn, err = w.output.Write(b[n:end])
+		n += nn
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// bufferedWriter collects the written bytes and writes them at once, when the buffer is full or flushed
+type bufferedWriter struct {
+	output io.Writer
+	size   int
+	buf    []byte
+}
+
+// Write adds b to the buffer. If b does not fit into the buffer, the buffer is flushed before.
+// If b is larger than the buffer, it is written directly.
+func (w *bufferedWriter) Write(b []byte) (n int, err error) {
+	if len(w.buf)+len(b) > w.size {
+		if err = w.Flush(); err != nil {
+			return
+		}
+	}
+
+	if len(b) >= w.size {
+		return w.output.Write(b)
+	}
+
+	if w.buf == nil {
+		w.buf = make([]byte, 0, w.size)
+	}
+
+	w.buf = append(w.buf, b...)
+	return len(b), nil
+}
+
+// Flush writes the buffered bytes to the output. The buffer is emptied, even if the writing fails.
+func (w *bufferedWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := w.output.Write(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
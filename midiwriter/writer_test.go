@@ -4,8 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/gomidi/midi"
 	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
 )
 
 func TestRunningStatus(t *testing.T) {
@@ -22,6 +27,18 @@ func TestRunningStatus(t *testing.T) {
 	if got, want := fmt.Sprintf("% X", bf.Bytes()), expected; got != want {
 		t.Errorf("got:\n%#v\nwanted:\n%#v\n\n", got, want)
 	}
+
+	bf.Reset()
+
+	// the later option wins
+	wr = New(&bf, NoRunningStatus(), RunningStatus())
+
+	wr.Write(channel.Channel0.NoteOn(50, 33))
+	wr.Write(channel.Channel0.NoteOff(50))
+
+	if got, want := fmt.Sprintf("% X", bf.Bytes()), expected; got != want {
+		t.Errorf("got:\n%#v\nwanted:\n%#v\n\n", got, want)
+	}
 }
 
 func TestNoRunningStatus(t *testing.T) {
@@ -39,3 +56,232 @@ func TestNoRunningStatus(t *testing.T) {
 		t.Errorf("got:\n%#v\nwanted:\n%#v\n\n", got, want)
 	}
 }
+
+type chunkWriter struct {
+	bf *bytes.Buffer
+}
+
+func (c chunkWriter) Write(b []byte) (int, error) {
+	fmt.Fprintf(c.bf, "% X\n", b)
+	return len(b), nil
+}
+
+func TestSysExPacing(t *testing.T) {
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+
+	sleep := func(d time.Duration) {
+		fmt.Fprintf(&bf, "sleep %s\n", d)
+	}
+
+	wr := New(chunkWriter{&bf}, SysExPacing(4, 20*time.Millisecond), func(c *config) { c.sleep = sleep })
+
+	wr.Write(channel.Channel0.NoteOn(50, 33))
+	wr.Write(sysex.SysEx([]byte{0x41, 0x10, 0x42, 0x12, 0x40, 0x00, 0x7F}))
+	wr.Write(sysex.SysEx([]byte{0x41, 0x10}))
+	wr.Write(channel.Channel0.NoteOn(50, 33))
+
+	expected := `
+90 32 21
+F0 41 10 42
+sleep 20ms
+12 40 00 7F
+sleep 20ms
+F7
+F0 41 10 F7
+90 32 21
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestWriteRealtime(t *testing.T) {
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+
+	var wr midi.Writer
+
+	// the clock ticks while the writer waits between the fragments
+	sleep := func(d time.Duration) {
+		wr.(RealtimeWriter).WriteRealtime(realtime.TimingClock)
+	}
+
+	wr = New(chunkWriter{&bf}, SysExPacing(4, time.Millisecond), func(c *config) { c.sleep = sleep })
+
+	wr.Write(channel.Channel0.NoteOn(50, 33))
+	wr.Write(sysex.SysEx([]byte{0x41, 0x10, 0x42, 0x12, 0x40, 0x00, 0x7F}))
+	wr.(RealtimeWriter).WriteRealtime(realtime.Reset)
+	wr.Write(channel.Channel0.NoteOn(50, 33))
+
+	expected := `
+90 32 21
+F0 41 10 42
+F8
+12 40 00 7F
+F8
+F7
+FF
+90 32 21
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestBuffered(t *testing.T) {
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+
+	wr := New(chunkWriter{&bf}, Buffered(8))
+
+	wr.Write(channel.Channel0.NoteOn(50, 33))
+	wr.Write(channel.Channel0.NoteOff(50))
+	wr.Write(channel.Channel1.NoteOn(50, 33))
+
+	// bypasses the buffer
+	wr.(RealtimeWriter).WriteRealtime(realtime.TimingClock)
+
+	wr.Write(sysex.SysEx([]byte{0x41, 0x10, 0x42, 0x12, 0x40, 0x00, 0x7F}))
+	wr.Write(channel.Channel1.NoteOff(50))
+
+	bf.WriteString("flush\n")
+
+	if err := wr.(Flusher).Flush(); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	// nothing left to flush
+	wr.(Flusher).Flush()
+
+	expected := `
+F8
+90 32 21 32 00 91 32 21
+F0 41 10 42 12 40 00 7F F7
+flush
+91 32 00
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ka := NewKeepAlive(New(chunkWriter{&bf}), 0)
+	ka.now = func() time.Time { return now }
+
+	check := func(d time.Duration) {
+		now = now.Add(d)
+		sent, err := ka.Check()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		fmt.Fprintf(&bf, "%v: %v\n", d, sent)
+	}
+
+	check(0)
+	ka.Write(channel.Channel0.NoteOn(50, 33))
+	check(100 * time.Millisecond)
+	check(100 * time.Millisecond)
+	check(25 * time.Millisecond)
+	check(100 * time.Millisecond)
+
+	expected := `
+FE
+0s: true
+90 32 21
+100ms: false
+100ms: false
+FE
+25ms: true
+100ms: false
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, validate := range []bool{false, true} {
+		var bf bytes.Buffer
+
+		var opts []Option
+		if validate {
+			opts = append(opts, Validate())
+		}
+
+		wr := New(&bf, opts...)
+		err := wr.Write(channel.Channel(16).NoteOn(60, 100))
+
+		if validate {
+			if err == nil || bf.Len() != 0 {
+				t.Errorf("[validate] expected an error and nothing written, got %v and % X", err, bf.Bytes())
+			}
+			continue
+		}
+
+		if err != nil || bf.Len() != 3 {
+			t.Errorf("expected the message to be written, got %v and % X", err, bf.Bytes())
+		}
+	}
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		write    func(wr midi.Writer) error
+		expected string
+	}{
+		{
+			func(wr midi.Writer) error { return Locate(wr, syscommon.SPPAtBar(3, 4, 4)) },
+			`
+FC
+F2 20 00
+`,
+		},
+		{
+			func(wr midi.Writer) error { return StartAt(wr, syscommon.SPPAtBeat(2)) },
+			`
+FC
+F2 08 00
+FB
+`,
+		},
+		{
+			func(wr midi.Writer) error { return StartAt(wr, 0) },
+			`
+FC
+FA
+`,
+		},
+		{
+			func(wr midi.Writer) error { return SelectSong(wr, 3) },
+			`
+FC
+F3 03
+F2 00 00
+`,
+		},
+	}
+
+	for i, test := range tests {
+		var bf bytes.Buffer
+		bf.WriteString("\n")
+
+		if err := test.write(New(chunkWriter{&bf})); err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := bf.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package monitor provides wrappers for readers and writers that log every message with its raw bytes,
+its description, its channel, the time and the direction. It is meant for building MIDI monitors and for
+debugging devices.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/monitor"
+		"github.com/gomidi/midi/midireader"
+		"github.com/gomidi/midi/midiwriter"
+	)
+
+	mon := monitor.New(monitor.WriteTo(os.Stdout))
+
+	rd := mon.Reader(midireader.New(in, mon.Realtime(nil)))
+	wr := mon.Writer(midiwriter.New(out))
+
+	// prints e.g.
+	// 12:01:02.123456  in   91 41 64  ch 1   channel.NoteOn channel 1 key 65 velocity 100
+
+A Watchdog detects inputs that stopped sending (dead cables, sleeping devices):
+
+	dog := monitor.NewWatchdog(3*time.Second, onSilent, onResume, monitor.ActiveSensingOnly())
+	stop := dog.Start()
+	defer stop()
+
+	rd := midireader.New(dog.Bytes(in), dog.Realtime(nil))
+
+To follow the Active Sensing protocol, the watchdog waits for the first Active Sensing message and
+calls onSilent, when the sender stays silent for 300ms afterwards:
+
+	dog := monitor.NewWatchdog(midiwriter.ActiveSensingInterval, silenceNotes, nil, monitor.WaitForActiveSensing())
+
+The sending side of Active Sensing is midiwriter.KeepAlive.
+
+The entries can also be logged to a *slog.Logger via the Slog handler.
+
+*/
+package monitor
@@ -0,0 +1,266 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// Direction is the direction of a message
+type Direction int
+
+const (
+	// In is the direction of messages that are read
+	In Direction = iota
+
+	// Out is the direction of messages that are written
+	Out
+)
+
+// String returns "in" or "out"
+func (d Direction) String() string {
+	if d == Out {
+		return "out"
+	}
+	return "in"
+}
+
+// maxHex is the number of bytes that are shown in hex by Entry.String
+const maxHex = 16
+
+// Entry is a logged message
+type Entry struct {
+	// Time is the time the message has been read or written
+	Time time.Time
+
+	// Direction is the direction of the message
+	Direction Direction
+
+	// Name is the name of the monitor or the name of the source of a read message
+	Name string
+
+	// Message is the message
+	Message midi.Message
+
+	// Provenance is the provenance of written messages, if they have been written with provenance
+	Provenance midi.Provenance
+}
+
+// Raw returns the raw bytes of the message
+func (e Entry) Raw() []byte {
+	return e.Message.Raw()
+}
+
+// Hex returns the raw bytes of the message in hex notation
+func (e Entry) Hex() string {
+	return fmt.Sprintf("% X", e.Raw())
+}
+
+// Channel returns the channel of the message (0-15) or -1, if the message is no channel message
+func (e Entry) Channel() int {
+	if cm, is := e.Message.(channel.Message); is {
+		return int(cm.Channel())
+	}
+	return -1
+}
+
+// String returns a line for the entry. Raw data that is longer than 16 bytes is truncated.
+func (e Entry) String() string {
+	var bf strings.Builder
+
+	bf.WriteString(e.Time.Format("15:04:05.000000"))
+	fmt.Fprintf(&bf, "  %-3s", e.Direction)
+
+	if e.Name != "" {
+		bf.WriteString("  " + e.Name)
+	}
+
+	raw := e.Raw()
+	if len(raw) > maxHex {
+		fmt.Fprintf(&bf, "  % X ... (%v bytes)", raw[:maxHex], len(raw))
+	} else {
+		fmt.Fprintf(&bf, "  % X", raw)
+	}
+
+	if ch := e.Channel(); ch >= 0 {
+		fmt.Fprintf(&bf, "  ch %-2v", ch)
+	} else {
+		bf.WriteString("  ch --")
+	}
+
+	bf.WriteString("  " + e.Message.String())
+
+	if len(e.Provenance.Transforms) > 0 || e.Provenance.Source != "" {
+		bf.WriteString("  [" + e.Provenance.String() + "]")
+	}
+
+	return bf.String()
+}
+
+// Handler handles the logged entries
+type Handler func(Entry)
+
+// WriteTo returns a handler that writes each entry as a line to w.
+// It may be used by several monitors at the same time.
+func WriteTo(w io.Writer) Handler {
+	var mx sync.Mutex
+	return func(e Entry) {
+		mx.Lock()
+		io.WriteString(w, e.String()+"\n")
+		mx.Unlock()
+	}
+}
+
+// Slog returns a handler that logs each entry to l at the given level with the attributes
+// direction, name, hex, channel (if any) and provenance (if any). The message is the description of the MIDI message.
+func Slog(l *slog.Logger, level slog.Level) Handler {
+	return func(e Entry) {
+		attrs := []slog.Attr{
+			slog.Time("time", e.Time),
+			slog.String("direction", e.Direction.String()),
+			slog.String("hex", e.Hex()),
+		}
+
+		if e.Name != "" {
+			attrs = append(attrs, slog.String("name", e.Name))
+		}
+
+		if ch := e.Channel(); ch >= 0 {
+			attrs = append(attrs, slog.Int("channel", ch))
+		}
+
+		if len(e.Provenance.Transforms) > 0 || e.Provenance.Source != "" {
+			attrs = append(attrs, slog.String("provenance", e.Provenance.String()))
+		}
+
+		l.LogAttrs(context.Background(), level, e.Message.String(), attrs...)
+	}
+}
+
+// Option is an option for a Monitor
+type Option func(*Monitor)
+
+// Name is an option that sets the name of the monitor. Without a name, the name
+// of a read message is the name of its source, if it is a midi.NamedReader.
+func Name(name string) Option {
+	return func(m *Monitor) {
+		m.name = name
+	}
+}
+
+// Clock is an option that sets the function that returns the current time (default: time.Now).
+// The time of read messages is taken from the reader instead, if it has a Time method that returns a non zero time
+// (like the reader returned by midireader.New with the Timestamps option).
+func Clock(now func() time.Time) Option {
+	return func(m *Monitor) {
+		m.now = now
+	}
+}
+
+// Monitor logs the messages of the readers and writers it wraps
+type Monitor struct {
+	handler Handler
+	name    string
+	now     func() time.Time
+}
+
+// New returns a new monitor that passes the entries to the given handler
+func New(h Handler, opts ...Option) *Monitor {
+	m := &Monitor{
+		handler: h,
+		now:     time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Reader returns a reader that logs each message that has been read from rd.
+func (m *Monitor) Reader(rd midi.Reader) midi.Reader {
+	name := m.name
+	if name == "" {
+		name = midi.SourceName(rd)
+	}
+	return &reader{Monitor: m, rd: rd, name: name}
+}
+
+// Writer returns a writer that logs each message before it is written to wr.
+// It is a midi.ProvenanceWriter that passes the provenance to wr.
+func (m *Monitor) Writer(wr midi.Writer) midi.ProvenanceWriter {
+	return &writer{Monitor: m, wr: wr}
+}
+
+// Realtime returns a realtime handler (e.g. for midireader.New) that logs each realtime message
+// before it is passed to next. next may be nil.
+func (m *Monitor) Realtime(next func(realtime.Message)) func(realtime.Message) {
+	return func(msg realtime.Message) {
+		m.handler(Entry{Time: m.now(), Direction: In, Name: m.name, Message: msg})
+		if next != nil {
+			next(msg)
+		}
+	}
+}
+
+type timed interface {
+	Time() time.Time
+}
+
+type reader struct {
+	*Monitor
+	rd   midi.Reader
+	name string
+}
+
+// Name returns the name of the monitored reader
+func (r *reader) Name() string {
+	return r.name
+}
+
+// Read reads and logs the next message
+func (r *reader) Read() (midi.Message, error) {
+	msg, err := r.rd.Read()
+	if err != nil {
+		return msg, err
+	}
+
+	e := Entry{Direction: In, Name: r.name, Message: msg}
+
+	if t, ok := r.rd.(timed); ok {
+		e.Time = t.Time()
+	}
+
+	if e.Time.IsZero() {
+		e.Time = r.now()
+	}
+
+	r.handler(e)
+	return msg, nil
+}
+
+type writer struct {
+	*Monitor
+	wr midi.Writer
+}
+
+// Write logs and writes the message
+func (w *writer) Write(msg midi.Message) error {
+	w.handler(Entry{Time: w.now(), Direction: Out, Name: w.name, Message: msg})
+	return w.wr.Write(msg)
+}
+
+// WriteProvenance logs and writes the message with its provenance
+func (w *writer) WriteProvenance(msg midi.Message, p midi.Provenance) error {
+	w.handler(Entry{Time: w.now(), Direction: Out, Name: w.name, Message: msg, Provenance: p})
+	return midi.WriteProvenance(w.wr, msg, p)
+}
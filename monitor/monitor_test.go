@@ -0,0 +1,93 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package monitor
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/sysex"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+func fakeClock() func() time.Time {
+	t := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(time.Millisecond)
+		return t
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel1.NoteOn(65, 100))
+	in.Write([]byte{0xF8})
+	wr.Write(sysex.SysEx(make([]byte, 20)))
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	mon := New(WriteTo(&out), Clock(fakeClock()))
+
+	rd := mon.Reader(midi.Name(midireader.New(&in, mon.Realtime(nil)), "keyboard"))
+
+	var sink bytes.Buffer
+	dst := mon.Writer(midiwriter.New(&sink))
+
+	err := midi.Pipe(rd, dst, midi.TransformFunc(func(msg midi.Message) []midi.Message {
+		return []midi.Message{msg}
+	}))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+12:00:00.001000  in   keyboard  91 41 64  ch 1   channel.NoteOn channel 1 key 65 velocity 100
+12:00:00.002000  out  91 41 64  ch 1   channel.NoteOn channel 1 key 65 velocity 100  [keyboard -> midi.TransformFunc]
+12:00:00.003000  in   F8  ch --  TimingClock
+12:00:00.004000  in   keyboard  F0 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 ... (22 bytes)  ch --  sysex.SysEx len: 20
+12:00:00.005000  out  F0 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 ... (22 bytes)  ch --  sysex.SysEx len: 20  [keyboard -> midi.TransformFunc]
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(midi.Message) error {
+	return nil
+}
+
+func TestSlog(t *testing.T) {
+	var out bytes.Buffer
+
+	l := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "time" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	mon := New(Slog(l, slog.LevelDebug), Name("synth"))
+	mon.Writer(nopWriter{}).Write(channel.Channel2.ControlChange(7, 100))
+
+	expected := `level=DEBUG msg="channel.ControlChange channel 2 controller 7 (\"Volume (MSB)\") value 100" direction=out hex="B2 07 64" name=synth channel=2` + "\n"
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
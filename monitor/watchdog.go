@@ -0,0 +1,227 @@
+package monitor
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// WatchdogOption is an option for a Watchdog
+type WatchdogOption func(*Watchdog)
+
+// ActiveSensingOnly is an option that lets the watchdog only count Active Sensing messages as sign of life.
+// Devices that send Active Sensing do so every 300ms, so a missing Active Sensing is a reliable sign for
+// a dead cable or a device that has been switched off, even if the device is just not played.
+func ActiveSensingOnly() WatchdogOption {
+	return func(w *Watchdog) {
+		w.sensingOnly = true
+	}
+}
+
+// WaitForActiveSensing is an option that keeps the watchdog disarmed until the first Active Sensing message
+// has been seen, as the MIDI specification demands from receivers. Once armed, every message counts as sign of life,
+// so the timeout should be about 300ms (see midiwriter.ActiveSensingInterval), and the onSilent callback is
+// the place to silence the sounding notes. Inputs that never send Active Sensing are never reported as silent.
+func WaitForActiveSensing() WatchdogOption {
+	return func(w *Watchdog) {
+		w.waitSensing = true
+	}
+}
+
+// WatchdogClock is an option that sets the function that returns the current time (default: time.Now).
+func WatchdogClock(now func() time.Time) WatchdogOption {
+	return func(w *Watchdog) {
+		w.now = now
+	}
+}
+
+// Watchdog watches an input and calls a callback when the input has been silent for
+// the configured timeout and another callback when the input resumes.
+// The activity of an input is reported by wrapping its io.Reader (Bytes), its midi.Reader (Reader)
+// or its realtime handler (Realtime). The silence is detected by calling Check, either manually
+// or periodically via Start.
+type Watchdog struct {
+	mx          sync.Mutex
+	timeout     time.Duration
+	onSilent    func(last time.Time)
+	onResume    func(silence time.Duration)
+	sensingOnly bool
+	waitSensing bool
+	armed       bool
+	now         func() time.Time
+	last        time.Time
+	silent      bool
+}
+
+// NewWatchdog returns a new watchdog that considers an input to be silent, if there was no activity for the given timeout.
+// onSilent is called with the time of the last activity when the input becomes silent, onResume is called with the duration
+// of the silence when the input is active again. Both callbacks may be nil.
+// The time of the creation counts as first activity.
+func NewWatchdog(timeout time.Duration, onSilent func(last time.Time), onResume func(silence time.Duration), opts ...WatchdogOption) *Watchdog {
+	w := &Watchdog{
+		timeout:  timeout,
+		onSilent: onSilent,
+		onResume: onResume,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	w.last = w.now()
+	return w
+}
+
+// Alive reports activity of the input
+func (w *Watchdog) Alive() {
+	w.mx.Lock()
+	now := w.now()
+	silence := now.Sub(w.last)
+	resumed := w.silent
+	w.last = now
+	w.silent = false
+	w.mx.Unlock()
+
+	if resumed && w.onResume != nil {
+		w.onResume(silence)
+	}
+}
+
+// Check checks, if the input has been silent for the timeout and calls the onSilent callback once
+// per silence. It returns whether the input is silent.
+func (w *Watchdog) Check() (silent bool) {
+	w.mx.Lock()
+	if w.waitSensing && !w.armed {
+		w.mx.Unlock()
+		return false
+	}
+	if w.silent || w.now().Sub(w.last) < w.timeout {
+		silent = w.silent
+		w.mx.Unlock()
+		return
+	}
+	w.silent = true
+	last := w.last
+	w.mx.Unlock()
+
+	if w.onSilent != nil {
+		w.onSilent(last)
+	}
+	return true
+}
+
+// Silent returns whether the input has been detected as silent by the last Check
+func (w *Watchdog) Silent() bool {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+	return w.silent
+}
+
+// Start starts a goroutine that calls Check every quarter of the timeout.
+// The returned function stops the goroutine.
+func (w *Watchdog) Start() (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(w.timeout / 4)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				w.Check()
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// Bytes returns an io.Reader that reports each successful read of at least one byte from rd as activity.
+// It is meant to wrap the raw input before it is passed to midireader.New.
+// With the ActiveSensingOnly option, only reading an Active Sensing byte (0xFE) is reported.
+func (w *Watchdog) Bytes(rd io.Reader) io.Reader {
+	return &watchedBytes{w, rd}
+}
+
+// Reader returns a midi.Reader that reports each message read from rd as activity.
+// With the ActiveSensingOnly option, only reading an Active Sensing message is reported.
+func (w *Watchdog) Reader(rd midi.Reader) midi.Reader {
+	return &watchedReader{w, rd}
+}
+
+// Realtime returns a realtime handler (e.g. for midireader.New) that reports each realtime message
+// (or each Active Sensing message with the ActiveSensingOnly option) as activity before it is passed to next.
+// next may be nil.
+func (w *Watchdog) Realtime(next func(realtime.Message)) func(realtime.Message) {
+	return func(msg realtime.Message) {
+		w.activity(msg == realtime.Activesense)
+		if next != nil {
+			next(msg)
+		}
+	}
+}
+
+// activity reports an activity, where sensing is true for Active Sensing messages
+func (w *Watchdog) activity(sensing bool) {
+	if w.waitSensing {
+		w.mx.Lock()
+		if sensing {
+			w.armed = true
+		}
+		armed := w.armed
+		w.mx.Unlock()
+
+		if armed {
+			w.Alive()
+		}
+		return
+	}
+
+	if !w.sensingOnly || sensing {
+		w.Alive()
+	}
+}
+
+type watchedBytes struct {
+	w  *Watchdog
+	rd io.Reader
+}
+
+// Read reads from the wrapped reader and reports the activity
+func (b *watchedBytes) Read(p []byte) (n int, err error) {
+	n, err = b.rd.Read(p)
+	if n > 0 {
+		b.w.activity(bytes.IndexByte(p[:n], 0xFE) >= 0)
+	}
+	return
+}
+
+type watchedReader struct {
+	w  *Watchdog
+	rd midi.Reader
+}
+
+// Name returns the name of the watched reader
+func (r *watchedReader) Name() string {
+	return midi.SourceName(r.rd)
+}
+
+// Read reads the next message and reports the activity
+func (r *watchedReader) Read() (midi.Message, error) {
+	msg, err := r.rd.Read()
+	if err == nil {
+		r.w.activity(msg == realtime.Activesense)
+	}
+	return msg, err
+}
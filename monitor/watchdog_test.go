@@ -0,0 +1,123 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+func TestWatchdog(t *testing.T) {
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	w := NewWatchdog(time.Second,
+		func(last time.Time) { fmt.Fprintf(&out, "silent since %s\n", last.Format("15:04:05")) },
+		func(silence time.Duration) { fmt.Fprintf(&out, "resumed after %s\n", silence) },
+		WatchdogClock(clock),
+	)
+
+	rd := w.Bytes(bytes.NewReader([]byte{0x90, 0x40, 0x64}))
+	var b [1]byte
+
+	now = now.Add(500 * time.Millisecond)
+	rd.Read(b[:])
+	fmt.Fprintf(&out, "check: %v\n", w.Check())
+
+	now = now.Add(2 * time.Second)
+	fmt.Fprintf(&out, "check: %v\n", w.Check())
+	fmt.Fprintf(&out, "check: %v\n", w.Check())
+
+	now = now.Add(time.Second)
+	rd.Read(b[:])
+	fmt.Fprintf(&out, "check: %v\n", w.Check())
+
+	now = now.Add(time.Second)
+	rd.Read(b[:])
+	rd.Read(b[:])
+	if _, err := rd.Read(b[:]); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+	fmt.Fprintf(&out, "check: %v\n", w.Check())
+
+	expected := `
+check: false
+silent since 12:00:00
+check: true
+check: true
+resumed after 3s
+check: false
+check: false
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestWatchdogActiveSensing(t *testing.T) {
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	w := NewWatchdog(time.Second, nil, nil, WatchdogClock(clock), ActiveSensingOnly())
+	rt := w.Realtime(nil)
+
+	now = now.Add(2 * time.Second)
+	rt(realtime.TimingClock)
+
+	if !w.Check() {
+		t.Errorf("expected input to be silent despite timing clock")
+	}
+
+	rt(realtime.Activesense)
+
+	if w.Silent() {
+		t.Errorf("expected input to be alive after active sensing")
+	}
+}
+
+func TestWatchdogWaitForActiveSensing(t *testing.T) {
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	var lost int
+	w := NewWatchdog(300*time.Millisecond, func(time.Time) { lost++ }, nil, WatchdogClock(clock), WaitForActiveSensing())
+	rd := w.Bytes(bytes.NewReader([]byte{0x90, 0x40, 0x64, 0xFE, 0x40, 0x00}))
+	var b [3]byte
+
+	now = now.Add(time.Second)
+	rd.Read(b[:])
+
+	if w.Check() {
+		t.Errorf("expected watchdog to be disarmed before the first active sensing")
+	}
+
+	now = now.Add(time.Second)
+	rd.Read(b[:1])
+
+	now = now.Add(200 * time.Millisecond)
+	rd.Read(b[:1])
+
+	// any message keeps the armed watchdog alive
+	now = now.Add(200 * time.Millisecond)
+
+	if w.Check() {
+		t.Errorf("expected input to be alive after the note")
+	}
+
+	now = now.Add(200 * time.Millisecond)
+
+	if !w.Check() {
+		t.Errorf("expected input to be silent after 400ms")
+	}
+
+	if got, want := lost, 1; got != want {
+		t.Errorf("onSilent called %v times; wanted %v", got, want)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midi
+
+import (
+	"fmt"
+)
+
+// TransformName returns the name of a transform for the provenance (see Provenance). If the transform has
+// a Name method, it is used, otherwise the type.
+func TransformName(t Transform) string {
+	if n, ok := t.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", t)
+}
@@ -0,0 +1,13 @@
+//go:build tinygo || miditiny
+// +build tinygo miditiny
+
+package midi
+
+// TransformName returns the name of a transform for the provenance (see Provenance). If the transform has
+// a Name method, it is used, otherwise "transform", since the tiny profile avoids reflection.
+func TransformName(t Transform) string {
+	if n, ok := t.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return "transform"
+}
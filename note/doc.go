@@ -0,0 +1,25 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package note converts between MIDI keys, scientific pitch names (e.g. "C#4", "Bb2") and frequencies.
+
+By default, middle C (key 60) is C4 and the reference pitch A4 (key 69) is 440 Hz.
+Both can be changed with options, e.g. MiddleC(3) for the convention of Yamaha or Reference(432).
+
+Usage
+
+	note.Name(61)                    // "C#4"
+	note.Name(61, note.Flats())      // "Db4"
+	note.Name(60, note.MiddleC(3))   // "C3"
+
+	key, err := note.Parse("Bb2")    // 46
+
+	note.Frequency(69)                  // 440
+	note.Frequency(69, note.Reference(432)) // 432
+
+	key, cents := note.Key(445)      // 69, 19.56...
+
+*/
+package note
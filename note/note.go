@@ -0,0 +1,154 @@
+package note
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidName is the cause of the error that is returned by Parse, if the name is no valid note name
+var ErrInvalidName = errors.New("invalid note name")
+
+// Option is an option for the conversions
+type Option func(*config)
+
+type config struct {
+	middleC   int
+	flats     bool
+	reference float64
+}
+
+// MiddleC is an option that sets the octave of middle C (key 60) (default: 4).
+// Some manufacturers (e.g. Yamaha) use 3.
+func MiddleC(octave int) Option {
+	return func(c *config) {
+		c.middleC = octave
+	}
+}
+
+// Flats is an option that makes Name use flats instead of sharps for the black keys
+func Flats() Option {
+	return func(c *config) {
+		c.flats = true
+	}
+}
+
+// Reference is an option that sets the frequency of A4 (key 69) in Hz (default: 440).
+func Reference(hz float64) Option {
+	return func(c *config) {
+		c.reference = hz
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{middleC: 4, reference: 440}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+var (
+	sharpNames = [12]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	flatNames  = [12]string{"C", "Db", "D", "Eb", "E", "F", "Gb", "G", "Ab", "A", "Bb", "B"}
+)
+
+// Name returns the scientific pitch name of the given key, e.g. "C4" for 60
+func Name(key uint8, opts ...Option) string {
+	c := newConfig(opts)
+
+	names := sharpNames
+	if c.flats {
+		names = flatNames
+	}
+
+	return names[key%12] + strconv.Itoa(int(key)/12-5+c.middleC)
+}
+
+// Parse returns the key for the given scientific pitch name. The letter may be followed by any number of
+// accidentals (#, b, ♯ or ♭) and is followed by the octave, e.g. "C4", "F#3", "Bb-1" or "E♭5".
+// The error has the cause ErrInvalidName, if name is no valid note name or out of the MIDI range.
+func Parse(name string, opts ...Option) (key uint8, err error) {
+	c := newConfig(opts)
+
+	invalid := func() error {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	if name == "" {
+		return 0, invalid()
+	}
+
+	step := strings.IndexByte("C D EF G A B", strings.ToUpper(name[:1])[0])
+	if step < 0 || name[0] == ' ' {
+		return 0, invalid()
+	}
+
+	rest := name[1:]
+
+	for {
+		switch {
+		case strings.HasPrefix(rest, "#"):
+			step++
+			rest = rest[1:]
+			continue
+		case strings.HasPrefix(rest, "♯"):
+			step++
+			rest = rest[len("♯"):]
+			continue
+		case strings.HasPrefix(rest, "b"):
+			step--
+			rest = rest[1:]
+			continue
+		case strings.HasPrefix(rest, "♭"):
+			step--
+			rest = rest[len("♭"):]
+			continue
+		}
+		break
+	}
+
+	octave, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0, invalid()
+	}
+
+	n := (octave-c.middleC+5)*12 + step
+	if n < 0 || n > 127 {
+		return 0, invalid()
+	}
+
+	return uint8(n), nil
+}
+
+// Frequency returns the frequency of the given key in Hz in equal temperament
+func Frequency(key uint8, opts ...Option) float64 {
+	c := newConfig(opts)
+	return c.reference * math.Pow(2, (float64(key)-69)/12)
+}
+
+// Key returns the key that is closest to the given frequency in Hz and the deviation of the frequency
+// from the key in cents (between -50 and 50). Frequencies outside of the MIDI range return the lowest or highest key
+// with a larger deviation.
+// hz must be positive.
+func Key(hz float64, opts ...Option) (key uint8, cents float64) {
+	c := newConfig(opts)
+
+	n := 69 + 12*math.Log2(hz/c.reference)
+	if math.IsNaN(n) {
+		return 0, 0
+	}
+
+	k := math.Round(n)
+
+	switch {
+	case k < 0:
+		k = 0
+	case k > 127:
+		k = 127
+	}
+
+	return uint8(k), (n - k) * 100
+}
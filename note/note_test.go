@@ -0,0 +1,123 @@
+package note
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		key      uint8
+		opts     []Option
+		expected string
+	}{
+		{60, nil, "C4"},
+		{0, nil, "C-1"},
+		{127, nil, "G9"},
+		{58, nil, "A#3"},
+		{58, []Option{Flats()}, "Bb3"},
+		{60, []Option{MiddleC(3)}, "C3"},
+		{0, []Option{MiddleC(3)}, "C-2"},
+	}
+
+	for i, test := range tests {
+		if got := Name(test.key, test.opts...); got != test.expected {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, test.expected)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected uint8
+	}{
+		{"C-1", nil, 0},
+		{"C4", nil, 60},
+		{"c4", nil, 60},
+		{"C#4", nil, 61},
+		{"C♯4", nil, 61},
+		{"Bb2", nil, 46},
+		{"E♭5", nil, 75},
+		{"B#3", nil, 60},
+		{"Cb4", nil, 59},
+		{"F##2", nil, 43},
+		{"G9", nil, 127},
+		{"C3", []Option{MiddleC(3)}, 60},
+		{"C-2", []Option{MiddleC(3)}, 0},
+	}
+
+	for i, test := range tests {
+		got, err := Parse(test.name, test.opts...)
+
+		if err != nil {
+			t.Errorf("[%v] error: %v", i, err)
+			continue
+		}
+
+		if got != test.expected {
+			t.Errorf("[%v] got: %v wanted: %v", i, got, test.expected)
+		}
+	}
+
+	for _, invalid := range []string{"", "H4", "C", " 4", "G#9", "C-2", "C4x"} {
+		if key, err := Parse(invalid); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Parse(%q) = %v, %v; wanted ErrInvalidName", invalid, key, err)
+		}
+	}
+
+	for key := 0; key < 128; key++ {
+		for _, opts := range [][]Option{nil, {Flats()}, {MiddleC(3)}} {
+			if got, err := Parse(Name(uint8(key), opts...), opts...); err != nil || got != uint8(key) {
+				t.Errorf("round trip of %v: got %v, %v", key, got, err)
+			}
+		}
+	}
+}
+
+func TestFrequency(t *testing.T) {
+	tests := []struct {
+		key      uint8
+		opts     []Option
+		expected float64
+	}{
+		{69, nil, 440},
+		{81, nil, 880},
+		{57, nil, 220},
+		{60, nil, 261.6256},
+		{69, []Option{Reference(432)}, 432},
+		{69, []Option{Reference(443)}, 443},
+	}
+
+	for i, test := range tests {
+		if got := Frequency(test.key, test.opts...); math.Abs(got-test.expected) > 0.0001 {
+			t.Errorf("[%v] got: %v wanted: %v", i, got, test.expected)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		hz    float64
+		opts  []Option
+		key   uint8
+		cents float64
+	}{
+		{440, nil, 69, 0},
+		{445, nil, 69, 19.56},
+		{261.6256, nil, 60, 0},
+		{432, []Option{Reference(432)}, 69, 0},
+		{440, []Option{Reference(432)}, 69, 31.77},
+		{1, nil, 0, -3637.63},
+	}
+
+	for i, test := range tests {
+		key, cents := Key(test.hz, test.opts...)
+
+		if key != test.key || math.Abs(cents-test.cents) > 0.01 {
+			t.Errorf("[%v] got: %v %.2f wanted: %v %.2f", i, key, cents, test.key, test.cents)
+		}
+	}
+}
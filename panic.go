@@ -0,0 +1,56 @@
+package midi
+
+import (
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// PanicOption is an option for Panic
+type PanicOption func(*panicConfig)
+
+type panicConfig struct {
+	noteOffs bool
+}
+
+// ExplicitNoteOffs is an option for Panic that lets it additionally send a note off message for each of the
+// 128 keys on each channel, for devices that ignore the channel mode messages.
+func ExplicitNoteOffs() PanicOption {
+	return func(c *panicConfig) {
+		c.noteOffs = true
+	}
+}
+
+// Panic silences all notes by sending the channel mode messages All Sound Off (controller 120),
+// All Notes Off (controller 123) and Reset All Controllers (controller 121) on all 16 channels.
+func Panic(w Writer, opts ...PanicOption) error {
+	var c panicConfig
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	for ch := uint8(0); ch < 16; ch++ {
+		cc := channel.Channel(ch)
+
+		for _, msg := range []Message{
+			cc.AllSoundOff(),
+			cc.AllNotesOff(),
+			cc.ResetAllControllers(),
+		} {
+			if err := w.Write(msg); err != nil {
+				return err
+			}
+		}
+
+		if !c.noteOffs {
+			continue
+		}
+
+		for key := uint8(0); key < 128; key++ {
+			if err := w.Write(cc.NoteOff(key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package midi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+func TestPanic(t *testing.T) {
+	tests := []struct {
+		opts []midi.PanicOption
+		len  int
+		head string
+	}{
+		// running status: status and 2 bytes for the first message per channel, 2 bytes for the others
+		{nil, 16 * (3 + 2 + 2), "B0 78 00 7B 00 79 00 B1 78 00"},
+		{[]midi.PanicOption{midi.ExplicitNoteOffs()}, 16 * (3 + 2 + 2 + 3 + 127*2), "B0 78 00 7B 00 79 00 90 00 00 01 00"},
+	}
+
+	for _, test := range tests {
+		var bf bytes.Buffer
+
+		err := midi.Panic(midiwriter.New(&bf), test.opts...)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, want := bf.Len(), test.len; got != want {
+			t.Errorf("len = %v; wanted %v", got, want)
+		}
+
+		if got, want := hexHead(bf.Bytes(), len(test.head)), test.head; got != want {
+			t.Errorf("got % s; wanted %s", got, want)
+		}
+	}
+}
+
+func hexHead(b []byte, n int) string {
+	const digits = "0123456789ABCDEF"
+	var s []byte
+	for i, c := range b {
+		if i > 0 {
+			s = append(s, ' ')
+		}
+		s = append(s, digits[c>>4], digits[c&0x0F])
+		if len(s) >= n {
+			break
+		}
+	}
+	return string(s[:n])
+}
@@ -0,0 +1,37 @@
+package midi
+
+import (
+	"github.com/gomidi/midi/internal/midilib"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+// Parse parses the raw bytes of a single message, as returned by Raw or MarshalBinary, without the need for a reader.
+// Running status is not supported and data must not contain more than the message.
+// A single 0xFF is returned as realtime.Reset, while 0xFF followed by more bytes is parsed as a meta message
+// (not in the tiny profile, see the package documentation).
+// Like the readers, noteoff messages are returned as channel.NoteOff (use the UnmarshalBinary method of
+// channel.NoteOffVelocity to keep the velocity).
+// The error has the cause ErrInvalidMessage, if data is no valid message.
+func Parse(data []byte) (Message, error) {
+	if len(data) == 0 {
+		return nil, midilib.InvalidMessage(data, "MIDI message")
+	}
+
+	switch b := data[0]; {
+	case b >= 0x80 && b <= 0xEF:
+		return channel.ParseMessage(data)
+	case b == 0xF0 || b == 0xF7:
+		return sysex.ParseMessage(data)
+	case b >= 0xF1 && b <= 0xF6:
+		return syscommon.ParseMessage(data)
+	case b == 0xFF && len(data) > 1:
+		return parseMeta(data)
+	case b >= 0xF8:
+		return realtime.ParseMessage(data)
+	default:
+		return nil, midilib.InvalidMessage(data, "MIDI message")
+	}
+}
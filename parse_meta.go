@@ -0,0 +1,13 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midi
+
+import (
+	"github.com/gomidi/midi/midimessage/meta"
+)
+
+// parseMeta parses a meta message
+func parseMeta(data []byte) (Message, error) {
+	return meta.ParseMessage(data)
+}
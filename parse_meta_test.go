@@ -0,0 +1,20 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midi_test
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/meta"
+)
+
+func TestParseMeta(t *testing.T) {
+	testParse(t, []midi.Message{
+		meta.Tempo(120),
+		meta.Text("hello"),
+		meta.TimeSig{Numerator: 3, Denominator: 4, ClocksPerClick: 24, DemiSemiQuaverPerQuarter: 8},
+		meta.EndOfTrack,
+	})
+}
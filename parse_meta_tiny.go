@@ -0,0 +1,13 @@
+//go:build tinygo || miditiny
+// +build tinygo miditiny
+
+package midi
+
+import (
+	"github.com/gomidi/midi/internal/midilib"
+)
+
+// parseMeta returns an error, since the meta messages are not part of the tiny profile.
+func parseMeta(data []byte) (Message, error) {
+	return nil, midilib.InvalidMessage(data, "MIDI message")
+}
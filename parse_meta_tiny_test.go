@@ -0,0 +1,18 @@
+//go:build tinygo || miditiny
+// +build tinygo miditiny
+
+package midi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/meta"
+)
+
+func TestParseMeta(t *testing.T) {
+	if msg, err := midi.Parse(meta.Tempo(120).Raw()); !errors.Is(err, midi.ErrInvalidMessage) {
+		t.Errorf("got %v, %v; wanted ErrInvalidMessage", msg, err)
+	}
+}
@@ -0,0 +1,120 @@
+package midi_test
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+func TestParse(t *testing.T) {
+	tests := []midi.Message{
+		channel.Channel2.NoteOn(65, 90),
+		channel.Channel2.NoteOff(65),
+		channel.Channel15.ControlChange(7, 100),
+		channel.Channel0.ProgramChange(12),
+		channel.Channel1.Pitchbend(-200),
+		channel.Channel1.Aftertouch(30),
+		channel.Channel1.PolyAftertouch(60, 30),
+		realtime.TimingClock,
+		realtime.Reset,
+		syscommon.SPP(300),
+		syscommon.SongSelect(3),
+		syscommon.MTC(5),
+		syscommon.Tune,
+		syscommon.Unknown{Status: 0xF4, Data: []byte{0x12}},
+		sysex.SysEx([]byte{0x41, 0x10, 0x42}),
+		sysex.Start([]byte{0x41}),
+		sysex.End([]byte{0x10, 0x42}),
+	}
+
+	testParse(t, tests)
+}
+
+// testParse checks that the parsed binary representation of each message is a message of the same type with the same bytes
+func testParse(t *testing.T, tests []midi.Message) {
+	t.Helper()
+
+	for i, want := range tests {
+		data, err := want.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatalf("[%v] Error: %v", i, err)
+		}
+
+		got, err := midi.Parse(data)
+		if err != nil {
+			t.Errorf("[%v] Error: %v", i, err)
+			continue
+		}
+
+		if fmt.Sprintf("%T % X", got, got.Raw()) != fmt.Sprintf("%T % X", want, data) {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := [][]byte{
+		nil,
+		{0x3C, 0x40},
+		{0x90, 0x3C},
+		{0x90, 0x3C, 0x40, 0x40},
+		{0xC0, 0x80},
+		{0xF2, 0x01},
+		{0xF8, 0xF8},
+		{0xFF, 0x51, 0x03, 0x07},
+	}
+
+	for i, data := range tests {
+		if msg, err := midi.Parse(data); !errors.Is(err, midi.ErrInvalidMessage) {
+			t.Errorf("[%v] got %v, %v; wanted ErrInvalidMessage", i, msg, err)
+		}
+	}
+}
+
+func TestUnmarshalBinary(t *testing.T) {
+	var noteOff channel.NoteOffVelocity
+
+	if err := noteOff.UnmarshalBinary([]byte{0x82, 0x3C, 0x20}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	var tempo meta.Tempo
+
+	if err := tempo.UnmarshalBinary(meta.Tempo(500000).Raw()); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	var sx sysex.SysEx
+
+	if err := sx.UnmarshalBinary([]byte{0xF0, 0x41, 0x10, 0xF7}); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	var noteOn = channel.Channel0.NoteOn(60, 100)
+
+	// the wrong type leaves the message untouched
+	if err := noteOn.UnmarshalBinary([]byte{0x90, 0x3C, 0x00}); !errors.Is(err, midi.ErrInvalidMessage) {
+		t.Errorf("got %v; wanted ErrInvalidMessage", err)
+	}
+
+	got := fmt.Sprintf("\n%s\n%s\n%s\n%s\n", noteOff, tempo, sx, noteOn)
+
+	expected := `
+channel.NoteOffVelocity channel 2 key 60 velocity 32
+meta.Tempo BPM: 120.00
+sysex.SysEx len: 2
+channel.NoteOn channel 0 key 60 velocity 100
+`
+
+	if got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+}
@@ -0,0 +1,132 @@
+package midi
+
+import (
+	"io"
+)
+
+// Transform transforms a message into zero, one or more messages.
+// Returning nil drops the message, returning more than one message expands it.
+type Transform interface {
+	Transform(Message) []Message
+}
+
+// TransformFunc is a function that is a Transform
+type TransformFunc func(Message) []Message
+
+// Transform calls the function
+func (f TransformFunc) Transform(msg Message) []Message {
+	return f(msg)
+}
+
+// Flusher is a Transform that holds back state and releases messages when the stream ends
+// (e.g. synthesized note-off messages).
+type Flusher interface {
+	Transform
+
+	// Flush returns the messages that should be written at the end of the stream
+	Flush() []Message
+}
+
+// TransformWriter returns a writer that applies the given transforms (in the given order) to each message
+// before it writes the resulting messages to dst.
+// Close flushes the transforms that are Flushers but makes no attempt to close dst.
+// If dst is a ProvenanceWriter, the names of the applied transforms are added to the provenance.
+func TransformWriter(dst Writer, transforms ...Transform) interface {
+	ProvenanceWriter
+	WriteCloser
+} {
+	_, track := dst.(ProvenanceWriter)
+	return &transformWriter{dst: dst, transforms: transforms, track: track}
+}
+
+type transformWriter struct {
+	dst        Writer
+	transforms []Transform
+	track      bool
+}
+
+// Write transforms and writes the given message
+func (t *transformWriter) Write(msg Message) error {
+	return t.write(msg, Provenance{}, 0)
+}
+
+// WriteProvenance transforms and writes the given message while keeping track of the provenance
+func (t *transformWriter) WriteProvenance(msg Message, p Provenance) error {
+	return t.write(msg, p, 0)
+}
+
+// Close flushes all transforms that are Flushers
+func (t *transformWriter) Close() error {
+	for i, tr := range t.transforms {
+		f, ok := tr.(Flusher)
+		if !ok {
+			continue
+		}
+
+		var p Provenance
+		if t.track {
+			p = p.Add(TransformName(tr))
+		}
+
+		for _, m := range f.Flush() {
+			if err := t.write(m, p, i+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *transformWriter) write(msg Message, p Provenance, i int) error {
+	if i == len(t.transforms) {
+		if t.track {
+			return WriteProvenance(t.dst, msg, p)
+		}
+		return t.dst.Write(msg)
+	}
+
+	tr := t.transforms[i]
+	if t.track {
+		p = p.Add(TransformName(tr))
+	}
+
+	for _, m := range tr.Transform(msg) {
+		if err := t.write(m, p, i+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Pipe reads messages from src, applies the given transforms (in the given order) and writes the resulting messages to dst,
+// until src returns an error.
+// When src returns io.EOF, the transforms that are Flushers are flushed and nil is returned.
+// Any other error (including smf.ErrFinished) is returned after flushing.
+// If dst is a ProvenanceWriter, it receives the provenance of each message, where
+// the source is the name of src, if it is a NamedReader.
+func Pipe(src Reader, dst Writer, transforms ...Transform) error {
+	tw := TransformWriter(dst, transforms...)
+	p := Provenance{Source: SourceName(src)}
+
+	for {
+		msg, err := src.Read()
+
+		if err != nil {
+			if ferr := tw.Close(); ferr != nil {
+				return ferr
+			}
+
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		err = tw.WriteProvenance(msg, p)
+
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,86 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package midi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+type flusher struct {
+	midi.TransformFunc
+}
+
+func (flusher) Name() string {
+	return "flusher"
+}
+
+func (flusher) Flush() []midi.Message {
+	return []midi.Message{channel.Channel5.NoteOff(1)}
+}
+
+func TestPipe(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+	wr.Write(channel.Channel1.ControlChange(7, 100))
+	wr.Write(channel.Channel1.NoteOff(60))
+
+	// drops control changes
+	dropCC := midi.TransformFunc(func(msg midi.Message) []midi.Message {
+		if _, is := msg.(channel.ControlChange); is {
+			return nil
+		}
+		return []midi.Message{msg}
+	})
+
+	// adds an octave
+	octave := midi.TransformFunc(func(msg midi.Message) []midi.Message {
+		switch v := msg.(type) {
+		case channel.NoteOn:
+			return []midi.Message{v, channel.Channel(v.Channel()).NoteOn(v.Key()+12, v.Velocity())}
+		case channel.NoteOff:
+			return []midi.Message{v, channel.Channel(v.Channel()).NoteOff(v.Key() + 12)}
+		}
+		return []midi.Message{msg}
+	})
+
+	pass := midi.TransformFunc(func(msg midi.Message) []midi.Message {
+		return []midi.Message{msg}
+	})
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	dst := midi.ProvenanceFunc(func(msg midi.Message, p midi.Provenance) error {
+		out.WriteString(msg.String() + " [" + p.String() + "]\n")
+		return nil
+	})
+
+	src := midi.Name(midireader.New(&in, nil), "keyboard")
+
+	err := midi.Pipe(src, dst, dropCC, octave, flusher{pass})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+channel.NoteOn channel 1 key 60 velocity 100 [keyboard -> midi.TransformFunc -> midi.TransformFunc -> flusher]
+channel.NoteOn channel 1 key 72 velocity 100 [keyboard -> midi.TransformFunc -> midi.TransformFunc -> flusher]
+channel.NoteOff channel 1 key 60 [keyboard -> midi.TransformFunc -> midi.TransformFunc -> flusher]
+channel.NoteOff channel 1 key 72 [keyboard -> midi.TransformFunc -> midi.TransformFunc -> flusher]
+channel.NoteOff channel 5 key 1 [ -> flusher]
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package midi
+
+import (
+	"strings"
+)
+
+// Provenance describes where a message came from: the reader it has been read from
+// and the transforms that have been applied to it (in the order of application).
+// It is passed out-of-band to writers that implement ProvenanceWriter, so that the messages
+// themselves stay untouched.
+type Provenance struct {
+	// Source is the name of the reader the message has been read from
+	Source string
+
+	// Transforms are the names of the transforms that have been applied to the message
+	Transforms []string
+}
+
+// String returns the provenance in the form "source -> transform1 -> transform2"
+func (p Provenance) String() string {
+	return strings.Join(append([]string{p.Source}, p.Transforms...), " -> ")
+}
+
+// Add returns a copy of the provenance with the given transform appended
+func (p Provenance) Add(transform string) Provenance {
+	tr := make([]string, len(p.Transforms), len(p.Transforms)+1)
+	copy(tr, p.Transforms)
+	p.Transforms = append(tr, transform)
+	return p
+}
+
+// ProvenanceWriter is a Writer that is interested in the provenance of the messages
+// that are written to it.
+type ProvenanceWriter interface {
+	Writer
+
+	// WriteProvenance writes the given message that has the given provenance
+	WriteProvenance(Message, Provenance) error
+}
+
+// WriteProvenance writes msg to w, passing the provenance if w is a ProvenanceWriter.
+func WriteProvenance(w Writer, msg Message, p Provenance) error {
+	if pw, ok := w.(ProvenanceWriter); ok {
+		return pw.WriteProvenance(msg, p)
+	}
+	return w.Write(msg)
+}
+
+// NamedReader is a Reader that has a name which is used as the source of the provenance
+type NamedReader interface {
+	Reader
+	Name() string
+}
+
+// Name returns a NamedReader for the given reader with the given name
+func Name(rd Reader, name string) NamedReader {
+	return &namedReader{rd, name}
+}
+
+type namedReader struct {
+	Reader
+	name string
+}
+
+// Name returns the name of the reader
+func (n *namedReader) Name() string {
+	return n.name
+}
+
+// SourceName returns the name of rd if it is a NamedReader and an empty string otherwise
+func SourceName(rd Reader) string {
+	if n, ok := rd.(NamedReader); ok {
+		return n.Name()
+	}
+	return ""
+}
+
+// ProvenanceFunc is a ProvenanceWriter that passes every written message to a function
+type ProvenanceFunc func(Message, Provenance) error
+
+// Write passes msg with an empty provenance to the function
+func (f ProvenanceFunc) Write(msg Message) error {
+	return f(msg, Provenance{})
+}
+
+// WriteProvenance passes msg and p to the function
+func (f ProvenanceFunc) WriteProvenance(msg Message, p Provenance) error {
+	return f(msg, p)
+}
@@ -0,0 +1,68 @@
+package midi
+
+import (
+	"testing"
+)
+
+type testMsg string
+
+func (t testMsg) String() string { return string(t) }
+func (t testMsg) Raw() []byte    { return []byte(t) }
+
+type plainWriter struct {
+	written []Message
+}
+
+func (p *plainWriter) Write(msg Message) error {
+	p.written = append(p.written, msg)
+	return nil
+}
+
+func TestProvenance(t *testing.T) {
+	p := Provenance{Source: "keyboard"}
+	p1 := p.Add("transpose")
+	p2 := p1.Add("channel")
+	p3 := p1.Add("velocity")
+
+	if got, want := p2.String(), "keyboard -> transpose -> channel"; got != want {
+		t.Errorf("got %#v; wanted %#v", got, want)
+	}
+
+	if got, want := p3.String(), "keyboard -> transpose -> velocity"; got != want {
+		t.Errorf("got %#v; wanted %#v", got, want)
+	}
+
+	if got, want := p.String(), "keyboard"; got != want {
+		t.Errorf("got %#v; wanted %#v", got, want)
+	}
+}
+
+func TestWriteProvenance(t *testing.T) {
+	var got Provenance
+
+	pw := ProvenanceFunc(func(msg Message, p Provenance) error {
+		got = p
+		return nil
+	})
+
+	WriteProvenance(pw, testMsg("a"), Provenance{Source: "in"})
+
+	if got.Source != "in" {
+		t.Errorf("got source %#v; wanted %#v", got.Source, "in")
+	}
+
+	var w plainWriter
+	WriteProvenance(&w, testMsg("b"), Provenance{Source: "in"})
+
+	if len(w.written) != 1 {
+		t.Errorf("expected message to be written to plain writer")
+	}
+}
+
+func TestSourceName(t *testing.T) {
+	rd := Name(nil, "in1")
+
+	if got, want := SourceName(rd), "in1"; got != want {
+		t.Errorf("got %#v; wanted %#v", got, want)
+	}
+}
@@ -0,0 +1,23 @@
+package midi
+
+import (
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// SendReset sends a System Reset (0xFF) to w and then replays the given initialization sequence
+// (e.g. the sysex, bank and program changes and controllers that bring the device into the expected state).
+// Note that the writers of midiwriter clear their running status when writing a System Reset, since the
+// receiver does the same.
+func SendReset(w Writer, init ...Message) error {
+	if err := w.Write(realtime.Reset); err != nil {
+		return err
+	}
+
+	for _, msg := range init {
+		if err := w.Write(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package midi_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+func TestSendReset(t *testing.T) {
+	var bf bytes.Buffer
+	wr := midiwriter.New(&bf)
+
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+
+	err := midi.SendReset(wr, channel.Channel0.NoteOn(60, 100), channel.Channel0.NoteOn(62, 100))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the running status is not used across the reset
+	if got, want := fmt.Sprintf("% X", bf.Bytes()), "90 3C 64 FF 90 3C 64 3E 64"; got != want {
+		t.Errorf("got %q; wanted %q", got, want)
+	}
+}
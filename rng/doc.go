@@ -0,0 +1,20 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package rng provides the seedable source of random numbers that is shared by all components
+that make use of randomness (e.g. humanizing).
+
+Passing a Source that has been created with a known seed makes the output of these components
+reproducible, which is needed for tests and to regenerate the same take.
+
+	src := rng.New(42)
+
+	// ... pass src to the components and generate
+
+	// regenerate exactly the same output
+	src.Reset()
+
+*/
+package rng
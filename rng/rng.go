@@ -0,0 +1,75 @@
+package rng
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Source is a source of random numbers
+type Source interface {
+	// Intn returns a random number in [0,n). It panics if n <= 0.
+	Intn(n int) int
+
+	// Float64 returns a random number in [0.0,1.0).
+	Float64() float64
+}
+
+var (
+	_ Source = &Rand{}
+	_ Source = &rand.Rand{}
+)
+
+// Rand is a Source that produces the same sequence of numbers for the same seed.
+// It is safe for concurrent use, but the sequence is only reproducible if the order of calls is.
+type Rand struct {
+	mx   sync.Mutex
+	seed int64
+	rand *rand.Rand
+}
+
+// New returns a Source that is seeded with the given seed
+func New(seed int64) *Rand {
+	return &Rand{seed: seed, rand: rand.New(rand.NewSource(seed))}
+}
+
+// NewTime returns a Source that is seeded with the current time.
+// The used seed can be retrieved via InitialSeed to reproduce the sequence later.
+func NewTime() *Rand {
+	return New(time.Now().UnixNano())
+}
+
+// InitialSeed returns the seed the source has been created with
+func (r *Rand) InitialSeed() int64 {
+	return r.seed
+}
+
+// Reset restarts the sequence of numbers from the beginning
+func (r *Rand) Reset() {
+	r.mx.Lock()
+	r.rand.Seed(r.seed)
+	r.mx.Unlock()
+}
+
+// Intn returns a random number in [0,n). It panics if n <= 0.
+func (r *Rand) Intn(n int) int {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return r.rand.Intn(n)
+}
+
+// Float64 returns a random number in [0.0,1.0).
+func (r *Rand) Float64() float64 {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return r.rand.Float64()
+}
+
+// Range returns a random number in [-max,max] from src.
+// It returns 0 if max <= 0.
+func Range(src Source, max int) int {
+	if max <= 0 {
+		return 0
+	}
+	return src.Intn(2*max+1) - max
+}
@@ -0,0 +1,49 @@
+package rng
+
+import (
+	"reflect"
+	"testing"
+)
+
+func sequence(src Source, n int) (res []int) {
+	for i := 0; i < n; i++ {
+		res = append(res, src.Intn(128))
+	}
+	return
+}
+
+func TestReproducible(t *testing.T) {
+	a := sequence(New(42), 10)
+	b := sequence(New(42), 10)
+
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed, different sequence: %v vs %v", a, b)
+	}
+
+	r := New(7)
+	c := sequence(r, 10)
+	r.Reset()
+	d := sequence(r, 10)
+
+	if !reflect.DeepEqual(c, d) {
+		t.Errorf("sequence after Reset differs: %v vs %v", c, d)
+	}
+
+	if got, want := r.InitialSeed(), int64(7); got != want {
+		t.Errorf("InitialSeed() = %v; wanted %v", got, want)
+	}
+}
+
+func TestRange(t *testing.T) {
+	r := New(1)
+
+	for i := 0; i < 100; i++ {
+		if v := Range(r, 5); v < -5 || v > 5 {
+			t.Fatalf("Range(r, 5) = %v; out of range", v)
+		}
+	}
+
+	if v := Range(r, 0); v != 0 {
+		t.Errorf("Range(r, 0) = %v; wanted 0", v)
+	}
+}
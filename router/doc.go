@@ -0,0 +1,57 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package router provides a routing matrix (a software MIDI patchbay) that connects named inputs
+to named outputs via routes with filters. Routes and outputs may be changed at runtime while messages are routed.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/router"
+		"github.com/gomidi/midi/midireader"
+		"github.com/gomidi/midi/midiwriter"
+	)
+
+	r := router.New()
+	r.AddOutput("synth", midiwriter.New(synthOut))
+	r.AddOutput("drums", midiwriter.New(drumsOut))
+
+	// channel 10 goes to the drums, everything else to the synth
+	r.Connect("keyboard", "drums", router.Channels(9))
+	r.Connect("keyboard", "synth", router.Not(router.Channels(9)))
+
+	go r.ReadFrom("keyboard", midireader.New(keyboardIn, nil))
+
+	// later
+	r.Disconnect("keyboard", "synth")
+
+Unattended installations can be guarded against stuck notes: with the StuckNotes option, notes that sound longer
+than the given maximum on the given outputs are released by Guard (or on demand by ReleaseStuck).
+
+	r := router.New(router.StuckNotes(30*time.Second, "synth"))
+	stop := r.Guard()
+	defer stop()
+
+For debugging a live setup after the fact, the routing decisions can be recorded with the Record option,
+written to a file and replayed against a modified configuration:
+
+	tr := router.NewTrace()
+	r := router.New(router.Record(tr))
+	// ... after the show
+	tr.WriteTo(f)
+
+	// later
+	tr, err := router.ReadTrace(f)
+	tr2 := router.NewTrace()
+	err = tr.Replay(modifiedRouter(router.Record(tr2)))
+	for _, d := range router.Diff(tr, tr2) {
+		fmt.Println(d)
+	}
+
+For testing a configuration without hardware, use the sinks of a dryrun.Report as outputs and
+pass the report via the Report option to count the hits of each route.
+
+*/
+package router
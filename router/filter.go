@@ -0,0 +1,50 @@
+package router
+
+import (
+	"reflect"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// Filter decides whether a message passes a route
+type Filter func(msg midi.Message) bool
+
+// Channels returns a filter that passes only channel messages of the given channels (0-15)
+func Channels(channels ...uint8) Filter {
+	var pass [16]bool
+	for _, ch := range channels {
+		pass[ch&0x0F] = true
+	}
+
+	return func(msg midi.Message) bool {
+		cm, is := msg.(channel.Message)
+		return is && pass[cm.Channel()&0x0F]
+	}
+}
+
+// Types returns a filter that passes only messages of the same types as the given examples,
+// e.g. Types(channel.NoteOn{}, channel.NoteOff{}).
+func Types(examples ...midi.Message) Filter {
+	types := make([]reflect.Type, len(examples))
+	for i, ex := range examples {
+		types[i] = reflect.TypeOf(ex)
+	}
+
+	return func(msg midi.Message) bool {
+		t := reflect.TypeOf(msg)
+		for _, tt := range types {
+			if t == tt {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a filter that passes the messages that are rejected by the given filter
+func Not(f Filter) Filter {
+	return func(msg midi.Message) bool {
+		return !f(msg)
+	}
+}
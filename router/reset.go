@@ -0,0 +1,56 @@
+package router
+
+import (
+	"sort"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// InitSequence is an option for the router that sets the initialization sequence of the given output,
+// that is replayed by SendReset after the System Reset.
+func InitSequence(output string, init ...midi.Message) Option {
+	return func(r *Router) {
+		if r.init == nil {
+			r.init = map[string][]midi.Message{}
+		}
+		r.init[output] = init
+	}
+}
+
+// SendReset sends a System Reset followed by the initialization sequence of the output (see InitSequence)
+// to each output, in the order of their names. The notes tracked for the StuckNotes policy are cleared.
+// The messages have the provenance "router -> reset".
+// The first write error is returned after all outputs have been handled.
+func (r *Router) SendReset() (err error) {
+	r.mx.RLock()
+	names := make([]string, 0, len(r.outputs))
+	for name := range r.outputs {
+		names = append(names, name)
+	}
+	outs := make([]*output, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		outs[i] = r.outputs[name]
+	}
+	r.mx.RUnlock()
+
+	p := midi.Provenance{Source: "router"}.Add("reset")
+
+	for i, out := range outs {
+		msgs := append([]midi.Message{realtime.Reset}, r.init[names[i]]...)
+
+		out.mx.Lock()
+		for _, msg := range msgs {
+			if out.notes != nil {
+				out.notes.Track(msg)
+			}
+			if werr := midi.WriteProvenance(out.wr, msg, p); werr != nil && err == nil {
+				err = werr
+			}
+		}
+		out.mx.Unlock()
+	}
+
+	return
+}
@@ -0,0 +1,35 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package router
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestSendReset(t *testing.T) {
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	r := New(InitSequence("synth", channel.Channel0.ProgramChange(4), channel.Channel0.ControlChange(7, 100)))
+	r.AddOutput("synth", logWriter{"synth", &out})
+	r.AddOutput("drums", logWriter{"drums", &out})
+
+	if err := r.SendReset(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+drums: Reset [router -> reset]
+synth: Reset [router -> reset]
+synth: channel.ProgramChange channel 0 program 4 [router -> reset]
+synth: channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 100 [router -> reset]
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
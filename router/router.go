@@ -0,0 +1,283 @@
+package router
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/dryrun"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/state"
+)
+
+// Route connects an input with an output
+type Route struct {
+	// From is the name of the input
+	From string
+
+	// To is the name of the output
+	To string
+
+	// Filters must all pass a message, to let it be routed
+	Filters []Filter
+
+	// Transform rewrites the messages that passed the filters (may be nil)
+	Transform midi.Transform
+}
+
+// Name returns the name of the route, which is used for the hits of a dryrun.Report
+func (r Route) Name() string {
+	return r.From + " -> " + r.To
+}
+
+// pass returns true, if all filters pass the message
+func (r Route) pass(msg midi.Message) bool {
+	for _, f := range r.Filters {
+		if !f(msg) {
+			return false
+		}
+	}
+	return true
+}
+
+// Option is an option for a Router
+type Option func(*Router)
+
+// Report is an option for the router that registers a hit of the route at the given report for each routed message
+func Report(rep *dryrun.Report) Option {
+	return func(r *Router) {
+		r.report = rep
+	}
+}
+
+type output struct {
+	mx sync.Mutex
+	wr midi.Writer
+
+	// notes tracks the sounding notes for the StuckNotes policy (nil if the output is not guarded)
+	notes *state.Notes
+	max   time.Duration
+}
+
+// Router routes the messages of named inputs to named outputs.
+// It is safe for concurrent use: messages of different inputs can be routed concurrently, while
+// the writes to each output are serialized.
+type Router struct {
+	mx      sync.RWMutex
+	routes  []Route
+	outputs map[string]*output
+	report  *dryrun.Report
+	stuck   map[string]time.Duration
+	now     func() time.Time
+	trace   *Trace
+	init    map[string][]midi.Message
+}
+
+// New returns a new router without outputs and routes
+func New(opts ...Option) *Router {
+	r := &Router{
+		outputs: map[string]*output{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// AddOutput adds an output with the given name, replacing any output with the same name.
+// If wr is a midi.ProvenanceWriter, it receives the provenance of the messages.
+func (r *Router) AddOutput(name string, wr midi.Writer) {
+	r.mx.Lock()
+	r.outputs[name] = r.newOutput(name, wr)
+	r.mx.Unlock()
+}
+
+// RemoveOutput removes the output with the given name. The routes to the output are kept.
+func (r *Router) RemoveOutput(name string) {
+	r.mx.Lock()
+	delete(r.outputs, name)
+	r.mx.Unlock()
+}
+
+// Connect adds a route from the input to the output with the given filters,
+// replacing any route between them.
+func (r *Router) Connect(from, to string, filters ...Filter) {
+	r.connect(Route{From: from, To: to, Filters: filters})
+}
+
+// ConnectTransform adds a route from the input to the output with the given filters, that passes the
+// messages through the given transform (e.g. a compiled script.Program), replacing any route between them.
+// The name of the transform is added to the provenance.
+func (r *Router) ConnectTransform(from, to string, t midi.Transform, filters ...Filter) {
+	r.connect(Route{From: from, To: to, Filters: filters, Transform: t})
+}
+
+func (r *Router) connect(route Route) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	from, to := route.From, route.To
+
+	// copy on write, since routing works on a snapshot of the routes
+	routes := make([]Route, 0, len(r.routes)+1)
+	replaced := false
+
+	for _, rt := range r.routes {
+		if rt.From == from && rt.To == to {
+			routes = append(routes, route)
+			replaced = true
+			continue
+		}
+		routes = append(routes, rt)
+	}
+
+	if !replaced {
+		routes = append(routes, route)
+	}
+
+	r.routes = routes
+}
+
+// Disconnect removes the route from the input to the output
+func (r *Router) Disconnect(from, to string) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	routes := make([]Route, 0, len(r.routes))
+
+	for _, rt := range r.routes {
+		if rt.From != from || rt.To != to {
+			routes = append(routes, rt)
+		}
+	}
+
+	r.routes = routes
+}
+
+// Routes returns the current routes
+func (r *Router) Routes() []Route {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+	return r.routes
+}
+
+// Route routes the message as coming from the input with the given name.
+// It is written to the outputs of all routes of the input, whose filters pass it.
+// Routes to outputs that don't exist are skipped.
+// The first write error is returned after the message has been written to all other outputs.
+func (r *Router) Route(input string, msg midi.Message) error {
+	return r.route(input, msg, midi.Provenance{Source: input})
+}
+
+func (r *Router) route(input string, msg midi.Message, p midi.Provenance) (err error) {
+	r.mx.RLock()
+	routes := r.routes
+	r.mx.RUnlock()
+
+	p = p.Add("router")
+
+	var decision *Decision
+	if r.trace != nil {
+		decision = &Decision{Input: input, Message: msg}
+	}
+
+	for _, rt := range routes {
+		if rt.From != input || !rt.pass(msg) {
+			continue
+		}
+
+		r.mx.RLock()
+		out, has := r.outputs[rt.To]
+		r.mx.RUnlock()
+
+		if !has {
+			continue
+		}
+
+		if r.report != nil {
+			r.report.Hit(rt.Name())
+		}
+
+		msgs, mp := []midi.Message{msg}, p
+		if rt.Transform != nil {
+			msgs, mp = rt.Transform.Transform(msg), p.Add(midi.TransformName(rt.Transform))
+		}
+
+		out.mx.Lock()
+		for _, m := range msgs {
+			werr := midi.WriteProvenance(out.wr, m, mp)
+			if out.notes != nil {
+				out.notes.Track(m)
+			}
+
+			if werr != nil && err == nil {
+				err = werr
+			}
+		}
+		out.mx.Unlock()
+
+		if decision != nil {
+			decision.Deliveries = append(decision.Deliveries, Delivery{Output: rt.To, Messages: msgs})
+		}
+	}
+
+	if decision != nil {
+		r.trace.add(r.clock(), *decision)
+	}
+
+	return
+}
+
+// clock returns the current time
+func (r *Router) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// Input returns a writer that routes the written messages as coming from the input with the given name.
+// It is a midi.ProvenanceWriter, so the provenance of messages that come from a pipe is kept.
+func (r *Router) Input(name string) midi.ProvenanceWriter {
+	return &input{router: r, name: name}
+}
+
+type input struct {
+	router *Router
+	name   string
+}
+
+// Write routes the message
+func (i *input) Write(msg midi.Message) error {
+	return i.router.Route(i.name, msg)
+}
+
+// WriteProvenance routes the message keeping the given provenance
+func (i *input) WriteProvenance(msg midi.Message, p midi.Provenance) error {
+	if p.Source == "" {
+		p.Source = i.name
+	}
+	return i.router.route(i.name, msg, p)
+}
+
+// ReadFrom reads the messages from rd and routes them as coming from the input with the given name,
+// until rd returns an error. It returns nil for io.EOF and smf.ErrFinished, otherwise the error.
+// Write errors are not returned, to keep the input working when an output fails.
+// Start one goroutine per input.
+func (r *Router) ReadFrom(input string, rd midi.Reader) error {
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			if err == io.EOF || err == smf.ErrFinished {
+				return nil
+			}
+			return err
+		}
+
+		r.Route(input, msg)
+	}
+}
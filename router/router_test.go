@@ -0,0 +1,183 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package router
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/dryrun"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+type logWriter struct {
+	name string
+	bf   *bytes.Buffer
+}
+
+func (l logWriter) Write(msg midi.Message) error {
+	return l.WriteProvenance(msg, midi.Provenance{})
+}
+
+func (l logWriter) WriteProvenance(msg midi.Message, p midi.Provenance) error {
+	l.bf.WriteString(l.name + ": " + msg.String() + " [" + p.String() + "]\n")
+	return nil
+}
+
+func TestRouter(t *testing.T) {
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	rep := dryrun.New()
+	r := New(Report(rep))
+	r.AddOutput("drums", logWriter{"drums", &out})
+	r.AddOutput("synth", logWriter{"synth", &out})
+
+	r.Connect("keyboard", "drums", Channels(9))
+	r.Connect("keyboard", "synth", Not(Channels(9)), Types(channel.NoteOn{}, channel.NoteOff{}))
+	r.Connect("pads", "drums")
+
+	r.Route("keyboard", channel.Channel9.NoteOn(36, 100))
+	r.Route("keyboard", channel.Channel0.NoteOn(60, 100))
+	r.Route("keyboard", channel.Channel0.ControlChange(1, 100))
+	r.Route("pads", channel.Channel9.NoteOn(38, 100))
+	r.Input("pads").WriteProvenance(channel.Channel9.NoteOff(38), midi.Provenance{Source: "pads", Transforms: []string{"transpose"}})
+
+	// reconfigure
+	r.Disconnect("keyboard", "drums")
+	r.Connect("keyboard", "synth")
+	r.RemoveOutput("drums")
+
+	r.Route("keyboard", channel.Channel9.NoteOff(36))
+	r.Route("keyboard", channel.Channel0.ControlChange(1, 0))
+	r.Route("pads", realtime.Start)
+
+	expected := `
+drums: channel.NoteOn channel 9 key 36 velocity 100 [keyboard -> router]
+synth: channel.NoteOn channel 0 key 60 velocity 100 [keyboard -> router]
+drums: channel.NoteOn channel 9 key 38 velocity 100 [pads -> router]
+drums: channel.NoteOff channel 9 key 38 [pads -> transpose -> router]
+synth: channel.NoteOff channel 9 key 36 [keyboard -> router]
+synth: channel.ControlChange channel 0 controller 1 ("Modulation Wheel (MSB)") value 0 [keyboard -> router]
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	tests := []struct {
+		route string
+		hits  int
+	}{
+		{"keyboard -> drums", 1},
+		{"keyboard -> synth", 3},
+		{"pads -> drums", 2},
+	}
+
+	for _, test := range tests {
+		if got, want := rep.Hits(test.route), test.hits; got != want {
+			t.Errorf("Hits(%q) = %v; wanted %v", test.route, got, want)
+		}
+	}
+}
+
+func TestRouterConcurrent(t *testing.T) {
+	var in1, in2 bytes.Buffer
+
+	w1, w2 := midiwriter.New(&in1), midiwriter.New(&in2)
+	for i := uint8(0); i < 100; i++ {
+		w1.Write(channel.Channel0.NoteOn(i, 100))
+		w2.Write(channel.Channel1.NoteOn(i, 100))
+	}
+
+	rep := dryrun.New()
+	sink := rep.Sink("out")
+
+	r := New()
+	r.AddOutput("out", sink)
+	r.Connect("a", "out")
+	r.Connect("b", "out")
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		r.ReadFrom("a", midireader.New(&in1, nil))
+		wg.Done()
+	}()
+	go func() {
+		r.ReadFrom("b", midireader.New(&in2, nil))
+		wg.Done()
+	}()
+	go func() {
+		for i := 0; i < 100; i++ {
+			r.Connect("c", "out", Channels(uint8(i%16)))
+			r.Routes()
+		}
+		wg.Done()
+	}()
+	wg.Wait()
+
+	if got, want := sink.Count(), 200; got != want {
+		t.Errorf("sink.Count() = %v; wanted %v", got, want)
+	}
+}
+
+type octave struct{}
+
+func (octave) Transform(msg midi.Message) []midi.Message {
+	if n, ok := msg.(channel.NoteOn); ok {
+		return []midi.Message{msg, channel.Channel(n.Channel()).NoteOn(n.Key()+12, n.Velocity())}
+	}
+	return []midi.Message{msg}
+}
+
+func (octave) Name() string {
+	return "octave"
+}
+
+func TestConnectTransform(t *testing.T) {
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	r := New()
+	r.AddOutput("synth", logWriter{"synth", &out})
+	r.ConnectTransform("keyboard", "synth", octave{}, Channels(0))
+
+	r.Route("keyboard", channel.Channel0.NoteOn(60, 100))
+	r.Route("keyboard", channel.Channel1.NoteOn(60, 100))
+	r.Route("keyboard", channel.Channel0.ProgramChange(2))
+
+	expected := `
+synth: channel.NoteOn channel 0 key 60 velocity 100 [keyboard -> router -> octave]
+synth: channel.NoteOn channel 0 key 72 velocity 100 [keyboard -> router -> octave]
+synth: channel.ProgramChange channel 0 program 2 [keyboard -> router -> octave]
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestTransformName(t *testing.T) {
+	var out bytes.Buffer
+
+	r := New()
+	r.AddOutput("synth", logWriter{"synth", &out})
+
+	// unnamed transforms get the same name as in a pipe
+	r.ConnectTransform("keyboard", "synth", midi.TransformFunc(func(msg midi.Message) []midi.Message {
+		return []midi.Message{msg}
+	}))
+
+	r.Route("keyboard", channel.Channel0.NoteOn(60, 100))
+
+	if got, want := out.String(), "synth: channel.NoteOn channel 0 key 60 velocity 100 [keyboard -> router -> midi.TransformFunc]\n"; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package router
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/state"
+)
+
+// StuckNotes is an option for the router that tracks the notes written to the given outputs
+// (or to all outputs, if no output is given) and lets ReleaseStuck and Guard send note offs for notes
+// that sound longer than max. It guards unattended installations against notes that got stuck
+// because of a lost note off.
+// The option may be passed several times to use different maximums for different outputs.
+func StuckNotes(max time.Duration, outputs ...string) Option {
+	return func(r *Router) {
+		if r.stuck == nil {
+			r.stuck = map[string]time.Duration{}
+		}
+
+		if len(outputs) == 0 {
+			r.stuck[""] = max
+			return
+		}
+
+		for _, o := range outputs {
+			r.stuck[o] = max
+		}
+	}
+}
+
+// Clock is an option that sets the function that returns the current time for the StuckNotes policy
+// and the Record option (default: time.Now).
+func Clock(now func() time.Time) Option {
+	return func(r *Router) {
+		r.now = now
+	}
+}
+
+// stuckMax returns the maximum duration of the notes for the given output or 0, if the output is not guarded
+func (r *Router) stuckMax(output string) time.Duration {
+	if max, has := r.stuck[output]; has {
+		return max
+	}
+	return r.stuck[""]
+}
+
+// newOutput returns an output that tracks the notes, if it is guarded by the StuckNotes policy
+func (r *Router) newOutput(name string, wr midi.Writer) *output {
+	out := &output{wr: wr}
+
+	if max := r.stuckMax(name); max > 0 {
+		out.max = max
+		if r.now != nil {
+			out.notes = state.NewNotes(state.Clock(r.now))
+		} else {
+			out.notes = state.NewNotes()
+		}
+	}
+
+	return out
+}
+
+// ReleaseStuck writes note offs to the guarded outputs for all notes that sound longer than
+// the maximum of the StuckNotes policy. Sustained notes are released by additionally releasing
+// the sustain pedal of their channel. The messages have the provenance "router -> stucknotes".
+// The first write error is returned after all outputs have been handled.
+func (r *Router) ReleaseStuck() (err error) {
+	r.mx.RLock()
+	outs := make([]*output, 0, len(r.outputs))
+	for _, out := range r.outputs {
+		if out.notes != nil {
+			outs = append(outs, out)
+		}
+	}
+	r.mx.RUnlock()
+
+	p := midi.Provenance{Source: "router"}.Add("stucknotes")
+
+	for _, out := range outs {
+		out.mx.Lock()
+		werr := out.releaseStuck(p)
+		out.mx.Unlock()
+
+		if werr != nil && err == nil {
+			err = werr
+		}
+	}
+
+	return
+}
+
+// Guard starts a goroutine that calls ReleaseStuck every quarter of the smallest maximum of the
+// StuckNotes policy. The returned function stops the goroutine. Guard does nothing without the StuckNotes option.
+func (r *Router) Guard() (stop func()) {
+	var min time.Duration
+
+	for _, max := range r.stuck {
+		if max > 0 && (min == 0 || max < min) {
+			min = max
+		}
+	}
+
+	if min == 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(min / 4)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				r.ReleaseStuck()
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// releaseStuck writes the note offs for the stuck notes. The output must be locked.
+func (o *output) releaseStuck(p midi.Provenance) (err error) {
+	var msgs []midi.Message
+	var sustained [16]bool
+
+	for _, n := range o.notes.Hanging(o.max) {
+		msgs = append(msgs, channel.Channel(n.Channel).NoteOff(n.Key))
+		sustained[n.Channel] = sustained[n.Channel] || n.Sustained
+	}
+
+	for ch, sus := range sustained {
+		if sus {
+			msgs = append(msgs, channel.Channel(ch).ControlChange(64, 0))
+		}
+	}
+
+	for _, msg := range msgs {
+		o.notes.Track(msg)
+		if werr := midi.WriteProvenance(o.wr, msg, p); werr != nil && err == nil {
+			err = werr
+		}
+	}
+
+	return
+}
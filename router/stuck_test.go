@@ -0,0 +1,70 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package router
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestStuckNotes(t *testing.T) {
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	r := New(StuckNotes(30*time.Second, "synth"), Clock(clock))
+	r.AddOutput("synth", logWriter{"synth", &out})
+	r.AddOutput("drums", logWriter{"drums", &out})
+
+	r.Connect("keyboard", "synth")
+	r.Connect("keyboard", "drums")
+
+	r.Route("keyboard", channel.Channel0.NoteOn(60, 100))
+	now = now.Add(20 * time.Second)
+	r.Route("keyboard", channel.Channel0.NoteOn(64, 100))
+	r.Route("keyboard", channel.Channel1.ControlChange(64, 127))
+	r.Route("keyboard", channel.Channel1.NoteOn(40, 100))
+	r.Route("keyboard", channel.Channel1.NoteOff(40))
+
+	out.WriteString("-- 20s\n")
+	r.ReleaseStuck()
+
+	now = now.Add(15 * time.Second)
+	out.WriteString("-- 35s\n")
+	r.ReleaseStuck()
+
+	now = now.Add(20 * time.Second)
+	out.WriteString("-- 55s\n")
+	r.ReleaseStuck()
+	r.ReleaseStuck()
+
+	expected := `
+synth: channel.NoteOn channel 0 key 60 velocity 100 [keyboard -> router]
+drums: channel.NoteOn channel 0 key 60 velocity 100 [keyboard -> router]
+synth: channel.NoteOn channel 0 key 64 velocity 100 [keyboard -> router]
+drums: channel.NoteOn channel 0 key 64 velocity 100 [keyboard -> router]
+synth: channel.ControlChange channel 1 controller 64 ("Hold Pedal (on/off)") value 127 [keyboard -> router]
+drums: channel.ControlChange channel 1 controller 64 ("Hold Pedal (on/off)") value 127 [keyboard -> router]
+synth: channel.NoteOn channel 1 key 40 velocity 100 [keyboard -> router]
+drums: channel.NoteOn channel 1 key 40 velocity 100 [keyboard -> router]
+synth: channel.NoteOff channel 1 key 40 [keyboard -> router]
+drums: channel.NoteOff channel 1 key 40 [keyboard -> router]
+-- 20s
+-- 35s
+synth: channel.NoteOff channel 0 key 60 [router -> stucknotes]
+-- 55s
+synth: channel.NoteOff channel 0 key 64 [router -> stucknotes]
+synth: channel.NoteOff channel 1 key 40 [router -> stucknotes]
+synth: channel.ControlChange channel 1 controller 64 ("Hold Pedal (on/off)") value 0 [router -> stucknotes]
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
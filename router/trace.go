@@ -0,0 +1,282 @@
+package router
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midireader"
+)
+
+// Delivery is the result of a route for a message
+type Delivery struct {
+	// Output is the name of the output of the route
+	Output string
+
+	// Messages are the messages that have been written to the output (none, if the transform of the route dropped the message)
+	Messages []midi.Message
+}
+
+// Decision is the routing decision for a message
+type Decision struct {
+	// Time is the time of the decision, relative to the first decision of the trace
+	Time time.Duration
+
+	// Input is the name of the input the message came from
+	Input string
+
+	// Message is the routed message
+	Message midi.Message
+
+	// Deliveries are the results of the routes whose filters passed the message and whose output exists
+	Deliveries []Delivery
+}
+
+// String returns a readable description of the decision
+func (d Decision) String() string {
+	var bf strings.Builder
+	fmt.Fprintf(&bf, "%.6fs %s: %s =>", d.Time.Seconds(), d.Input, d.Message)
+
+	if len(d.Deliveries) == 0 {
+		bf.WriteString(" (not routed)")
+	}
+
+	for i, dl := range d.Deliveries {
+		if i > 0 {
+			bf.WriteString(" |")
+		}
+		bf.WriteString(" " + dl.describe())
+	}
+
+	return bf.String()
+}
+
+func (dl Delivery) describe() string {
+	if len(dl.Messages) == 0 {
+		return dl.Output + ": (dropped)"
+	}
+
+	s := make([]string, len(dl.Messages))
+	for i, msg := range dl.Messages {
+		s[i] = msg.String()
+	}
+	return dl.Output + ": " + strings.Join(s, "; ")
+}
+
+// Trace records the routing decisions of a router (see the Record option).
+// Traces can be written to and read from files, to replay the decisions against a modified configuration
+// after the fact. It is safe for concurrent use.
+type Trace struct {
+	mx        sync.Mutex
+	start     time.Time
+	decisions []Decision
+}
+
+// NewTrace returns an empty trace
+func NewTrace() *Trace {
+	return &Trace{}
+}
+
+// Record is an option for the router that records each routing decision in the given trace.
+// The times are taken from the Clock option.
+func Record(t *Trace) Option {
+	return func(r *Router) {
+		r.trace = t
+	}
+}
+
+// add adds a decision that has been made at the given time
+func (t *Trace) add(now time.Time, d Decision) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if len(t.decisions) == 0 {
+		t.start = now
+	}
+
+	d.Time = now.Sub(t.start)
+	t.decisions = append(t.decisions, d)
+}
+
+// Decisions returns the recorded decisions
+func (t *Trace) Decisions() []Decision {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	return append([]Decision(nil), t.decisions...)
+}
+
+// WriteTo writes the trace to w, one decision per line. The format of a line is
+//
+//	<nanoseconds> TAB <quoted input> TAB <hex message> (TAB <quoted output> = <hex message>, <hex message>...)*
+//
+// Meta messages (from SMF files) can't be written, since they are not part of the MIDI wire format.
+func (t *Trace) WriteTo(w io.Writer) (n int64, err error) {
+	for _, d := range t.Decisions() {
+		var bf strings.Builder
+
+		fmt.Fprintf(&bf, "%d\t%s\t% X", d.Time.Nanoseconds(), strconv.Quote(d.Input), d.Message.Raw())
+
+		for _, dl := range d.Deliveries {
+			bf.WriteString("\t" + strconv.Quote(dl.Output) + "=")
+			for i, msg := range dl.Messages {
+				if i > 0 {
+					bf.WriteString(",")
+				}
+				fmt.Fprintf(&bf, "% X", msg.Raw())
+			}
+		}
+
+		bf.WriteString("\n")
+
+		var nn int
+		nn, err = io.WriteString(w, bf.String())
+		n += int64(nn)
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// ReadTrace reads a trace that has been written by Trace.WriteTo
+func ReadTrace(rd io.Reader) (*Trace, error) {
+	t := &Trace{}
+	sc := bufio.NewScanner(rd)
+	line := 0
+
+	for sc.Scan() {
+		line++
+
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+
+		d, err := parseDecision(sc.Text())
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", line, err)
+		}
+
+		t.decisions = append(t.decisions, d)
+	}
+
+	return t, sc.Err()
+}
+
+func parseDecision(line string) (d Decision, err error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < 3 {
+		return d, fmt.Errorf("expected at least 3 fields, got %v", len(fields))
+	}
+
+	ns, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return d, fmt.Errorf("invalid time %q", fields[0])
+	}
+	d.Time = time.Duration(ns)
+
+	if d.Input, err = strconv.Unquote(fields[1]); err != nil {
+		return d, fmt.Errorf("invalid input name %s", fields[1])
+	}
+
+	if d.Message, err = parseMessage(fields[2]); err != nil {
+		return
+	}
+
+	for _, f := range fields[3:] {
+		i := strings.LastIndex(f, "=")
+		if i < 0 {
+			return d, fmt.Errorf("invalid delivery %q", f)
+		}
+
+		var dl Delivery
+		if dl.Output, err = strconv.Unquote(f[:i]); err != nil {
+			return d, fmt.Errorf("invalid output name %s", f[:i])
+		}
+
+		if f[i+1:] != "" {
+			for _, h := range strings.Split(f[i+1:], ",") {
+				var msg midi.Message
+				if msg, err = parseMessage(h); err != nil {
+					return
+				}
+				dl.Messages = append(dl.Messages, msg)
+			}
+		}
+
+		d.Deliveries = append(d.Deliveries, dl)
+	}
+
+	return d, nil
+}
+
+// parseMessage parses a message from its raw bytes in hex notation
+func parseMessage(h string) (midi.Message, error) {
+	p := midireader.NewParser(midireader.NoteOffVelocity())
+
+	var msg midi.Message
+	var ok bool
+
+	for _, s := range strings.Fields(h) {
+		b, err := strconv.ParseUint(s, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid byte %q", s)
+		}
+		msg, ok = p.Feed(byte(b))
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("incomplete message %q", h)
+	}
+
+	return msg, nil
+}
+
+// Replay routes the messages of the recorded decisions through the given router, as if they came from their inputs.
+// The router would typically have a modified configuration and a Record option, to compare the new decisions
+// with the recorded ones via Diff. The first write error is returned after all messages have been routed.
+func (t *Trace) Replay(r *Router) (err error) {
+	for _, d := range t.Decisions() {
+		if rerr := r.Route(d.Input, d.Message); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	return
+}
+
+// Diff compares the deliveries of the decisions of the traces and returns a line for each decision that differs.
+// The decisions are compared by their position in the traces.
+func Diff(a, b *Trace) (diffs []string) {
+	da, db := a.Decisions(), b.Decisions()
+
+	n := len(da)
+	if len(db) > n {
+		n = len(db)
+	}
+
+	for i := 0; i < n; i++ {
+		switch {
+		case i >= len(da):
+			diffs = append(diffs, fmt.Sprintf("#%v: added %s", i, db[i].describe()))
+		case i >= len(db):
+			diffs = append(diffs, fmt.Sprintf("#%v: removed %s", i, da[i].describe()))
+		case da[i].describe() != db[i].describe():
+			diffs = append(diffs, fmt.Sprintf("#%v: %s\n  became %s", i, da[i].describe(), db[i].describe()))
+		}
+	}
+
+	return
+}
+
+// describe returns the description of the decision without the time
+func (d Decision) describe() string {
+	d.Time = 0
+	s := d.String()
+	return s[strings.Index(s, " ")+1:]
+}
@@ -0,0 +1,106 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package router
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/sysex"
+)
+
+type discard struct{}
+
+func (discard) Write(midi.Message) error { return nil }
+
+func TestTrace(t *testing.T) {
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+
+	tr := NewTrace()
+	r := New(Record(tr), Clock(clock))
+	r.AddOutput("synth", discard{})
+	r.AddOutput("drums", discard{})
+	r.ConnectTransform("keyboard", "synth", octave{}, Not(Channels(9)))
+	r.Connect("keyboard", "drums", Channels(9))
+
+	r.Route("keyboard", channel.Channel0.NoteOn(60, 100))
+	r.Route("keyboard", channel.Channel9.NoteOffVelocity(36, 20))
+	r.Route("keyboard", realtime.Start)
+	r.Route("pads", channel.Channel9.NoteOn(36, 100))
+	r.Route("keyboard", sysex.SysEx([]byte{0x41, 0x10}))
+
+	var bf bytes.Buffer
+	tr.WriteTo(&bf)
+
+	expected := `0	"keyboard"	90 3C 64	"synth"=90 3C 64,90 48 64
+1000000	"keyboard"	89 24 14	"drums"=89 24 14
+2000000	"keyboard"	FA	"synth"=FA
+3000000	"pads"	99 24 64
+4000000	"keyboard"	F0 41 10 F7	"synth"=F0 41 10 F7
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	read, err := ReadTrace(strings.NewReader(expected))
+	if err != nil {
+		t.Fatalf("can't read trace: %v", err)
+	}
+
+	var bf2 bytes.Buffer
+	read.WriteTo(&bf2)
+
+	if got, want := bf2.String(), expected; got != want {
+		t.Errorf("trace changed after reading; got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if got, want := read.Decisions()[1].String(), `0.001000s keyboard: channel.NoteOffVelocity channel 9 key 36 velocity 20 => drums: channel.NoteOffVelocity channel 9 key 36 velocity 20`; got != want {
+		t.Errorf("got %q; wanted %q", got, want)
+	}
+
+	// replay against a modified configuration
+	tr2 := NewTrace()
+	r2 := New(Record(tr2))
+	r2.AddOutput("synth", discard{})
+	r2.AddOutput("drums", discard{})
+	r2.Connect("keyboard", "synth", Types(channel.NoteOn{}))
+	r2.Connect("keyboard", "drums", Channels(9))
+
+	if err := read.Replay(r2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := "\n" + strings.Join(Diff(read, tr2), "\n") + "\n"
+
+	expectedDiff := `
+#0: keyboard: channel.NoteOn channel 0 key 60 velocity 100 => synth: channel.NoteOn channel 0 key 60 velocity 100; channel.NoteOn channel 0 key 72 velocity 100
+  became keyboard: channel.NoteOn channel 0 key 60 velocity 100 => synth: channel.NoteOn channel 0 key 60 velocity 100
+#2: keyboard: Start => synth: Start
+  became keyboard: Start => (not routed)
+#4: keyboard: sysex.SysEx len: 2 => synth: sysex.SysEx len: 2
+  became keyboard: sysex.SysEx len: 2 => (not routed)
+`
+
+	if got != expectedDiff {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expectedDiff)
+	}
+}
+
+func TestReadTraceError(t *testing.T) {
+	_, err := ReadTrace(strings.NewReader("0\t\"keyboard\"\t90 3C 64\n1\t\"keyboard\"\t90 3C\n"))
+
+	if err == nil || err.Error() != `line 2: incomplete message "90 3C"` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
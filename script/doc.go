@@ -0,0 +1,58 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package script provides a small rule language for user defined conditions and rewrites of channel messages
+that can be loaded at runtime (e.g. from a configuration file), without recompiling.
+
+A script consists of rules, one per line (or separated by semicolons). Each rule has an optional condition
+and a comma separated list of actions:
+
+	# move the modulation wheel of channel 2 to controller 11 and scale it
+	if cc == 1 && ch == 2 then cc = 11, value = value * 0.8
+
+	# drop the notes above the middle C
+	if note && key > 60 then drop
+
+	velocity = velocity + 10
+
+The rules are applied in order; each rule sees the message as rewritten by the rules before.
+The action drop removes the message and ends the evaluation.
+
+The variables are
+
+	ch        the channel (0-15)
+	key       the key of note and polyphonic aftertouch messages
+	velocity  the velocity of note on messages and of note off messages with velocity
+	cc        the controller number of control change messages
+	value     the value of control change messages, the pressure of aftertouch messages and
+	          the value of pitch bend messages (-8192 - 8191)
+	program   the program of program change messages
+
+and the flags noteon, noteoff, note, controlchange, programchange, pitchbend, aftertouch and polyaftertouch
+that are 1 for the matching kind of message and 0 otherwise.
+Variables that don't exist for a message (e.g. key for a control change) are -1 and can't be assigned.
+Assigned values are rounded and clamped to the valid range. Messages that are no channel messages
+pass unchanged, unless a rule without condition drops them.
+
+Expressions support numbers, the operators + - * / % == != < <= > >= && || ! and parentheses.
+Comparisons and logical operators result in 1 (true) or 0 (false).
+
+Usage
+
+	import (
+		"github.com/gomidi/midi"
+		"github.com/gomidi/midi/script"
+	)
+
+	prog, err := script.Compile(src)
+
+	// as transform
+	err = midi.Pipe(rd, wr, prog)
+
+	// as router filter and rewrite
+	r.ConnectTransform("keyboard", "synth", prog, router.Filter(prog.Match))
+
+*/
+package script
@@ -0,0 +1,351 @@
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tEOF tokenKind = iota
+	tEnd           // end of a rule (newline or semicolon)
+	tNumber
+	tIdent
+	tOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	line int
+}
+
+// lex splits the source into tokens
+func lex(src string) ([]token, error) {
+	var toks []token
+	line := 1
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == '\n' || c == ';':
+			toks = append(toks, token{kind: tEnd, text: string(c), line: line})
+			if c == '\n' {
+				line++
+			}
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			num, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %v: invalid number %q", line, src[i:j])
+			}
+			toks = append(toks, token{kind: tNumber, text: src[i:j], num: num, line: line})
+			i = j
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_':
+			j := i
+			for j < len(src) && (src[j] >= 'a' && src[j] <= 'z' || src[j] >= 'A' && src[j] <= 'Z' || src[j] >= '0' && src[j] <= '9' || src[j] == '_') {
+				j++
+			}
+			toks = append(toks, token{kind: tIdent, text: strings.ToLower(src[i:j]), line: line})
+			i = j
+		default:
+			op := ""
+			if i+1 < len(src) {
+				switch src[i : i+2] {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = src[i : i+2]
+				}
+			}
+			if op == "" {
+				switch c {
+				case '+', '-', '*', '/', '%', '<', '>', '!', '(', ')', ',', '=':
+					op = string(c)
+				default:
+					return nil, fmt.Errorf("line %v: unexpected character %q", line, c)
+				}
+			}
+			toks = append(toks, token{kind: tOp, text: op, line: line})
+			i += len(op)
+		}
+	}
+
+	return append(toks, token{kind: tEOF, line: line}), nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token, if it is the given operator or keyword
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tOp || t.kind == tIdent) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %v: %s", p.peek().line, fmt.Sprintf(format, args...))
+}
+
+// describe returns a description of the next token for error messages
+func (p *parser) describe() string {
+	switch t := p.peek(); t.kind {
+	case tEOF:
+		return "end of script"
+	case tEnd:
+		return "end of line"
+	default:
+		return strconv.Quote(t.text)
+	}
+}
+
+func (p *parser) program() ([]rule, error) {
+	var rules []rule
+
+	for {
+		for p.peek().kind == tEnd {
+			p.next()
+		}
+
+		if p.peek().kind == tEOF {
+			return rules, nil
+		}
+
+		r, err := p.rule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+
+		if k := p.peek().kind; k != tEnd && k != tEOF {
+			return nil, p.errorf("unexpected %s", p.describe())
+		}
+	}
+}
+
+func (p *parser) rule() (r rule, err error) {
+	if p.accept("if") {
+		r.cond, err = p.expr()
+		if err != nil {
+			return
+		}
+		if !p.accept("then") {
+			return r, p.errorf("expected then, got %s", p.describe())
+		}
+	}
+
+	for {
+		var a action
+		a, err = p.action()
+		if err != nil {
+			return
+		}
+		r.actions = append(r.actions, a)
+
+		if !p.accept(",") {
+			return
+		}
+	}
+}
+
+func (p *parser) action() (a action, err error) {
+	if p.accept("drop") {
+		return action{drop: true}, nil
+	}
+
+	t := p.peek()
+	if t.kind != tIdent {
+		return a, p.errorf("expected assignment or drop, got %s", p.describe())
+	}
+	p.next()
+
+	v, has := variables[t.text]
+	if !has || v >= numFields {
+		return a, fmt.Errorf("line %v: can't assign to %q", t.line, t.text)
+	}
+
+	if !p.accept("=") {
+		return a, p.errorf("expected =, got %s", p.describe())
+	}
+
+	a.field = v
+	a.value, err = p.expr()
+	return
+}
+
+func (p *parser) expr() (expr, error) {
+	return p.binary(0)
+}
+
+// levels of the binary operators, from the lowest to the highest precedence
+var levels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) binary(level int) (expr, error) {
+	if level == len(levels) {
+		return p.unary()
+	}
+
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tOp || !contains(levels[level], t.text) {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryOp(t.text, left, right)
+	}
+}
+
+func (p *parser) unary() (expr, error) {
+	switch {
+	case p.accept("!"):
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *env) float64 { return boolean(x(e) == 0) }, nil
+	case p.accept("-"):
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(e *env) float64 { return -x(e) }, nil
+	}
+
+	return p.primary()
+}
+
+func (p *parser) primary() (expr, error) {
+	t := p.peek()
+
+	switch t.kind {
+	case tNumber:
+		p.next()
+		return func(*env) float64 { return t.num }, nil
+	case tIdent:
+		v, has := variables[t.text]
+		if !has {
+			return nil, p.errorf("unknown variable %q", t.text)
+		}
+		p.next()
+		return func(e *env) float64 { return e.get(v) }, nil
+	}
+
+	if p.accept("(") {
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("expected ), got %s", p.describe())
+		}
+		return x, nil
+	}
+
+	return nil, p.errorf("unexpected %s", p.describe())
+}
+
+func binaryOp(op string, l, r expr) expr {
+	switch op {
+	case "||":
+		return func(e *env) float64 { return boolean(l(e) != 0 || r(e) != 0) }
+	case "&&":
+		return func(e *env) float64 { return boolean(l(e) != 0 && r(e) != 0) }
+	case "==":
+		return func(e *env) float64 { return boolean(l(e) == r(e)) }
+	case "!=":
+		return func(e *env) float64 { return boolean(l(e) != r(e)) }
+	case "<":
+		return func(e *env) float64 { return boolean(l(e) < r(e)) }
+	case "<=":
+		return func(e *env) float64 { return boolean(l(e) <= r(e)) }
+	case ">":
+		return func(e *env) float64 { return boolean(l(e) > r(e)) }
+	case ">=":
+		return func(e *env) float64 { return boolean(l(e) >= r(e)) }
+	case "+":
+		return func(e *env) float64 { return l(e) + r(e) }
+	case "-":
+		return func(e *env) float64 { return l(e) - r(e) }
+	case "*":
+		return func(e *env) float64 { return l(e) * r(e) }
+	case "/":
+		return func(e *env) float64 {
+			d := r(e)
+			if d == 0 {
+				return 0
+			}
+			return l(e) / d
+		}
+	default: // %
+		return func(e *env) float64 {
+			d := int64(r(e))
+			if d == 0 {
+				return 0
+			}
+			return float64(int64(l(e)) % d)
+		}
+	}
+}
+
+func boolean(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func contains(ops []string, op string) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package script
+
+import (
+	"math"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// the assignable fields of a message
+const (
+	fChannel = iota
+	fKey
+	fVelocity
+	fController
+	fValue
+	fProgram
+	numFields
+)
+
+// the flags for the kind of message
+const (
+	fNoteOn = numFields + iota
+	fNoteOff
+	fNote
+	fControlChange
+	fProgramChange
+	fPitchbend
+	fAftertouch
+	fPolyAftertouch
+)
+
+var variables = map[string]int{
+	"ch":             fChannel,
+	"key":            fKey,
+	"velocity":       fVelocity,
+	"cc":             fController,
+	"value":          fValue,
+	"program":        fProgram,
+	"noteon":         fNoteOn,
+	"noteoff":        fNoteOff,
+	"note":           fNote,
+	"controlchange":  fControlChange,
+	"programchange":  fProgramChange,
+	"pitchbend":      fPitchbend,
+	"aftertouch":     fAftertouch,
+	"polyaftertouch": fPolyAftertouch,
+}
+
+type expr func(*env) float64
+
+type action struct {
+	drop  bool
+	field int
+	value expr
+}
+
+type rule struct {
+	cond    expr
+	actions []action
+}
+
+// env is the state of a message while the rules are applied
+type env struct {
+	msg      midi.Message
+	has      [numFields]bool
+	vals     [numFields]float64
+	modified bool
+}
+
+func (e *env) get(v int) float64 {
+	if v < numFields {
+		if !e.has[v] {
+			return -1
+		}
+		return e.vals[v]
+	}
+
+	var is bool
+
+	switch v {
+	case fNoteOn:
+		_, is = e.msg.(channel.NoteOn)
+	case fNoteOff:
+		is = isNoteOff(e.msg)
+	case fNote:
+		_, is = e.msg.(channel.NoteOn)
+		is = is || isNoteOff(e.msg)
+	case fControlChange:
+		_, is = e.msg.(channel.ControlChange)
+	case fProgramChange:
+		_, is = e.msg.(channel.ProgramChange)
+	case fPitchbend:
+		_, is = e.msg.(channel.Pitchbend)
+	case fAftertouch:
+		_, is = e.msg.(channel.Aftertouch)
+	case fPolyAftertouch:
+		_, is = e.msg.(channel.PolyAftertouch)
+	}
+
+	return boolean(is)
+}
+
+func (e *env) set(field int, value float64) {
+	if !e.has[field] {
+		return
+	}
+	e.vals[field] = value
+	e.modified = true
+}
+
+func (e *env) field(field int, value float64) {
+	e.has[field] = true
+	e.vals[field] = value
+}
+
+func isNoteOff(msg midi.Message) bool {
+	switch msg.(type) {
+	case channel.NoteOff, channel.NoteOffVelocity:
+		return true
+	}
+	return false
+}
+
+// load sets the fields of the message
+func (e *env) load(msg midi.Message) {
+	e.msg = msg
+
+	switch v := msg.(type) {
+	case channel.NoteOn:
+		e.field(fChannel, float64(v.Channel()))
+		e.field(fKey, float64(v.Key()))
+		e.field(fVelocity, float64(v.Velocity()))
+	case channel.NoteOffVelocity:
+		e.field(fChannel, float64(v.Channel()))
+		e.field(fKey, float64(v.Key()))
+		e.field(fVelocity, float64(v.Velocity()))
+	case channel.NoteOff:
+		e.field(fChannel, float64(v.Channel()))
+		e.field(fKey, float64(v.Key()))
+	case channel.PolyAftertouch:
+		e.field(fChannel, float64(v.Channel()))
+		e.field(fKey, float64(v.Key()))
+		e.field(fValue, float64(v.Pressure()))
+	case channel.ControlChange:
+		e.field(fChannel, float64(v.Channel()))
+		e.field(fController, float64(v.Controller()))
+		e.field(fValue, float64(v.Value()))
+	case channel.ProgramChange:
+		e.field(fChannel, float64(v.Channel()))
+		e.field(fProgram, float64(v.Program()))
+	case channel.Aftertouch:
+		e.field(fChannel, float64(v.Channel()))
+		e.field(fValue, float64(v.Pressure()))
+	case channel.Pitchbend:
+		e.field(fChannel, float64(v.Channel()))
+		e.field(fValue, float64(v.Value()))
+	}
+}
+
+// message returns the rewritten message
+func (e *env) message() midi.Message {
+	if !e.modified {
+		return e.msg
+	}
+
+	ch := channel.Channel(clamp(e.vals[fChannel], 0, 15))
+	key := uint8(clamp(e.vals[fKey], 0, 127))
+	velocity := uint8(clamp(e.vals[fVelocity], 0, 127))
+	value := uint8(clamp(e.vals[fValue], 0, 127))
+
+	switch e.msg.(type) {
+	case channel.NoteOn:
+		return ch.NoteOn(key, velocity)
+	case channel.NoteOffVelocity:
+		return ch.NoteOffVelocity(key, velocity)
+	case channel.NoteOff:
+		return ch.NoteOff(key)
+	case channel.PolyAftertouch:
+		return ch.PolyAftertouch(key, value)
+	case channel.ControlChange:
+		return ch.ControlChange(uint8(clamp(e.vals[fController], 0, 127)), value)
+	case channel.ProgramChange:
+		return ch.ProgramChange(uint8(clamp(e.vals[fProgram], 0, 127)))
+	case channel.Aftertouch:
+		return ch.Aftertouch(value)
+	case channel.Pitchbend:
+		return ch.Pitchbend(int16(clamp(e.vals[fValue], -8192, 8191)))
+	}
+
+	return e.msg
+}
+
+func clamp(v float64, min, max int) int {
+	i := int(math.Round(v))
+	if i < min {
+		return min
+	}
+	if i > max {
+		return max
+	}
+	return i
+}
+
+// Program is a compiled script. It is safe for concurrent use.
+type Program struct {
+	rules []rule
+}
+
+// Compile compiles the given script. The errors report the line of the problem.
+func Compile(src string) (*Program, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{toks: toks}
+	rules, err := p.program()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Program{rules: rules}, nil
+}
+
+// MustCompile is like Compile but panics if the script can't be compiled
+func MustCompile(src string) *Program {
+	p, err := Compile(src)
+	if err != nil {
+		panic("script: " + err.Error())
+	}
+	return p
+}
+
+// Apply applies the rules to the message and returns the rewritten message.
+// keep is false, if the message has been dropped.
+func (p *Program) Apply(msg midi.Message) (res midi.Message, keep bool) {
+	var e env
+	e.load(msg)
+
+	for _, r := range p.rules {
+		if r.cond != nil && r.cond(&e) == 0 {
+			continue
+		}
+
+		for _, a := range r.actions {
+			if a.drop {
+				return nil, false
+			}
+			e.set(a.field, a.value(&e))
+		}
+	}
+
+	return e.message(), true
+}
+
+// Transform applies the rules to the message. It allows to use the program as midi.Transform.
+func (p *Program) Transform(msg midi.Message) []midi.Message {
+	if res, keep := p.Apply(msg); keep {
+		return []midi.Message{res}
+	}
+	return nil
+}
+
+// Match returns whether the message is kept by the rules. It allows to use the program as router.Filter.
+func (p *Program) Match(msg midi.Message) bool {
+	_, keep := p.Apply(msg)
+	return keep
+}
+
+// Name returns the name of the transform
+func (p *Program) Name() string {
+	return "script"
+}
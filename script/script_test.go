@@ -0,0 +1,97 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package script
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+func TestProgram(t *testing.T) {
+	prog, err := Compile(`
+# remap the modulation wheel of channel 2
+if cc == 1 && ch == 2 then cc = 11, value = value * 0.8
+
+if note && key > 60 then drop ; if noteon then velocity = velocity + 10
+if pitchbend then value = -value
+if programchange && !(program < 10) then ch = 9, program = program % 10
+`)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msgs := []midi.Message{
+		channel.Channel2.ControlChange(1, 100),
+		channel.Channel1.ControlChange(1, 100),
+		channel.Channel0.NoteOn(60, 120),
+		channel.Channel0.NoteOn(61, 100),
+		channel.Channel0.NoteOffVelocity(60, 20),
+		channel.Channel0.Pitchbend(-8192),
+		channel.Channel0.ProgramChange(23),
+		channel.Channel0.ProgramChange(3),
+		realtime.Start,
+	}
+
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+
+	for _, msg := range msgs {
+		for _, m := range prog.Transform(msg) {
+			bf.WriteString(m.String() + "\n")
+		}
+	}
+
+	expected := `
+channel.ControlChange channel 2 controller 11 ("Expression (MSB)") value 80
+channel.ControlChange channel 1 controller 1 ("Modulation Wheel (MSB)") value 100
+channel.NoteOn channel 0 key 60 velocity 127
+channel.NoteOffVelocity channel 0 key 60 velocity 20
+channel.Pitchbend channel 0 value 8191 absValue 0
+channel.ProgramChange channel 9 program 3
+channel.ProgramChange channel 0 program 3
+Start
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if prog.Match(channel.Channel0.NoteOff(72)) {
+		t.Errorf("expected note off of key 72 to be dropped")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		err string
+	}{
+		{"if cc == 1 ch = 2", `line 1: expected then, got "ch"`},
+		{"\nfoo = 2", `line 2: can't assign to "foo"`},
+		{"note = 1", `line 1: can't assign to "note"`},
+		{"ch = (1 + 2", `line 1: expected ), got end of script`},
+		{"ch = bar", `line 1: unknown variable "bar"`},
+		{"ch = 1 $", `line 1: unexpected character '$'`},
+		{"if ch then", `line 1: expected assignment or drop, got end of script`},
+		{"ch = 1 2", `line 1: unexpected "2"`},
+	}
+
+	for _, test := range tests {
+		_, err := Compile(test.src)
+
+		if err == nil {
+			t.Errorf("Compile(%q) returned no error", test.src)
+			continue
+		}
+
+		if got, want := err.Error(), test.err; got != want {
+			t.Errorf("Compile(%q) = %q; wanted %q", test.src, got, want)
+		}
+	}
+}
@@ -16,5 +16,35 @@ The MIDI messages that can be read/written from/to a SMF file can be found here:
   github.com/gomidi/midi/midimessage/cc         (Control Change Messages)
   github.com/gomidi/midi/midimessage/meta       (Meta Messages)
   github.com/gomidi/midi/midimessage/sysex      (System Exclusive Messages)
+
+Events returns an iterator over the events of a Reader:
+
+	for ev, err := range smf.Events(smfreader.New(file)) {
+		if err != nil {
+			...
+		}
+		fmt.Println(ev.Track, ev.AbsTicks, ev.Message)
+	}
+
+A File holds a whole SMF file in memory with the absolute times of the events, so that it can be edited:
+
+	f, err := smfreader.Load(file)
+
+	tr := f.Tracks[1]
+	i := tr.Insert(480, channel.Channel0.NoteOn(60, 100))
+	tr.Move(i, 960)
+
+	err = smfwriter.Save(dest, f)
+
+The tracks of a File can be merged (MergeTracks), split by channel (SplitTrack) and a range of ticks can be extracted
+as a new File (Extract). Retime converts a File to another resolution of ticks per quarter note.
+Flatten rewrites a File to a constant tempo without changing the real time positions of the events
+and Stretch scales its playback time.
+
+A Timeline converts between ticks, bar:beat:tick positions and real time, based on the time signatures and tempos:
+
+	tl, err := smf.NewTimeline(f)
+	fmt.Println(tl.Position(ev.AbsTicks), tl.Time(ev.AbsTicks))
+	abs := tl.AbsTicks(smf.Position{Bar: 9, Beat: 1})
 */
 package smf
@@ -0,0 +1,206 @@
+package smf
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+)
+
+// MergeTracks merges the tracks with the given indices into one track that replaces the first of them.
+// The other tracks are removed. Events of the same time keep the order of the given tracks.
+func (f *File) MergeTracks(tracks ...int) (*Track, error) {
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("no tracks to merge")
+	}
+
+	merged := &Track{}
+	remove := map[int]bool{}
+
+	for _, i := range tracks {
+		if i < 0 || i >= len(f.Tracks) {
+			return nil, fmt.Errorf("track %v does not exist", i)
+		}
+
+		if remove[i] {
+			return nil, fmt.Errorf("track %v given twice", i)
+		}
+
+		remove[i] = true
+		t := f.Tracks[i]
+		merged.Events = append(merged.Events, t.Events...)
+
+		if t.End > merged.End {
+			merged.End = t.End
+		}
+	}
+
+	sort.SliceStable(merged.Events, func(a, b int) bool {
+		return merged.Events[a].AbsTicks < merged.Events[b].AbsTicks
+	})
+
+	var result []*Track
+
+	for i, t := range f.Tracks {
+		switch {
+		case i == tracks[0]:
+			result = append(result, merged)
+		case !remove[i]:
+			result = append(result, t)
+		}
+	}
+
+	f.Tracks = result
+	return merged, nil
+}
+
+// SplitByChannel splits the track into a track of the messages that are no channel messages (e.g. meta messages)
+// followed by a track for each channel that is used (ordered by channel).
+// All returned tracks have the end of the track.
+func (t *Track) SplitByChannel() []*Track {
+	var (
+		rest     = &Track{End: t.End}
+		channels [16]*Track
+	)
+
+	for _, ev := range t.Events {
+		cm, is := ev.Message.(channel.Message)
+
+		if !is || cm.Channel() > 15 {
+			rest.Events = append(rest.Events, ev)
+			continue
+		}
+
+		if channels[cm.Channel()] == nil {
+			channels[cm.Channel()] = &Track{End: t.End}
+		}
+
+		channels[cm.Channel()].Events = append(channels[cm.Channel()].Events, ev)
+	}
+
+	res := []*Track{rest}
+
+	for _, ct := range channels {
+		if ct != nil {
+			res = append(res, ct)
+		}
+	}
+
+	return res
+}
+
+// SplitTrack replaces the track with the given index by the tracks that are returned by its SplitByChannel method.
+// A SMF0 file becomes a SMF1 file.
+func (f *File) SplitTrack(i int) error {
+	if i < 0 || i >= len(f.Tracks) {
+		return fmt.Errorf("track %v does not exist", i)
+	}
+
+	split := f.Tracks[i].SplitByChannel()
+
+	tracks := append([]*Track{}, f.Tracks[:i]...)
+	tracks = append(tracks, split...)
+	f.Tracks = append(tracks, f.Tracks[i+1:]...)
+
+	if f.Format == SMF0 {
+		f.Format = SMF1
+	}
+
+	return nil
+}
+
+// Extract returns a new file with the events from the time from (inclusive) to the time to (exclusive)
+// of each track, moved to start at 0. The last tempo, time signature, key signature, track name and program changes
+// before from are put at 0, so that the extract sounds like the range in the original file.
+// Notes that are sounding at the time to are ended at the end of the extract.
+func (f *File) Extract(from, to uint64) (*File, error) {
+	if to <= from {
+		return nil, fmt.Errorf("invalid range %v-%v", from, to)
+	}
+
+	res := &File{Format: f.Format, TimeFormat: f.TimeFormat}
+
+	for _, t := range f.Tracks {
+		var (
+			ex      = res.AddTrack()
+			state   = map[string]Event{}
+			keys    []string
+			inRange []Event
+			notes   = map[[2]uint8]bool{}
+		)
+
+		for _, ev := range t.Events {
+			switch {
+			case ev.AbsTicks >= to:
+			case ev.AbsTicks >= from:
+				inRange = append(inRange, Event{AbsTicks: ev.AbsTicks - from, Message: ev.Message})
+				trackNote(notes, ev)
+			default:
+				if k := stateKey(ev); k != "" {
+					if _, has := state[k]; !has {
+						keys = append(keys, k)
+					}
+					state[k] = Event{Message: ev.Message}
+				}
+			}
+		}
+
+		for _, k := range keys {
+			ex.Events = append(ex.Events, state[k])
+		}
+
+		ex.Events = append(ex.Events, inRange...)
+
+		end := t.End
+		if end > to {
+			end = to
+		}
+
+		if end > from {
+			ex.End = end - from
+		}
+
+		for ch := uint8(0); ch < 16 && len(notes) > 0; ch++ {
+			for key := uint8(0); key < 128; key++ {
+				if notes[[2]uint8{ch, key}] {
+					ex.Events = append(ex.Events, Event{AbsTicks: to - from, Message: channel.Channel(ch).NoteOff(key)})
+					delete(notes, [2]uint8{ch, key})
+					ex.End = to - from
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// trackNote keeps track of the sounding notes by channel and key
+func trackNote(notes map[[2]uint8]bool, ev Event) {
+	switch m := ev.Message.(type) {
+	case channel.NoteOn:
+		notes[[2]uint8{m.Channel(), m.Key()}] = true
+	case channel.NoteOff:
+		delete(notes, [2]uint8{m.Channel(), m.Key()})
+	case channel.NoteOffVelocity:
+		delete(notes, [2]uint8{m.Channel(), m.Key()})
+	}
+}
+
+// stateKey returns the key of the messages that define the state for the following events, "" for other messages
+func stateKey(ev Event) string {
+	switch m := ev.Message.(type) {
+	case meta.Tempo:
+		return "tempo"
+	case meta.TimeSig:
+		return "timesig"
+	case meta.Key:
+		return "key"
+	case meta.Track:
+		return "track"
+	case channel.ProgramChange:
+		return fmt.Sprintf("program %v", m.Channel())
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,131 @@
+package smf_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/internal/examples"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+func trackString(t *smf.Track) string {
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for _, ev := range t.Events {
+		out.WriteString(fmt.Sprintf("@%v %s\n", ev.AbsTicks, ev.Message))
+	}
+
+	out.WriteString(fmt.Sprintf("end @%v\n", t.End))
+	return out.String()
+}
+
+func TestMergeAndSplit(t *testing.T) {
+	f, err := smfreader.Load(bytes.NewReader(examples.SpecSMF1))
+
+	if err != nil {
+		t.Fatalf("can't load: %v", err)
+	}
+
+	merged, err := f.MergeTracks(1, 2, 3)
+
+	if err != nil {
+		t.Fatalf("can't merge: %v", err)
+	}
+
+	if got, want := len(f.Tracks), 2; got != want {
+		t.Errorf("len(f.Tracks) = %v; wanted %v", got, want)
+	}
+
+	expected := `
+@0 channel.ProgramChange channel 0 program 5
+@0 channel.ProgramChange channel 1 program 46
+@0 channel.ProgramChange channel 2 program 70
+@0 channel.NoteOn channel 2 key 48 velocity 96
+@0 channel.NoteOn channel 2 key 60 velocity 96
+@96 channel.NoteOn channel 1 key 67 velocity 64
+@192 channel.NoteOn channel 0 key 76 velocity 32
+@384 channel.NoteOff channel 0 key 76
+@384 channel.NoteOff channel 1 key 67
+@384 channel.NoteOff channel 2 key 48
+@384 channel.NoteOff channel 2 key 60
+end @384
+`
+
+	if got, want := trackString(merged), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if err := f.SplitTrack(1); err != nil {
+		t.Fatalf("can't split: %v", err)
+	}
+
+	// the track without channel messages and the tracks of the channels 0-2
+	if got, want := len(f.Tracks), 5; got != want {
+		t.Fatalf("len(f.Tracks) = %v; wanted %v", got, want)
+	}
+
+	expected = `
+@0 channel.ProgramChange channel 1 program 46
+@96 channel.NoteOn channel 1 key 67 velocity 64
+@384 channel.NoteOff channel 1 key 67
+end @384
+`
+
+	if got, want := trackString(f.Tracks[3]), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if _, err := f.MergeTracks(1, 7); err == nil {
+		t.Errorf("expected error for missing track")
+	}
+}
+
+func TestExtract(t *testing.T) {
+	f, err := smfreader.Load(bytes.NewReader(examples.SpecSMF1))
+
+	if err != nil {
+		t.Fatalf("can't load: %v", err)
+	}
+
+	ex, err := f.Extract(96, 300)
+
+	if err != nil {
+		t.Fatalf("can't extract: %v", err)
+	}
+
+	var out bytes.Buffer
+
+	for _, tr := range ex.Tracks {
+		out.WriteString(trackString(tr))
+	}
+
+	expected := `
+@0 meta.TimeSig 4/4 clocksperclick 24 dsqpq 8
+@0 meta.Tempo BPM: 120.00
+end @204
+
+@0 channel.ProgramChange channel 0 program 5
+@96 channel.NoteOn channel 0 key 76 velocity 32
+@204 channel.NoteOff channel 0 key 76
+end @204
+
+@0 channel.ProgramChange channel 1 program 46
+@0 channel.NoteOn channel 1 key 67 velocity 64
+@204 channel.NoteOff channel 1 key 67
+end @204
+
+@0 channel.ProgramChange channel 2 program 70
+end @204
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if _, err := f.Extract(96, 96); err == nil {
+		t.Errorf("expected error for empty range")
+	}
+}
@@ -0,0 +1,157 @@
+package smf
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/meta"
+)
+
+// Event is a message of a track at an absolute time
+type Event struct {
+	// AbsTicks is the absolute time of the event within its track in ticks
+	AbsTicks uint64
+
+	Message midi.Message
+}
+
+// Track is a track of a File. The end of track message is not part of the events but is added when saving.
+type Track struct {
+	// Events are the events of the track, ordered by time
+	Events []Event
+
+	// End is the time of the end of the track in ticks. When saving, the end of track message is
+	// written at End or after the last event, whatever is later.
+	End uint64
+}
+
+// Insert inserts the message at the given time after the events of the same time and returns its index
+func (t *Track) Insert(abs uint64, msg midi.Message) int {
+	i := sort.Search(len(t.Events), func(i int) bool {
+		return t.Events[i].AbsTicks > abs
+	})
+
+	t.Events = append(t.Events, Event{})
+	copy(t.Events[i+1:], t.Events[i:])
+	t.Events[i] = Event{AbsTicks: abs, Message: msg}
+	return i
+}
+
+// Delete removes the event with the given index
+func (t *Track) Delete(i int) {
+	t.Events = append(t.Events[:i], t.Events[i+1:]...)
+}
+
+// Move moves the event with the given index to the given time (after the events of that time) and returns its new index
+func (t *Track) Move(i int, abs uint64) int {
+	msg := t.Events[i].Message
+	t.Delete(i)
+	return t.Insert(abs, msg)
+}
+
+// File is a SMF file in memory that can be edited. Use Load to read a file and Save to write it.
+type File struct {
+	Format     Format
+	TimeFormat TimeFormat
+	Tracks     []*Track
+}
+
+// AddTrack adds an empty track and returns it
+func (f *File) AddTrack() *Track {
+	t := &Track{}
+	f.Tracks = append(f.Tracks, t)
+	return t
+}
+
+// Header returns the header of the file
+func (f *File) Header() Header {
+	return Header{Format: f.Format, NumTracks: uint16(len(f.Tracks)), TimeFormat: f.TimeFormat}
+}
+
+// Load reads the whole file from rd. The end of track messages set the ends of the tracks.
+// See smfreader.Load for reading from an io.Reader.
+func Load(rd Reader) (*File, error) {
+	if err := rd.ReadHeader(); err != nil {
+		return nil, err
+	}
+
+	h := rd.Header()
+	f := &File{Format: h.Format, TimeFormat: h.TimeFormat}
+
+	var (
+		abs   uint64
+		track int16 = -1
+	)
+
+	for {
+		msg, err := rd.Read()
+
+		if err == ErrFinished {
+			return f, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if rd.Track() != track {
+			track = rd.Track()
+			abs = 0
+		}
+
+		for int(rd.Track()) >= len(f.Tracks) {
+			f.AddTrack()
+		}
+
+		t := f.Tracks[rd.Track()]
+		abs += uint64(rd.Delta())
+
+		if msg == meta.EndOfTrack {
+			t.End = abs
+			continue
+		}
+
+		t.Events = append(t.Events, Event{AbsTicks: abs, Message: msg})
+	}
+}
+
+// Save writes the tracks to wr with the delta times computed from the absolute times.
+// The header of wr must match the header of the file (see smfwriter.Save for writing to an io.Writer).
+func (f *File) Save(wr Writer) error {
+	if n := wr.Header().NumTracks; int(n) != len(f.Tracks) {
+		return fmt.Errorf("writer for %v tracks can't write %v tracks", n, len(f.Tracks))
+	}
+
+	for i, t := range f.Tracks {
+		var last uint64
+
+		for j, ev := range t.Events {
+			if ev.AbsTicks < last {
+				return fmt.Errorf("track %v event %v: events not ordered by time", i, j)
+			}
+
+			if ev.Message == meta.EndOfTrack {
+				return fmt.Errorf("track %v event %v: end of track within the events", i, j)
+			}
+
+			wr.SetDelta(uint32(ev.AbsTicks - last))
+			last = ev.AbsTicks
+
+			if err := wr.Write(ev.Message); err != nil {
+				return err
+			}
+		}
+
+		if t.End > last {
+			wr.SetDelta(uint32(t.End - last))
+		}
+
+		// the writer returns ErrFinished after the last track
+		if err := wr.Write(meta.EndOfTrack); err != nil && err != ErrFinished {
+			return err
+		}
+	}
+
+	return nil
+}
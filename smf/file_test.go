@@ -0,0 +1,88 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package smf_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/internal/examples"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf/smfreader"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+func TestFile(t *testing.T) {
+	f, err := smfreader.Load(bytes.NewReader(examples.SpecSMF1))
+
+	if err != nil {
+		t.Fatalf("can't load: %v", err)
+	}
+
+	var bf bytes.Buffer
+
+	if err := smfwriter.Save(&bf, f); err != nil {
+		t.Fatalf("can't save: %v", err)
+	}
+
+	if got, want := fmt.Sprintf("% X", bf.Bytes()), fmt.Sprintf("% X", examples.SpecSMF1); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	tr := f.Tracks[1]
+
+	// the note on of track 1 is at 192
+	i := tr.Move(1, 480)
+	tr.Insert(0, channel.Channel0.ControlChange(7, 100))
+	tr.Delete(0) // the program change, the control change is inserted after it
+	tr.Insert(96, channel.Channel0.NoteOn(60, 100))
+
+	bf.Reset()
+
+	if err := smfwriter.Save(&bf, f); err != nil {
+		t.Fatalf("can't save: %v", err)
+	}
+
+	f, err = smfreader.Load(bytes.NewReader(bf.Bytes()))
+
+	if err != nil {
+		t.Fatalf("can't load: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for _, ev := range f.Tracks[1].Events {
+		out.WriteString(fmt.Sprintf("@%v %s\n", ev.AbsTicks, ev.Message))
+	}
+
+	out.WriteString(fmt.Sprintf("end @%v\n", f.Tracks[1].End))
+
+	expected := `
+@0 channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 100
+@96 channel.NoteOn channel 0 key 60 velocity 100
+@384 channel.NoteOff channel 0 key 76
+@480 channel.NoteOn channel 0 key 76 velocity 32
+end @480
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if i != 2 {
+		t.Errorf("Move returned %v; wanted 2", i)
+	}
+
+	var wr bytes.Buffer
+
+	if err := f.Save(smfwriter.New(&wr)); err == nil {
+		t.Errorf("expected error for writer with wrong number of tracks")
+	}
+
+	if got, want := f.Header().NumTracks, uint16(4); got != want {
+		t.Errorf("Header().NumTracks = %v; wanted %v", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+package smf
+
+import (
+	"iter"
+
+	"github.com/gomidi/midi"
+)
+
+// TrackEvent is a message of a SMF file together with its position
+type TrackEvent struct {
+	// Track is the number of the track (starting with 0)
+	Track int16
+
+	// Delta is the time distance to the previous event of the track in ticks
+	Delta uint32
+
+	// AbsTicks is the absolute time of the event within its track in ticks
+	AbsTicks uint64
+
+	Message midi.Message
+}
+
+// Events returns an iterator over the events of all tracks that are read from rd, e.g.
+//
+//	for ev, err := range smf.Events(rd) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(ev.Track, ev.AbsTicks, ev.Message)
+//	}
+//
+// The iteration ends at the end of the file (ErrFinished is not passed) or after the first error.
+// Breaking out of the loop stops the reading.
+func Events(rd Reader) iter.Seq2[TrackEvent, error] {
+	return func(yield func(TrackEvent, error) bool) {
+		var abs uint64
+		track := int16(-1)
+
+		for {
+			msg, err := rd.Read()
+
+			if err == ErrFinished {
+				return
+			}
+
+			if err != nil {
+				yield(TrackEvent{Track: rd.Track()}, err)
+				return
+			}
+
+			if rd.Track() != track {
+				track = rd.Track()
+				abs = 0
+			}
+
+			abs += uint64(rd.Delta())
+
+			if !yield(TrackEvent{Track: track, Delta: rd.Delta(), AbsTicks: abs, Message: msg}, nil) {
+				return
+			}
+		}
+	}
+}
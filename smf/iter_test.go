@@ -0,0 +1,59 @@
+package smf_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/internal/examples"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+func TestEvents(t *testing.T) {
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for ev, err := range smf.Events(smfreader.New(bytes.NewReader(examples.SpecSMF1))) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if ev.Track < 2 {
+			continue
+		}
+
+		fmt.Fprintf(&out, "Track %v@%v+%v %s\n", ev.Track, ev.AbsTicks, ev.Delta, ev.Message)
+
+		// early exit
+		if ev.Track == 3 && ev.AbsTicks > 0 {
+			break
+		}
+	}
+
+	expected := `
+Track 2@0+0 channel.ProgramChange channel 1 program 46
+Track 2@96+96 channel.NoteOn channel 1 key 67 velocity 64
+Track 2@384+288 channel.NoteOff channel 1 key 67
+Track 2@384+0 meta.EndOfTrack
+Track 3@0+0 channel.ProgramChange channel 2 program 70
+Track 3@0+0 channel.NoteOn channel 2 key 48 velocity 96
+Track 3@0+0 channel.NoteOn channel 2 key 60 velocity 96
+Track 3@384+384 channel.NoteOff channel 2 key 48
+`
+
+	if got := out.String(); got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+
+	var errs int
+	for _, err := range smf.Events(smfreader.New(bytes.NewReader(examples.SpecSMF1[:30]))) {
+		if err != nil {
+			errs++
+		}
+	}
+
+	if errs != 1 {
+		t.Errorf("got %v errors for a truncated file, wanted 1", errs)
+	}
+}
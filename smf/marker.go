@@ -0,0 +1,86 @@
+package smf
+
+import (
+	"sort"
+
+	"github.com/gomidi/midi/midimessage/meta"
+)
+
+// Marker is a marker or cue point of a File
+type Marker struct {
+	// Track is the index of the track
+	Track int
+
+	// AbsTicks is the absolute time of the marker in ticks
+	AbsTicks uint64
+
+	Name string
+
+	// Cuepoint is true for cue points, false for markers
+	Cuepoint bool
+}
+
+// Markers returns the markers and cue points of all tracks, ordered by time
+func (f *File) Markers() (res []Marker) {
+	for i, t := range f.Tracks {
+		for _, ev := range t.Events {
+			switch m := ev.Message.(type) {
+			case meta.Marker:
+				res = append(res, Marker{Track: i, AbsTicks: ev.AbsTicks, Name: string(m)})
+			case meta.Cuepoint:
+				res = append(res, Marker{Track: i, AbsTicks: ev.AbsTicks, Name: string(m), Cuepoint: true})
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(a, b int) bool {
+		return res[a].AbsTicks < res[b].AbsTicks
+	})
+
+	return
+}
+
+// AddMarker adds a marker with the given name at the given time to the first track (that is created if needed)
+func (f *File) AddMarker(abs uint64, name string) {
+	f.firstTrack().Insert(abs, meta.Marker(name))
+}
+
+// AddCuepoint adds a cue point with the given name at the given time to the first track (that is created if needed)
+func (f *File) AddCuepoint(abs uint64, name string) {
+	f.firstTrack().Insert(abs, meta.Cuepoint(name))
+}
+
+// FindMarker returns the first marker or cue point with the given name
+func (f *File) FindMarker(name string) (Marker, bool) {
+	for _, m := range f.Markers() {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Marker{}, false
+}
+
+// MarkerAt returns the last marker or cue point at or before the given time, e.g. the section that is playing
+func (f *File) MarkerAt(abs uint64) (Marker, bool) {
+	var (
+		res   Marker
+		found bool
+	)
+
+	for _, m := range f.Markers() {
+		if m.AbsTicks > abs {
+			break
+		}
+		res, found = m, true
+	}
+
+	return res, found
+}
+
+// firstTrack returns the first track, that is created if there is none
+func (f *File) firstTrack() *Track {
+	if len(f.Tracks) == 0 {
+		return f.AddTrack()
+	}
+	return f.Tracks[0]
+}
@@ -0,0 +1,37 @@
+package smf_test
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf"
+)
+
+func TestMarkers(t *testing.T) {
+	f := &smf.File{}
+	f.AddMarker(960, "chorus")
+	f.AddCuepoint(480, "door slam")
+	f.AddMarker(0, "verse")
+	f.AddTrack().Insert(100, channel.Channel0.NoteOn(60, 100))
+
+	var got string
+	for _, m := range f.Markers() {
+		got += m.Name + ";"
+	}
+
+	if want := "verse;door slam;chorus;"; got != want {
+		t.Errorf("Markers() = %v; wanted %v", got, want)
+	}
+
+	if m, found := f.FindMarker("door slam"); !found || m.AbsTicks != 480 || !m.Cuepoint {
+		t.Errorf("FindMarker() = %v, %v", m, found)
+	}
+
+	if _, found := f.FindMarker("bridge"); found {
+		t.Errorf("FindMarker() found missing marker")
+	}
+
+	if m, found := f.MarkerAt(959); !found || m.Name != "door slam" {
+		t.Errorf("MarkerAt(959) = %v, %v", m, found)
+	}
+}
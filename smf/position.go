@@ -0,0 +1,168 @@
+package smf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gomidi/midi/midimessage/meta"
+)
+
+// Position is a musical position. Bars and beats start with 1, Tick is the offset within the beat.
+// The beat is the note value of the denominator of the time signature, e.g. an eighth note in 6/8.
+type Position struct {
+	Bar  uint32
+	Beat uint32
+	Tick uint32
+}
+
+// String returns the position in the form bar:beat:tick, e.g. 3:2:120
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d:%d", p.Bar, p.Beat, p.Tick)
+}
+
+// meterChange is a change of the time signature that starts a new bar
+type meterChange struct {
+	abs       uint64
+	bar       uint32 // the number of the bar that starts at abs (starting with 0)
+	beats     uint32 // beats per bar
+	beatTicks uint64 // ticks per beat
+}
+
+// Timeline converts between absolute times in ticks, musical positions and real time, based on the
+// time signature and tempo messages of a file. Without time signature, 4/4 is assumed and without tempo, 120 BPM.
+// A time signature change that is not at the start of a bar starts a new bar.
+type Timeline struct {
+	ticks4th uint64
+	meters   []meterChange
+	tempos   *tempoMap
+}
+
+// NewTimeline returns the Timeline of the file, that must have a metric time format.
+// The time signatures and tempos are taken from all tracks, so the Timeline does not fit SMF2 files whose
+// tracks have their own time signatures and tempos; use a File with the single track in this case.
+func NewTimeline(f *File) (*Timeline, error) {
+	resolution, is := f.TimeFormat.(MetricTicks)
+
+	if !is {
+		return nil, fmt.Errorf("time format %v has no musical time", f.TimeFormat)
+	}
+
+	tl := &Timeline{ticks4th: uint64(resolution.Ticks4th()), tempos: newTempoMap(resolution, f.Tracks...)}
+
+	var sigs []Event
+
+	for _, t := range f.Tracks {
+		for _, ev := range t.Events {
+			if ts, is := ev.Message.(meta.TimeSig); is && ts.Numerator > 0 && ts.Denominator > 0 {
+				sigs = append(sigs, ev)
+			}
+		}
+	}
+
+	sort.SliceStable(sigs, func(a, b int) bool {
+		return sigs[a].AbsTicks < sigs[b].AbsTicks
+	})
+
+	tl.meters = []meterChange{tl.meter(0, 0, meta.TimeSig{Numerator: 4, Denominator: 4})}
+
+	for _, ev := range sigs {
+		last := tl.meters[len(tl.meters)-1]
+		ts := ev.Message.(meta.TimeSig)
+
+		if ev.AbsTicks == last.abs {
+			tl.meters[len(tl.meters)-1] = tl.meter(last.abs, last.bar, ts)
+			continue
+		}
+
+		// a change within a bar starts a new bar
+		bars := (ev.AbsTicks - last.abs + last.barTicks() - 1) / last.barTicks()
+
+		tl.meters = append(tl.meters, tl.meter(ev.AbsTicks, last.bar+uint32(bars), ts))
+	}
+
+	return tl, nil
+}
+
+// meter returns the meter change for the time signature
+func (tl *Timeline) meter(abs uint64, bar uint32, ts meta.TimeSig) meterChange {
+	beatTicks := tl.ticks4th * 4 / uint64(ts.Denominator)
+	if beatTicks == 0 {
+		beatTicks = 1
+	}
+	return meterChange{abs: abs, bar: bar, beats: uint32(ts.Numerator), beatTicks: beatTicks}
+}
+
+// barTicks returns the length of a bar in ticks
+func (m meterChange) barTicks() uint64 {
+	return uint64(m.beats) * m.beatTicks
+}
+
+// Position returns the musical position of the absolute time in ticks
+func (tl *Timeline) Position(abs uint64) Position {
+	i := sort.Search(len(tl.meters), func(i int) bool {
+		return tl.meters[i].abs > abs
+	}) - 1
+
+	m := tl.meters[i]
+	rel := abs - m.abs
+	bar := rel / m.barTicks()
+	rel -= bar * m.barTicks()
+
+	return Position{
+		Bar:  m.bar + uint32(bar) + 1,
+		Beat: uint32(rel/m.beatTicks) + 1,
+		Tick: uint32(rel % m.beatTicks),
+	}
+}
+
+// AbsTicks returns the absolute time in ticks of the musical position.
+// Beats and ticks beyond the bar or beat are added, bar and beat 0 are treated as 1.
+func (tl *Timeline) AbsTicks(p Position) uint64 {
+	bar := uint32(0)
+	if p.Bar > 0 {
+		bar = p.Bar - 1
+	}
+
+	beat := uint64(0)
+	if p.Beat > 0 {
+		beat = uint64(p.Beat - 1)
+	}
+
+	i := sort.Search(len(tl.meters), func(i int) bool {
+		return tl.meters[i].bar > bar
+	}) - 1
+
+	m := tl.meters[i]
+	return m.abs + uint64(bar-m.bar)*m.barTicks() + beat*m.beatTicks + uint64(p.Tick)
+}
+
+// Time returns the real time of the absolute time in ticks
+func (tl *Timeline) Time(abs uint64) time.Duration {
+	return time.Duration(math.Round(tl.tempos.micros(abs) * float64(time.Microsecond)))
+}
+
+// TicksAt returns the absolute time in ticks that is played at the given real time (rounded down)
+func (tl *Timeline) TicksAt(d time.Duration) uint64 {
+	if d <= 0 {
+		return 0
+	}
+
+	var (
+		micros = float64(d) / float64(time.Microsecond)
+		last   uint64
+		at     float64
+		tempo  float64 = defaultTempo
+	)
+
+	for _, c := range tl.tempos.changes {
+		next := at + float64(c.abs-last)*tempo/float64(tl.ticks4th)
+		if next > micros {
+			break
+		}
+		last, at, tempo = c.abs, next, c.tempo
+	}
+
+	return last + uint64(math.Floor((micros-at)*float64(tl.ticks4th)/tempo+1e-9))
+}
@@ -0,0 +1,67 @@
+package smf_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+)
+
+func TestTimeline(t *testing.T) {
+	f := &smf.File{TimeFormat: smf.MetricTicks(96)}
+	tr := f.AddTrack()
+
+	// 2 bars of 4/4, then 6/8 from bar 3, then 3/4 in the middle of the second 6/8 bar, which starts bar 5
+	tr.Insert(0, meta.TimeSig{Numerator: 4, Denominator: 4})
+	tr.Insert(768, meta.TimeSig{Numerator: 6, Denominator: 8})
+	tr.Insert(768+288+96, meta.TimeSig{Numerator: 3, Denominator: 4})
+	tr.Insert(0, meta.BPM(120))
+	tr.Insert(384, meta.BPM(60))
+
+	tl, err := smf.NewTimeline(f)
+
+	if err != nil {
+		t.Fatalf("can't create timeline: %v", err)
+	}
+
+	tests := []struct {
+		abs      uint64
+		position string
+	}{
+		{0, "1:1:0"},
+		{95, "1:1:95"},
+		{96, "1:2:0"},
+		{384 + 200, "2:3:8"},
+		{768, "3:1:0"},
+		{768 + 48, "3:2:0"},
+		{768 + 288 + 50, "4:2:2"},
+		{768 + 288 + 96, "5:1:0"},
+		{768 + 288 + 96 + 3*96 + 1, "6:1:1"},
+	}
+
+	for i, test := range tests {
+		p := tl.Position(test.abs)
+
+		if got, want := p.String(), test.position; got != want {
+			t.Errorf("[%v] Position(%v) = %v; wanted %v", i, test.abs, got, want)
+		}
+
+		if got, want := tl.AbsTicks(p), test.abs; got != want {
+			t.Errorf("[%v] AbsTicks(%v) = %v; wanted %v", i, p, got, want)
+		}
+	}
+
+	// 2 seconds at 120 BPM, then 1 beat at 60 BPM
+	if got, want := tl.Time(384+96), 3*time.Second; got != want {
+		t.Errorf("Time = %v; wanted %v", got, want)
+	}
+
+	if got, want := tl.TicksAt(3*time.Second), uint64(384+96); got != want {
+		t.Errorf("TicksAt = %v; wanted %v", got, want)
+	}
+
+	if got, want := tl.TicksAt(time.Second), uint64(192); got != want {
+		t.Errorf("TicksAt = %v; wanted %v", got, want)
+	}
+}
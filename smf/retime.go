@@ -0,0 +1,47 @@
+package smf
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Retime converts the file to the given resolution of ticks per quarter note.
+// The absolute times of the events are rescaled and rounded to the nearest tick, so that the rounding errors
+// don't add up over the delta times. Events that end up at the same time keep their order.
+// It returns an error if the file does not have a metric time format.
+func Retime(f *File, resolution MetricTicks) error {
+	old, is := f.TimeFormat.(MetricTicks)
+
+	if !is {
+		return fmt.Errorf("can't retime time format %v", f.TimeFormat)
+	}
+
+	from, to := uint64(old.Ticks4th()), uint64(resolution.Ticks4th())
+
+	for _, t := range f.Tracks {
+		for i := range t.Events {
+			t.Events[i].AbsTicks = rescale(t.Events[i].AbsTicks, from, to)
+		}
+		t.End = rescale(t.End, from, to)
+	}
+
+	f.TimeFormat = MetricTicks(resolution.Number())
+	return nil
+}
+
+// rescale returns abs*to/from rounded to the nearest integer (halves are rounded up)
+func rescale(abs, from, to uint64) uint64 {
+	if from == to {
+		return abs
+	}
+
+	var n, rem big.Int
+	n.SetUint64(abs)
+	n.Mul(&n, new(big.Int).SetUint64(to))
+	n.QuoRem(&n, new(big.Int).SetUint64(from), &rem)
+
+	if 2*rem.Uint64() >= from {
+		return n.Uint64() + 1
+	}
+	return n.Uint64()
+}
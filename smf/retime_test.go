@@ -0,0 +1,43 @@
+package smf_test
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf"
+)
+
+func TestRetime(t *testing.T) {
+	f := &smf.File{TimeFormat: smf.MetricTicks(960)}
+	tr := f.AddTrack()
+
+	// deltas of 7 ticks would all be rounded to 0 at 24 ticks per quarter, if they were rescaled one by one
+	for i := uint64(0); i < 100; i++ {
+		tr.Insert(i*7, channel.Channel0.NoteOn(60, 100))
+	}
+	tr.End = 100 * 7
+
+	if err := smf.Retime(f, smf.MetricTicks(24)); err != nil {
+		t.Fatalf("can't retime: %v", err)
+	}
+
+	for i, ev := range tr.Events {
+		// no drift means the error stays within half a tick
+		exact := float64(i) * 7 * 24 / 960
+		if d := float64(ev.AbsTicks) - exact; d < -0.5 || d > 0.5 {
+			t.Errorf("[%v] @%v; wanted about %v", i, ev.AbsTicks, exact)
+		}
+	}
+
+	if got, want := tr.End, uint64(18); got != want {
+		t.Errorf("End = %v; wanted %v", got, want)
+	}
+
+	if got, want := f.TimeFormat, smf.TimeFormat(smf.MetricTicks(24)); got != want {
+		t.Errorf("TimeFormat = %v; wanted %v", got, want)
+	}
+
+	if err := smf.Retime(&smf.File{TimeFormat: smf.TimeCode{FramesPerSecond: 25, SubFrames: 40}}, smf.MetricTicks(96)); err == nil {
+		t.Errorf("expected error for time code")
+	}
+}
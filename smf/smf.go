@@ -40,7 +40,7 @@ type Writer interface {
 	// - The number of tracks that are written will never execeed the NumTracks that have been defined when creating the writer.
 	//   If the last track has been written, io.EOF will be returned. (Also for any further attempt to write).
 	// - It is the responsibility of the caller to make sure the provided NumTracks (which defaults to 1) is not
-	//   larger as the number of tracks in the file.
+	//   larger as the number of tracks in the file (see smfwriter.Finisher for a writer that corrects it).
 	// Any error stops the writing, is tracked and prohibits further writing.
 	// At the end smf.ErrFinished will be returned
 	Write(midi.Message) error
@@ -176,6 +176,21 @@ func (c *Chunk) Write(b []byte) (int, error) {
 	return len(b), nil
 }
 
+// UnknownChunk is a chunk that is neither a header nor a track chunk, e.g. proprietary data of a sequencer
+type UnknownChunk struct {
+	// Type is the 4 character type of the chunk, e.g. "XFIH"
+	Type string
+
+	// Data is the body of the chunk
+	Data []byte
+
+	// Track is the number of the track before the chunk (starting with 0), -1 if the chunk comes before the first track
+	Track int16
+
+	// Offset is the byte offset of the chunk in the file (set by the reader, ignored by the writer)
+	Offset int64
+}
+
 var (
 	_ TimeFormat = MetricTicks(0)
 	_ TimeFormat = TimeCode{}
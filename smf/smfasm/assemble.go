@@ -0,0 +1,233 @@
+package smfasm
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gomidi/midi/midijson"
+	"github.com/gomidi/midi/note"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+// Assemble reads the text form from src and writes the SMF file to dest.
+// The options are passed to the writer after the options for the header.
+// The error tells the line of the text that could not be assembled.
+func Assemble(dest io.Writer, src io.Reader, options ...smfwriter.Option) error {
+	file, err := parse(src)
+	if err != nil {
+		return err
+	}
+	return midijson.WriteSMF(dest, file, options...)
+}
+
+// parse parses the text form
+func parse(src io.Reader) (*midijson.SMF, error) {
+	var (
+		sc     = bufio.NewScanner(src)
+		file   *midijson.SMF
+		lineNo int
+	)
+
+	sc.Buffer(nil, 1<<24)
+
+	for sc.Scan() {
+		lineNo++
+
+		tokens, err := tokenize(sc.Text())
+
+		switch {
+		case err != nil:
+		case len(tokens) == 0:
+			continue
+		case tokens[0] == "MThd":
+			if file != nil {
+				err = errors.New("duplicate header")
+				break
+			}
+			file = &midijson.SMF{Tracks: []midijson.Track{}}
+			err = parseHeader(file, tokens[1:])
+		case file == nil:
+			err = errors.New("expected header")
+		case tokens[0] == "MTrk":
+			if len(tokens) > 1 {
+				err = fmt.Errorf("unexpected %q", tokens[1])
+				break
+			}
+			file.Tracks = append(file.Tracks, midijson.Track{})
+		case len(file.Tracks) == 0:
+			err = errors.New("expected track")
+		default:
+			var ev midijson.Event
+			ev, err = parseEvent(tokens)
+			tr := &file.Tracks[len(file.Tracks)-1]
+			*tr = append(*tr, ev)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if file == nil {
+		return nil, errors.New("missing header")
+	}
+
+	return file, nil
+}
+
+// parseHeader sets the properties of the header
+func parseHeader(file *midijson.SMF, props []string) error {
+	for _, prop := range props {
+		name, val, _ := strings.Cut(prop, "=")
+
+		switch name {
+		case "format":
+			n, err := strconv.ParseUint(val, 10, 16)
+			if err != nil || n > 2 {
+				return fmt.Errorf("invalid format %q", val)
+			}
+			file.Format = uint16(n)
+		case "ticks":
+			n, err := strconv.ParseUint(val, 10, 15)
+			if err != nil {
+				return fmt.Errorf("invalid ticks %q", val)
+			}
+			file.TicksPerQuarter = uint16(n)
+		case "timecode":
+			fps, sub, _ := strings.Cut(val, "/")
+			f, err1 := strconv.ParseUint(fps, 10, 8)
+			s, err2 := strconv.ParseUint(sub, 10, 8)
+			if err1 != nil || err2 != nil {
+				return fmt.Errorf("invalid timecode %q", val)
+			}
+			file.TimeCode = &midijson.TimeCode{FramesPerSecond: uint8(f), SubFrames: uint8(s)}
+		default:
+			return fmt.Errorf("unknown header property %q", name)
+		}
+	}
+
+	return nil
+}
+
+// parseEvent parses the delta, the type and the properties of an event
+func parseEvent(tokens []string) (ev midijson.Event, err error) {
+	delta, err := strconv.ParseUint(tokens[0], 10, 32)
+	if err != nil {
+		return ev, fmt.Errorf("invalid delta %q", tokens[0])
+	}
+
+	if len(tokens) < 2 {
+		return ev, errors.New("missing message type")
+	}
+
+	props := map[string]interface{}{"type": tokens[1]}
+
+	for _, prop := range tokens[2:] {
+		name, val, found := strings.Cut(prop, "=")
+
+		if !found || name == "" || name == "type" {
+			return ev, fmt.Errorf("invalid property %q", prop)
+		}
+
+		props[name], err = parseValue(val)
+		if err != nil {
+			return ev, fmt.Errorf("property %s: %v", name, err)
+		}
+	}
+
+	b, err := json.Marshal(props)
+	if err != nil {
+		return ev, err
+	}
+
+	msg, err := midijson.Unmarshal(b)
+	if err != nil {
+		return ev, err
+	}
+
+	ev.Delta = uint32(delta)
+	ev.Message.Message = msg
+	return ev, nil
+}
+
+// parseValue returns the value of a property
+func parseValue(val string) (interface{}, error) {
+	if strings.HasPrefix(val, `"`) {
+		return strconv.Unquote(val)
+	}
+
+	if val == "true" || val == "false" {
+		return val == "true", nil
+	}
+
+	if key, err := note.Parse(val); err == nil {
+		return key, nil
+	}
+
+	if _, err := strconv.ParseFloat(val, 64); err != nil {
+		return nil, fmt.Errorf("invalid value %q", val)
+	}
+
+	return json.Number(val), nil
+}
+
+// tokenize splits the line at white space and removes the comment. Quoted strings may contain white space and ;
+func tokenize(line string) (tokens []string, err error) {
+	var (
+		token   []byte
+		quoted  bool
+		escaped bool
+	)
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		switch {
+		case quoted:
+			token = append(token, c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				quoted = false
+			}
+			continue
+		case c == ';':
+			i = len(line)
+		case c == ' ' || c == '\t':
+		case c == '"':
+			quoted = true
+			token = append(token, c)
+			continue
+		default:
+			token = append(token, c)
+			continue
+		}
+
+		if len(token) > 0 {
+			tokens = append(tokens, string(token))
+			token = nil
+		}
+	}
+
+	if quoted {
+		return nil, errors.New("unterminated quoted string")
+	}
+
+	if len(token) > 0 {
+		tokens = append(tokens, string(token))
+	}
+
+	return tokens, nil
+}
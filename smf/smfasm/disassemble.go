@@ -0,0 +1,109 @@
+package smfasm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gomidi/midi/midijson"
+	"github.com/gomidi/midi/note"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+// commentColumn is the column of the annotations
+const commentColumn = 56
+
+// Disassemble reads the SMF file from src and writes its text form to dest.
+// The options are passed to the reader.
+func Disassemble(dest io.Writer, src io.Reader, options ...smfreader.Option) error {
+	// keep the difference between noteoff messages and noteon messages with velocity 0
+	file, err := midijson.ReadSMF(src, append([]smfreader.Option{smfreader.NoteOffVelocity()}, options...)...)
+
+	if err != nil {
+		return err
+	}
+
+	wr := bufio.NewWriter(dest)
+
+	if file.TimeCode != nil {
+		fmt.Fprintf(wr, "MThd format=%d timecode=%d/%d\n", file.Format, file.TimeCode.FramesPerSecond, file.TimeCode.SubFrames)
+	} else {
+		fmt.Fprintf(wr, "MThd format=%d ticks=%d\n", file.Format, file.TicksPerQuarter)
+	}
+
+	for i, tr := range file.Tracks {
+		fmt.Fprintf(wr, "\nMTrk ; track %d\n", i+1)
+
+		var abs uint64
+
+		for _, ev := range tr {
+			abs += uint64(ev.Delta)
+
+			instr, err := instruction(ev)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(wr, "%-*s ; @%d % X\n", commentColumn, instr, abs, ev.Message.Raw())
+		}
+	}
+
+	return wr.Flush()
+}
+
+// noteTypes are the message types whose key property is written as note name
+var noteTypes = map[string]bool{
+	"channel.NoteOn":          true,
+	"channel.NoteOff":         true,
+	"channel.NoteOffVelocity": true,
+	"channel.PolyAftertouch":  true,
+}
+
+// instruction returns the line for the event (without the comment)
+func instruction(ev midijson.Event) (string, error) {
+	b, err := ev.Message.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	var props map[string]json.RawMessage
+
+	if err := json.Unmarshal(b, &props); err != nil {
+		return "", err
+	}
+
+	var typ string
+	json.Unmarshal(props["type"], &typ)
+	delete(props, "type")
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var bf strings.Builder
+	fmt.Fprintf(&bf, "%6d %s", ev.Delta, typ)
+
+	for _, name := range names {
+		val := string(props[name])
+
+		switch {
+		case val[0] == '"':
+			var s string
+			json.Unmarshal(props[name], &s)
+			val = strconv.Quote(s)
+		case name == "key" && noteTypes[typ]:
+			n, _ := strconv.Atoi(val)
+			val = note.Name(uint8(n))
+		}
+
+		fmt.Fprintf(&bf, " %s=%s", name, val)
+	}
+
+	return bf.String(), nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package smfasm disassembles SMF files into an editable text form ("MIDI assembly") and assembles the text back
+into SMF files. It is meant for debugging generated files and for writing small test files by hand.
+
+The text starts with the header, followed by the tracks. Each event is written on its own line with the delta time
+in ticks, the type of the message and its properties (the same as in the JSON representation of the midijson package).
+Keys of notes are written as note names (60 is C4). Everything after a ; is a comment; the disassembler annotates
+each event with its absolute time in ticks and its raw bytes.
+
+	MThd format=1 ticks=480
+
+	MTrk ; track 1
+	     0 meta.Tempo bpm=120                                ; @0 FF 51 03 07 A1 20
+	     0 meta.EndOfTrack                                   ; @0 FF 2F 00
+
+	MTrk ; track 2
+	     0 channel.NoteOn channel=0 key=C4 velocity=100      ; @0 90 3C 64
+	   480 channel.NoteOff channel=0 key=C4                  ; @480 90 3C 00
+	     0 meta.Text text="end"                              ; @480 FF 01 03 65 6E 64
+	     0 meta.EndOfTrack                                   ; @480 FF 2F 00
+
+Instead of ticks, the header may define a SMPTE time format with timecode=<frames per second>/<subframes>, e.g. timecode=25/40.
+Strings are quoted like Go strings. Sysex and other binary data is written as a quoted string of hexadecimal bytes.
+A missing end of track is added by the assembler.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/smf/smfasm"
+	)
+
+	// given some SMF file and some output
+	var (
+		file   io.Reader
+		output io.Writer
+	)
+
+	err := smfasm.Disassemble(output, file)
+
+	// and back
+	err = smfasm.Assemble(output, textFile)
+
+*/
+package smfasm
@@ -0,0 +1,99 @@
+package smfasm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/sysex"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var file bytes.Buffer
+
+	wr := smfwriter.New(&file, smfwriter.NumTracks(2), smfwriter.TimeFormat(smf.MetricTicks(480)))
+
+	wr.Write(meta.BPM(120))
+	wr.Write(meta.EndOfTrack)
+
+	wr.Write(meta.Track("Piano; \"left\""))
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+	wr.Write(channel.Channel0.NoteOn(66, 100))
+	wr.SetDelta(480)
+	wr.Write(channel.Channel0.NoteOff(60))
+	wr.Write(channel.Channel0.NoteOffVelocity(66, 20))
+	wr.Write(sysex.SysEx{0x7E, 0x7F, 0x09, 0x01})
+	wr.Write(meta.EndOfTrack)
+
+	original := file.Bytes()
+
+	var text bytes.Buffer
+
+	if err := Disassemble(&text, bytes.NewReader(original)); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := `MThd format=1 ticks=480
+
+MTrk ; track 1
+     0 meta.Tempo bpm=120                                ; @0 FF 51 03 07 A1 20
+     0 meta.EndOfTrack                                   ; @0 FF 2F 00
+
+MTrk ; track 2
+     0 meta.Track text="Piano; \"left\""                 ; @0 FF 04 0D 50 69 61 6E 6F 3B 20 22 6C 65 66 74 22
+     0 channel.NoteOn channel=0 key=C4 velocity=100      ; @0 90 3C 64
+     0 channel.NoteOn channel=0 key=F#4 velocity=100     ; @0 90 42 64
+   480 channel.NoteOff channel=0 key=C4                  ; @480 90 3C 00
+     0 channel.NoteOffVelocity channel=0 key=F#4 velocity=20 ; @480 80 42 14
+     0 sysex.SysEx data="7E 7F 09 01"                    ; @480 F0 7E 7F 09 01 F7
+     0 meta.EndOfTrack                                   ; @480 FF 2F 00
+`
+
+	if got, want := text.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	var back bytes.Buffer
+
+	if err := Assemble(&back, strings.NewReader(text.String())); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if got, want := fmt.Sprintf("% X", back.Bytes()), fmt.Sprintf("% X", original); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestAssembleErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"MTrk\n", "line 1: expected header"},
+		{"MThd format=3\n", `line 1: invalid format "3"`},
+		{"MThd\n0 meta.EndOfTrack\n", "line 2: expected track"},
+		{"MThd\nMTrk\n0 channel.NoteOn channel=0 key=H4\n", `line 3: property key: invalid value "H4"`},
+		{"MThd\nMTrk\n0 channel.NoteOn channel=0 key=C4 velocity=200\n", "line 3: invalid channel.NoteOn: velocity 200 out of range"},
+		{"MThd\nMTrk\n0 meta.Text text=\"abc\n", "line 3: unterminated quoted string"},
+		{"MThd\nMTrk\nx meta.EndOfTrack\n", `line 3: invalid delta "x"`},
+		{"; nothing\n", "missing header"},
+	}
+
+	for i, test := range tests {
+		err := Assemble(&bytes.Buffer{}, strings.NewReader(test.input))
+
+		if err == nil {
+			t.Errorf("[%v] expected error", i)
+			continue
+		}
+
+		if got, want := err.Error(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
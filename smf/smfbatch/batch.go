@@ -0,0 +1,182 @@
+package smfbatch
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+// Func processes a single SMF file. It is called concurrently for different files.
+// The reader has already read the header. The function may read until smf.ErrFinished
+// or return early (with or without an error).
+type Func func(file string, rd smf.Reader) error
+
+// FileError is an error that happened while processing a file
+type FileError struct {
+	File string
+	Err  error
+}
+
+// Error returns the error message
+func (f FileError) Error() string {
+	return fmt.Sprintf("%s: %v", f.File, f.Err)
+}
+
+// Result are the aggregated statistics of a batch run
+type Result struct {
+	// Files is the number of processed files
+	Files int
+
+	// Failed is the number of files for which an error happened
+	Failed int
+
+	// Messages is the number of MIDI messages that have been read from all files
+	Messages uint64
+
+	// Duration is the time it took to process all files
+	Duration time.Duration
+
+	// Errors are the errors per file, in the order of the given files
+	Errors []FileError
+}
+
+type config struct {
+	workers int
+	options []smfreader.Option
+}
+
+// Option is an option for Run
+type Option func(*config)
+
+// Workers sets the number of files that are processed concurrently.
+// It defaults to runtime.NumCPU().
+func Workers(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.workers = n
+		}
+	}
+}
+
+// ReaderOptions sets the options for the smfreader that reads each file
+func ReaderOptions(options ...smfreader.Option) Option {
+	return func(c *config) {
+		c.options = options
+	}
+}
+
+type job struct {
+	no   int
+	file string
+}
+
+type failure struct {
+	no int
+	FileError
+}
+
+// Run processes the given files with fn by a pool of workers and returns the aggregated result.
+// It returns when all files have been processed.
+func Run(files []string, fn Func, opts ...Option) (res Result) {
+	c := &config{workers: runtime.NumCPU()}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var (
+		start    = time.Now()
+		jobs     = make(chan job)
+		wg       sync.WaitGroup
+		mx       sync.Mutex
+		failures []failure
+		messages uint64
+	)
+
+	for i := 0; i < c.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				n, err := process(j.file, fn, c.options)
+				atomic.AddUint64(&messages, n)
+				if err != nil {
+					mx.Lock()
+					failures = append(failures, failure{j.no, FileError{j.file, err}})
+					mx.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i, file := range files {
+		jobs <- job{i, file}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(failures, func(a, b int) bool {
+		return failures[a].no < failures[b].no
+	})
+
+	for _, f := range failures {
+		res.Errors = append(res.Errors, f.FileError)
+	}
+
+	res.Files = len(files)
+	res.Failed = len(failures)
+	res.Messages = messages
+	res.Duration = time.Since(start)
+	return
+}
+
+func process(file string, fn Func, options []smfreader.Option) (n uint64, err error) {
+	var fnErr error
+
+	cr := &countingReader{}
+
+	err = smfreader.ReadFile(file, func(rd smf.Reader) {
+		cr.Reader = rd
+		fnErr = fn(file, cr)
+		n = cr.count
+	}, options...)
+
+	if fnErr != nil {
+		return n, fnErr
+	}
+
+	// the tracks are only missing, if the file has been read until its end
+	if err == smfreader.ErrMissing && !cr.done {
+		return n, nil
+	}
+
+	return n, err
+}
+
+// countingReader counts the messages that have been read
+type countingReader struct {
+	smf.Reader
+	count uint64
+
+	// done is true, if the reading has ended with an error (e.g. smf.ErrFinished)
+	done bool
+}
+
+// Read reads a message and counts it
+func (c *countingReader) Read() (midi.Message, error) {
+	m, err := c.Reader.Read()
+	if err == nil {
+		c.count++
+	} else {
+		c.done = true
+	}
+	return m, err
+}
@@ -0,0 +1,143 @@
+package smfbatch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "smfbatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var files []string
+
+	for i := 0; i < 5; i++ {
+		file := filepath.Join(dir, string(rune('a'+i))+".mid")
+		err = smfwriter.WriteFile(file, func(wr smf.Writer) {
+			wr.Write(channel.Channel1.NoteOn(60, 100))
+			wr.SetDelta(96)
+			wr.Write(channel.Channel1.NoteOff(60))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	broken := filepath.Join(dir, "broken.mid")
+	ioutil.WriteFile(broken, []byte("MThd"), 0644)
+	files = append(files[:2], append([]string{broken}, files[2:]...)...)
+
+	var notes uint64
+
+	res := Run(files, func(file string, rd smf.Reader) error {
+		for {
+			m, err := rd.Read()
+
+			if err == smf.ErrFinished {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if _, is := m.(channel.NoteOn); is {
+				atomic.AddUint64(&notes, 1)
+			}
+		}
+	}, Workers(3))
+
+	if got, want := res.Files, 6; got != want {
+		t.Errorf("Files = %v; wanted %v", got, want)
+	}
+
+	if got, want := res.Failed, 1; got != want {
+		t.Fatalf("Failed = %v; wanted %v", got, want)
+	}
+
+	if got, want := res.Errors[0].File, broken; got != want {
+		t.Errorf("Errors[0].File = %v; wanted %v", got, want)
+	}
+
+	// NoteOn, NoteOff and EndOfTrack per file
+	if got, want := res.Messages, uint64(15); got != want {
+		t.Errorf("Messages = %v; wanted %v", got, want)
+	}
+
+	if got, want := notes, uint64(5); got != want {
+		t.Errorf("notes = %v; wanted %v", got, want)
+	}
+}
+
+func TestRunEarlyReturn(t *testing.T) {
+	dir, err := ioutil.TempDir("", "smfbatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "a.mid")
+	err = smfwriter.WriteFile(file, func(wr smf.Writer) {
+		wr.Write(channel.Channel1.NoteOn(60, 100))
+		wr.SetDelta(96)
+		wr.Write(channel.Channel1.NoteOff(60))
+		wr.Write(meta.EndOfTrack)
+		wr.Write(channel.Channel2.NoteOn(60, 100))
+	}, smfwriter.NumTracks(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	single := filepath.Join(dir, "b.mid")
+	err = smfwriter.WriteFile(single, func(wr smf.Writer) {
+		wr.Write(channel.Channel1.NoteOn(60, 100))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the header announces two tracks, but only one follows
+	missing := filepath.Join(dir, "missing.mid")
+	data, _ := ioutil.ReadFile(single)
+	data[11] = 2
+	ioutil.WriteFile(missing, data, 0644)
+
+	// stops after the first message
+	res := Run([]string{file, missing}, func(file string, rd smf.Reader) error {
+		_, err := rd.Read()
+		return err
+	})
+
+	if got, want := res.Failed, 0; got != want {
+		t.Errorf("Failed = %v; wanted %v (%v)", got, want, res.Errors)
+	}
+
+	// reads until the end
+	res = Run([]string{file, missing}, func(file string, rd smf.Reader) error {
+		for {
+			if _, err := rd.Read(); err != nil {
+				return nil
+			}
+		}
+	})
+
+	if got, want := res.Failed, 1; got != want {
+		t.Fatalf("Failed = %v; wanted %v (%v)", got, want, res.Errors)
+	}
+
+	if got, want := res.Errors[0].File, missing; got != want {
+		t.Errorf("Errors[0].File = %v; wanted %v", got, want)
+	}
+}
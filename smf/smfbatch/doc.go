@@ -0,0 +1,46 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package smfbatch provides the concurrent processing of many Standard MIDI Files (SMF).
+
+A fixed number of workers reads the files, so that the memory usage is bounded, regardless
+of the number of files. Errors are collected per file and do not stop the processing of the other files.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/smf"
+		"github.com/gomidi/midi/smf/smfbatch"
+		. "github.com/gomidi/midi/midimessage/channel"
+	)
+
+	var notes uint64
+
+	countNotes := func(file string, rd smf.Reader) error {
+		for {
+			m, err := rd.Read()
+
+			if err == smf.ErrFinished {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if _, is := m.(NoteOn); is {
+				atomic.AddUint64(&notes, 1)
+			}
+		}
+	}
+
+	res := smfbatch.Run(files, countNotes, smfbatch.Workers(8))
+
+	for _, err := range res.Errors {
+		// deal with err.File and err.Err
+	}
+
+*/
+package smfbatch
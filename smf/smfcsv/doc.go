@@ -0,0 +1,45 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package smfcsv converts SMF files to and from the CSV format of the midicsv and csvmidi tools by John Walker
+(see https://www.fourmilab.ch/webtools/midicsv/), so that SMF files can be diffed, scripted and edited with text tools.
+
+Each line of the CSV describes an event with the track number, the absolute time in ticks, the record type and
+its parameters, e.g.
+
+	0, 0, Header, 1, 2, 480
+	1, 0, Start_track
+	1, 0, Tempo, 500000
+	1, 0, End_track
+	2, 0, Start_track
+	2, 0, Note_on_c, 0, 60, 100
+	2, 480, Note_off_c, 0, 60, 0
+	2, 480, End_track
+	0, 0, End_of_file
+
+The output of ToCSV matches the output of midicsv and ToSMF writes every status byte (like csvmidi), so that
+a file that has been written without running status survives the round trip byte by byte.
+A noteon message with velocity 0 remains a Note_on_c record.
+Lines that are empty or start with # or ; are ignored by ToSMF.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/smf/smfcsv"
+	)
+
+	// given some SMF file and some output
+	var (
+		file   io.Reader
+		output io.Writer
+	)
+
+	err := smfcsv.ToCSV(output, file)
+
+	// and back
+	err = smfcsv.ToSMF(output, csvFile)
+
+*/
+package smfcsv
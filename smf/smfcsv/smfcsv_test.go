@@ -0,0 +1,169 @@
+package smfcsv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/sysex"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var file bytes.Buffer
+
+	wr := smfwriter.New(&file, smfwriter.NumTracks(2), smfwriter.TimeFormat(smf.MetricTicks(480)), smfwriter.NoRunningStatus())
+
+	wr.Write(meta.Sequence("Song \"1\""))
+	wr.Write(meta.BPM(120))
+	wr.Write(meta.TimeSig{Numerator: 3, Denominator: 4, ClocksPerClick: 24, DemiSemiQuaverPerQuarter: 8})
+	wr.Write(meta.Key{Key: 7, IsMajor: true, Num: 1})
+	wr.Write(meta.Program("Organ\n"))
+	wr.SetDelta(1920)
+	wr.Write(meta.EndOfTrack)
+
+	wr.Write(channel.Channel1.ProgramChange(19))
+	wr.Write(channel.Channel1.NoteOn(60, 100))
+	wr.SetDelta(480)
+	wr.Write(channel.Channel1.NoteOff(60))
+	wr.Write(channel.Channel1.NoteOn(64, 100))
+	wr.SetDelta(480)
+	wr.Write(channel.Channel1.NoteOffVelocity(64, 64))
+	wr.Write(channel.Channel1.Pitchbend(-8192))
+	wr.Write(channel.Channel1.ControlChange(7, 90))
+	wr.Write(sysex.SysEx{0x7E, 0x7F, 0x09, 0x01})
+	wr.SetDelta(10)
+	wr.Write(meta.EndOfTrack)
+
+	original := file.Bytes()
+
+	var csv bytes.Buffer
+
+	if err := ToCSV(&csv, bytes.NewReader(original)); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := `0, 0, Header, 1, 2, 480
+1, 0, Start_track
+1, 0, Title_t, "Song ""1"""
+1, 0, Tempo, 500000
+1, 0, Time_signature, 3, 2, 24, 8
+1, 0, Key_signature, 1, "major"
+1, 0, Unknown_meta_event, 8, 6, 79, 114, 103, 97, 110, 10
+1, 1920, End_track
+2, 0, Start_track
+2, 0, Program_c, 1, 19
+2, 0, Note_on_c, 1, 60, 100
+2, 480, Note_on_c, 1, 60, 0
+2, 480, Note_on_c, 1, 64, 100
+2, 960, Note_off_c, 1, 64, 64
+2, 960, Pitch_bend_c, 1, 0
+2, 960, Control_c, 1, 7, 90
+2, 960, System_exclusive, 5, 126, 127, 9, 1, 247
+2, 970, End_track
+0, 0, End_of_file
+`
+
+	if got, want := csv.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	var back bytes.Buffer
+
+	if err := ToSMF(&back, strings.NewReader(csv.String())); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if got, want := fmt.Sprintf("% X", back.Bytes()), fmt.Sprintf("% X", original); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestToSMF(t *testing.T) {
+	input := `# a comment
+0, 0, Header, 0, 1, 96
+
+1, 0, Start_track
+1, 0, Text_t, "a, ""b"" \\ \303\244"
+1, 0, SMPTE_offset, 96, 0, 3, 0, 0
+1, 0, Sequencer_specific, 2, 1, 2
+1, 0, Key_signature, -3, "minor"
+1, 24, System_exclusive_packet, 2, 243, 1
+1, 48, End_track
+0, 0, End_of_file
+`
+	var file, csv bytes.Buffer
+
+	if err := ToSMF(&file, strings.NewReader(input)); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if err := ToCSV(&csv, &file); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := `0, 0, Header, 0, 1, 96
+1, 0, Start_track
+1, 0, Text_t, "a, ""b"" \\ \303\244"
+1, 0, SMPTE_offset, 96, 0, 3, 0, 0
+1, 0, Sequencer_specific, 2, 1, 2
+1, 0, Key_signature, -3, "minor"
+1, 24, System_exclusive_packet, 2, 243, 1
+1, 48, End_track
+0, 0, End_of_file
+`
+
+	if got, want := csv.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestToSMFErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1, 0, Start_track\n", "line 1: expected header"},
+		{"0, 0, Header, 0, 1, 96\n1, 0, Note_on_c, 16, 60, 100\n", `line 2: invalid field 4: "16"`},
+		{"0, 0, Header, 0, 1, 96\n1, 0, Note_on_c, 1, 60\n", "line 2: expected 6 fields, got 5"},
+		{"0, 0, Header, 0, 1, 96\n1, 10, Note_on_c, 1, 60, 100\n1, 5, End_track\n", "line 3: time 5 is before the previous event at 10"},
+		{"0, 0, Header, 0, 1, 96\n1, 0, Title_t, \"abc\n", "line 2: unterminated quoted string"},
+		{"0, 0, Header, 0, 1, 96\n1, 0, Note\n", `line 2: unknown record type "Note"`},
+		{"0, 0, Header, 0, 1, 96\n", "missing End_of_file"},
+	}
+
+	for i, test := range tests {
+		err := ToSMF(&bytes.Buffer{}, strings.NewReader(test.input))
+
+		if err == nil {
+			t.Errorf("[%v] expected error", i)
+			continue
+		}
+
+		if got, want := err.Error(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestWritePatchChange(t *testing.T) {
+	var bf bytes.Buffer
+
+	wr := bufio.NewWriter(&bf)
+	writeEvent(wr, 1, 960, channel.Channel3.PatchChange(1, 2, 5))
+	wr.Flush()
+
+	expected := `2, 960, Control_c, 3, 0, 1
+2, 960, Control_c, 3, 32, 2
+2, 960, Program_c, 3, 5
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
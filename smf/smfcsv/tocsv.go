@@ -0,0 +1,206 @@
+package smfcsv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/internal/midilib"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+// ToCSV reads the SMF file from src and writes it in the CSV format of midicsv to dest.
+// The options are passed to the reader.
+func ToCSV(dest io.Writer, src io.Reader, options ...smfreader.Option) error {
+	// keep the difference between noteoff messages and noteon messages with velocity 0
+	rd := smfreader.New(src, append([]smfreader.Option{smfreader.NoteOffVelocity()}, options...)...)
+
+	if err := rd.ReadHeader(); err != nil {
+		return err
+	}
+
+	h := rd.Header()
+	wr := bufio.NewWriter(dest)
+
+	fmt.Fprintf(wr, "0, 0, Header, %d, %d, %d\n", h.Format.Type(), h.NumTracks, division(h.TimeFormat))
+
+	var (
+		track int16 = -1
+		time  uint64
+	)
+
+	for {
+		msg, err := rd.Read()
+
+		if err == smf.ErrFinished {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if rd.Track() != track {
+			track = rd.Track()
+			time = 0
+			fmt.Fprintf(wr, "%d, 0, Start_track\n", track+1)
+		}
+
+		time += uint64(rd.Delta())
+		writeEvent(wr, track, time, msg)
+	}
+
+	fmt.Fprint(wr, "0, 0, End_of_file\n")
+	return wr.Flush()
+}
+
+// division returns the raw value of the time format in the header
+func division(tf smf.TimeFormat) uint16 {
+	switch v := tf.(type) {
+	case smf.MetricTicks:
+		return uint16(v)
+	case smf.TimeCode:
+		return uint16(byte(-int8(v.FramesPerSecond)))<<8 | uint16(v.SubFrames)
+	default:
+		return 0
+	}
+}
+
+// textTypes are the names of the text meta messages
+var textTypes = map[byte]string{
+	0x01: "Text_t",
+	0x02: "Copyright_t",
+	0x03: "Title_t",
+	0x04: "Instrument_name_t",
+	0x05: "Lyric_t",
+	0x06: "Marker_t",
+	0x07: "Cue_point_t",
+}
+
+// writeEvent writes the records of the message at the given time.
+// A channel.PatchChange is written as one record for each of its messages.
+func writeEvent(wr *bufio.Writer, track int16, time uint64, msg midi.Message) {
+	if pc, is := msg.(channel.PatchChange); is {
+		for _, m := range pc.Messages() {
+			writeEvent(wr, track, time, m)
+		}
+		return
+	}
+
+	fmt.Fprintf(wr, "%d, %d, ", track+1, time)
+	writeRecord(wr, msg.Raw())
+}
+
+// writeRecord writes the record type and the parameters for the given raw message
+func writeRecord(wr *bufio.Writer, raw []byte) {
+	ch := raw[0] & 0x0F
+
+	switch raw[0] >> 4 {
+	case 0x8:
+		fmt.Fprintf(wr, "Note_off_c, %d, %d, %d\n", ch, raw[1], raw[2])
+		return
+	case 0x9:
+		fmt.Fprintf(wr, "Note_on_c, %d, %d, %d\n", ch, raw[1], raw[2])
+		return
+	case 0xA:
+		fmt.Fprintf(wr, "Poly_aftertouch_c, %d, %d, %d\n", ch, raw[1], raw[2])
+		return
+	case 0xB:
+		fmt.Fprintf(wr, "Control_c, %d, %d, %d\n", ch, raw[1], raw[2])
+		return
+	case 0xC:
+		fmt.Fprintf(wr, "Program_c, %d, %d\n", ch, raw[1])
+		return
+	case 0xD:
+		fmt.Fprintf(wr, "Channel_aftertouch_c, %d, %d\n", ch, raw[1])
+		return
+	case 0xE:
+		fmt.Fprintf(wr, "Pitch_bend_c, %d, %d\n", ch, uint16(raw[1])|uint16(raw[2])<<7)
+		return
+	}
+
+	switch raw[0] {
+	case 0xF0:
+		fmt.Fprint(wr, "System_exclusive")
+		writeData(wr, raw[1:])
+		return
+	case 0xF7:
+		fmt.Fprint(wr, "System_exclusive_packet")
+		writeData(wr, raw[1:])
+		return
+	}
+
+	// meta message
+	typ := raw[1]
+	data, _ := midilib.ReadVarLengthData(bytes.NewReader(raw[2:]))
+
+	if name, is := textTypes[typ]; is {
+		fmt.Fprintf(wr, "%s, ", name)
+		writeText(wr, data)
+		wr.WriteString("\n")
+		return
+	}
+
+	switch {
+	case typ == 0x00 && len(data) == 2:
+		fmt.Fprintf(wr, "Sequence_number, %d\n", uint16(data[0])<<8|uint16(data[1]))
+	case typ == 0x20 && len(data) == 1:
+		fmt.Fprintf(wr, "Channel_prefix, %d\n", data[0])
+	case typ == 0x21 && len(data) == 1:
+		fmt.Fprintf(wr, "MIDI_port, %d\n", data[0])
+	case typ == 0x2F && len(data) == 0:
+		fmt.Fprint(wr, "End_track\n")
+	case typ == 0x51 && len(data) == 3:
+		fmt.Fprintf(wr, "Tempo, %d\n", uint32(data[0])<<16|uint32(data[1])<<8|uint32(data[2]))
+	case typ == 0x54 && len(data) == 5:
+		fmt.Fprintf(wr, "SMPTE_offset, %d, %d, %d, %d, %d\n", data[0], data[1], data[2], data[3], data[4])
+	case typ == 0x58 && len(data) == 4:
+		fmt.Fprintf(wr, "Time_signature, %d, %d, %d, %d\n", data[0], data[1], data[2], data[3])
+	case typ == 0x59 && len(data) == 2:
+		mode := "major"
+		if data[1] != 0 {
+			mode = "minor"
+		}
+		fmt.Fprintf(wr, "Key_signature, %d, \"%s\"\n", int8(data[0]), mode)
+	case typ == 0x7F:
+		fmt.Fprint(wr, "Sequencer_specific")
+		writeData(wr, data)
+	default:
+		fmt.Fprintf(wr, "Unknown_meta_event, %d", typ)
+		writeData(wr, data)
+	}
+}
+
+// writeData writes the length and the bytes of data as parameters
+func writeData(wr *bufio.Writer, data []byte) {
+	fmt.Fprintf(wr, ", %d", len(data))
+	for _, b := range data {
+		fmt.Fprintf(wr, ", %d", b)
+	}
+	wr.WriteString("\n")
+}
+
+// writeText writes the text as quoted string. Quotes are doubled, backslashes are escaped and
+// bytes that are no printable ASCII characters are written as octal escape sequences.
+func writeText(wr *bufio.Writer, text []byte) {
+	wr.WriteByte('"')
+
+	for _, c := range text {
+		switch {
+		case c < ' ' || c > '~':
+			fmt.Fprintf(wr, "\\%03o", c)
+		case c == '"':
+			wr.WriteString(`""`)
+		case c == '\\':
+			wr.WriteString(`\\`)
+		default:
+			wr.WriteByte(c)
+		}
+	}
+
+	wr.WriteByte('"')
+}
@@ -0,0 +1,345 @@
+package smfcsv
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/internal/vlq"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+// ToSMF reads the CSV in the format of midicsv from src and writes the SMF file to dest.
+// Every status byte is written (no running status). The options are passed to the writer after the
+// options for the header.
+// The error tells the line of the CSV that could not be converted.
+func ToSMF(dest io.Writer, src io.Reader, options ...smfwriter.Option) error {
+	var (
+		sc   = bufio.NewScanner(src)
+		wr   smf.Writer
+		line int
+		last uint32
+	)
+
+	sc.Buffer(nil, 1<<24)
+
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+
+		if text == "" || text[0] == '#' || text[0] == ';' {
+			continue
+		}
+
+		r := &record{}
+		r.fields, r.err = splitFields(text)
+
+		if r.err == nil && len(r.fields) < 3 {
+			r.err = errors.New("missing fields")
+		}
+
+		if r.err != nil {
+			return fmt.Errorf("line %d: %v", line, r.err)
+		}
+
+		typ := r.fields[2]
+		time := uint32(r.num(1, 0, 0xFFFFFFFF))
+
+		switch {
+		case typ == "Header":
+			if wr != nil {
+				r.fail("duplicate header")
+				break
+			}
+			format := r.num(3, 0, 2)
+			ntracks := r.num(4, 1, 0xFFFF)
+			div := uint16(r.num(5, 0, 0xFFFF))
+
+			if r.err == nil {
+				opts := []smfwriter.Option{
+					smfwriter.NoRunningStatus(),
+					smfwriter.Format([]smf.Format{smf.SMF0, smf.SMF1, smf.SMF2}[format]),
+					smfwriter.NumTracks(uint16(ntracks)),
+					smfwriter.TimeFormat(timeFormat(div)),
+				}
+				wr = smfwriter.New(dest, append(opts, options...)...)
+			}
+		case wr == nil:
+			r.fail("expected header")
+		case typ == "End_of_file":
+			return nil
+		case typ == "Start_track":
+			last = 0
+		case time < last:
+			r.fail("time %d is before the previous event at %d", time, last)
+		default:
+			msg := r.message(typ)
+
+			if r.err != nil {
+				break
+			}
+
+			wr.SetDelta(time - last)
+			last = time
+
+			// the writer returns smf.ErrFinished after the last track
+			if err := wr.Write(msg); err != nil && err != smf.ErrFinished {
+				r.err = err
+			}
+		}
+
+		if r.err != nil {
+			return fmt.Errorf("line %d: %v", line, r.err)
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return err
+	}
+
+	return errors.New("missing End_of_file")
+}
+
+// timeFormat returns the time format of the raw header value
+func timeFormat(division uint16) smf.TimeFormat {
+	if division&0x8000 == 0 {
+		return smf.MetricTicks(division)
+	}
+	return smf.TimeCode{FramesPerSecond: uint8(-int8(byte(division >> 8))), SubFrames: byte(division)}
+}
+
+// rawMessage is a message of the given bytes
+type rawMessage []byte
+
+// Raw returns the bytes of the message
+func (m rawMessage) Raw() []byte {
+	return m
+}
+
+// String represents the message as a string (for debugging)
+func (m rawMessage) String() string {
+	return fmt.Sprintf("smfcsv.rawMessage % X", []byte(m))
+}
+
+// channelTypes are the record types of the channel messages with the message type and the number of data bytes
+var channelTypes = map[string]struct {
+	typ  byte
+	args int
+}{
+	"Note_off_c":           {0x8, 2},
+	"Note_on_c":            {0x9, 2},
+	"Poly_aftertouch_c":    {0xA, 2},
+	"Control_c":            {0xB, 2},
+	"Program_c":            {0xC, 1},
+	"Channel_aftertouch_c": {0xD, 1},
+}
+
+// metaTypes are the meta message types of the records with a single number
+var metaTypes = map[string]struct {
+	typ  byte
+	size int
+}{
+	"Sequence_number": {0x00, 2},
+	"Channel_prefix":  {0x20, 1},
+	"MIDI_port":       {0x21, 1},
+	"Tempo":           {0x51, 3},
+}
+
+// record is a line of the CSV. The first error is kept.
+type record struct {
+	fields []string
+	err    error
+}
+
+// fail sets the error, if there is none
+func (r *record) fail(format string, vals ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf(format, vals...)
+	}
+}
+
+// num returns the number of the field at index i that must be within min and max
+func (r *record) num(i int, min, max int64) int64 {
+	if i >= len(r.fields) {
+		r.fail("missing field %d", i+1)
+		return 0
+	}
+
+	n, err := strconv.ParseInt(r.fields[i], 10, 64)
+
+	if err != nil || n < min || n > max {
+		r.fail("invalid field %d: %q", i+1, r.fields[i])
+		return 0
+	}
+
+	return n
+}
+
+// fieldCount checks that the record has n fields
+func (r *record) fieldCount(n int) {
+	if len(r.fields) != n {
+		r.fail("expected %d fields, got %d", n, len(r.fields))
+	}
+}
+
+// data returns the bytes that follow the length at index i
+func (r *record) data(i int) []byte {
+	n := int(r.num(i, 0, 0x0FFFFFFF))
+	r.fieldCount(i + 1 + n)
+
+	b := make([]byte, 0, n)
+
+	for j := 0; j < n && r.err == nil; j++ {
+		b = append(b, byte(r.num(i+1+j, 0, 255)))
+	}
+
+	return b
+}
+
+// metaMessage returns the raw meta message of the given type with the data
+func metaMessage(typ byte, data []byte) rawMessage {
+	b := []byte{0xFF, typ}
+	b = append(b, vlq.Encode(uint32(len(data)))...)
+	return append(b, data...)
+}
+
+// message returns the message of the record with the given type
+func (r *record) message(typ string) midi.Message {
+	if c, is := channelTypes[typ]; is {
+		r.fieldCount(4 + c.args)
+		msg := rawMessage{c.typ<<4 | byte(r.num(3, 0, 15))}
+		for i := 0; i < c.args; i++ {
+			msg = append(msg, byte(r.num(4+i, 0, 127)))
+		}
+		return msg
+	}
+
+	if m, is := metaTypes[typ]; is {
+		r.fieldCount(4)
+		n := r.num(3, 0, 1<<(8*uint(m.size))-1)
+		data := make([]byte, m.size)
+		for i := range data {
+			data[i] = byte(n >> (8 * uint(m.size-1-i)))
+		}
+		return metaMessage(m.typ, data)
+	}
+
+	for b, name := range textTypes {
+		if name == typ {
+			r.fieldCount(4)
+			return metaMessage(b, []byte(r.fields[3]))
+		}
+	}
+
+	switch typ {
+	case "Pitch_bend_c":
+		r.fieldCount(5)
+		v := r.num(4, 0, 0x3FFF)
+		return rawMessage{0xE0 | byte(r.num(3, 0, 15)), byte(v & 0x7F), byte(v >> 7)}
+	case "End_track":
+		r.fieldCount(3)
+		// the writer ends the track when it gets meta.EndOfTrack
+		return meta.EndOfTrack
+	case "SMPTE_offset":
+		r.fieldCount(8)
+		return metaMessage(0x54, []byte{byte(r.num(3, 0, 255)), byte(r.num(4, 0, 255)), byte(r.num(5, 0, 255)), byte(r.num(6, 0, 255)), byte(r.num(7, 0, 255))})
+	case "Time_signature":
+		r.fieldCount(7)
+		return metaMessage(0x58, []byte{byte(r.num(3, 0, 255)), byte(r.num(4, 0, 255)), byte(r.num(5, 0, 255)), byte(r.num(6, 0, 255))})
+	case "Key_signature":
+		r.fieldCount(5)
+		sf := int8(r.num(3, -7, 7))
+		var mode byte
+		switch r.fields[4] {
+		case "major":
+		case "minor":
+			mode = 1
+		default:
+			r.fail("invalid mode %q", r.fields[4])
+		}
+		return metaMessage(0x59, []byte{byte(sf), mode})
+	case "Sequencer_specific":
+		return metaMessage(0x7F, r.data(3))
+	case "Unknown_meta_event":
+		t := byte(r.num(3, 0, 127))
+		return metaMessage(t, r.data(4))
+	case "System_exclusive":
+		return append(rawMessage{0xF0}, r.data(3)...)
+	case "System_exclusive_packet":
+		return append(rawMessage{0xF7}, r.data(3)...)
+	default:
+		r.fail("unknown record type %q", typ)
+		return nil
+	}
+}
+
+// splitFields splits the line at the commas. The fields are trimmed and quoted strings are unquoted.
+func splitFields(line string) (fields []string, err error) {
+	for {
+		line = strings.TrimLeft(line, " \t")
+
+		var field string
+
+		if strings.HasPrefix(line, `"`) {
+			field, line, err = unquote(line[1:])
+			if err != nil {
+				return nil, err
+			}
+			line = strings.TrimLeft(line, " \t")
+			if line != "" && line[0] != ',' {
+				return nil, errors.New("unexpected characters after quoted string")
+			}
+		} else {
+			i := strings.IndexByte(line, ',')
+			if i < 0 {
+				i = len(line)
+			}
+			field = strings.TrimRight(line[:i], " \t")
+			line = line[i:]
+		}
+
+		fields = append(fields, field)
+
+		if line == "" {
+			return fields, nil
+		}
+
+		// skip the comma
+		line = line[1:]
+	}
+}
+
+// unquote returns the text of the quoted string (without the leading quote) and the rest of the line
+func unquote(s string) (text, rest string, err error) {
+	var b []byte
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' && i+1 < len(s) && s[i+1] == '"':
+			b = append(b, '"')
+			i++
+		case c == '"':
+			return string(b), s[i+1:], nil
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\\':
+			b = append(b, '\\')
+			i++
+		case c == '\\' && i+3 < len(s):
+			n, err := strconv.ParseUint(s[i+1:i+4], 8, 8)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid escape sequence %q", s[i:i+4])
+			}
+			b = append(b, byte(n))
+			i += 3
+		default:
+			b = append(b, c)
+		}
+	}
+
+	return "", "", errors.New("unterminated quoted string")
+}
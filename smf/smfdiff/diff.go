@@ -0,0 +1,299 @@
+package smfdiff
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+// Kind is the kind of a difference
+type Kind int
+
+const (
+	// Added is an event that is only in the second file
+	Added Kind = iota + 1
+
+	// Removed is an event that is only in the first file
+	Removed
+
+	// Changed is an event that differs between the files
+	Changed
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// Difference is a difference between the events of two SMF files
+type Difference struct {
+	Kind Kind
+
+	// Track is the number of the track (starting with 0)
+	Track int
+
+	// Time is the absolute time of the event in ticks
+	Time uint64
+
+	// Old is the event of the first file, nil if the event has been added
+	Old midi.Message
+
+	// New is the event of the second file, nil if the event has been removed
+	New midi.Message
+}
+
+// String represents the difference as a string, e.g.
+// track 1 @480 changed: channel.NoteOn channel 0 key 60 velocity 100 => channel.NoteOn channel 0 key 60 velocity 90
+func (d Difference) String() string {
+	switch d.Kind {
+	case Added:
+		return fmt.Sprintf("track %d @%d added: %s", d.Track, d.Time, d.New)
+	case Removed:
+		return fmt.Sprintf("track %d @%d removed: %s", d.Track, d.Time, d.Old)
+	default:
+		return fmt.Sprintf("track %d @%d %s: %s => %s", d.Track, d.Time, d.Kind, d.Old, d.New)
+	}
+}
+
+// Result is the result of a comparison
+type Result struct {
+	// Header are the differences of the headers, e.g. "format: 0 => 1"
+	Header []string
+
+	// Events are the differences of the events, ordered by track and time
+	Events []Difference
+}
+
+// Equal returns whether the files are semantically equal
+func (r *Result) Equal() bool {
+	return len(r.Header) == 0 && len(r.Events) == 0
+}
+
+// String lists the differences, one per line
+func (r *Result) String() string {
+	var bf strings.Builder
+
+	for _, h := range r.Header {
+		bf.WriteString("header " + h + "\n")
+	}
+
+	for _, d := range r.Events {
+		bf.WriteString(d.String() + "\n")
+	}
+
+	return bf.String()
+}
+
+// Compare compares the SMF files a and b. The options are passed to the readers of both files.
+func Compare(a, b io.Reader, options ...smfreader.Option) (*Result, error) {
+	fa, err := read(a, options)
+	if err != nil {
+		return nil, fmt.Errorf("first file: %v", err)
+	}
+
+	fb, err := read(b, options)
+	if err != nil {
+		return nil, fmt.Errorf("second file: %v", err)
+	}
+
+	res := &Result{}
+
+	if fa.header.Format != fb.header.Format {
+		res.Header = append(res.Header, fmt.Sprintf("format: %d => %d", fa.header.Format.Type(), fb.header.Format.Type()))
+	}
+
+	if fa.header.TimeFormat != fb.header.TimeFormat {
+		res.Header = append(res.Header, fmt.Sprintf("time format: %s => %s", fa.header.TimeFormat, fb.header.TimeFormat))
+	}
+
+	if len(fa.tracks) != len(fb.tracks) {
+		res.Header = append(res.Header, fmt.Sprintf("tracks: %d => %d", len(fa.tracks), len(fb.tracks)))
+	}
+
+	for i := 0; i < len(fa.tracks) || i < len(fb.tracks); i++ {
+		var ta, tb []event
+
+		if i < len(fa.tracks) {
+			ta = fa.tracks[i]
+		}
+
+		if i < len(fb.tracks) {
+			tb = fb.tracks[i]
+		}
+
+		res.Events = append(res.Events, compareTrack(i, ta, tb)...)
+	}
+
+	return res, nil
+}
+
+// event is a message at an absolute time
+type event struct {
+	time uint64
+	msg  midi.Message
+	raw  []byte
+}
+
+// file is a read SMF file
+type file struct {
+	header smf.Header
+	tracks [][]event
+}
+
+// read reads the events of all tracks
+func read(src io.Reader, options []smfreader.Option) (*file, error) {
+	rd := smfreader.New(src, options...)
+
+	if err := rd.ReadHeader(); err != nil {
+		return nil, err
+	}
+
+	f := &file{header: rd.Header()}
+	var time uint64
+
+	for {
+		msg, err := rd.Read()
+
+		if err == smf.ErrFinished {
+			return f, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if int(rd.Track()) >= len(f.tracks) {
+			f.tracks = append(f.tracks, make([][]event, int(rd.Track())+1-len(f.tracks))...)
+			time = 0
+		}
+
+		time += uint64(rd.Delta())
+
+		// the raw bytes of the messages are normalized by the reader,
+		// e.g. noteoff messages are always returned as channel.NoteOff
+		ev := event{time: time, msg: msg, raw: msg.Raw()}
+		f.tracks[rd.Track()] = append(f.tracks[rd.Track()], ev)
+	}
+}
+
+// compareTrack compares the events of a track, grouped by their time
+func compareTrack(track int, a, b []event) (diffs []Difference) {
+	for len(a) > 0 || len(b) > 0 {
+		var time uint64
+
+		switch {
+		case len(a) == 0:
+			time = b[0].time
+		case len(b) == 0:
+			time = a[0].time
+		case a[0].time < b[0].time:
+			time = a[0].time
+		default:
+			time = b[0].time
+		}
+
+		var na, nb int
+
+		for na < len(a) && a[na].time == time {
+			na++
+		}
+
+		for nb < len(b) && b[nb].time == time {
+			nb++
+		}
+
+		diffs = append(diffs, compareTick(track, time, a[:na], b[:nb])...)
+		a, b = a[na:], b[nb:]
+	}
+
+	return
+}
+
+// compareTick compares the events at the same time, based on the longest common subsequence
+func compareTick(track int, time uint64, a, b []event) (diffs []Difference) {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case bytes.Equal(a[i].raw, b[j].raw):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var removed, added []event
+	var i, j int
+
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && bytes.Equal(a[i].raw, b[j].raw):
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, a[i])
+			i++
+		default:
+			added = append(added, b[j])
+			j++
+		}
+	}
+
+	// pair the removed and added events that refer to the same thing
+	for _, r := range removed {
+		d := Difference{Kind: Removed, Track: track, Time: time, Old: r.msg}
+
+		for k, ad := range added {
+			if bytes.Equal(identity(r.raw), identity(ad.raw)) {
+				d.Kind = Changed
+				d.New = ad.msg
+				added = append(added[:k], added[k+1:]...)
+				break
+			}
+		}
+
+		diffs = append(diffs, d)
+	}
+
+	for _, ad := range added {
+		diffs = append(diffs, Difference{Kind: Added, Track: track, Time: time, New: ad.msg})
+	}
+
+	return
+}
+
+// identity returns the bytes that identify what a message refers to:
+// the status and the key or controller for note, polyphonic aftertouch and control change messages,
+// the status for other channel messages, the type for meta messages and the first byte for sysex.
+func identity(raw []byte) []byte {
+	switch {
+	case raw[0] >= 0x80 && raw[0] <= 0xBF && len(raw) > 1:
+		return raw[:2]
+	case raw[0] == 0xFF && len(raw) > 1:
+		return raw[:2]
+	default:
+		return raw[:1]
+	}
+}
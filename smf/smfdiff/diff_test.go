@@ -0,0 +1,99 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package smfdiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+func TestCompare(t *testing.T) {
+	var a, b bytes.Buffer
+
+	// running status, noteon with velocity 0
+	wa := smfwriter.New(&a, smfwriter.NumTracks(2))
+	wa.Write(meta.BPM(120))
+	wa.Write(meta.EndOfTrack)
+	wa.Write(channel.Channel0.NoteOn(60, 100))
+	wa.Write(channel.Channel0.ControlChange(7, 100))
+	wa.SetDelta(480)
+	wa.Write(channel.Channel0.NoteOff(60))
+	wa.Write(channel.Channel0.NoteOn(62, 100))
+	wa.SetDelta(480)
+	wa.Write(channel.Channel0.NoteOff(62))
+	wa.Write(meta.EndOfTrack)
+
+	// no running status, noteoff with velocity, time split over several deltas
+	wb := smfwriter.New(&b, smfwriter.NumTracks(2), smfwriter.NoRunningStatus())
+	wb.Write(meta.BPM(120))
+	wb.Write(meta.EndOfTrack)
+	wb.Write(channel.Channel0.NoteOn(60, 100))
+	wb.Write(channel.Channel0.ControlChange(7, 90))
+	wb.SetDelta(240)
+	wb.Write(meta.Marker("half"))
+	wb.SetDelta(240)
+	wb.Write(channel.Channel0.NoteOffVelocity(60, 64))
+	wb.Write(channel.Channel0.NoteOn(62, 100))
+	wb.Write(channel.Channel0.NoteOn(64, 100))
+	wb.SetDelta(480)
+	wb.Write(channel.Channel0.NoteOffVelocity(62, 20))
+	wb.Write(channel.Channel0.NoteOff(64))
+	wb.Write(meta.EndOfTrack)
+
+	res, err := Compare(bytes.NewReader(a.Bytes()), bytes.NewReader(b.Bytes()))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := `
+track 1 @0 changed: channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 100 => channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 90
+track 1 @240 added: meta.Marker: "half"
+track 1 @480 added: channel.NoteOn channel 0 key 64 velocity 100
+track 1 @960 added: channel.NoteOff channel 0 key 64
+`
+
+	if got, want := "\n"+res.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if res.Equal() {
+		t.Errorf("files must not be equal")
+	}
+
+	// fewer tracks and another time format
+	var c bytes.Buffer
+	wc := smfwriter.New(&c, smfwriter.TimeFormat(smf.MetricTicks(480)))
+	wc.Write(meta.BPM(120))
+	wc.Write(meta.EndOfTrack)
+
+	res, err = Compare(bytes.NewReader(a.Bytes()), &c)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected = `
+header format: 1 => 0
+header time format: 960 MetricTicks => 480 MetricTicks
+header tracks: 2 => 1
+track 1 @0 removed: channel.NoteOn channel 0 key 60 velocity 100
+track 1 @0 removed: channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 100
+track 1 @480 removed: channel.NoteOff channel 0 key 60
+track 1 @480 removed: channel.NoteOn channel 0 key 62 velocity 100
+track 1 @960 removed: channel.NoteOff channel 0 key 62
+track 1 @960 removed: meta.EndOfTrack
+`
+
+	if got, want := "\n"+res.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if res, _ = Compare(bytes.NewReader(a.Bytes()), bytes.NewReader(a.Bytes())); !res.Equal() {
+		t.Errorf("same file must be equal, got:\n%s", res)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package smfdiff compares two SMF files event by event, e.g. for regression tests of software that generates MIDI files.
+
+The comparison is semantic: the events are compared by track and absolute time, while the representation in the file
+(running status, noteoff messages vs noteon messages with velocity 0, the velocity of noteoff messages and
+the distribution of the time over the delta times) does not matter.
+Events that differ at the same time and refer to the same thing (e.g. a note-on message for the same key or a
+control change of the same controller) are reported as changed, other differences as added or removed.
+Differences of the headers are reported separately.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/smf/smfdiff"
+	)
+
+	// given two SMF files
+	var want, got io.Reader
+
+	res, err := smfdiff.Compare(want, got)
+
+	if err != nil {
+		// the files could not be read
+	}
+
+	if !res.Equal() {
+		fmt.Println(res)
+	}
+
+*/
+package smfdiff
@@ -0,0 +1,29 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package smflint checks Standard MIDI Files (SMF) for structural problems and suspicious content, e.g. for the
+quality assurance of generated or collected files.
+
+The structural checks cover the consistency of the header with the track chunks, the lengths of the track chunks,
+missing end of track messages and data after them. The content checks report notes that are not ended before
+the end of their track and notes that are started again while they are sounding.
+
+Each problem has a severity: errors make the file (partly) unreadable for strict readers, warnings point to
+content that is probably not intended and infos are merely noted (e.g. proprietary chunks).
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/smf/smflint"
+	)
+
+	for _, p := range smflint.Validate(file) {
+		if p.Severity == smflint.Error {
+			fmt.Println(p)
+		}
+	}
+
+*/
+package smflint
@@ -0,0 +1,212 @@
+package smflint
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+)
+
+var (
+	// ErrFormat is the cause of the problem that is reported, if a SMF0 file has more than one track
+	ErrFormat = errors.New("format 0 with more than one track")
+
+	// ErrExtraTrack is the cause of the problem that is reported for a track chunk after the declared number of tracks
+	ErrExtraTrack = errors.New("track chunk beyond the number of tracks in the header")
+
+	// ErrDataAfterEndOfTrack is the cause of the problem that is reported for data after the end of track message
+	// within the track chunk
+	ErrDataAfterEndOfTrack = errors.New("data after end of track")
+
+	// ErrUnknownChunk is the cause of the problem that is reported for chunks that are neither header nor track chunks
+	ErrUnknownChunk = errors.New("unknown chunk")
+
+	// ErrOrphanedNote is the cause of the problem that is reported for a note that is not ended within its track
+	ErrOrphanedNote = errors.New("note on without note off")
+
+	// ErrOverlappingNote is the cause of the problem that is reported for a note that is started while it is sounding
+	ErrOverlappingNote = errors.New("overlapping note on")
+)
+
+// Severity is the severity of a problem
+type Severity int
+
+const (
+	// Info is a problem that is merely noted
+	Info Severity = iota
+
+	// Warning is a problem that probably is not intended
+	Warning
+
+	// Error is a problem that makes the file (partly) unreadable for strict readers
+	Error
+)
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Problem is a problem of a SMF file
+type Problem struct {
+	Severity Severity
+
+	// Track is the number of the track (starting with 0), -1 for the header
+	Track int16
+
+	// Offset is the byte offset of the problem in the file
+	Offset int64
+
+	// Time is the absolute time of the problem within the track in ticks
+	Time uint64
+
+	// Err is the cause of the problem, e.g. ErrOrphanedNote or smfreader.ErrMissingEndOfTrack.
+	// It may be wrapped with details.
+	Err error
+}
+
+// String represents the problem as a string, e.g.
+// warning: track 1 @480 (offset 62): overlapping note on: channel 0 key 60
+func (p Problem) String() string {
+	if p.Track < 0 {
+		return fmt.Sprintf("%s: header (offset %d): %v", p.Severity, p.Offset, p.Err)
+	}
+	return fmt.Sprintf("%s: track %d @%d (offset %d): %v", p.Severity, p.Track, p.Time, p.Offset, p.Err)
+}
+
+// Validate reads the SMF file from src and returns its problems in the order of their occurrence.
+// A file that can't be read at all results in a single problem with the error of the reader.
+func Validate(src io.Reader) []Problem {
+	l := &linter{notes: map[[2]uint8]bool{}}
+
+	rd := smfreader.New(src,
+		smfreader.Recover(l.recovered),
+		smfreader.OnSkip(l.skipped),
+		smfreader.OnUnknownChunk(l.unknownChunk),
+	).(smfreader.PositionReader)
+
+	l.rd = rd
+
+	if err := rd.ReadHeader(); err != nil {
+		l.fatal(-1, 0, err)
+		return l.problems
+	}
+
+	if hd := rd.Header(); hd.Format == smf.SMF0 && hd.NumTracks != 1 {
+		l.report(Error, -1, 8, fmt.Errorf("%w: %d tracks", ErrFormat, hd.NumTracks))
+	}
+
+	for {
+		msg, err := rd.Read()
+
+		if err == smf.ErrFinished {
+			return l.problems
+		}
+
+		if err != nil {
+			if re, is := err.(*midi.ReadError); is && !re.Fatal {
+				l.report(Warning, re.Track, re.Offset, re.Err)
+				continue
+			}
+			l.fatal(rd.Track(), rd.Offset(), err)
+			return l.problems
+		}
+
+		l.check(msg)
+	}
+}
+
+type linter struct {
+	rd       smfreader.PositionReader
+	problems []Problem
+
+	// notes are the sounding notes of the current track by channel and key
+	notes map[[2]uint8]bool
+}
+
+// report adds a problem
+func (l *linter) report(sev Severity, track int16, offset int64, err error) {
+	var time uint64
+	if track >= 0 && l.rd != nil {
+		time = l.rd.AbsTicks()
+	}
+	l.problems = append(l.problems, Problem{Severity: sev, Track: track, Offset: offset, Time: time, Err: err})
+}
+
+// fatal reports an error that stops the reading, with the position of a *midi.ReadError, if it is one
+func (l *linter) fatal(track int16, offset int64, err error) {
+	if re, is := err.(*midi.ReadError); is {
+		track, offset, err = re.Track, re.Offset, re.Err
+	}
+	l.report(Error, track, offset, err)
+}
+
+// check checks the notes of a message
+func (l *linter) check(msg midi.Message) {
+	switch m := msg.(type) {
+	case channel.NoteOn:
+		n := [2]uint8{m.Channel(), m.Key()}
+		if l.notes[n] {
+			l.report(Warning, l.rd.Track(), l.rd.Offset(), fmt.Errorf("%w: channel %d key %d", ErrOverlappingNote, n[0], n[1]))
+		}
+		l.notes[n] = true
+	case channel.NoteOff:
+		delete(l.notes, [2]uint8{m.Channel(), m.Key()})
+	case channel.NoteOffVelocity:
+		delete(l.notes, [2]uint8{m.Channel(), m.Key()})
+	default:
+		if msg == meta.EndOfTrack {
+			l.endOfTrack()
+		}
+	}
+}
+
+// endOfTrack reports the notes that are still sounding (ordered by channel and key)
+func (l *linter) endOfTrack() {
+	for ch := 0; ch < 16 && len(l.notes) > 0; ch++ {
+		for key := 0; key < 128; key++ {
+			n := [2]uint8{uint8(ch), uint8(key)}
+			if l.notes[n] {
+				l.report(Warning, l.rd.Track(), l.rd.Offset(), fmt.Errorf("%w: channel %d key %d", ErrOrphanedNote, ch, key))
+				delete(l.notes, n)
+			}
+		}
+	}
+}
+
+// recovered reports a problem the reader recovered from
+func (l *linter) recovered(p *midi.ReadError) {
+	l.report(Error, p.Track, p.Offset, p.Err)
+}
+
+// skipped reports the data after an end of track message. The reader has already reported
+// the wrong length of the track for the same offset which is replaced.
+func (l *linter) skipped(b []byte, offset int64) {
+	if last := len(l.problems) - 1; last >= 0 && l.problems[last].Offset == offset && l.problems[last].Err == smfreader.ErrTrackLength {
+		l.problems = l.problems[:last]
+	}
+	l.report(Warning, l.rd.Track(), offset, fmt.Errorf("%w: % X", ErrDataAfterEndOfTrack, b))
+}
+
+// unknownChunk reports chunks other than tracks and track chunks beyond the number of tracks
+func (l *linter) unknownChunk(c smf.UnknownChunk) {
+	if c.Type == "MTrk" {
+		l.report(Error, c.Track, c.Offset, ErrExtraTrack)
+		return
+	}
+	l.report(Info, c.Track, c.Offset, fmt.Errorf("%w %q", ErrUnknownChunk, c.Type))
+}
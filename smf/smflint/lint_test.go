@@ -0,0 +1,75 @@
+package smflint
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	const header = "4D546864 00000006 0001 0002 0060"
+
+	tests := []struct {
+		descr    string
+		input    string
+		expected string
+	}{
+		{
+			"intact",
+			header + "4D54726B 0000000C 00903C64 60803C00 00FF2F00" + "4D54726B 00000007 00C005 00FF2F00",
+			``,
+		},
+		{
+			"format 0 with two tracks",
+			"4D546864 00000006 0000 0002 0060" + "4D54726B 00000004 00FF2F00" + "4D54726B 00000004 00FF2F00",
+			`
+error: header (offset 8): format 0 with more than one track: 2 tracks`,
+		},
+		{
+			"orphaned and overlapping notes",
+			header + "4D54726B 00000010 00903C64 10903C64 00903E64 00FF2F00" + "4D54726B 00000004 00FF2F00",
+			`
+warning: track 0 @16 (offset 26): overlapping note on: channel 0 key 60
+warning: track 0 @16 (offset 34): note on without note off: channel 0 key 60
+warning: track 0 @16 (offset 34): note on without note off: channel 0 key 62`,
+		},
+		{
+			"data after end of track and missing end of track",
+			header + "4D54726B 00000008 00FF2F00 00FF0100" + "4D54726B 00000003 00C005" + "58594E48 00000001 00",
+			`
+warning: track 0 @0 (offset 26): data after end of track: 00 FF 01 00
+error: track 1 @0 (offset 41): missing end of track
+info: track 1 @0 (offset 41): unknown chunk "XYNH"`,
+		},
+		{
+			"extra track",
+			header + "4D54726B 00000004 00FF2F00" + "4D54726B 00000004 00FF2F00" + "4D54726B 00000004 00FF2F00",
+			`
+error: track 1 @0 (offset 38): track chunk beyond the number of tracks in the header`,
+		},
+		{
+			"no header",
+			"4D54726B 00000004 00FF2F00",
+			`
+error: header (offset 0): Expected SMF Midi header.`,
+		},
+	}
+
+	for i, test := range tests {
+		input, err := hex.DecodeString(strings.Replace(test.input, " ", "", -1))
+		if err != nil {
+			t.Fatalf("[%v] invalid input: %v", i, err)
+		}
+
+		var bf strings.Builder
+
+		for _, p := range Validate(bytes.NewReader(input)) {
+			bf.WriteString("\n" + p.String())
+		}
+
+		if got, want := bf.String(), test.expected; got != want {
+			t.Errorf("[%v] %s\ngot:\n%s\n\nwanted:\n%s\n\n", i, test.descr, got, want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package smflyrics extracts the lyrics of Standard MIDI Files (SMF) as timed syllables and embeds lyrics into them.
+
+Two conventions are supported:
+
+  - lyric meta messages (the SMF standard): each message is a syllable, a space at its end ends the word
+    and a carriage return or line feed ends the line
+  - text meta messages in the karaoke (.kar) convention: a space at the start of a syllable starts a word,
+    "/" starts a line and "\" a paragraph; texts starting with "@" are header entries
+    ("@K" marks the file, "@T" are title, author and copyright, "@L" is the language, "@V" the version, "@I" infos)
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/smf/smflyrics"
+	)
+
+	l, err := smflyrics.Extract(f)
+
+	for _, s := range l.Syllables {
+		fmt.Println(s.Time, s.Text)
+	}
+
+	fmt.Println(strings.Join(l.Lines(), "\n"))
+
+	// put one syllable on each note of the melody track
+	err = smflyrics.Embed(f.Tracks[1], "Hap-py birth-day to you")
+
+*/
+package smflyrics
@@ -0,0 +1,336 @@
+package smflyrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+)
+
+// Syllable is a timed syllable of the lyrics
+type Syllable struct {
+	// AbsTicks is the absolute time in ticks
+	AbsTicks uint64
+
+	// Time is the real time (0 for files without metric time format)
+	Time time.Duration
+
+	// Text is the syllable without spaces and line markers
+	Text string
+
+	// Word is true, if the syllable starts a word
+	Word bool
+
+	// Line is true, if the syllable starts a line
+	Line bool
+
+	// Paragraph is true, if the syllable starts a paragraph
+	Paragraph bool
+}
+
+// Lyrics are the lyrics of a file
+type Lyrics struct {
+	// Karaoke is true, if the lyrics are text messages in the karaoke (.kar) convention
+	Karaoke bool
+
+	// Title are the @T entries of karaoke files: title, author and copyright
+	Title []string
+
+	// Info are the @I entries of karaoke files
+	Info []string
+
+	// Language is the @L entry of karaoke files
+	Language string
+
+	// Version is the @V entry of karaoke files
+	Version string
+
+	Syllables []Syllable
+}
+
+// Lines returns the lines of the lyrics. Paragraphs are separated by empty lines.
+func (l *Lyrics) Lines() (lines []string) {
+	var line strings.Builder
+
+	for i, s := range l.Syllables {
+		if i > 0 && (s.Line || s.Paragraph) {
+			lines = append(lines, line.String())
+			line.Reset()
+
+			if s.Paragraph {
+				lines = append(lines, "")
+			}
+		}
+
+		if s.Word && line.Len() > 0 {
+			line.WriteString(" ")
+		}
+
+		line.WriteString(s.Text)
+	}
+
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+
+	return
+}
+
+// Extract returns the lyrics of the file. The lyric messages are used if there are any,
+// otherwise the text messages of the first track that contains a karaoke header ("@K") or a text starting with "/" or "\".
+func Extract(f *smf.File) (*Lyrics, error) {
+	var (
+		lyrics []smf.Event
+		texts  [][]smf.Event
+	)
+
+	for _, t := range f.Tracks {
+		var tr []smf.Event
+
+		for _, ev := range t.Events {
+			switch ev.Message.(type) {
+			case meta.Lyric:
+				lyrics = append(lyrics, ev)
+			case meta.Text:
+				tr = append(tr, ev)
+			}
+		}
+
+		texts = append(texts, tr)
+	}
+
+	var l *Lyrics
+
+	if len(lyrics) > 0 {
+		sort.SliceStable(lyrics, func(a, b int) bool {
+			return lyrics[a].AbsTicks < lyrics[b].AbsTicks
+		})
+		l = fromLyrics(lyrics)
+	} else {
+		l = &Lyrics{}
+		for _, tr := range texts {
+			if isKaraoke(tr) {
+				l = fromKaraoke(tr)
+				break
+			}
+		}
+	}
+
+	if tl, err := smf.NewTimeline(f); err == nil {
+		for i := range l.Syllables {
+			l.Syllables[i].Time = tl.Time(l.Syllables[i].AbsTicks)
+		}
+	}
+
+	return l, nil
+}
+
+// isKaraoke returns whether the text messages follow the karaoke convention
+func isKaraoke(texts []smf.Event) bool {
+	for _, ev := range texts {
+		s := string(ev.Message.(meta.Text))
+		if strings.HasPrefix(s, "@K") || strings.HasPrefix(s, "/") || strings.HasPrefix(s, "\\") {
+			return true
+		}
+	}
+	return false
+}
+
+// fromLyrics returns the syllables of lyric messages
+func fromLyrics(events []smf.Event) *Lyrics {
+	var (
+		l = &Lyrics{}
+
+		// the flags of the next syllable
+		word, line = true, true
+	)
+
+	for _, ev := range events {
+		var (
+			s     = string(ev.Message.(meta.Lyric))
+			text  = strings.TrimLeft(s, " \r\n")
+			core  = strings.TrimRight(text, " \r\n")
+			lead  = s[:len(s)-len(text)]
+			trail = text[len(core):]
+		)
+
+		// spaces and line breaks before or after a syllable separate words and lines
+		word = word || lead != ""
+		line = line || strings.ContainsAny(lead, "\r\n")
+
+		if core != "" {
+			l.Syllables = append(l.Syllables, Syllable{AbsTicks: ev.AbsTicks, Text: core, Word: word, Line: line})
+			word, line = false, false
+		}
+
+		word = word || trail != ""
+		line = line || strings.ContainsAny(trail, "\r\n")
+	}
+
+	return l
+}
+
+// fromKaraoke returns the header and the syllables of text messages in the karaoke convention
+func fromKaraoke(events []smf.Event) *Lyrics {
+	l := &Lyrics{Karaoke: true}
+	first := true
+
+	for _, ev := range events {
+		s := string(ev.Message.(meta.Text))
+
+		if strings.HasPrefix(s, "@") && len(s) >= 2 {
+			val := s[2:]
+			switch s[1] {
+			case 'T':
+				l.Title = append(l.Title, val)
+			case 'I':
+				l.Info = append(l.Info, val)
+			case 'L':
+				l.Language = val
+			case 'V':
+				l.Version = val
+			}
+			continue
+		}
+
+		syl := Syllable{AbsTicks: ev.AbsTicks, Line: first}
+
+		switch {
+		case strings.HasPrefix(s, "\\"):
+			syl.Paragraph, syl.Line, s = !first, true, s[1:]
+		case strings.HasPrefix(s, "/"):
+			syl.Line, s = true, s[1:]
+		}
+
+		text := strings.TrimLeft(s, " ")
+		syl.Word = syl.Line || text != s
+		syl.Text = strings.TrimRight(text, " ")
+
+		if syl.Text == "" {
+			continue
+		}
+
+		l.Syllables = append(l.Syllables, syl)
+		first = false
+	}
+
+	return l
+}
+
+// Split splits the text into syllables (without times): words are separated by spaces,
+// syllables within words by "-", lines by line breaks and paragraphs by empty lines.
+func Split(text string) (res []Syllable) {
+	paragraph := false
+
+	for _, line := range strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n") {
+		words := strings.Fields(line)
+
+		if len(words) == 0 {
+			paragraph = len(res) > 0
+			continue
+		}
+
+		for i, word := range words {
+			for j, syl := range strings.Split(word, "-") {
+				if syl == "" {
+					continue
+				}
+				res = append(res, Syllable{Text: syl, Word: j == 0, Line: i == 0 && j == 0, Paragraph: paragraph})
+				paragraph = false
+			}
+		}
+	}
+
+	return
+}
+
+// noteOns returns the times of the note on messages of the track (one per time)
+func noteOns(t *smf.Track) (res []uint64) {
+	for _, ev := range t.Events {
+		if _, is := ev.Message.(channel.NoteOn); !is {
+			continue
+		}
+		if len(res) == 0 || res[len(res)-1] != ev.AbsTicks {
+			res = append(res, ev.AbsTicks)
+		}
+	}
+	return
+}
+
+// Embed adds the syllables of the text (see Split) as lyric messages at the note ons of the track,
+// one syllable per note (chords count as one note). A space at the end of a syllable ends a word and a
+// carriage return ends a line. It returns an error, if there are more syllables than notes.
+func Embed(t *smf.Track, text string) error {
+	syls := Split(text)
+	times := noteOns(t)
+
+	if len(syls) > len(times) {
+		return fmt.Errorf("%v syllables for %v notes", len(syls), len(times))
+	}
+
+	for i, s := range syls {
+		txt := s.Text
+		switch {
+		case i+1 == len(syls):
+		case syls[i+1].Line:
+			txt += "\r"
+		case syls[i+1].Word:
+			txt += " "
+		}
+		t.Insert(times[i], meta.Lyric(txt))
+	}
+
+	return nil
+}
+
+// EmbedKaraoke adds a track with the syllables of the text (see Split) as text messages in the karaoke convention,
+// aligned to the note ons of the track with the given index (see Embed) and returns it.
+// The track starts with the karaoke header ("@K"), the language (if not empty) and the title entries.
+func EmbedKaraoke(f *smf.File, melody int, text, language string, title ...string) (*smf.Track, error) {
+	if melody < 0 || melody >= len(f.Tracks) {
+		return nil, fmt.Errorf("track %v does not exist", melody)
+	}
+
+	syls := Split(text)
+	times := noteOns(f.Tracks[melody])
+
+	if len(syls) > len(times) {
+		return nil, fmt.Errorf("%v syllables for %v notes", len(syls), len(times))
+	}
+
+	t := &smf.Track{}
+	t.Insert(0, meta.Text("@KMIDI KARAOKE FILE"))
+
+	if language != "" {
+		t.Insert(0, meta.Text("@L"+language))
+	}
+
+	for _, ti := range title {
+		t.Insert(0, meta.Text("@T"+ti))
+	}
+
+	for i, s := range syls {
+		txt := s.Text
+		switch {
+		case s.Paragraph:
+			txt = "\\" + txt
+		case s.Line:
+			txt = "/" + txt
+		case s.Word:
+			txt = " " + txt
+		}
+		t.Insert(times[i], meta.Text(txt))
+	}
+
+	f.Tracks = append(f.Tracks, t)
+
+	if f.Format == smf.SMF0 {
+		f.Format = smf.SMF1
+	}
+
+	return t, nil
+}
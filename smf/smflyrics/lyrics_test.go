@@ -0,0 +1,101 @@
+package smflyrics
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+)
+
+func melody() *smf.File {
+	f := &smf.File{TimeFormat: smf.MetricTicks(96)}
+	f.AddTrack().Insert(0, meta.BPM(120))
+
+	tr := f.AddTrack()
+	for i := uint64(0); i < 8; i++ {
+		tr.Insert(i*96, channel.Channel0.NoteOn(60, 100))
+		tr.Insert(i*96+48, channel.Channel0.NoteOff(60))
+	}
+
+	return f
+}
+
+func syllables(l *Lyrics) string {
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for _, s := range l.Syllables {
+		out.WriteString(fmt.Sprintf("@%v %v %q word %v line %v paragraph %v\n", s.AbsTicks, s.Time, s.Text, s.Word, s.Line, s.Paragraph))
+	}
+
+	return out.String()
+}
+
+func TestEmbedAndExtract(t *testing.T) {
+	const text = "Hap-py birth-day\nto you\n\nhap-py"
+
+	expected := `
+@0 0s "Hap" word true line true paragraph false
+@96 500ms "py" word false line false paragraph false
+@192 1s "birth" word true line false paragraph false
+@288 1.5s "day" word false line false paragraph false
+@384 2s "to" word true line true paragraph false
+@480 2.5s "you" word true line false paragraph false
+@576 3s "hap" word true line true paragraph %v
+@672 3.5s "py" word false line false paragraph false
+`
+
+	f := melody()
+
+	if err := Embed(f.Tracks[1], text); err != nil {
+		t.Fatalf("can't embed: %v", err)
+	}
+
+	l, err := Extract(f)
+
+	if err != nil {
+		t.Fatalf("can't extract: %v", err)
+	}
+
+	// lyric messages have no paragraphs
+	if got, want := syllables(l), fmt.Sprintf(expected, false); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if got, want := strings.Join(l.Lines(), "|"), "Happy birthday|to you|happy"; got != want {
+		t.Errorf("Lines() = %q; wanted %q", got, want)
+	}
+
+	f = melody()
+
+	if _, err := EmbedKaraoke(f, 1, text, "EN", "Happy Birthday", "Traditional"); err != nil {
+		t.Fatalf("can't embed: %v", err)
+	}
+
+	l, err = Extract(f)
+
+	if err != nil {
+		t.Fatalf("can't extract: %v", err)
+	}
+
+	if got, want := syllables(l), fmt.Sprintf(expected, true); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if got, want := strings.Join(l.Lines(), "|"), "Happy birthday|to you||happy"; got != want {
+		t.Errorf("Lines() = %q; wanted %q", got, want)
+	}
+
+	if !l.Karaoke || l.Language != "EN" || !reflect.DeepEqual(l.Title, []string{"Happy Birthday", "Traditional"}) {
+		t.Errorf("wrong header: %#v", l)
+	}
+
+	if err := Embed(melody().Tracks[1], text+" and more syl-la-bles"); err == nil {
+		t.Errorf("expected error for too many syllables")
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package smfquantize snaps the times of notes in Standard MIDI Files (SMF) to a grid of note values, e.g. sixteenth notes
+or eighth note triplets.
+
+The strength moves the notes only partly towards the grid, keeping some of the feel of a played performance.
+Swing delays every second grid point (50% is straight, 66% is a triplet feel).
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/smf/smfquantize"
+	)
+
+	// sixteenth notes, moving the notes 80% of the way, with a light swing
+	q := smfquantize.New(16, smfquantize.Strength(80), smfquantize.Swing(58))
+
+	// as SMF to SMF filter
+	err := q.Filter(dest, src)
+
+	// or on a loaded file
+	f, err := smfreader.Load(src)
+	err = q.File(f)
+
+A Groove captures the timing and accents of a played track per grid step and applies them to another track:
+
+	gr := smfquantize.ExtractGroove(resolution, drums.Tracks[0], 16, 16)
+	gr.Apply(resolution, bass.Tracks[0], 100)
+
+*/
+package smfquantize
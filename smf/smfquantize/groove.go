@@ -0,0 +1,130 @@
+package smfquantize
+
+import (
+	"math"
+	"sort"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf"
+)
+
+// Groove is the timing and accent feel of a cycle of grid steps, e.g. the 16 sixteenth notes of a 4/4 bar.
+// It is extracted from a played track via ExtractGroove and applied to other tracks.
+type Groove struct {
+	// Division is the note value of the steps, e.g. 16 for sixteenth notes
+	Division uint32
+
+	// Timing are the offsets of the notes at each step of the cycle in fractions of a step (-0.5 to 0.5)
+	Timing []float64
+
+	// Accent are the offsets of the velocities at each step of the cycle from the average velocity
+	Accent []float64
+}
+
+// ExtractGroove returns the groove of the note on messages of the track for a cycle of the given number of steps
+// of the given note value. The offsets of each step are the averages of all notes at the step in all cycles;
+// steps without notes have no offsets.
+func ExtractGroove(resolution smf.MetricTicks, t *smf.Track, steps int, division uint32) *Groove {
+	if steps <= 0 {
+		steps = 16
+	}
+
+	var (
+		g        = New(division).grid(resolution)
+		timing   = make([]float64, steps)
+		velocity = make([]float64, steps)
+		count    = make([]int, steps)
+		sum      float64
+		numNotes int
+		groove   = &Groove{Division: division, Timing: make([]float64, steps), Accent: make([]float64, steps)}
+	)
+
+	for _, ev := range t.Events {
+		on, is := ev.Message.(channel.NoteOn)
+		if !is {
+			continue
+		}
+
+		step := math.Round(float64(ev.AbsTicks) / g)
+		i := int(step) % steps
+
+		timing[i] += (float64(ev.AbsTicks) - step*g) / g
+		velocity[i] += float64(on.Velocity())
+		count[i]++
+		sum += float64(on.Velocity())
+		numNotes++
+	}
+
+	for i := range count {
+		if count[i] == 0 {
+			continue
+		}
+		groove.Timing[i] = timing[i] / float64(count[i])
+		groove.Accent[i] = velocity[i]/float64(count[i]) - sum/float64(numNotes)
+	}
+
+	return groove
+}
+
+// Apply moves the notes of the track to the nearest step of the groove and changes their velocities by its accents.
+// The note offs are moved together with their note ons, so that the lengths of the notes are kept.
+// The strength in percent (0-100) sets how far the notes are moved and how much of the accents are applied.
+func (gr *Groove) Apply(resolution smf.MetricTicks, t *smf.Track, strength float64) {
+	steps := len(gr.Timing)
+
+	if steps == 0 || len(gr.Accent) != steps {
+		return
+	}
+
+	var (
+		g     = New(gr.Division).grid(resolution)
+		s     = math.Max(0, math.Min(strength, 100)) / 100
+		moved = map[[2]uint8][]int64{}
+	)
+
+	for i := range t.Events {
+		ev := &t.Events[i]
+
+		switch m := ev.Message.(type) {
+		case channel.NoteOn:
+			step := math.Round(float64(ev.AbsTicks) / g)
+			j := int(step) % steps
+			target := step*g + gr.Timing[j]*g
+			abs := math.Max(0, math.Round(float64(ev.AbsTicks)+s*(target-float64(ev.AbsTicks))))
+
+			k := [2]uint8{m.Channel(), m.Key()}
+			moved[k] = append(moved[k], int64(abs)-int64(ev.AbsTicks))
+
+			ev.AbsTicks = uint64(abs)
+			ev.Message = channel.Channel(m.Channel()).NoteOn(m.Key(), clampVelocity(float64(m.Velocity())+s*gr.Accent[j]))
+		case channel.NoteOff:
+			ev.AbsTicks = moveOff(moved, [2]uint8{m.Channel(), m.Key()}, ev.AbsTicks)
+		case channel.NoteOffVelocity:
+			ev.AbsTicks = moveOff(moved, [2]uint8{m.Channel(), m.Key()}, ev.AbsTicks)
+		}
+	}
+
+	sort.SliceStable(t.Events, func(a, b int) bool {
+		return t.Events[a].AbsTicks < t.Events[b].AbsTicks
+	})
+}
+
+// moveOff moves a note off by the distance its note on has been moved
+func moveOff(moved map[[2]uint8][]int64, k [2]uint8, abs uint64) uint64 {
+	if len(moved[k]) == 0 {
+		return abs
+	}
+
+	d := moved[k][0]
+	moved[k] = moved[k][1:]
+
+	if int64(abs)+d < 0 {
+		return 0
+	}
+	return uint64(int64(abs) + d)
+}
+
+// clampVelocity rounds the velocity and clamps it to 1-127
+func clampVelocity(v float64) uint8 {
+	return uint8(math.Max(1, math.Min(math.Round(v), 127)))
+}
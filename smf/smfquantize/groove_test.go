@@ -0,0 +1,64 @@
+package smfquantize
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf"
+)
+
+func TestGroove(t *testing.T) {
+	res := smf.MetricTicks(96)
+
+	// two bars of swung sixteenths (the offbeats 6 ticks late and softer)
+	played := &smf.Track{}
+	for i := uint64(0); i < 8; i++ {
+		abs, vel := i*24, uint8(100)
+		if i%2 == 1 {
+			abs, vel = abs+6, 80
+		}
+		played.Insert(abs, channel.Channel9.NoteOn(42, vel))
+		played.Insert(abs+10, channel.Channel9.NoteOff(42))
+	}
+
+	gr := ExtractGroove(res, played, 4, 16)
+
+	expected := &Groove{Division: 16, Timing: []float64{0, 0.25, 0, 0.25}, Accent: []float64{10, -10, 10, -10}}
+
+	if !reflect.DeepEqual(gr, expected) {
+		t.Fatalf("got:\n%#v\n\nwanted:\n%#v\n\n", gr, expected)
+	}
+
+	straight := &smf.Track{}
+	for i := uint64(0); i < 4; i++ {
+		straight.Insert(i*24, channel.Channel0.NoteOn(60, 90))
+		straight.Insert(i*24+12, channel.Channel0.NoteOff(60))
+	}
+
+	gr.Apply(res, straight, 100)
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for _, ev := range straight.Events {
+		out.WriteString(fmt.Sprintf("@%v %s\n", ev.AbsTicks, ev.Message))
+	}
+
+	want := `
+@0 channel.NoteOn channel 0 key 60 velocity 100
+@12 channel.NoteOff channel 0 key 60
+@30 channel.NoteOn channel 0 key 60 velocity 80
+@42 channel.NoteOff channel 0 key 60
+@48 channel.NoteOn channel 0 key 60 velocity 100
+@60 channel.NoteOff channel 0 key 60
+@78 channel.NoteOn channel 0 key 60 velocity 80
+@90 channel.NoteOff channel 0 key 60
+`
+
+	if got := out.String(); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
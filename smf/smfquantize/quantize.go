@@ -0,0 +1,186 @@
+package smfquantize
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+// Option is an option for the Quantizer
+type Option func(*Quantizer)
+
+// Triplets divides the grid into triplets, e.g. New(8, Triplets()) quantizes to eighth note triplets
+func Triplets() Option {
+	return func(q *Quantizer) {
+		q.triplets = true
+	}
+}
+
+// Strength sets how far the notes are moved towards the grid in percent (0-100, default 100)
+func Strength(percent float64) Option {
+	return func(q *Quantizer) {
+		q.strength = math.Max(0, math.Min(percent, 100)) / 100
+	}
+}
+
+// Swing delays every second grid point. 50 percent (the default) is straight, 66.7 percent is a triplet feel,
+// values are limited to 50-75.
+func Swing(percent float64) Option {
+	return func(q *Quantizer) {
+		q.swing = math.Max(50, math.Min(percent, 75))/50 - 1
+	}
+}
+
+// KeepLengths moves the note offs together with their note ons, instead of quantizing them
+func KeepLengths() Option {
+	return func(q *Quantizer) {
+		q.keepLengths = true
+	}
+}
+
+// Quantizer snaps the times of notes to a grid
+type Quantizer struct {
+	division    uint32
+	triplets    bool
+	strength    float64
+	swing       float64
+	keepLengths bool
+}
+
+// New returns a Quantizer for the grid of the given note value, e.g. 8 for eighth notes and 16 for sixteenth notes
+func New(division uint32, opts ...Option) *Quantizer {
+	if division == 0 {
+		division = 16
+	}
+
+	q := &Quantizer{division: division, strength: 1}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q
+}
+
+// grid returns the distance between the grid points in ticks
+func (q *Quantizer) grid(resolution smf.MetricTicks) float64 {
+	g := float64(resolution.Ticks4th()) * 4 / float64(q.division)
+	if q.triplets {
+		g = g * 2 / 3
+	}
+	return g
+}
+
+// Time returns the quantized time of the given absolute time in ticks
+func (q *Quantizer) Time(resolution smf.MetricTicks, abs uint64) uint64 {
+	var (
+		g      = q.grid(resolution)
+		t      = float64(abs)
+		pair   = math.Floor(t/(2*g)) * 2 * g
+		target = pair
+	)
+
+	// the grid points around t: the start of the pair, the (swung) second point and the start of the next pair
+	for _, p := range []float64{pair + g + q.swing*g, pair + 2*g} {
+		if math.Abs(p-t) < math.Abs(target-t) {
+			target = p
+		}
+	}
+
+	return uint64(math.Round(t + q.strength*(target-t)))
+}
+
+// Track quantizes the note on and note off messages of the track. Note offs that would end up at or before their
+// note on keep the original length of the note.
+func (q *Quantizer) Track(resolution smf.MetricTicks, t *smf.Track) {
+	type note struct {
+		orig, abs uint64
+	}
+
+	open := map[[2]uint8][]note{}
+
+	for i := range t.Events {
+		ev := &t.Events[i]
+
+		var (
+			key [2]uint8
+			on  bool
+		)
+
+		switch m := ev.Message.(type) {
+		case channel.NoteOn:
+			key, on = [2]uint8{m.Channel(), m.Key()}, true
+		case channel.NoteOff:
+			key = [2]uint8{m.Channel(), m.Key()}
+		case channel.NoteOffVelocity:
+			key = [2]uint8{m.Channel(), m.Key()}
+		default:
+			continue
+		}
+
+		orig := ev.AbsTicks
+
+		if on {
+			ev.AbsTicks = q.Time(resolution, orig)
+			open[key] = append(open[key], note{orig: orig, abs: ev.AbsTicks})
+			continue
+		}
+
+		notes := open[key]
+
+		if len(notes) == 0 {
+			ev.AbsTicks = q.Time(resolution, orig)
+			continue
+		}
+
+		n := notes[0]
+		open[key] = notes[1:]
+
+		if !q.keepLengths {
+			ev.AbsTicks = q.Time(resolution, orig)
+		}
+
+		if q.keepLengths || ev.AbsTicks <= n.abs {
+			ev.AbsTicks = n.abs + (orig - n.orig)
+		}
+	}
+
+	sort.SliceStable(t.Events, func(a, b int) bool {
+		return t.Events[a].AbsTicks < t.Events[b].AbsTicks
+	})
+}
+
+// File quantizes the notes of all tracks of the file. The file must have a metric time format.
+func (q *Quantizer) File(f *smf.File) error {
+	resolution, is := f.TimeFormat.(smf.MetricTicks)
+
+	if !is {
+		return fmt.Errorf("can't quantize time format %v", f.TimeFormat)
+	}
+
+	for _, t := range f.Tracks {
+		q.Track(resolution, t)
+	}
+
+	return nil
+}
+
+// Filter reads the SMF file from src, quantizes it and writes it to dest
+func (q *Quantizer) Filter(dest io.Writer, src io.Reader) error {
+	f, err := smfreader.Load(src)
+	if err != nil {
+		return err
+	}
+
+	if err := q.File(f); err != nil {
+		return err
+	}
+
+	return smfwriter.Save(dest, f)
+}
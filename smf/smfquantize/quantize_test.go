@@ -0,0 +1,103 @@
+package smfquantize
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfreader"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		q        *Quantizer
+		abs      uint64
+		expected uint64
+	}{
+		{New(16), 10, 0},
+		{New(16), 13, 24},
+		{New(16), 37, 48},
+		{New(16, Strength(50)), 10, 5},
+		{New(16, Strength(0)), 10, 10},
+		{New(8), 60, 48},
+		{New(8, Swing(66.67)), 60, 64},
+		{New(8, Swing(66.67)), 90, 96},
+		{New(8, Triplets()), 40, 32},
+	}
+
+	for i, test := range tests {
+		if got, want := test.q.Time(smf.MetricTicks(96), test.abs), test.expected; got != want {
+			t.Errorf("[%v] Time(%v) = %v; wanted %v", i, test.abs, got, want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		q        *Quantizer
+		expected string
+	}{
+		{
+			New(16),
+			`
+@0 channel.NoteOn channel 0 key 60 velocity 100
+@24 channel.NoteOn channel 0 key 62 velocity 100
+@29 channel.NoteOff channel 0 key 62
+@48 channel.NoteOff channel 0 key 60
+end @50
+`,
+		},
+		{
+			New(16, KeepLengths()),
+			`
+@0 channel.NoteOn channel 0 key 60 velocity 100
+@24 channel.NoteOn channel 0 key 62 velocity 100
+@29 channel.NoteOff channel 0 key 62
+@40 channel.NoteOff channel 0 key 60
+end @50
+`,
+		},
+	}
+
+	for i, test := range tests {
+		var src bytes.Buffer
+		wr := smfwriter.New(&src, smfwriter.TimeFormat(smf.MetricTicks(96)))
+		wr.SetDelta(10)
+		wr.Write(channel.Channel0.NoteOn(60, 100))
+		wr.SetDelta(20)
+		wr.Write(channel.Channel0.NoteOn(62, 100))
+		wr.SetDelta(5)
+		wr.Write(channel.Channel0.NoteOff(62))
+		wr.SetDelta(15)
+		wr.Write(channel.Channel0.NoteOff(60))
+		wr.Write(meta.EndOfTrack)
+
+		var dest bytes.Buffer
+
+		if err := test.q.Filter(&dest, &src); err != nil {
+			t.Fatalf("[%v] can't quantize: %v", i, err)
+		}
+
+		f, err := smfreader.Load(&dest)
+		if err != nil {
+			t.Fatalf("[%v] can't load: %v", i, err)
+		}
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		for _, ev := range f.Tracks[0].Events {
+			out.WriteString(fmt.Sprintf("@%v %s\n", ev.AbsTicks, ev.Message))
+		}
+
+		out.WriteString(fmt.Sprintf("end @%v\n", f.Tracks[0].End))
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
@@ -54,5 +54,31 @@ Usage
 	   // real error happened
 	}
 
+The readers returned by New and by an Index are PositionReaders that also report the absolute tick
+within the track and the byte offset of the last message:
+
+	pos := rd.(smfreader.PositionReader)
+	fmt.Printf("track %v tick %v offset %v\n", pos.Track(), pos.AbsTicks(), pos.Offset())
+
+Slightly broken files (e.g. from old hardware) can be read with the Recover option, that reports the problems
+and returns what can be salvaged.
+
+Chunks other than the header and the tracks (e.g. proprietary "XFIH" chunks) are skipped, unless
+the OnUnknownChunk option is passed. They can be written back with a smfwriter.ChunkWriter.
+
+Texts of meta messages that are not UTF-8 (often Latin-1 or Shift-JIS) can be decoded with the TextEncoding option:
+
+	rd := smfreader.New(file, smfreader.TextEncoding(meta.Latin1))
+
+For large files that are accessible via an io.ReaderAt (e.g. an *os.File), an Index gives access to single tracks
+without decoding the others:
+
+	idx, err := smfreader.NewIndex(file)
+
+	// only track 2 and 5, starting at the tick 1920
+	rd, err := idx.Seek(1920, 2, 5)
+
+	readMIDI(rd)
+
 */
 package smfreader
@@ -3,10 +3,23 @@ package smfreader
 import "errors"
 
 var (
-	errUnsupportedSMFFormat  = errors.New("The SMF format was not expected.")
-	errExpectedMthd          = errors.New("Expected SMF Midi header.")
-	errBadSizeChunk          = errors.New("Chunk was an unexpected size.")
-	errInterruptedByCallback = errors.New("interrupted by callback")
+	// ErrUnsupportedSMFFormat is the cause of the error that is returned, if the format of the SMF file is not 0, 1 or 2
+	ErrUnsupportedSMFFormat = errors.New("The SMF format was not expected.")
+
+	// ErrExpectedMthd is the cause of the error that is returned, if the file does not start with a header chunk
+	ErrExpectedMthd = errors.New("Expected SMF Midi header.")
+
+	// ErrInvalidVarLength is the cause of the error that is returned in strict mode, if a delta time is longer than 4 bytes
+	ErrInvalidVarLength = errors.New("variable length quantity longer than 4 bytes")
+
+	// ErrTrackLength is the cause of the error that is returned in strict mode, if the end of track message
+	// is not at the end of the track chunk
+	ErrTrackLength = errors.New("length of track does not match the chunk header")
+
+	// ErrMissingEndOfTrack is the cause of the problem that is reported in recovery mode, if a track chunk ends
+	// without an end of track message
+	ErrMissingEndOfTrack = errors.New("missing end of track")
+
 	// ErrMissing is the error returned, if there is no more data, but tracks are missing
 	ErrMissing = errors.New("incomplete, tracks missing")
 )
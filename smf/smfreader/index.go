@@ -0,0 +1,118 @@
+package smfreader
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gomidi/midi/internal/midilib"
+	"github.com/gomidi/midi/smf"
+)
+
+// TrackInfo describes the position of a track chunk inside a SMF file
+type TrackInfo struct {
+	// Offset is the byte offset of the track data (after the chunk header)
+	Offset int64
+
+	// Length is the length of the track data in bytes
+	Length uint32
+}
+
+// Index is an index of the track chunks of a SMF file that is accessible via an io.ReaderAt
+// (e.g. an *os.File or a memory mapped file).
+// Only the header and the chunk headers are read when the index is created. The events
+// of a track are only decoded when the track is requested via Track.
+type Index struct {
+	src    io.ReaderAt
+	header smf.Header
+	tracks []TrackInfo
+	opts   []Option
+}
+
+// NewIndex reads the header of the SMF file and the headers of all chunks from src
+// without decoding any track data. Chunks that are not of type MTrk are skipped.
+// The given options are applied to each reader returned by Track.
+func NewIndex(src io.ReaderAt, opts ...Option) (*Index, error) {
+	idx := &Index{src: src, opts: opts}
+
+	var chunk smf.Chunk
+	length, err := chunk.ReadHeader(io.NewSectionReader(src, 0, 8))
+	if err != nil {
+		return nil, err
+	}
+
+	if chunk.Type() != "MThd" {
+		return nil, ErrExpectedMthd
+	}
+
+	var rd reader
+	err = rd.parseHeaderData(io.NewSectionReader(src, 8, int64(length)))
+	if err != nil {
+		return nil, err
+	}
+
+	idx.header = rd.header
+	offset := int64(8 + length)
+
+	for {
+		length, err = chunk.ReadHeader(io.NewSectionReader(src, offset, 8))
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		offset += 8
+
+		if chunk.Type() == "MTrk" {
+			idx.tracks = append(idx.tracks, TrackInfo{Offset: offset, Length: length})
+		}
+
+		offset += int64(length)
+	}
+
+	return idx, nil
+}
+
+// Header returns the header of the SMF file
+func (i *Index) Header() smf.Header {
+	return i.header
+}
+
+// NumTracks returns the number of track chunks that have been found.
+// It might differ from Header().NumTracks for broken files.
+func (i *Index) NumTracks() int {
+	return len(i.tracks)
+}
+
+// TrackInfo returns the position of the track with the given number (starting with 0)
+func (i *Index) TrackInfo(track int) (TrackInfo, error) {
+	if track < 0 || track >= len(i.tracks) {
+		return TrackInfo{}, fmt.Errorf("track %v does not exist", track)
+	}
+	return i.tracks[track], nil
+}
+
+// Track returns a smf.Reader that only reads the events of the given track (starting with 0).
+// The header of the returned reader is already read. Track reports the given track number.
+// When the end of the track is reached, smf.ErrFinished is returned.
+// The returned reader is a PositionReader whose offsets refer to the whole file.
+func (i *Index) Track(track int) (smf.Reader, error) {
+	info, err := i.TrackInfo(track)
+	if err != nil {
+		return nil, err
+	}
+
+	count := &midilib.CountingReader{R: io.NewSectionReader(i.src, info.Offset, int64(info.Length)), N: info.Offset}
+
+	rd := newReader(count, i.opts...)
+	rd.processedTracks = int16(track)
+	rd.header = i.header
+	rd.headerIsRead = true
+	rd.singleTrack = true
+	rd.trackEnd = info.Offset + int64(info.Length)
+
+	return rd, nil
+}
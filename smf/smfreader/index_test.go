@@ -0,0 +1,268 @@
+package smfreader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/internal/examples"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+	"github.com/gomidi/midi/smf/smfwriter"
+)
+
+func TestIndex(t *testing.T) {
+	idx, err := NewIndex(bytes.NewReader(examples.SpecSMF1))
+
+	if err != nil {
+		t.Fatalf("can't create index: %v", err)
+	}
+
+	if got, want := idx.NumTracks(), 4; got != want {
+		t.Fatalf("NumTracks() = %v; wanted %v", got, want)
+	}
+
+	rd, err := idx.Track(2)
+
+	if err != nil {
+		t.Fatalf("can't get track 2: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	var msg midi.Message
+
+	for {
+		msg, err = rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		out.WriteString(fmt.Sprintf("Track %v@%v %s\n", rd.Track(), rd.Delta(), msg))
+	}
+
+	expected := `
+Track 2@0 channel.ProgramChange channel 1 program 46
+Track 2@96 channel.NoteOn channel 1 key 67 velocity 64
+Track 2@288 channel.NoteOff channel 1 key 67
+Track 2@0 meta.EndOfTrack
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%v\n\nwanted\n%v\n\n", got, want)
+	}
+
+	if _, err = idx.Track(4); err == nil {
+		t.Errorf("expected error for track 4, got nil")
+	}
+}
+
+func TestIndexSeek(t *testing.T) {
+	idx, err := NewIndex(bytes.NewReader(examples.SpecSMF1))
+
+	if err != nil {
+		t.Fatalf("can't create index: %v", err)
+	}
+
+	tests := []struct {
+		tick     uint64
+		tracks   []int
+		expected string
+	}{
+		{0, []int{3, 2}, `
+Track 3@0 channel.ProgramChange channel 2 program 70 (0)
+Track 3@0 channel.NoteOn channel 2 key 48 velocity 96 (0)
+Track 3@0 channel.NoteOn channel 2 key 60 velocity 96 (0)
+Track 3@384 channel.NoteOff channel 2 key 48 (384)
+Track 3@0 channel.NoteOff channel 2 key 60 (384)
+Track 3@0 meta.EndOfTrack (384)
+Track 2@0 channel.ProgramChange channel 1 program 46 (0)
+Track 2@96 channel.NoteOn channel 1 key 67 velocity 64 (96)
+Track 2@288 channel.NoteOff channel 1 key 67 (384)
+Track 2@0 meta.EndOfTrack (384)
+`},
+		// the state before tick is chased
+		{90, []int{0, 2, 3}, `
+Track 0@0 meta.TimeSig 4/4 clocksperclick 24 dsqpq 8 (0)
+Track 0@0 meta.Tempo BPM: 120.00 (0)
+Track 0@294 meta.EndOfTrack (384)
+Track 2@0 channel.ProgramChange channel 1 program 46 (0)
+Track 2@6 channel.NoteOn channel 1 key 67 velocity 64 (96)
+Track 2@288 channel.NoteOff channel 1 key 67 (384)
+Track 2@0 meta.EndOfTrack (384)
+Track 3@0 channel.ProgramChange channel 2 program 70 (0)
+Track 3@294 channel.NoteOff channel 2 key 48 (384)
+Track 3@0 channel.NoteOff channel 2 key 60 (384)
+Track 3@0 meta.EndOfTrack (384)
+`},
+		{1000, []int{2}, `
+Track 2@0 channel.ProgramChange channel 1 program 46 (0)
+Track 2@0 meta.EndOfTrack (384)
+`},
+	}
+
+	for i, test := range tests {
+		rd, err := idx.Seek(test.tick, test.tracks...)
+
+		if err != nil {
+			t.Fatalf("[%v] can't seek: %v", i, err)
+		}
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		for {
+			msg, err := rd.Read()
+
+			if err != nil {
+				break
+			}
+
+			out.WriteString(fmt.Sprintf("Track %v@%v %s (%v)\n", rd.Track(), rd.Delta(), msg, rd.(PositionReader).AbsTicks()))
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%v\n\nwanted\n%v\n\n", i, got, want)
+		}
+	}
+
+	rd, _ := idx.Tracks()
+	var n int
+	for {
+		if _, err := rd.Read(); err != nil {
+			break
+		}
+		n++
+	}
+
+	if n != 17 {
+		t.Errorf("got %v messages of all tracks, wanted 17", n)
+	}
+
+	if _, err = idx.Tracks(4); err == nil {
+		t.Errorf("expected error for track 4, got nil")
+	}
+}
+
+func TestIndexNoEndOfTrack(t *testing.T) {
+	var data []byte
+	data = append(data, "MThd\x00\x00\x00\x06\x00\x01\x00\x02\x00\x60"...)
+	data = append(data, "MTrk\x00\x00\x00\x04\x00\x90\x3C\x64"...)
+	data = append(data, "MTrk\x00\x00\x00\x04\x00\xFF\x2F\x00"...)
+
+	var problems []error
+
+	// Strict is switched off by Recover, like for New
+	idx, err := NewIndex(bytes.NewReader(data), Strict(), Recover(func(p *midi.ReadError) {
+		problems = append(problems, p)
+	}))
+
+	if err != nil {
+		t.Fatalf("can't create index: %v", err)
+	}
+
+	rd, err := idx.Track(0)
+
+	if err != nil {
+		t.Fatalf("can't get track 0: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			if err != smf.ErrFinished {
+				t.Errorf("got error %v; wanted smf.ErrFinished", err)
+			}
+			break
+		}
+
+		out.WriteString(fmt.Sprintf("Track %v@%v %s\n", rd.Track(), rd.Delta(), msg))
+	}
+
+	expected := `
+Track 0@0 channel.NoteOn channel 0 key 60 velocity 100
+Track 0@0 meta.EndOfTrack
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%v\n\nwanted\n%v\n\n", got, want)
+	}
+
+	if len(problems) != 1 || !errors.Is(problems[0], ErrMissingEndOfTrack) {
+		t.Errorf("got problems %v; wanted ErrMissingEndOfTrack", problems)
+	}
+}
+
+func TestIndexSeekChase(t *testing.T) {
+	var bf bytes.Buffer
+
+	wr := smfwriter.New(&bf, smfwriter.NumTracks(1))
+	wr.Write(channel.Channel0.ControlChange(3, 100))
+	wr.Write(channel.Channel0.ProgramChange(3))
+	wr.SetDelta(10)
+	wr.Write(channel.Channel0.ControlChange(9, 64))
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+	wr.SetDelta(10)
+	wr.Write(channel.Channel0.ControlChange(3, 90))
+	wr.SetDelta(10)
+	wr.Write(channel.Channel0.NoteOff(60))
+	wr.Write(meta.EndOfTrack)
+
+	idx, err := NewIndex(bytes.NewReader(bf.Bytes()))
+
+	if err != nil {
+		t.Fatalf("can't create index: %v", err)
+	}
+
+	rd, err := idx.Seek(25)
+
+	if err != nil {
+		t.Fatalf("can't seek: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	for {
+		msg, err := rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		out.WriteString(fmt.Sprintf("@%v %s (%v)\n", rd.Delta(), msg, rd.(PositionReader).AbsTicks()))
+	}
+
+	// the last value of each controller, in the order of the last values (the controllers have no names, as in the tiny profile)
+	expected := `
+@0 channel.ProgramChange channel 0 program 3 (0)
+@0 channel.ControlChange channel 0 controller 9 value 64 (10)
+@0 channel.ControlChange channel 0 controller 3 value 90 (20)
+@5 channel.NoteOff channel 0 key 60 (30)
+@0 meta.EndOfTrack (30)
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	// without tracks
+	var empty seekReader
+
+	if got, want := empty.AbsTicks(), uint64(0); got != want {
+		t.Errorf("AbsTicks() = %v; wanted %v", got, want)
+	}
+
+	if got, want := empty.Offset(), int64(-1); got != want {
+		t.Errorf("Offset() = %v; wanted %v", got, want)
+	}
+}
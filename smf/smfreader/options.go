@@ -1,5 +1,11 @@
 package smfreader
 
+import (
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+)
+
 // Option is an option for the Reader
 type Option func(*reader)
 
@@ -22,6 +28,65 @@ func NoteOffVelocity() Option {
 	}
 }
 
+// ChannelModes lets the reader return the control change messages of the controllers 120-127
+// (All Sound Off, Reset All Controllers, Local Control, All Notes Off and the Omni/Mono/Poly modes) as channel.ChannelMode messages.
+// If this option is not set, they are returned as channel.ControlChange messages (default).
+func ChannelModes() Option {
+	return func(rd *reader) {
+		rd.readChannelModes = true
+	}
+}
+
+// Strict lets the reader return an error for deviations from the SMF specification that are tolerated by default:
+// delta times that are longer than 4 bytes (cause ErrInvalidVarLength) and end of track messages that are not at the end
+// of the track chunk (cause ErrTrackLength).
+func Strict() Option {
+	return func(rd *reader) {
+		rd.strict = true
+	}
+}
+
+// OnSkip lets the reader call fn with the bytes that are skipped in the default (tolerant) mode and their offset in the file.
+// Bytes are skipped, when the end of track message comes before the end of the track chunk.
+func OnSkip(fn func(skipped []byte, offset int64)) Option {
+	return func(rd *reader) {
+		rd.onSkip = fn
+	}
+}
+
+// OnUnknownChunk lets the reader call fn with the chunks that are neither header nor track chunks,
+// e.g. proprietary data of a sequencer. Such chunks are skipped otherwise.
+// The chunks after the last track are passed when the reader reaches the end of the file,
+// i.e. with the Read call that returns smf.ErrFinished.
+// See smfwriter.ChunkWriter for writing them back.
+func OnUnknownChunk(fn func(smf.UnknownChunk)) Option {
+	return func(rd *reader) {
+		rd.onChunk = fn
+	}
+}
+
+// Recover lets the reader tolerate broken files and return what can be salvaged:
+//   - a track that ends without an end of track message or that is truncated by the end of the file gets an end of track message
+//   - a wrong length in the header of a track chunk is ignored, if the next track can be found
+//   - after invalid data the rest of the track chunk is skipped and an end of track message is returned
+//   - if the file ends before all tracks are read, smf.ErrFinished is returned
+//
+// Each of these problems is passed to fn (as non fatal *midi.ReadError). Recover overrides Strict.
+func Recover(fn func(problem *midi.ReadError)) Option {
+	return func(rd *reader) {
+		rd.onProblem = fn
+	}
+}
+
+// TextEncoding lets the reader decode the texts of meta text messages (e.g. meta.Lyric or meta.Track) from the given encoding,
+// e.g. meta.Latin1. Texts that can't be decoded are returned as they are.
+// Without this option the texts are returned as they are in the file (assumed to be UTF-8).
+func TextEncoding(enc meta.Encoding) Option {
+	return func(rd *reader) {
+		rd.encoding = enc
+	}
+}
+
 type logger interface {
 	Printf(format string, vals ...interface{})
 }
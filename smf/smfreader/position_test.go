@@ -0,0 +1,78 @@
+package smfreader
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/internal/examples"
+)
+
+func readPositions(rd PositionReader) string {
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	var msg midi.Message
+	var err error
+
+	for {
+		msg, err = rd.Read()
+
+		if err != nil {
+			break
+		}
+
+		out.WriteString(fmt.Sprintf("Track %v@%v #%v %s\n", rd.Track(), rd.AbsTicks(), rd.Offset(), msg))
+	}
+
+	return out.String()
+}
+
+func TestPosition(t *testing.T) {
+	rd := New(bytes.NewReader(examples.SpecSMF1)).(PositionReader)
+
+	got := readPositions(rd)
+
+	expected := `
+Track 0@0 #22 meta.TimeSig 4/4 clocksperclick 24 dsqpq 8
+Track 0@0 #30 meta.Tempo BPM: 120.00
+Track 0@384 #37 meta.EndOfTrack
+Track 1@0 #50 channel.ProgramChange channel 0 program 5
+Track 1@192 #53 channel.NoteOn channel 0 key 76 velocity 32
+Track 1@384 #58 channel.NoteOff channel 0 key 76
+Track 1@384 #62 meta.EndOfTrack
+Track 2@0 #74 channel.ProgramChange channel 1 program 46
+Track 2@96 #77 channel.NoteOn channel 1 key 67 velocity 64
+Track 2@384 #81 channel.NoteOff channel 1 key 67
+Track 2@384 #85 meta.EndOfTrack
+Track 3@0 #97 channel.ProgramChange channel 2 program 70
+Track 3@0 #100 channel.NoteOn channel 2 key 48 velocity 96
+Track 3@0 #104 channel.NoteOn channel 2 key 60 velocity 96
+Track 3@384 #107 channel.NoteOff channel 2 key 48
+Track 3@384 #111 channel.NoteOff channel 2 key 60
+Track 3@384 #114 meta.EndOfTrack
+`
+
+	if got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+
+	idx, err := NewIndex(bytes.NewReader(examples.SpecSMF1))
+
+	if err != nil {
+		t.Fatalf("can't create index: %v", err)
+	}
+
+	tr, err := idx.Track(2)
+
+	if err != nil {
+		t.Fatalf("can't get track 2: %v", err)
+	}
+
+	fromIndex := readPositions(tr.(PositionReader))
+
+	if !bytes.Contains([]byte(expected), []byte(fromIndex[1:])) {
+		t.Errorf("positions of the index reader differ:\n%s\n\nwhole file:\n%s\n\n", fromIndex, got)
+	}
+}
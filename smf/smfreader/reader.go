@@ -1,15 +1,12 @@
 package smfreader
 
 import (
-	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 
 	"github.com/gomidi/midi/internal/runningstatus"
 
-	"errors"
-
 	"github.com/gomidi/midi"
 	"github.com/gomidi/midi/internal/midilib"
 	"github.com/gomidi/midi/midimessage/channel"
@@ -48,10 +45,35 @@ func ReadFile(file string, callback func(smf.Reader), options ...Option) error {
 	return nil
 }
 
-// New returns a smf.Reader
+// Load reads the whole SMF file from src into memory, see smf.File
+func Load(src io.Reader, options ...Option) (*smf.File, error) {
+	return smf.Load(New(src, options...))
+}
+
+// PositionReader is a smf.Reader that exposes the position of the last message that has been read,
+// so that callers don't have to sum up the delta times. The readers returned by New and by the methods of Index implement it.
+type PositionReader interface {
+	smf.Reader
+
+	// AbsTicks returns the absolute time of the last message within its track in ticks
+	AbsTicks() uint64
+
+	// Offset returns the byte offset of the last message in the file, i.e. the offset of its delta time
+	Offset() int64
+}
+
+var _ PositionReader = &reader{}
+
+// New returns a smf.Reader (a PositionReader)
 func New(src io.Reader, opts ...Option) smf.Reader {
+	return newReader(&midilib.CountingReader{R: src}, opts...)
+}
+
+// newReader returns a new reader for the counted input with the given options applied
+func newReader(count *midilib.CountingReader, opts ...Option) *reader {
 	rd := &reader{
-		input: src,
+		input: count,
+		count: count,
 		// state:           stateExpectHeader,
 		processedTracks: -1,
 		runningStatus:   runningstatus.NewSMFReader(),
@@ -62,18 +84,29 @@ func New(src io.Reader, opts ...Option) smf.Reader {
 		opt(rd)
 	}
 
-	if rd.readNoteOffPedantic {
-		rd.channelReader = channel.NewReader(rd.input, channel.ReadNoteOffVelocity())
-	} else {
-		rd.channelReader = channel.NewReader(rd.input)
+	if rd.onProblem != nil {
+		rd.strict = false
 	}
 
+	rd.channelReader = channel.NewReader(rd.input, rd.channelOptions()...)
+
 	return rd
 }
 
+// channelOptions returns the options for the channel reader
+func (r *reader) channelOptions() (opts []channel.ReaderOption) {
+	if r.readNoteOffPedantic {
+		opts = append(opts, channel.ReadNoteOffVelocity())
+	}
+	if r.readChannelModes {
+		opts = append(opts, channel.ReadChannelModes())
+	}
+	return
+}
+
 // Close closes the internal reader if it is an io.ReadCloser
 func (r *reader) Close() error {
-	if cl, is := r.input.(io.ReadCloser); is {
+	if cl, is := r.count.R.(io.ReadCloser); is {
 		return cl.Close()
 	}
 	return nil
@@ -90,6 +123,7 @@ func (r *reader) ReadHeader() error {
 
 type reader struct {
 	input  io.Reader
+	count  *midilib.CountingReader
 	logger logger
 
 	// state           state
@@ -99,6 +133,8 @@ type reader struct {
 	runningStatus       runningstatus.Reader
 	processedTracks     int16
 	deltatime           uint32
+	absTicks            uint64
+	offset              int64
 	header              smf.Header
 
 	sysexreader   *sysexReader
@@ -109,6 +145,23 @@ type reader struct {
 	headerIsRead        bool
 	// headerError         error
 	readNoteOffPedantic bool
+	readChannelModes    bool
+
+	strict   bool
+	onSkip   func(skipped []byte, offset int64)
+	trackEnd int64 // the offset of the end of the current track chunk
+
+	onChunk      func(smf.UnknownChunk)
+	trailingRead bool // the chunks after the last track have been read
+
+	// onProblem is called with the problems that are tolerated in recovery mode
+	onProblem func(problem *midi.ReadError)
+
+	// encoding is the encoding of the texts of meta messages
+	encoding meta.Encoding
+
+	// singleTrack is set for readers that only read a single track (see Index.Track)
+	singleTrack bool
 
 	error error
 }
@@ -123,6 +176,16 @@ func (r *reader) Track() int16 {
 	return r.processedTracks
 }
 
+// AbsTicks returns the absolute time of the last MIDI message within its track in ticks
+func (r *reader) AbsTicks() uint64 {
+	return r.absTicks
+}
+
+// Offset returns the byte offset of the last MIDI message in the file
+func (r *reader) Offset() int64 {
+	return r.offset
+}
+
 // Header returns the header of SMF file
 func (r *reader) Header() smf.Header {
 	if !r.headerIsRead {
@@ -132,6 +195,9 @@ func (r *reader) Header() smf.Header {
 }
 
 func (r *reader) tracksMissing() bool {
+	if r.singleTrack {
+		return false
+	}
 	// allow the last track to skip the endoftrack message
 	return r.processedTracks+1 < int16(r.header.NumTracks)
 }
@@ -148,6 +214,13 @@ func (r *reader) Read() (m midi.Message, err error) {
 
 func (r *reader) read() (m midi.Message, err error) {
 	if r.isDone {
+		if r.onChunk != nil && !r.singleTrack && !r.trailingRead {
+			r.trailingRead = true
+			r.readTrailingChunks()
+		}
+		if r.error != nil {
+			return nil, r.error
+		}
 		return nil, smf.ErrFinished
 	}
 
@@ -159,7 +232,8 @@ func (r *reader) read() (m midi.Message, err error) {
 		return nil, r.error
 	}
 
-	if r.expectChunk {
+	// unknown chunks are skipped
+	for r.expectChunk && r.error == nil {
 		r.readChunk()
 	}
 
@@ -167,9 +241,25 @@ func (r *reader) read() (m midi.Message, err error) {
 		return nil, r.error
 	}
 
+	// in recovery mode, missing tracks end the file
+	if r.isDone {
+		return nil, smf.ErrFinished
+	}
+
 	// now we are inside a track
 	r.deltatime = 0
+
+	if r.onProblem != nil && r.missingEndOfTrack() {
+		return r.endOfTrackRecovered(), nil
+	}
+
 	m, r.error = r.readEvent()
+
+	if r.error != nil && r.onProblem != nil && r.recoverTrack(r.error) {
+		r.error = nil
+		m = r.endOfTrackRecovered()
+	}
+
 	return m, r.error
 }
 
@@ -195,7 +285,7 @@ func (r *reader) readMThd() (err error) {
 
 	if chunk.Type() != "MThd" {
 		r.log("wrong chunker type: %v", chunk.Type())
-		err = errExpectedMthd
+		err = &midi.ReadError{Err: ErrExpectedMthd, Offset: 0, Track: -1, Fatal: true}
 		return
 	}
 
@@ -224,6 +314,7 @@ func (r *reader) readChunk() {
 	if r.error != nil {
 		// if we are here, not all tracks have been read, so io.EOF would be an error,
 		// so return errors here in each case
+		r.recoverMissing()
 		return
 	}
 
@@ -231,7 +322,9 @@ func (r *reader) readChunk() {
 	// We have a MTrk
 	if chunk.Type() == "MTrk" {
 		r.log("is track chunk")
+		r.trackEnd = r.count.N + int64(r.expectedChunkLength)
 		r.processedTracks++
+		r.absTicks = 0
 		r.expectChunk = false
 		//p.state = stateExpectTrackEvent
 		// we are done, lets go to the track events
@@ -246,18 +339,62 @@ func (r *reader) readChunk() {
 	*/
 
 	// The header is of an unknown type, skip over it.
-	_, r.error = io.CopyN(ioutil.Discard, r.input, int64(r.expectedChunkLength))
-	r.log("skipping chunk: %v", r.error)
+	r.error = r.skipChunk(chunk.Type())
 	if r.error != nil {
+		r.recoverMissing()
 		return
 	}
 
 	r.expectChunk = true
 }
 
+// skipChunk skips the body of an unknown chunk and passes it to the onChunk callback, if there is one
+func (r *reader) skipChunk(typ string) (err error) {
+	if r.onChunk == nil {
+		_, err = io.CopyN(ioutil.Discard, r.input, int64(r.expectedChunkLength))
+		r.log("skipping chunk: %v", err)
+		return r.unexpected(err)
+	}
+
+	var data []byte
+	data, err = midilib.ReadNBytes(int(r.expectedChunkLength), r.input)
+	r.log("reading unknown chunk: %v", err)
+	if err != nil {
+		return r.unexpected(err)
+	}
+
+	r.onChunk(smf.UnknownChunk{Type: typ, Data: data, Track: r.processedTracks, Offset: r.count.N - int64(len(data)) - 8})
+	return nil
+}
+
+// readTrailingChunks reads the unknown chunks after the last track until the end of the file
+func (r *reader) readTrailingChunks() {
+	for r.error == nil {
+		var chunk smf.Chunk
+		r.expectedChunkLength, r.error = chunk.ReadHeader(r.input)
+
+		if r.error == io.EOF {
+			r.error = nil
+			return
+		}
+
+		if r.error != nil {
+			r.error = r.unexpected(r.error)
+			return
+		}
+
+		r.error = r.skipChunk(chunk.Type())
+	}
+}
+
 func (r *reader) _readEvent(canary byte) (m midi.Message, err error) {
 	r.log("_readEvent, canary: % X", canary)
 
+	// system common and realtime messages are not allowed within SMF files
+	if canary > 0xF0 && canary != 0xF7 && canary != 0xFF {
+		return nil, r.invalidStatus(canary)
+	}
+
 	status, changed := r.runningStatus.Read(canary)
 	r.log("got status: % X, changed: %v", status, changed)
 
@@ -278,15 +415,25 @@ func (r *reader) _readEvent(canary byte) (m midi.Message, err error) {
 			r.log("read system common type: % X, err: %v", typ, err)
 
 			if err != nil {
-				return nil, nil
+				return nil, r.unexpected(err)
 			}
 
 			// since System Common messages are not allowed within smf files, there could only be meta messages
 			// all (event unknown) meta messages must be handled by the meta dispatcher
-			m, err = meta.NewReader(r.input, typ).Read()
-			r.log("got meta: %T", m)
+			var mm meta.Message
+			mm, err = meta.NewReader(r.input, typ).Read()
+			r.log("got meta: %T", mm)
+
+			// texts that can't be decoded are returned as they are
+			if err == nil && r.encoding != nil {
+				if dec, decErr := meta.Decode(mm, r.encoding); decErr == nil {
+					mm = dec
+				}
+			}
+			m = mm
 		default:
-			panic(fmt.Sprintf("must not happen: invalid canary % X", canary))
+			// data bytes without running status
+			return nil, r.invalidStatus(canary)
 		}
 
 		// on a voice/channel category message with status either given or cached (running status)
@@ -297,7 +444,7 @@ func (r *reader) _readEvent(canary byte) (m midi.Message, err error) {
 		if changed {
 			arg1, err = midilib.ReadByte(r.input)
 			if err != nil {
-				return
+				return nil, r.unexpected(err)
 			}
 		}
 
@@ -307,7 +454,7 @@ func (r *reader) _readEvent(canary byte) (m midi.Message, err error) {
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, r.unexpected(err)
 	}
 
 	if m == nil {
@@ -327,13 +474,10 @@ func (r *reader) _readEvent(canary byte) (m midi.Message, err error) {
 			}
 		*/
 
-		// TODO check the read length of the track against the length thas has been read
-		// return ErrTruncatedTrack if meta.EndOfTrack comes to early or ErrOverflowingTrack it it comes too late
-		if uint16(r.processedTracks+1) == r.header.NumTracks {
-			r.log("last track has been read")
-			r.isDone = true
-		} else {
-			r.expectChunk = true
+		r.finishTrack()
+
+		if err = r.checkTrackEnd(); err != nil {
+			return nil, err
 		}
 
 		// p.state = stateExpectChunk
@@ -342,6 +486,16 @@ func (r *reader) _readEvent(canary byte) (m midi.Message, err error) {
 	return m, nil
 }
 
+// finishTrack sets the state after the end of a track
+func (r *reader) finishTrack() {
+	if r.singleTrack || uint16(r.processedTracks+1) == r.header.NumTracks {
+		r.log("last track has been read")
+		r.isDone = true
+	} else {
+		r.expectChunk = true
+	}
+}
+
 func (r *reader) readEvent() (m midi.Message, err error) {
 	if r.error != nil {
 		return nil, r.error
@@ -349,13 +503,20 @@ func (r *reader) readEvent() (m midi.Message, err error) {
 
 	var deltatime uint32
 
+	start := r.count.N
+	r.offset = start
 	deltatime, err = midilib.ReadVarLength(r.input)
 	r.log("read delta: %v, err: %v", deltatime, err)
 	if err != nil {
 		return
 	}
 
+	if r.strict && r.count.N-start > 4 {
+		return nil, &midi.ReadError{Err: ErrInvalidVarLength, Offset: start, Track: r.processedTracks, Fatal: true}
+	}
+
 	r.deltatime = deltatime
+	r.absTicks += uint64(deltatime)
 
 	// read the canary in the coal mine to see, if we have a running status byte or a given one
 	var canary byte
@@ -386,7 +547,7 @@ func (r *reader) parseHeaderData(reader io.Reader) error {
 	case 2:
 		r.header.Format = smf.SMF2
 	default:
-		return errUnsupportedSMFFormat
+		return &midi.ReadError{Err: ErrUnsupportedSMFFormat, Offset: r.count.N - 1, Byte: byte(format), Track: -1, Fatal: true}
 	}
 
 	r.header.NumTracks, err = midilib.ReadUint16(reader)
@@ -477,4 +638,51 @@ func parseTimeCode(raw uint16) (t smf.TimeCode) {
 	return
 }
 
-var errUnexpectedEOF = errors.New("Unexpected End of File found.")
+// checkTrackEnd checks the position at the end of track against the length of the track chunk.
+// In strict mode, a difference is an error. Otherwise the remaining bytes of the chunk are skipped if another track follows.
+func (r *reader) checkTrackEnd() error {
+	if r.trackEnd == 0 || r.count.N == r.trackEnd {
+		return nil
+	}
+
+	if r.strict {
+		return &midi.ReadError{Err: ErrTrackLength, Offset: r.count.N, Track: r.processedTracks, Fatal: true}
+	}
+
+	if r.onProblem != nil {
+		return r.recoverTrackEnd()
+	}
+
+	// the rest of the last track only needs to be skipped, if the chunks after it are read
+	if (r.isDone && (r.onChunk == nil || r.singleTrack)) || r.count.N > r.trackEnd {
+		return nil
+	}
+
+	offset := r.count.N
+	skipped := make([]byte, r.trackEnd-offset)
+
+	if _, err := io.ReadFull(r.input, skipped); err != nil {
+		return r.unexpected(err)
+	}
+
+	r.log("skipped % X after end of track", skipped)
+
+	if r.onSkip != nil {
+		r.onSkip(skipped, offset)
+	}
+
+	return nil
+}
+
+// invalidStatus returns a ReadError for the invalid status byte b that has just been read
+func (r *reader) invalidStatus(b byte) error {
+	return &midi.ReadError{Err: midi.ErrInvalidStatus, Offset: r.count.N - 1, Byte: b, Track: r.processedTracks, Fatal: true}
+}
+
+// unexpected returns a ReadError for the end of the input within an event; other errors are returned unchanged
+func (r *reader) unexpected(err error) error {
+	if err == io.EOF || err == midi.ErrUnexpectedEOF {
+		return &midi.ReadError{Err: midi.ErrUnexpectedEOF, Offset: r.count.N, Track: r.processedTracks, Fatal: true}
+	}
+	return err
+}
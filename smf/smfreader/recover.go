@@ -0,0 +1,154 @@
+package smfreader
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/meta"
+)
+
+// problem reports a problem that is tolerated in recovery mode
+func (r *reader) problem(err error, offset int64) {
+	r.log("recovering from %v at %v", err, offset)
+	r.onProblem(&midi.ReadError{Err: err, Offset: offset, Track: r.processedTracks})
+}
+
+// recoverMissing ends the file in recovery mode, if the next chunk could not be read
+func (r *reader) recoverMissing() {
+	if r.onProblem == nil {
+		return
+	}
+
+	r.problem(ErrMissing, r.count.N)
+
+	r.error = nil
+	r.expectChunk = false
+	r.isDone = true
+}
+
+// recoverTrack reports the fatal error within a track and skips the rest of the track chunk.
+// It returns false for non fatal errors.
+func (r *reader) recoverTrack(err error) bool {
+	re, is := err.(*midi.ReadError)
+
+	if is && !re.Fatal {
+		return false
+	}
+
+	if !is {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = midi.ErrUnexpectedEOF
+		}
+		re = &midi.ReadError{Err: err, Offset: r.count.N, Track: r.processedTracks}
+	}
+
+	re.Fatal = false
+	r.onProblem(re)
+
+	if r.count.N < r.trackEnd {
+		io.CopyN(ioutil.Discard, r.input, r.trackEnd-r.count.N)
+	}
+
+	return true
+}
+
+// endOfTrackRecovered returns the end of track message that replaces a missing one
+func (r *reader) endOfTrackRecovered() midi.Message {
+	r.deltatime = 0
+	r.offset = r.count.N
+	r.trackEnd = 0
+	r.finishTrack()
+	return meta.EndOfTrack
+}
+
+// missingEndOfTrack checks, if the end of the track chunk has been reached without an end of track message.
+// If no chunk follows, the length of the chunk is considered to be wrong and the track continues.
+func (r *reader) missingEndOfTrack() bool {
+	if r.trackEnd == 0 || r.count.N != r.trackEnd {
+		return false
+	}
+
+	if next := r.peek(4); len(next) == 4 && !isChunkType(next) {
+		r.problem(ErrTrackLength, r.count.N)
+		r.trackEnd = 0
+		return false
+	}
+
+	r.problem(ErrMissingEndOfTrack, r.count.N)
+	return true
+}
+
+// recoverTrackEnd reports an end of track message that is not at the end of the track chunk.
+// If the next track chunk begins before the end of the chunk, the length of the chunk was wrong and the
+// reading continues there. Otherwise the rest of the chunk is skipped.
+func (r *reader) recoverTrackEnd() error {
+	r.problem(ErrTrackLength, r.count.N)
+
+	if (r.isDone && (r.onChunk == nil || r.singleTrack)) || r.count.N > r.trackEnd {
+		return nil
+	}
+
+	offset := r.count.N
+	skipped, _ := ioutil.ReadAll(io.LimitReader(r.input, r.trackEnd-offset))
+
+	if i := bytes.Index(skipped, []byte("MTrk")); i >= 0 {
+		r.unread(skipped[i:])
+		skipped = skipped[:i]
+	}
+
+	if len(skipped) > 0 && r.onSkip != nil {
+		r.onSkip(skipped, offset)
+	}
+
+	return nil
+}
+
+// peek returns the next n bytes (or less at the end of the input) without consuming them
+func (r *reader) peek(n int) []byte {
+	b := make([]byte, n)
+	n, _ = io.ReadFull(r.count.R, b)
+	r.count.R = &pushback{buf: b[:n], src: r.count.R}
+	return b[:n]
+}
+
+// unread puts the bytes that have been read back in front of the input
+func (r *reader) unread(b []byte) {
+	r.count.R = &pushback{buf: b, src: r.count.R}
+	r.count.N -= int64(len(b))
+}
+
+// isChunkType returns if b looks like the type of a chunk (4 printable ASCII characters)
+func isChunkType(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+// pushback is a reader that returns buf before reading from src
+type pushback struct {
+	buf []byte
+	src io.Reader
+}
+
+// Read reads from buf and then from src
+func (p *pushback) Read(b []byte) (int, error) {
+	if len(p.buf) == 0 {
+		return p.src.Read(b)
+	}
+	n := copy(b, p.buf)
+	p.buf = p.buf[n:]
+	return n, nil
+}
+
+// Close closes src if it is an io.ReadCloser
+func (p *pushback) Close() error {
+	if cl, is := p.src.(io.ReadCloser); is {
+		return cl.Close()
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package smfreader
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/smf"
+)
+
+func TestRecover(t *testing.T) {
+	const (
+		header = "4D546864 00000006 0001 0002 0060"
+		track1 = "00903C64 60803C00 00FF2F00"
+		track2 = "00C005 00FF2F00"
+	)
+
+	tests := []struct {
+		descr    string
+		input    string
+		expected string
+	}{
+		{
+			"intact",
+			header + "4D54726B 0000000C" + track1 + "4D54726B 00000007" + track2,
+			`
+Track 0@0 channel.NoteOn channel 0 key 60 velocity 100
+Track 0@96 channel.NoteOff channel 0 key 60
+Track 0@0 meta.EndOfTrack
+Track 1@0 channel.ProgramChange channel 0 program 5
+Track 1@0 meta.EndOfTrack
+`,
+		},
+		{
+			"truncated last track",
+			header + "4D54726B 0000000C" + track1 + "4D54726B 00000007" + "00C005 00FF",
+			`
+Track 0@0 channel.NoteOn channel 0 key 60 velocity 100
+Track 0@96 channel.NoteOff channel 0 key 60
+Track 0@0 meta.EndOfTrack
+Track 1@0 channel.ProgramChange channel 0 program 5
+Track 1@0 meta.EndOfTrack
+problem: Unexpected End of File found. at offset 47 in track 1
+`,
+		},
+		{
+			"missing end of track",
+			header + "4D54726B 00000008" + "00903C64 60803C00" + "4D54726B 00000007" + track2,
+			`
+Track 0@0 channel.NoteOn channel 0 key 60 velocity 100
+Track 0@96 channel.NoteOff channel 0 key 60
+Track 0@0 meta.EndOfTrack
+Track 1@0 channel.ProgramChange channel 0 program 5
+Track 1@0 meta.EndOfTrack
+problem: missing end of track at offset 30 in track 0
+`,
+		},
+		{
+			"track length too long",
+			header + "4D54726B 00000010" + track1 + "4D54726B 00000007" + track2,
+			`
+Track 0@0 channel.NoteOn channel 0 key 60 velocity 100
+Track 0@96 channel.NoteOff channel 0 key 60
+Track 0@0 meta.EndOfTrack
+Track 1@0 channel.ProgramChange channel 0 program 5
+Track 1@0 meta.EndOfTrack
+problem: length of track does not match the chunk header at offset 34 in track 0
+`,
+		},
+		{
+			"track length too short",
+			header + "4D54726B 00000008" + track1 + "4D54726B 00000007" + track2,
+			`
+Track 0@0 channel.NoteOn channel 0 key 60 velocity 100
+Track 0@96 channel.NoteOff channel 0 key 60
+Track 0@0 meta.EndOfTrack
+Track 1@0 channel.ProgramChange channel 0 program 5
+Track 1@0 meta.EndOfTrack
+problem: length of track does not match the chunk header at offset 30 in track 0
+`,
+		},
+		{
+			"missing track",
+			header + "4D54726B 0000000C" + track1,
+			`
+Track 0@0 channel.NoteOn channel 0 key 60 velocity 100
+Track 0@96 channel.NoteOff channel 0 key 60
+Track 0@0 meta.EndOfTrack
+problem: incomplete, tracks missing at offset 34 in track 0
+`,
+		},
+	}
+
+	for i, test := range tests {
+		input, err := hex.DecodeString(strings.Replace(test.input, " ", "", -1))
+		if err != nil {
+			t.Fatalf("[%v] invalid input: %v", i, err)
+		}
+
+		var problems []*midi.ReadError
+		rd := New(bytes.NewReader(input), Recover(func(p *midi.ReadError) {
+			problems = append(problems, p)
+		}))
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		for {
+			msg, err := rd.Read()
+			if err != nil {
+				if err != smf.ErrFinished {
+					out.WriteString(fmt.Sprintf("error: %v\n", err))
+				}
+				break
+			}
+			out.WriteString(fmt.Sprintf("Track %v@%v %s\n", rd.Track(), rd.Delta(), msg))
+		}
+
+		for _, p := range problems {
+			out.WriteString(fmt.Sprintf("problem: %v\n", p))
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] %s\ngot:\n%s\n\nwanted:\n%s\n\n", i, test.descr, got, want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package smfreader
+
+import (
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+)
+
+// Tracks returns a smf.Reader that only reads the given tracks (starting with 0), one after another in the given order.
+// Without tracks, all tracks are read. The other tracks are not decoded.
+// The header of the returned reader is already read. Track reports the number of the track in the file.
+// After the end of the last given track, smf.ErrFinished is returned.
+func (i *Index) Tracks(tracks ...int) (smf.Reader, error) {
+	return i.Seek(0, tracks...)
+}
+
+// Seek returns a smf.Reader like Tracks that starts each track at the given absolute time in ticks:
+// The events of a track before tick are skipped and the delta of the first returned event of a track is the distance to tick.
+// The state that is in effect at tick is chased: the last tempo, time signature and key signature and the last program change,
+// control change (of each controller), pitch bend and aftertouch of each channel before tick are returned first with a delta of 0,
+// in their order within the track. For them, AbsTicks and Offset report their original position.
+// If a track ends before tick, its end of track message is returned with a delta of 0 (after the chased state).
+// Only the events of the given tracks before tick are decoded, the other tracks are not touched.
+func (i *Index) Seek(tick uint64, tracks ...int) (smf.Reader, error) {
+	if len(tracks) == 0 {
+		for t := 0; t < i.NumTracks(); t++ {
+			tracks = append(tracks, t)
+		}
+	}
+
+	sr := &seekReader{header: i.header, tick: tick}
+
+	for _, t := range tracks {
+		rd, err := i.Track(t)
+		if err != nil {
+			return nil, err
+		}
+		sr.readers = append(sr.readers, rd)
+	}
+
+	return sr, nil
+}
+
+// seekReader reads the tracks of readers one after another, starting each at tick
+type seekReader struct {
+	header  smf.Header
+	tick    uint64
+	readers []smf.Reader
+	current int
+
+	// started is true, when the first event at or after tick of the current track has been read
+	started bool
+	delta   uint32
+
+	// chased holds the state of the current track before tick that has not yet been returned, followed by the first event
+	chased []chasedEvent
+
+	// position of the last returned chased event, valid if isChased is true
+	isChased bool
+	abs      uint64
+	offset   int64
+}
+
+// chasedEvent is an event before the seek position that carries state
+type chasedEvent struct {
+	msg    midi.Message
+	abs    uint64
+	offset int64
+}
+
+// chaseKey identifies the state that is set by an event
+type chaseKey struct {
+	kind, channel, controller uint8
+}
+
+// chaseKeyOf returns the key of the state that is set by msg and false, if msg carries no state
+func chaseKeyOf(msg midi.Message) (k chaseKey, ok bool) {
+	switch m := msg.(type) {
+	case meta.Tempo:
+		return chaseKey{0x51, 0, 0}, true
+	case meta.TimeSig:
+		return chaseKey{0x58, 0, 0}, true
+	case meta.Key:
+		return chaseKey{0x59, 0, 0}, true
+	case channel.ProgramChange:
+		return chaseKey{0xC, m.Channel(), 0}, true
+	case channel.ControlChange:
+		return chaseKey{0xB, m.Channel(), m.Controller()}, true
+	case channel.Pitchbend:
+		return chaseKey{0xE, m.Channel(), 0}, true
+	case channel.Aftertouch:
+		return chaseKey{0xD, m.Channel(), 0}, true
+	}
+	return k, false
+}
+
+var _ PositionReader = &seekReader{}
+
+// ReadHeader does nothing, since the header has been read by the index
+func (s *seekReader) ReadHeader() error {
+	return nil
+}
+
+// Header returns the header of the SMF file
+func (s *seekReader) Header() smf.Header {
+	return s.header
+}
+
+// Delta returns the delta time of the last message in ticks
+func (s *seekReader) Delta() uint32 {
+	return s.delta
+}
+
+// Track returns the number of the track of the last message
+func (s *seekReader) Track() int16 {
+	if len(s.readers) == 0 {
+		return -1
+	}
+
+	return s.last().Track()
+}
+
+// AbsTicks returns the absolute time of the last message within its track in ticks (not relative to the seek position).
+// Without tracks, it returns 0.
+func (s *seekReader) AbsTicks() uint64 {
+	if len(s.readers) == 0 {
+		return 0
+	}
+
+	if s.isChased {
+		return s.abs
+	}
+
+	return s.last().(PositionReader).AbsTicks()
+}
+
+// Offset returns the byte offset of the last message in the file. Without tracks, it returns -1.
+func (s *seekReader) Offset() int64 {
+	if len(s.readers) == 0 {
+		return -1
+	}
+
+	if s.isChased {
+		return s.offset
+	}
+
+	return s.last().(PositionReader).Offset()
+}
+
+// last returns the reader of the last message
+func (s *seekReader) last() smf.Reader {
+	if s.current >= len(s.readers) {
+		return s.readers[len(s.readers)-1]
+	}
+	return s.readers[s.current]
+}
+
+// Read reads the next message
+func (s *seekReader) Read() (midi.Message, error) {
+	s.isChased = false
+
+	// the chased state of the current track and its first event
+	if len(s.chased) > 0 {
+		ev := s.chased[0]
+		s.chased = s.chased[1:]
+
+		if len(s.chased) == 0 {
+			// the end of track may be before tick
+			s.delta = 0
+			if ev.abs >= s.tick {
+				s.delta = uint32(ev.abs - s.tick)
+			}
+			return ev.msg, nil
+		}
+
+		s.isChased, s.abs, s.offset = true, ev.abs, ev.offset
+		s.delta = 0
+		return ev.msg, nil
+	}
+
+tracks:
+	for s.current < len(s.readers) {
+		rd := s.readers[s.current]
+
+		if s.started {
+			msg, err := rd.Read()
+			if err == smf.ErrFinished {
+				s.current++
+				s.started = false
+				continue
+			}
+			s.delta = rd.Delta()
+			return msg, err
+		}
+
+		var (
+			abs    uint64
+			chased []chasedEvent
+			last   = map[chaseKey]int{}
+		)
+
+		for {
+			msg, err := rd.Read()
+			if err == smf.ErrFinished {
+				s.current++
+				continue tracks
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			abs += uint64(rd.Delta())
+
+			if abs >= s.tick || msg == meta.EndOfTrack {
+				s.started = true
+
+				if len(chased) > 0 {
+					s.chased = chased[:0]
+					for _, ev := range chased {
+						if ev.msg != nil {
+							s.chased = append(s.chased, ev)
+						}
+					}
+					s.chased = append(s.chased, chasedEvent{msg: msg, abs: abs})
+					return s.Read()
+				}
+
+				s.delta = 0
+				if abs >= s.tick {
+					s.delta = uint32(abs - s.tick)
+				}
+				return msg, nil
+			}
+
+			if k, ok := chaseKeyOf(msg); ok {
+				// only the last event of each kind is kept, in the order of the last events
+				if i, has := last[k]; has {
+					chased[i].msg = nil
+				}
+				last[k] = len(chased)
+				chased = append(chased, chasedEvent{msg: msg, abs: abs, offset: rd.(PositionReader).Offset()})
+			}
+		}
+	}
+
+	return nil, smf.ErrFinished
+}
@@ -2,6 +2,7 @@ package smfreader
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/gomidi/midi"
@@ -272,3 +273,127 @@ func TestX(t *testing.T) {
 	_ = msg
 	// fmt.Printf("%s\n", msg)
 }
+
+func TestReadErrors(t *testing.T) {
+	header := []byte{0x4D, 0x54, 0x68, 0x64, 0x00, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x01, 0x00, 0x60}
+	track := []byte{0x4D, 0x54, 0x72, 0x6B, 0x00, 0x00, 0x00, 0x08}
+
+	tests := []struct {
+		input    []byte
+		expected string
+	}{
+		{
+			// system common message inside the track
+			append(append(append([]byte{}, header...), track...), 0x00, 0x90, 0x3C, 0x64, 0x00, 0xF2, 0x00, 0x00),
+			"invalid status byte at offset 27 (byte 0xF2) in track 0",
+		},
+		{
+			// truncated note on
+			append(append(append([]byte{}, header...), track...), 0x00, 0x90, 0x3C),
+			"Unexpected End of File found. at offset 25 in track 0",
+		},
+		{
+			// format 3
+			[]byte{0x4D, 0x54, 0x68, 0x64, 0x00, 0x00, 0x00, 0x06, 0x00, 0x03, 0x00, 0x01, 0x00, 0x60},
+			"The SMF format was not expected. at offset 9 (byte 0x03)",
+		},
+	}
+
+	for i, test := range tests {
+		rd := New(bytes.NewReader(test.input))
+
+		var err error
+
+		for err == nil {
+			_, err = rd.Read()
+		}
+
+		var rerr *midi.ReadError
+
+		if !errors.As(err, &rerr) {
+			t.Fatalf("[%v] expected *midi.ReadError, got: %#v", i, err)
+		}
+
+		if !rerr.Fatal {
+			t.Errorf("[%v] expected fatal error", i)
+		}
+
+		if got, want := err.Error(), test.expected; got != want {
+			t.Errorf("[%v] got %q; wanted %q", i, got, want)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	header := []byte{0x4D, 0x54, 0x68, 0x64, 0x00, 0x00, 0x00, 0x06, 0x00, 0x01, 0x00, 0x02, 0x00, 0x60}
+
+	var in []byte
+	in = append(in, header...)
+	// the first track has 3 bytes of garbage after the end of track
+	in = append(in, 0x4D, 0x54, 0x72, 0x6B, 0x00, 0x00, 0x00, 0x07, 0x00, 0xFF, 0x2F, 0x00, 0x01, 0x02, 0x03)
+	in = append(in, 0x4D, 0x54, 0x72, 0x6B, 0x00, 0x00, 0x00, 0x08, 0x00, 0x90, 0x3C, 0x64, 0x00, 0xFF, 0x2F, 0x00)
+
+	tests := []struct {
+		input    []byte
+		strict   bool
+		expected string
+	}{
+		{
+			in,
+			false,
+			`
+skipped 01 02 03 at offset 26
+Track 0@0 meta.EndOfTrack
+Track 1@0 channel.NoteOn channel 0 key 60 velocity 100
+Track 1@0 meta.EndOfTrack
+error: SMF action finished successfully
+`,
+		},
+		{
+			in,
+			true,
+			`
+error: length of track does not match the chunk header at offset 26 in track 0
+`,
+		},
+		{
+			// 5 byte delta time
+			append(append(append([]byte{}, header[:11]...), 0x01, 0x00, 0x60,
+				0x4D, 0x54, 0x72, 0x6B, 0x00, 0x00, 0x00, 0x08), 0x80, 0x80, 0x80, 0x80, 0x00, 0xFF, 0x2F, 0x00),
+			true,
+			`
+error: variable length quantity longer than 4 bytes at offset 22 in track 0
+`,
+		},
+	}
+
+	for i, test := range tests {
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		opts := []Option{OnSkip(func(skipped []byte, offset int64) {
+			fmt.Fprintf(&out, "skipped % X at offset %v\n", skipped, offset)
+		})}
+
+		if test.strict {
+			opts = append(opts, Strict())
+		}
+
+		rd := New(bytes.NewReader(test.input), opts...)
+
+		for {
+			msg, err := rd.Read()
+
+			if err != nil {
+				fmt.Fprintf(&out, "error: %v\n", err)
+				break
+			}
+
+			fmt.Fprintf(&out, "Track %v@%v %s\n", rd.Track(), rd.Delta(), msg)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package smfwriter
+
+import (
+	"io"
+	"math"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/smf"
+)
+
+// CopyFrom reads the messages of a live stream from src and writes them to the current track of dest,
+// with the delta times computed from the times of the messages. The time of a message is returned by timestamp,
+// that is called after each read. If timestamp is nil, the time is taken from src, if it has a Time method that returns
+// a non zero time (like the reader returned by midireader.New with the Timestamps option), otherwise time.Now is used.
+// The first message is written at the current position of the track.
+//
+// For metric time formats the delta times are based on a tempo of 120 BPM, the default of SMF files.
+// A meta.Tempo message that is read from src changes the tempo for the following messages.
+// Realtime messages are not written, since they are not allowed in SMF files.
+// When src returns io.EOF, the track is ended with a meta.EndOfTrack message and nil is returned.
+func CopyFrom(dest smf.Writer, src midi.Reader, timestamp func() time.Time) error {
+	if timestamp == nil {
+		timestamp = time.Now
+
+		if tr, is := src.(interface{ Time() time.Time }); is {
+			timestamp = func() time.Time {
+				if t := tr.Time(); !t.IsZero() {
+					return t
+				}
+				return time.Now()
+			}
+		}
+	}
+
+	var (
+		ticks = newTicker(dest.Header().TimeFormat)
+		start time.Time
+		last  uint64
+	)
+
+	for {
+		msg, err := src.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if _, is := msg.(realtime.Message); is {
+			continue
+		}
+
+		t := timestamp()
+
+		if start.IsZero() {
+			start = t
+		}
+
+		abs := ticks.at(t.Sub(start))
+		if abs < last {
+			abs = last
+		}
+
+		dest.SetDelta(uint32(abs - last))
+		last = abs
+
+		if err = dest.Write(msg); err != nil {
+			return err
+		}
+
+		if tempo, is := msg.(meta.Tempo); is {
+			ticks.setTempo(t.Sub(start), tempo)
+		}
+	}
+
+	if err := dest.Write(meta.EndOfTrack); err != nil && err != smf.ErrFinished {
+		return err
+	}
+
+	return nil
+}
+
+// ticker converts the time since the start into absolute ticks
+type ticker struct {
+	// ticksPerSec are the ticks per second of the current tempo
+	ticksPerSec float64
+
+	// ticks4th are the ticks per quarter note of metric time formats (0 for time code)
+	ticks4th float64
+
+	// the position of the last tempo change
+	changeTime  time.Duration
+	changeTicks float64
+}
+
+func newTicker(tf smf.TimeFormat) ticker {
+	switch v := tf.(type) {
+	case smf.TimeCode:
+		fps := float64(v.FramesPerSecond)
+		if v.FramesPerSecond == 29 {
+			fps = 29.97
+		}
+		return ticker{ticksPerSec: fps * float64(v.SubFrames)}
+	case smf.MetricTicks:
+		t := ticker{ticks4th: float64(v.Ticks4th())}
+		// 120 BPM
+		t.ticksPerSec = t.ticks4th * 2
+		return t
+	default:
+		return ticker{ticksPerSec: float64(smf.MetricTicks(0).Ticks4th()) * 2}
+	}
+}
+
+// at returns the absolute ticks of the time since the start
+func (t *ticker) at(d time.Duration) uint64 {
+	return uint64(math.Round(t.changeTicks + (d-t.changeTime).Seconds()*t.ticksPerSec))
+}
+
+// setTempo changes the tempo at the given time since the start. It is ignored for time code.
+func (t *ticker) setTempo(d time.Duration, tempo meta.Tempo) {
+	if t.ticks4th == 0 || tempo.MuSecPerQN() == 0 {
+		return
+	}
+
+	t.changeTicks += (d - t.changeTime).Seconds() * t.ticksPerSec
+	t.changeTime = d
+	t.ticksPerSec = t.ticks4th * 1000000 / float64(tempo.MuSecPerQN())
+}
@@ -73,5 +73,21 @@ Usage
 
 	// deal with err
 
+Writers that are not used via WriteFile should be finished via the Finisher interface. It writes the last track with
+a meta.EndOfTrack message and corrects the number of tracks in the header, if less tracks have been written:
+
+	wr := smfwriter.New(file, smfwriter.NumTracks(4))
+	// write the tracks
+	err := wr.(smfwriter.Finisher).Finish()
+
+A live stream can be recorded with CopyFrom, that computes the delta times from the times of the messages:
+
+	rd := midireader.New(input, nil, midireader.Timestamps())
+	err := smfwriter.CopyFrom(smfwriter.New(file), rd, nil)
+
+The texts of meta messages can be written in another encoding than UTF-8 with the TextEncoding option, e.g.
+
+	smfwriter.WriteFile("file.mid", writeMIDI, smfwriter.TextEncoding(meta.Latin1))
+
 */
 package smfwriter
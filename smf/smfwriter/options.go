@@ -1,6 +1,7 @@
 package smfwriter
 
 import (
+	"github.com/gomidi/midi/midimessage/meta"
 	"github.com/gomidi/midi/smf"
 )
 
@@ -30,6 +31,14 @@ func NoRunningStatus() Option {
 	}
 }
 
+// RunningStatus lets the writer use running status to compress the file (the default).
+// It overrides a NoRunningStatus option that was passed before (e.g. as part of a default option set).
+func RunningStatus() Option {
+	return func(w *writer) {
+		w.noRunningStatus = false
+	}
+}
+
 // TimeFormat sets the timeformat. Allowed values are smf.MetricTicks and smf.TimeCode
 // Without passing this option or when timeformat is nil, smf.MetricTicks(960) will be used.
 func TimeFormat(timeformat smf.TimeFormat) Option {
@@ -58,8 +67,27 @@ func NumTracks(ntracks uint16) Option {
 // Format sets the SMF file format version.
 // Valid values are: smf.SMF0 (single track), smf.SMF1 (multi track), smf.SMF2 (sequential track)
 // If this option is not given, SMF0 will be used as default if the number of tracks is 1, otherwise SMF1.
+// SMF2 files consist of independent patterns, one per track (see player.Playlist).
 func Format(f smf.Format) Option {
 	return func(w *writer) {
 		w.header.Format = f
 	}
 }
+
+// Validate lets the writer refuse channel messages with values out of range (see channel.Validate), e.g. a channel above 15.
+// The error of channel.Validate is returned and nothing is written. The delta time is kept for the next message.
+// Without this option, invalid messages are written as they are.
+func Validate() Option {
+	return func(w *writer) {
+		w.validate = true
+	}
+}
+
+// TextEncoding lets the writer encode the texts of meta text messages (e.g. meta.Lyric or meta.Track) with the given encoding,
+// e.g. meta.Latin1. If a text can't be encoded, the error is returned and nothing is written. The delta time is kept for the next message.
+// Without this option the texts are written as they are (UTF-8).
+func TextEncoding(enc meta.Encoding) Option {
+	return func(w *writer) {
+		w.encoding = enc
+	}
+}
@@ -3,13 +3,19 @@ package smfwriter
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gomidi/midi"
 	"github.com/gomidi/midi/internal/examples"
 	"github.com/gomidi/midi/midimessage/channel"
 	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/midimessage/realtime"
 	"github.com/gomidi/midi/midimessage/sysex"
 	"github.com/gomidi/midi/smf"
 	"github.com/gomidi/midi/smf/smfreader"
@@ -233,6 +239,31 @@ func TestRunningStatus(t *testing.T) {
 	}
 }
 
+func TestRunningStatusOption(t *testing.T) {
+
+	var bf bytes.Buffer
+
+	// the last option wins
+	wr := New(&bf, NoRunningStatus(), RunningStatus())
+
+	err := wr.WriteHeader()
+
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	wr.Write(channel.Channel0.NoteOn(50, 33))
+	wr.SetDelta(2)
+	wr.Write(channel.Channel0.NoteOff(50))
+	wr.Write(meta.EndOfTrack)
+
+	expected := "4D 54 68 64 00 00 00 06 00 00 00 01 03 C0 4D 54 72 6B 00 00 00 0B 00 90 32 21 02 32 00 00 FF 2F 00"
+
+	if got, want := fmt.Sprintf("% X", bf.Bytes()), expected; got != want {
+		t.Errorf("got:\n%#v\nwanted:\n%#v\n\n", got, want)
+	}
+}
+
 func TestNoRunningStatus(t *testing.T) {
 
 	var bf bytes.Buffer
@@ -256,3 +287,292 @@ func TestNoRunningStatus(t *testing.T) {
 		t.Errorf("got:\n%#v\nwanted:\n%#v\n\n", got, want)
 	}
 }
+
+func TestPatchChange(t *testing.T) {
+
+	var bf bytes.Buffer
+
+	wr := New(&bf)
+
+	wr.SetDelta(4)
+	wr.Write(channel.Channel0.PatchChange(1, 2, 5))
+	wr.Write(meta.EndOfTrack)
+
+	expected := "4D 54 68 64 00 00 00 06 00 00 00 01 03 C0 4D 54 72 6B 00 00 00 0E 04 B0 00 01 00 20 02 00 C0 05 00 FF 2F 00"
+
+	if got, want := fmt.Sprintf("% X", bf.Bytes()), expected; got != want {
+		t.Errorf("got:\n%#v\nwanted:\n%#v\n\n", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	var bf bytes.Buffer
+
+	wr := New(&bf, Validate())
+
+	wr.SetDelta(4)
+	if err := wr.Write(channel.Channel(16).NoteOn(60, 100)); err == nil {
+		t.Errorf("expected an error")
+	}
+
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+	wr.Write(meta.EndOfTrack)
+
+	expected := "4D 54 68 64 00 00 00 06 00 00 00 01 03 C0 4D 54 72 6B 00 00 00 08 04 90 3C 64 00 FF 2F 00"
+
+	if got, want := fmt.Sprintf("% X", bf.Bytes()), expected; got != want {
+		t.Errorf("got:\n%#v\nwanted:\n%#v\n\n", got, want)
+	}
+}
+
+func TestUnknownChunks(t *testing.T) {
+	var bf bytes.Buffer
+
+	wr := New(&bf, NumTracks(2)).(ChunkWriter)
+
+	wr.WriteChunk(smf.UnknownChunk{Type: "XFIH", Data: []byte{0x01, 0x02}})
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+
+	if err := wr.WriteChunk(smf.UnknownChunk{Type: "XFKM"}); err == nil {
+		t.Errorf("expected error for chunk inside track")
+	}
+
+	wr.Write(meta.EndOfTrack)
+	wr.Write(meta.EndOfTrack)
+
+	if err := wr.WriteChunk(smf.UnknownChunk{Type: "XFKM", Data: []byte{0x03}}); err != nil {
+		t.Fatalf("can't write chunk after the last track: %v", err)
+	}
+
+	original := bf.Bytes()
+
+	var chunks []smf.UnknownChunk
+	var msgs []midi.Message
+
+	rd := smfreader.New(bytes.NewReader(original), smfreader.OnUnknownChunk(func(c smf.UnknownChunk) {
+		chunks = append(chunks, c)
+	}))
+
+	for {
+		msg, err := rd.Read()
+		if err == smf.ErrFinished {
+			break
+		}
+		if err != nil {
+			t.Fatalf("can't read: %v", err)
+		}
+		msgs = append(msgs, msg)
+	}
+
+	expected := []smf.UnknownChunk{
+		{Type: "XFIH", Data: []byte{0x01, 0x02}, Track: -1, Offset: 14},
+		{Type: "XFKM", Data: []byte{0x03}, Track: 1, Offset: 52},
+	}
+
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("got:\n%#v\n\nwanted:\n%#v\n\n", chunks, expected)
+	}
+
+	if got, want := len(msgs), 3; got != want {
+		t.Errorf("got %v messages; wanted %v", got, want)
+	}
+
+	// write it back
+	var back bytes.Buffer
+	wr = New(&back, NumTracks(2)).(ChunkWriter)
+	wr.WriteChunk(chunks[0])
+	for _, msg := range msgs {
+		wr.Write(msg)
+	}
+	wr.WriteChunk(chunks[1])
+
+	if got, want := fmt.Sprintf("% X", back.Bytes()), fmt.Sprintf("% X", original); got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestTextEncoding(t *testing.T) {
+	var bf bytes.Buffer
+
+	wr := New(&bf, TextEncoding(meta.Latin1))
+	wr.Write(meta.Track("Grüße"))
+
+	if err := wr.Write(meta.Lyric("日本")); err == nil {
+		t.Errorf("expected error for text that can't be encoded as Latin-1")
+	}
+
+	wr.Write(meta.EndOfTrack)
+
+	if !bytes.Contains(bf.Bytes(), []byte{0xFF, 0x04, 0x05, 'G', 'r', 0xFC, 0xDF, 'e'}) {
+		t.Errorf("track name is not encoded as Latin-1: % X", bf.Bytes())
+	}
+
+	var msgs []string
+
+	rd := smfreader.New(bytes.NewReader(bf.Bytes()), smfreader.TextEncoding(meta.Latin1))
+
+	for {
+		msg, err := rd.Read()
+		if err != nil {
+			break
+		}
+		msgs = append(msgs, msg.String())
+	}
+
+	expected := `meta.Track: "Grüße"
+meta.EndOfTrack`
+
+	if got, want := strings.Join(msgs, "\n"), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestFinish(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "finish.mid"))
+
+	if err != nil {
+		t.Fatalf("can't create file: %v", err)
+	}
+
+	defer f.Close()
+
+	wr := New(f, NumTracks(3)).(Finisher)
+	wr.Write(meta.Track("first"))
+	wr.Write(meta.EndOfTrack)
+	wr.SetDelta(10)
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+
+	if err := wr.Finish(); err != nil {
+		t.Fatalf("can't finish: %v", err)
+	}
+
+	if err := wr.Write(channel.Channel0.NoteOff(60)); err != smf.ErrFinished {
+		t.Errorf("expected smf.ErrFinished for write after Finish, got %v", err)
+	}
+
+	f.Seek(0, io.SeekStart)
+
+	rd := smfreader.New(f)
+	var msgs []string
+
+	for {
+		msg, err := rd.Read()
+		if err == smf.ErrFinished {
+			break
+		}
+		if err != nil {
+			t.Fatalf("can't read: %v", err)
+		}
+		msgs = append(msgs, fmt.Sprintf("%v@%v %s", rd.Track(), rd.Delta(), msg))
+	}
+
+	if got, want := rd.Header().NumTracks, uint16(2); got != want {
+		t.Errorf("NumTracks = %v; wanted %v", got, want)
+	}
+
+	expected := `0@0 meta.Track: "first"
+0@0 meta.EndOfTrack
+1@10 channel.NoteOn channel 0 key 60 velocity 100
+1@0 meta.EndOfTrack`
+
+	if got, want := strings.Join(msgs, "\n"), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	// the number of tracks can't be corrected without seeking
+	var bf bytes.Buffer
+	wr = New(&bf, NumTracks(2)).(Finisher)
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+
+	if err := wr.Finish(); err == nil {
+		t.Errorf("expected error for missing tracks without io.WriteSeeker")
+	}
+}
+
+func TestSMF2(t *testing.T) {
+	tests := []struct {
+		ntracks  uint16
+		expected string
+	}{
+		{1, "4D 54 68 64 00 00 00 06 00 02 00 01 03 C0"},
+		{3, "4D 54 68 64 00 00 00 06 00 02 00 03 03 C0"},
+	}
+
+	for i, test := range tests {
+		var bf bytes.Buffer
+
+		wr := New(&bf, Format(smf.SMF2), NumTracks(test.ntracks))
+		wr.WriteHeader()
+
+		if got, want := fmt.Sprintf("% X", bf.Bytes()), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+// timedReader returns the messages at the given times (in milliseconds)
+type timedReader struct {
+	msgs  []midi.Message
+	times []int
+	i     int
+	start time.Time
+}
+
+func (r *timedReader) Read() (midi.Message, error) {
+	if r.i >= len(r.msgs) {
+		return nil, io.EOF
+	}
+	r.i++
+	return r.msgs[r.i-1], nil
+}
+
+func (r *timedReader) Time() time.Time {
+	return r.start.Add(time.Duration(r.times[r.i-1]) * time.Millisecond)
+}
+
+func TestCopyFrom(t *testing.T) {
+	src := &timedReader{
+		msgs: []midi.Message{
+			channel.Channel0.NoteOn(60, 100),
+			realtime.TimingClock,
+			channel.Channel0.NoteOff(60),
+			meta.FractionalBPM(60),
+			channel.Channel0.NoteOn(62, 100),
+		},
+		times: []int{100, 200, 350, 600, 1100},
+		start: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var bf bytes.Buffer
+	wr := New(&bf, TimeFormat(smf.MetricTicks(96)))
+
+	if err := CopyFrom(wr, src, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rd := smfreader.New(bytes.NewReader(bf.Bytes()))
+	var msgs []string
+
+	for {
+		msg, err := rd.Read()
+		if err == smf.ErrFinished {
+			break
+		}
+		if err != nil {
+			t.Fatalf("can't read: %v", err)
+		}
+		msgs = append(msgs, fmt.Sprintf("%v %s", rd.Delta(), msg))
+	}
+
+	// 192 ticks per second at 120 BPM and 96 ticks per second at 60 BPM
+	expected := `0 channel.NoteOn channel 0 key 60 velocity 100
+48 channel.NoteOff channel 0 key 60
+48 meta.Tempo BPM: 60.00
+48 channel.NoteOn channel 0 key 62 velocity 100
+0 meta.EndOfTrack`
+
+	if got, want := strings.Join(msgs, "\n"), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
@@ -13,20 +13,22 @@ import (
 
 	"github.com/gomidi/midi"
 
+	"github.com/gomidi/midi/midimessage/channel"
 	"github.com/gomidi/midi/midimessage/meta"
 	"github.com/gomidi/midi/smf"
 )
 
 // WriteFile creates file, calls callback with a writer and closes file
 //
-// WriteFile makes sure that the data of the last track is written by sending
-// an meta.EndOfTrack message after callback has been run.
+// WriteFile makes sure that the data of the last track is written by calling
+// Finish after callback has been run (see Finisher).
 //
 // For single track (SMF0) files this makes sense since no meta.EndOfTrack message
 // must then be send from callback (although it does not harm).
 //
 // For multitrack files however there must be sending of meta.EndOfTrack anyway,
 // so it is better practise to send it after each track (including the last one).
+// If callback writes less tracks than passed via NumTracks, the number of tracks in the header is corrected.
 // The options and their defaults are the same as for New and they are documented
 // at the corresponding option.
 // The callback may call the given writer to write messages. If any of this write
@@ -59,12 +61,12 @@ func WriteFile(file string, callback func(smf.Writer), options ...Option) error
 	}
 
 	// make sure the data of the last track is written
-	err = wr.Write(meta.EndOfTrack)
+	err = wr.Finish()
 
-	if err != nil && err != smf.ErrFinished {
+	if err != nil {
 		f.Close()
 		os.Remove(file)
-		return fmt.Errorf("could not write end of track message to midi file %#v", file)
+		return fmt.Errorf("could not finish midi file %#v: %v", file, err)
 	}
 
 	err = f.Close()
@@ -76,6 +78,22 @@ func WriteFile(file string, callback func(smf.Writer), options ...Option) error
 	return nil
 }
 
+// Save writes the SMF file f to dest with the delta times and the lengths of the tracks computed from f.
+// The given options are passed to the writer after the options for the format, the time format and the number of tracks of f.
+func Save(dest io.Writer, f *smf.File, options ...Option) error {
+	if len(f.Tracks) == 0 {
+		return fmt.Errorf("can't save file without tracks")
+	}
+
+	opts := []Option{TimeFormat(f.TimeFormat), NumTracks(uint16(len(f.Tracks)))}
+
+	if f.Format != nil {
+		opts = append(opts, Format(f.Format))
+	}
+
+	return f.Save(New(dest, append(opts, options...)...))
+}
+
 // New returns a Writer
 //
 // The writer just uses an io.Writer..It is the responsibility of the caller to open and close any file where appropriate.
@@ -89,6 +107,33 @@ func New(dest io.Writer, opts ...Option) smf.Writer {
 	return newWriter(dest, opts...)
 }
 
+// ChunkWriter is a smf.Writer that also writes chunks that are neither header nor track chunks,
+// e.g. proprietary chunks that have been read via smfreader.OnUnknownChunk. The writer returned by New implements it.
+type ChunkWriter interface {
+	smf.Writer
+
+	// WriteChunk writes the given chunk after the tracks that have been written so far.
+	// It must not be called in the middle of a track; the Track field of the chunk is ignored.
+	// It may also be called after the last track.
+	WriteChunk(c smf.UnknownChunk) error
+}
+
+var _ ChunkWriter = &writer{}
+
+// Finisher is a smf.Writer that can finish the file, so that the file is valid even if the last meta.EndOfTrack message
+// has not been written or less tracks than passed via NumTracks have been written. The writer returned by New implements it.
+type Finisher interface {
+	smf.Writer
+
+	// Finish writes the current track with an added meta.EndOfTrack message, if it has messages (or if no track has been written).
+	// If less tracks have been written than announced in the header, the number of tracks in the header is corrected.
+	// This requires the destination to be an io.WriteSeeker, otherwise an error is returned.
+	// Every Write after Finish returns smf.ErrFinished.
+	Finish() error
+}
+
+var _ Finisher = &writer{}
+
 type writer struct {
 	header          smf.Header
 	track           smf.Chunk
@@ -97,14 +142,89 @@ type writer struct {
 	tracksProcessed uint16
 	deltatime       uint32
 	noRunningStatus bool
+	validate        bool
+	encoding        meta.Encoding
 	error           error
 	runningWriter   runningstatus.SMFWriter
+
+	// headerOffset is the position of the header in the output, if the output is an io.WriteSeeker
+	headerOffset int64
 }
 
+// Close finishes the file (see Finish) and closes the output, if it is an io.WriteCloser
 func (w *writer) Close() error {
+	err := w.Finish()
+
 	if cl, is := w.output.(io.WriteCloser); is {
-		return cl.Close()
+		if cerr := cl.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Finish writes the current track and corrects the number of tracks in the header
+func (w *writer) Finish() error {
+	if w.error != nil && w.error != smf.ErrFinished {
+		return w.error
+	}
+
+	if !w.headerWritten {
+		if err := w.WriteHeader(); err != nil {
+			return err
+		}
+	}
+
+	if w.error == smf.ErrFinished {
+		return nil
+	}
+
+	if w.track.Len() > 0 || w.tracksProcessed == 0 {
+		w.addMessage(w.deltatime, meta.EndOfTrack)
+		if err := w.writeTrackTo(w.output); err != nil && err != smf.ErrFinished {
+			w.error = err
+			return err
+		}
+	}
+
+	if w.tracksProcessed < w.header.NumTracks {
+		if err := w.patchNumTracks(); err != nil {
+			w.error = err
+			return err
+		}
+	}
+
+	w.error = smf.ErrFinished
+	return nil
+}
+
+// patchNumTracks sets the number of tracks in the written header to the number of written tracks
+func (w *writer) patchNumTracks() error {
+	ws, is := w.output.(io.WriteSeeker)
+
+	if !is {
+		return fmt.Errorf("%v of %v tracks written: can't correct the header, since the output is not an io.WriteSeeker", w.tracksProcessed, w.header.NumTracks)
 	}
+
+	end, err := ws.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("could not correct the number of tracks: %v", err)
+	}
+
+	// the number of tracks follows the chunk type, the length and the format
+	if _, err = ws.Seek(w.headerOffset+10, io.SeekStart); err != nil {
+		return fmt.Errorf("could not correct the number of tracks: %v", err)
+	}
+
+	if err = binary.Write(ws, binary.BigEndian, w.tracksProcessed); err != nil {
+		return fmt.Errorf("could not correct the number of tracks: %v", err)
+	}
+
+	if _, err = ws.Seek(end, io.SeekStart); err != nil {
+		return fmt.Errorf("could not correct the number of tracks: %v", err)
+	}
+
+	w.header.NumTracks = w.tracksProcessed
 	return nil
 }
 
@@ -112,6 +232,10 @@ func (w *writer) WriteHeader() error {
 	if w.headerWritten {
 		return w.error
 	}
+	if ws, is := w.output.(io.WriteSeeker); is {
+		w.headerOffset, _ = ws.Seek(0, io.SeekCurrent)
+	}
+
 	err := w.writeHeader(w.output)
 	w.headerWritten = true
 
@@ -178,6 +302,19 @@ func (w *writer) Write(m midi.Message) (err error) {
 		w.error = fmt.Errorf("writing header before midi message %#v failed: %v", m, w.error)
 		return w.error
 	}
+
+	if cm, is := m.(channel.Message); is && w.validate {
+		if err = channel.Validate(cm); err != nil {
+			return err
+		}
+	}
+
+	if mm, is := m.(meta.Message); is && w.encoding != nil {
+		if m, err = meta.Encode(mm, w.encoding); err != nil {
+			return err
+		}
+	}
+
 	defer func() {
 		w.deltatime = 0
 	}()
@@ -195,10 +332,55 @@ func (w *writer) Write(m midi.Message) (err error) {
 		}
 		return
 	}
+
+	// a patch change is written as its messages at the same time
+	if pc, is := m.(channel.PatchChange); is {
+		delta := w.deltatime
+		for _, msg := range pc.Messages() {
+			w.addMessage(delta, msg)
+			delta = 0
+		}
+		return
+	}
+
 	w.addMessage(w.deltatime, m)
 	return
 }
 
+// WriteChunk writes the given chunk after the tracks that have been written so far
+func (w *writer) WriteChunk(c smf.UnknownChunk) error {
+	if w.error != nil && w.error != smf.ErrFinished {
+		return w.error
+	}
+
+	if !w.headerWritten {
+		w.error = w.WriteHeader()
+	}
+	if w.error != nil && w.error != smf.ErrFinished {
+		w.error = fmt.Errorf("writing header before chunk %q failed: %v", c.Type, w.error)
+		return w.error
+	}
+
+	if len(c.Type) != 4 || c.Type == "MThd" || c.Type == "MTrk" {
+		return fmt.Errorf("invalid chunk type %q", c.Type)
+	}
+
+	if w.track.Len() > 0 {
+		return fmt.Errorf("can't write chunk %q inside track %v", c.Type, w.tracksProcessed)
+	}
+
+	var ch smf.Chunk
+	ch.SetType([4]byte{c.Type[0], c.Type[1], c.Type[2], c.Type[3]})
+	ch.Write(c.Data)
+
+	if _, err := ch.WriteTo(w.output); err != nil {
+		w.error = fmt.Errorf("could not write chunk %q: %v", c.Type, err)
+		return w.error
+	}
+
+	return nil
+}
+
 /*
 
 					| time type            | bit 15 | bits 14 thru 8        | bits 7 thru 0   |
@@ -0,0 +1,128 @@
+package smf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gomidi/midi/midimessage/meta"
+)
+
+// defaultTempo is the tempo of a file without tempo messages (120 BPM) in microseconds per quarter note
+const defaultTempo = 500000
+
+// tempoChange is a change of the tempo at an absolute time
+type tempoChange struct {
+	abs   uint64
+	tempo float64 // microseconds per quarter note
+}
+
+// tempoMap converts absolute times in ticks to microseconds
+type tempoMap struct {
+	ticks4th float64
+	changes  []tempoChange
+}
+
+// newTempoMap returns the tempo map of the tempo messages of the given tracks
+func newTempoMap(resolution MetricTicks, tracks ...*Track) *tempoMap {
+	m := &tempoMap{ticks4th: float64(resolution.Ticks4th())}
+
+	for _, t := range tracks {
+		for _, ev := range t.Events {
+			if tempo, is := ev.Message.(meta.Tempo); is && tempo > 0 {
+				m.changes = append(m.changes, tempoChange{abs: ev.AbsTicks, tempo: float64(tempo)})
+			}
+		}
+	}
+
+	// for the same time, the last change of the last track wins
+	sort.SliceStable(m.changes, func(a, b int) bool {
+		return m.changes[a].abs < m.changes[b].abs
+	})
+
+	return m
+}
+
+// micros returns the time of the given absolute time in microseconds
+func (m *tempoMap) micros(abs uint64) float64 {
+	var (
+		res   float64
+		last  uint64
+		tempo float64 = defaultTempo
+	)
+
+	for _, c := range m.changes {
+		if c.abs >= abs {
+			break
+		}
+		res += float64(c.abs-last) * tempo / m.ticks4th
+		last, tempo = c.abs, c.tempo
+	}
+
+	// a change at abs itself does not affect the time of abs
+	return res + float64(abs-last)*tempo/m.ticks4th
+}
+
+// Flatten rewrites the file to the given constant tempo, while the events keep their positions in real time.
+// All tempo messages are removed and a single tempo message is put at the start of the first track
+// (of each track for SMF2 files, since their tracks have their own tempos).
+// It returns an error if the file does not have a metric time format.
+func Flatten(f *File, tempo meta.Tempo) error {
+	resolution, is := f.TimeFormat.(MetricTicks)
+
+	if !is {
+		return fmt.Errorf("can't flatten time format %v", f.TimeFormat)
+	}
+
+	if tempo == 0 {
+		return fmt.Errorf("invalid tempo 0")
+	}
+
+	var (
+		ticksPerMicro = float64(resolution.Ticks4th()) / float64(tempo)
+		global        = newTempoMap(resolution, f.Tracks...)
+	)
+
+	for i, t := range f.Tracks {
+		m := global
+		if f.Format == SMF2 {
+			m = newTempoMap(resolution, t)
+		}
+
+		var events []Event
+
+		for _, ev := range t.Events {
+			if _, is := ev.Message.(meta.Tempo); is {
+				continue
+			}
+			ev.AbsTicks = uint64(math.Round(m.micros(ev.AbsTicks) * ticksPerMicro))
+			events = append(events, ev)
+		}
+
+		if i == 0 || f.Format == SMF2 {
+			events = append([]Event{{Message: tempo}}, events...)
+		}
+
+		t.Events = events
+		t.End = uint64(math.Round(m.micros(t.End) * ticksPerMicro))
+	}
+
+	return nil
+}
+
+// Stretch scales the playback time of the file by the given factor (e.g. 2 for half the speed) by scaling the
+// times of all events. The tempo messages are kept, so a flattened file stays flattened.
+func Stretch(f *File, factor float64) error {
+	if factor <= 0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		return fmt.Errorf("invalid factor %v", factor)
+	}
+
+	for _, t := range f.Tracks {
+		for i := range t.Events {
+			t.Events[i].AbsTicks = uint64(math.Round(float64(t.Events[i].AbsTicks) * factor))
+		}
+		t.End = uint64(math.Round(float64(t.End) * factor))
+	}
+
+	return nil
+}
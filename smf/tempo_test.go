@@ -0,0 +1,118 @@
+package smf_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/meta"
+	"github.com/gomidi/midi/smf"
+)
+
+func tempoFile() *smf.File {
+	f := &smf.File{Format: smf.SMF1, TimeFormat: smf.MetricTicks(96)}
+
+	t0 := f.AddTrack()
+	t0.Insert(0, meta.BPM(120))
+	t0.Insert(96, meta.BPM(60))
+
+	t1 := f.AddTrack()
+	for i := uint64(0); i < 4; i++ {
+		t1.Insert(i*96, channel.Channel0.NoteOn(60, 100))
+	}
+	t1.End = 384
+
+	return f
+}
+
+func fileString(f *smf.File) string {
+	var out bytes.Buffer
+
+	for i, t := range f.Tracks {
+		out.WriteString(fmt.Sprintf("\ntrack %v", i))
+		out.WriteString(trackString(t))
+	}
+
+	return out.String()
+}
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		tempo    meta.Tempo
+		expected string
+	}{
+		{
+			meta.BPM(120),
+			`
+track 0
+@0 meta.Tempo BPM: 120.00
+end @0
+
+track 1
+@0 channel.NoteOn channel 0 key 60 velocity 100
+@96 channel.NoteOn channel 0 key 60 velocity 100
+@288 channel.NoteOn channel 0 key 60 velocity 100
+@480 channel.NoteOn channel 0 key 60 velocity 100
+end @672
+`,
+		},
+		{
+			meta.BPM(60),
+			`
+track 0
+@0 meta.Tempo BPM: 60.00
+end @0
+
+track 1
+@0 channel.NoteOn channel 0 key 60 velocity 100
+@48 channel.NoteOn channel 0 key 60 velocity 100
+@144 channel.NoteOn channel 0 key 60 velocity 100
+@240 channel.NoteOn channel 0 key 60 velocity 100
+end @336
+`,
+		},
+	}
+
+	for i, test := range tests {
+		f := tempoFile()
+
+		if err := smf.Flatten(f, test.tempo); err != nil {
+			t.Fatalf("[%v] can't flatten: %v", i, err)
+		}
+
+		if got, want := fileString(f), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestStretch(t *testing.T) {
+	f := tempoFile()
+
+	if err := smf.Stretch(f, 2); err != nil {
+		t.Fatalf("can't stretch: %v", err)
+	}
+
+	expected := `
+track 0
+@0 meta.Tempo BPM: 120.00
+@192 meta.Tempo BPM: 60.00
+end @0
+
+track 1
+@0 channel.NoteOn channel 0 key 60 velocity 100
+@192 channel.NoteOn channel 0 key 60 velocity 100
+@384 channel.NoteOn channel 0 key 60 velocity 100
+@576 channel.NoteOn channel 0 key 60 velocity 100
+end @768
+`
+
+	if got, want := fileString(f), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if err := smf.Stretch(f, 0); err == nil {
+		t.Errorf("expected error for factor 0")
+	}
+}
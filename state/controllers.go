@@ -0,0 +1,251 @@
+package state
+
+import (
+	"sync"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+type channelState struct {
+	controllers [120]int16 // -1 if unset
+	program     int16
+	pitchbend   int16
+	hasBend     bool
+	aftertouch  int16
+
+	// rpn is true, if a registered parameter number (controllers 101 and 100) has been selected
+	// more recently than a non-registered one (controllers 99 and 98)
+	rpn           bool
+	bendSemitones uint8
+	bendCents     uint8
+}
+
+func (c *channelState) reset() {
+	for i := range c.controllers {
+		c.controllers[i] = -1
+	}
+	c.program = -1
+	c.hasBend = false
+	c.aftertouch = -1
+	c.rpn = false
+	c.bendSemitones = channel.DefaultBendRange
+	c.bendCents = 0
+}
+
+// resetControllers resets the controllers as recommended for Reset All Controllers (RP-015):
+// program, bank, volume, pan, effects and sound controllers are kept.
+func (c *channelState) resetControllers() {
+	for _, cc := range []int{1, 11, 64, 65, 66, 67, 68, 69, 98, 99, 100, 101} {
+		c.controllers[cc] = -1
+	}
+	c.hasBend = false
+	c.aftertouch = -1
+	c.rpn = false
+}
+
+// dataEntry updates the pitch bend range, if RPN 0 (pitch bend sensitivity) is selected
+func (c *channelState) dataEntry(controller uint8, value uint8) {
+	if !c.rpn || c.controllers[101] != 0 || c.controllers[100] != 0 {
+		return
+	}
+
+	switch controller {
+	case 6:
+		c.bendSemitones = value
+	case 38:
+		c.bendCents = value
+	}
+}
+
+// Controllers tracks the latest value of every controller, the program, the pitch bend and the
+// aftertouch (channel pressure) per channel, so that the state can be restored, e.g. when resuming playback
+// in the middle of a file or when a device is reconnected.
+// The channel mode messages (controllers 120-127) are not tracked, but Reset All Controllers (121)
+// resets the controllers as recommended in RP-015 and the realtime message Reset forgets everything.
+// Controllers is safe for concurrent use.
+type Controllers struct {
+	mx       sync.Mutex
+	channels [16]channelState
+}
+
+// NewControllers returns a new controller tracker
+func NewControllers() *Controllers {
+	c := &Controllers{}
+	c.Reset()
+	return c
+}
+
+// Reset forgets all values
+func (c *Controllers) Reset() {
+	c.mx.Lock()
+	for i := range c.channels {
+		c.channels[i].reset()
+	}
+	c.mx.Unlock()
+}
+
+// Track updates the state with the given message
+func (c *Controllers) Track(msg midi.Message) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	// channel mode messages are tracked like their control changes
+	if cm, is := msg.(channel.ChannelMode); is {
+		msg = cm.ControlChange()
+	}
+
+	switch v := msg.(type) {
+	case channel.ControlChange:
+		ch := &c.channels[v.Channel()&0x0F]
+		switch cc := v.Controller(); {
+		case cc < 120:
+			ch.controllers[cc] = int16(v.Value())
+			switch cc {
+			case 100, 101:
+				ch.rpn = true
+			case 98, 99:
+				ch.rpn = false
+			case 6, 38:
+				ch.dataEntry(cc, v.Value())
+			}
+		case cc == 121:
+			ch.resetControllers()
+		}
+	case channel.ProgramChange:
+		c.channels[v.Channel()&0x0F].program = int16(v.Program())
+	case channel.Pitchbend:
+		ch := &c.channels[v.Channel()&0x0F]
+		ch.pitchbend = v.Value()
+		ch.hasBend = true
+	case channel.Aftertouch:
+		c.channels[v.Channel()&0x0F].aftertouch = int16(v.Pressure())
+	case realtime.Message:
+		if v == realtime.Reset {
+			for i := range c.channels {
+				c.channels[i].reset()
+			}
+		}
+	}
+}
+
+// Transform tracks the message and passes it unchanged, so that the tracker can be used inside a pipe
+func (c *Controllers) Transform(msg midi.Message) []midi.Message {
+	c.Track(msg)
+	return []midi.Message{msg}
+}
+
+// Name returns the name of the transform
+func (c *Controllers) Name() string {
+	return "controllers"
+}
+
+// Write tracks the message, so that the tracker can be used as midi.Writer
+func (c *Controllers) Write(msg midi.Message) error {
+	c.Track(msg)
+	return nil
+}
+
+// Reader returns a reader that tracks each message that is read from rd
+func (c *Controllers) Reader(rd midi.Reader) midi.Reader {
+	return &trackingReader{rd: rd, track: c.Track}
+}
+
+// Controller returns the value of the given controller on the given channel and whether it has been set
+func (c *Controllers) Controller(ch, controller uint8) (value uint8, ok bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if controller >= 120 {
+		return 0, false
+	}
+
+	v := c.channels[ch&0x0F].controllers[controller]
+	return uint8(v), v >= 0
+}
+
+// Program returns the program of the given channel and whether it has been set
+func (c *Controllers) Program(ch uint8) (program uint8, ok bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	p := c.channels[ch&0x0F].program
+	return uint8(p), p >= 0
+}
+
+// Pitchbend returns the pitch bend value of the given channel and whether it has been set
+func (c *Controllers) Pitchbend(ch uint8) (value int16, ok bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	st := &c.channels[ch&0x0F]
+	return st.pitchbend, st.hasBend
+}
+
+// BendRange returns the pitch bend range (sensitivity) of the given channel in semitones, as set by
+// the data entry controllers (6 and 38) while RPN 0 is selected (see channel.Channel.PitchbendSensitivity).
+// It defaults to channel.DefaultBendRange. Together with Pitchbend.Semitones, it interprets incoming pitch bends.
+func (c *Controllers) BendRange(ch uint8) float64 {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	st := &c.channels[ch&0x0F]
+	return float64(st.bendSemitones) + float64(st.bendCents)/100
+}
+
+// Aftertouch returns the aftertouch (channel pressure) of the given channel and whether it has been set
+func (c *Controllers) Aftertouch(ch uint8) (pressure uint8, ok bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	a := c.channels[ch&0x0F].aftertouch
+	return uint8(a), a >= 0
+}
+
+// Snapshot returns the messages that restore the tracked state, channel by channel.
+// Per channel, the bank select controllers (0 and 32) come first, followed by the program change,
+// the other controllers in ascending order, the pitch bend and the aftertouch.
+func (c *Controllers) Snapshot() []midi.Message {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	var res []midi.Message
+
+	for i := range c.channels {
+		st := &c.channels[i]
+		ch := channel.Channel(i)
+
+		for _, cc := range []uint8{0, 32} {
+			if v := st.controllers[cc]; v >= 0 {
+				res = append(res, ch.ControlChange(cc, uint8(v)))
+			}
+		}
+
+		if st.program >= 0 {
+			res = append(res, ch.ProgramChange(uint8(st.program)))
+		}
+
+		for cc, v := range st.controllers {
+			if v >= 0 && cc != 0 && cc != 32 {
+				res = append(res, ch.ControlChange(uint8(cc), uint8(v)))
+			}
+		}
+
+		if st.hasBend {
+			res = append(res, ch.Pitchbend(st.pitchbend))
+		}
+
+		if st.aftertouch >= 0 {
+			res = append(res, ch.Aftertouch(uint8(st.aftertouch)))
+		}
+	}
+
+	return res
+}
+
+// Replay writes the snapshot to w
+func (c *Controllers) Replay(w midi.Writer) error {
+	for _, msg := range c.Snapshot() {
+		if err := w.Write(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
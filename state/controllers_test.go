@@ -0,0 +1,109 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package state
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midireader"
+	"github.com/gomidi/midi/midiwriter"
+)
+
+type logWriter struct {
+	bf *bytes.Buffer
+}
+
+func (l logWriter) Write(msg midi.Message) error {
+	l.bf.WriteString(msg.String() + "\n")
+	return nil
+}
+
+func TestControllers(t *testing.T) {
+	var in bytes.Buffer
+
+	wr := midiwriter.New(&in)
+	wr.Write(channel.Channel0.ControlChange(7, 100))
+	wr.Write(channel.Channel0.ProgramChange(5))
+	wr.Write(channel.Channel0.ControlChange(0, 1))
+	wr.Write(channel.Channel0.ControlChange(1, 64))
+	wr.Write(channel.Channel0.ControlChange(7, 90))
+	wr.Write(channel.Channel0.Pitchbend(100))
+	wr.Write(channel.Channel0.NoteOn(60, 100))
+	wr.Write(channel.Channel3.Aftertouch(30))
+	wr.Write(channel.Channel3.ControlChange(1, 20))
+	wr.Write(channel.Channel3.ControlChange(121, 0))
+	wr.Write(channel.Channel3.ControlChange(10, 64))
+
+	c := NewControllers()
+	rd := c.Reader(midireader.New(&in, nil))
+
+	for {
+		if _, err := rd.Read(); err != nil {
+			break
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	if err := c.Replay(logWriter{&out}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+channel.ControlChange channel 0 controller 0 ("Bank Select (MSB)") value 1
+channel.ProgramChange channel 0 program 5
+channel.ControlChange channel 0 controller 1 ("Modulation Wheel (MSB)") value 64
+channel.ControlChange channel 0 controller 7 ("Volume (MSB)") value 90
+channel.Pitchbend channel 0 value 100 absValue 0
+channel.ControlChange channel 3 controller 10 ("Pan position (MSB)") value 64
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if v, ok := c.Controller(0, 7); !ok || v != 90 {
+		t.Errorf("Controller(0, 7) = %v, %v; wanted 90, true", v, ok)
+	}
+
+	if _, ok := c.Aftertouch(3); ok {
+		t.Errorf("Aftertouch(3) should have been reset")
+	}
+}
+
+func TestBendRange(t *testing.T) {
+	c := NewControllers()
+
+	if got := c.BendRange(0); got != channel.DefaultBendRange {
+		t.Errorf("got: %v wanted: %v", got, channel.DefaultBendRange)
+	}
+
+	for _, msg := range channel.Channel0.PitchbendSensitivity(12, 50) {
+		c.Track(msg)
+	}
+
+	// data entry after the null RPN and for a NRPN is ignored
+	c.Track(channel.Channel0.ControlChange(6, 3))
+	c.Track(channel.Channel1.ControlChange(99, 0))
+	c.Track(channel.Channel1.ControlChange(98, 0))
+	c.Track(channel.Channel1.ControlChange(6, 3))
+
+	if got := c.BendRange(0); got != 12.5 {
+		t.Errorf("got: %v wanted: %v", got, 12.5)
+	}
+
+	if got := c.BendRange(1); got != channel.DefaultBendRange {
+		t.Errorf("got: %v wanted: %v", got, channel.DefaultBendRange)
+	}
+
+	bend := channel.Channel0.PitchbendSemitones(-6.25, c.BendRange(0))
+
+	if got := bend.Semitones(c.BendRange(0)); got != -6.25 {
+		t.Errorf("got: %v wanted: %v", got, -6.25)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package state provides components that track the state of a MIDI stream: the notes that are currently sounding (Notes)
+and the values of the controllers, programs, pitch bends and aftertouch per channel (Controllers).
+
+The trackers consume messages either as midi.Transform inside a pipe, as midi.Writer or by wrapping a midi.Reader.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi"
+		"github.com/gomidi/midi/state"
+	)
+
+	notes := state.NewNotes()
+
+	go midi.Pipe(src, dst, notes)
+
+	// later
+	for _, n := range notes.Hanging(10 * time.Second) {
+		fmt.Println("hanging: ", n)
+	}
+
+	// silence everything that is still sounding
+	for _, msg := range notes.NoteOffs() {
+		dst.Write(msg)
+	}
+
+*/
+package state
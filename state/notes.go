@@ -0,0 +1,289 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+// Note is a sounding note
+type Note struct {
+	Channel  uint8
+	Key      uint8
+	Velocity uint8
+
+	// Held is true, as long as the key has not been released
+	Held bool
+
+	// Sustained is true, if the key has been released while the sustain pedal is down
+	Sustained bool
+
+	// Since is the time, the note has been started
+	Since time.Time
+}
+
+// String represents the note as a string (for debugging)
+func (n Note) String() string {
+	s := fmt.Sprintf("channel %v key %v velocity %v", n.Channel, n.Key, n.Velocity)
+	if n.Sustained {
+		s += " (sustained)"
+	}
+	return s
+}
+
+type noteState struct {
+	velocity  uint8
+	held      bool
+	sustained bool
+	since     time.Time
+}
+
+func (n noteState) sounding() bool {
+	return n.held || n.sustained
+}
+
+// Option is an option for the trackers
+type Option func(*config)
+
+type config struct {
+	now func() time.Time
+}
+
+// Clock is an option that sets the function that returns the current time (default: time.Now).
+func Clock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{now: time.Now}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// Notes tracks the notes that are currently sounding per channel, taking the sustain pedal (controller 64) into account:
+// A note that is released while the pedal is down keeps sounding until the pedal is released.
+// The channel mode messages All Sound Off (120), Reset All Controllers (121, releases the pedal)
+// and All Notes Off (123) as well as the realtime message Reset are respected.
+// Notes is safe for concurrent use.
+type Notes struct {
+	config
+	mx      sync.Mutex
+	notes   [16][128]noteState
+	sustain [16]bool
+}
+
+// NewNotes returns a new note tracker
+func NewNotes(opts ...Option) *Notes {
+	return &Notes{config: newConfig(opts)}
+}
+
+// Track updates the state with the given message
+func (n *Notes) Track(msg midi.Message) {
+	n.mx.Lock()
+	defer n.mx.Unlock()
+
+	// channel mode messages are tracked like their control changes
+	if cm, is := msg.(channel.ChannelMode); is {
+		msg = cm.ControlChange()
+	}
+
+	switch v := msg.(type) {
+	case channel.NoteOn:
+		if v.Velocity() == 0 {
+			n.release(v.Channel(), v.Key())
+			return
+		}
+		n.notes[v.Channel()&0x0F][v.Key()&0x7F] = noteState{velocity: v.Velocity(), held: true, since: n.now()}
+	case channel.NoteOff:
+		n.release(v.Channel(), v.Key())
+	case channel.NoteOffVelocity:
+		n.release(v.Channel(), v.Key())
+	case channel.ControlChange:
+		ch := v.Channel() & 0x0F
+		switch v.Controller() {
+		case 64:
+			if v.Value() >= 64 {
+				n.sustain[ch] = true
+			} else {
+				n.releaseSustain(ch)
+			}
+		case 121:
+			n.releaseSustain(ch)
+		case 120, 123:
+			n.notes[ch] = [128]noteState{}
+		}
+	case realtime.Message:
+		if v == realtime.Reset {
+			n.notes = [16][128]noteState{}
+			n.sustain = [16]bool{}
+		}
+	}
+}
+
+func (n *Notes) release(ch, key uint8) {
+	ch, key = ch&0x0F, key&0x7F
+	if n.sustain[ch] && n.notes[ch][key].held {
+		n.notes[ch][key].held = false
+		n.notes[ch][key].sustained = true
+		return
+	}
+	n.notes[ch][key] = noteState{}
+}
+
+func (n *Notes) releaseSustain(ch uint8) {
+	n.sustain[ch] = false
+	for key := range n.notes[ch] {
+		if n.notes[ch][key].sustained {
+			n.notes[ch][key] = noteState{}
+		}
+	}
+}
+
+// Transform tracks the message and passes it unchanged, so that the tracker can be used inside a pipe
+func (n *Notes) Transform(msg midi.Message) []midi.Message {
+	n.Track(msg)
+	return []midi.Message{msg}
+}
+
+// Name returns the name of the transform
+func (n *Notes) Name() string {
+	return "notes"
+}
+
+// Write tracks the message, so that the tracker can be used as midi.Writer
+func (n *Notes) Write(msg midi.Message) error {
+	n.Track(msg)
+	return nil
+}
+
+// Reader returns a reader that tracks each message that is read from rd
+func (n *Notes) Reader(rd midi.Reader) midi.Reader {
+	return &trackingReader{rd: rd, track: n.Track}
+}
+
+type trackingReader struct {
+	rd    midi.Reader
+	track func(midi.Message)
+}
+
+// Read reads and tracks the next message
+func (t *trackingReader) Read() (midi.Message, error) {
+	msg, err := t.rd.Read()
+	if err == nil {
+		t.track(msg)
+	}
+	return msg, err
+}
+
+// IsSounding returns true, if the given key is sounding on the given channel
+func (n *Notes) IsSounding(ch, key uint8) bool {
+	n.mx.Lock()
+	defer n.mx.Unlock()
+	return n.notes[ch&0x0F][key&0x7F].sounding()
+}
+
+// Sustain returns true, if the sustain pedal is down on the given channel
+func (n *Notes) Sustain(ch uint8) bool {
+	n.mx.Lock()
+	defer n.mx.Unlock()
+	return n.sustain[ch&0x0F]
+}
+
+// Sounding returns the sounding notes of the given channel, ordered by key
+func (n *Notes) Sounding(ch uint8) []Note {
+	n.mx.Lock()
+	defer n.mx.Unlock()
+	return n.sounding(ch&0x0F, nil)
+}
+
+func (n *Notes) sounding(ch uint8, res []Note) []Note {
+	for key, st := range n.notes[ch] {
+		if st.sounding() {
+			res = append(res, Note{
+				Channel:   ch,
+				Key:       uint8(key),
+				Velocity:  st.velocity,
+				Held:      st.held,
+				Sustained: st.sustained,
+				Since:     st.since,
+			})
+		}
+	}
+	return res
+}
+
+// All returns the sounding notes of all channels, ordered by channel and key
+func (n *Notes) All() []Note {
+	n.mx.Lock()
+	defer n.mx.Unlock()
+
+	var res []Note
+	for ch := uint8(0); ch < 16; ch++ {
+		res = n.sounding(ch, res)
+	}
+	return res
+}
+
+// Count returns the number of sounding notes of all channels
+func (n *Notes) Count() int {
+	return len(n.All())
+}
+
+// Hanging returns the notes that are sounding for longer than the given duration, the oldest first.
+// With a duration of 0, all sounding notes are returned (e.g. to report hanging notes at the end of a stream).
+func (n *Notes) Hanging(d time.Duration) []Note {
+	now := n.now()
+
+	var res []Note
+	for _, note := range n.All() {
+		if now.Sub(note.Since) >= d {
+			res = append(res, note)
+		}
+	}
+
+	sort.SliceStable(res, func(a, b int) bool {
+		return res[a].Since.Before(res[b].Since)
+	})
+
+	return res
+}
+
+// NoteOffs returns note off messages for all sounding notes, ordered by channel and key.
+// Since sustained notes only stop when the sustain pedal is released, the note offs of
+// a channel with sustained notes are followed by a release of the sustain pedal.
+// NoteOffs does not change the state: the messages must be tracked to update it.
+func (n *Notes) NoteOffs() []midi.Message {
+	n.mx.Lock()
+	defer n.mx.Unlock()
+
+	var res []midi.Message
+	for ch := uint8(0); ch < 16; ch++ {
+		var sustained bool
+		for _, note := range n.sounding(ch, nil) {
+			res = append(res, channel.Channel(ch).NoteOff(note.Key))
+			sustained = sustained || note.Sustained
+		}
+
+		if sustained {
+			res = append(res, channel.Channel(ch).ControlChange(64, 0))
+		}
+	}
+	return res
+}
+
+// Reset forgets all notes and releases all sustain pedals
+func (n *Notes) Reset() {
+	n.mx.Lock()
+	n.notes = [16][128]noteState{}
+	n.sustain = [16]bool{}
+	n.mx.Unlock()
+}
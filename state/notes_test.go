@@ -0,0 +1,90 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+func fakeClock() func() time.Time {
+	t := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(time.Second)
+		return t
+	}
+}
+
+func TestNotes(t *testing.T) {
+	n := NewNotes(Clock(fakeClock()))
+
+	tests := []struct {
+		msg      midi.Message
+		expected string
+	}{
+		{channel.Channel0.NoteOn(60, 100), "[channel 0 key 60 velocity 100]"},
+		{channel.Channel0.NoteOn(64, 90), "[channel 0 key 60 velocity 100 channel 0 key 64 velocity 90]"},
+		{channel.Channel1.NoteOn(40, 80), "[channel 0 key 60 velocity 100 channel 0 key 64 velocity 90 channel 1 key 40 velocity 80]"},
+		{channel.Channel0.NoteOff(60), "[channel 0 key 64 velocity 90 channel 1 key 40 velocity 80]"},
+		{channel.Channel1.ControlChange(64, 127), "[channel 0 key 64 velocity 90 channel 1 key 40 velocity 80]"},
+		{channel.Channel1.NoteOff(40), "[channel 0 key 64 velocity 90 channel 1 key 40 velocity 80 (sustained)]"},
+		{channel.Channel1.NoteOn(41, 70), "[channel 0 key 64 velocity 90 channel 1 key 40 velocity 80 (sustained) channel 1 key 41 velocity 70]"},
+		{channel.Channel1.ControlChange(64, 0), "[channel 0 key 64 velocity 90 channel 1 key 41 velocity 70]"},
+		{channel.Channel0.ControlChange(123, 0), "[channel 1 key 41 velocity 70]"},
+		{channel.Channel1.AllNotesOff(), "[]"},
+		{realtime.Reset, "[]"},
+	}
+
+	for i, test := range tests {
+		n.Track(test.msg)
+
+		if got, want := fmt.Sprint(n.All()), test.expected; got != want {
+			t.Errorf("[%v] after %s got %s; wanted %s", i, test.msg, got, want)
+		}
+	}
+}
+
+func TestNotesHanging(t *testing.T) {
+	n := NewNotes(Clock(fakeClock()))
+
+	var out bytes.Buffer
+
+	n.Write(channel.Channel2.ControlChange(64, 100))
+	n.Write(channel.Channel2.NoteOn(50, 100))
+	n.Write(channel.Channel2.NoteOff(50))
+	n.Write(channel.Channel0.NoteOn(60, 100))
+	n.Write(channel.Channel0.NoteOn(62, 100))
+
+	// now is 5 seconds later than the first message
+	for _, note := range n.Hanging(2 * time.Second) {
+		fmt.Fprintf(&out, "%s\n", note)
+	}
+
+	for _, msg := range n.NoteOffs() {
+		fmt.Fprintf(&out, "%s\n", msg)
+		n.Track(msg)
+	}
+
+	expected := `channel 2 key 50 velocity 100 (sustained)
+channel 0 key 60 velocity 100
+channel.NoteOff channel 0 key 60
+channel.NoteOff channel 0 key 62
+channel.NoteOff channel 2 key 50
+channel.ControlChange channel 2 controller 64 ("Hold Pedal (on/off)") value 0
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if got, want := n.Count(), 0; got != want {
+		t.Errorf("Count() = %v; wanted %v", got, want)
+	}
+}
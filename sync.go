@@ -0,0 +1,51 @@
+package midi
+
+import (
+	"sync"
+)
+
+// SyncWriter serializes the concurrent writes of several goroutines to a writer.
+// Each message is written completely before the next one is written, so the bytes of messages never interleave.
+// The messages are written in the order in which the writing goroutines acquire the writer; the messages of a single
+// goroutine keep their order. Use WriteAll for groups of messages that must not be split (e.g. RPN sequences).
+type SyncWriter struct {
+	mx  sync.Mutex
+	dst Writer
+}
+
+// NewSyncWriter returns a SyncWriter that writes to dst.
+// If dst is a ProvenanceWriter, the provenance of messages written via WriteProvenance is passed to it.
+func NewSyncWriter(dst Writer) *SyncWriter {
+	return &SyncWriter{dst: dst}
+}
+
+var _ ProvenanceWriter = &SyncWriter{}
+
+// Write writes the message
+func (s *SyncWriter) Write(msg Message) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.dst.Write(msg)
+}
+
+// WriteProvenance writes the message with its provenance
+func (s *SyncWriter) WriteProvenance(msg Message, p Provenance) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return WriteProvenance(s.dst, msg, p)
+}
+
+// WriteAll writes the messages as a group without messages of other goroutines in between.
+// It stops at the first error.
+func (s *SyncWriter) WriteAll(msgs ...Message) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	for _, msg := range msgs {
+		if err := s.dst.Write(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
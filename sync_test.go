@@ -0,0 +1,70 @@
+package midi_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// byteWriter writes each byte separately, to provoke interleaving
+type byteWriter struct {
+	bf *bytes.Buffer
+}
+
+func (b byteWriter) Write(msg midi.Message) error {
+	for _, c := range msg.Raw() {
+		b.bf.WriteByte(c)
+	}
+	return nil
+}
+
+func TestSyncWriter(t *testing.T) {
+	var bf bytes.Buffer
+	wr := midi.NewSyncWriter(byteWriter{&bf})
+
+	var wg sync.WaitGroup
+
+	for ch := uint8(0); ch < 4; ch++ {
+		wg.Add(1)
+		go func(ch channel.Channel) {
+			defer wg.Done()
+			for i := uint8(0); i < 100; i++ {
+				wr.Write(ch.NoteOn(i, 100))
+			}
+			wr.WriteAll(ch.ControlChange(101, 0), ch.ControlChange(100, 0), ch.ControlChange(6, 2))
+		}(channel.Channel(ch))
+	}
+
+	wg.Wait()
+
+	raw := bf.Bytes()
+
+	if got, want := len(raw), 4*(100+3)*3; got != want {
+		t.Fatalf("len = %v; wanted %v", got, want)
+	}
+
+	var last [4]int
+
+	for i := 0; i < len(raw); i += 3 {
+		status := raw[i]
+		ch := int(status & 0x0F)
+
+		switch status & 0xF0 {
+		case 0x90:
+			if int(raw[i+1]) != last[ch] {
+				t.Fatalf("channel %v: got key %v; wanted %v", ch, raw[i+1], last[ch])
+			}
+			last[ch]++
+		case 0xB0:
+			if raw[i+1] != 101 || raw[i+4] != 100 || raw[i+7] != 6 {
+				t.Fatalf("RPN of channel %v has been split: % X", ch, raw[i:i+9])
+			}
+			i += 6
+		default:
+			t.Fatalf("unexpected byte % X at %v", status, i)
+		}
+	}
+}
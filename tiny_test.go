@@ -0,0 +1,37 @@
+package midi_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestTinyDeps checks that the live core does not depend on reflection and the fmt package in the tiny profile
+func TestTinyDeps(t *testing.T) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	core := []string{
+		".",
+		"./midireader",
+		"./midiwriter",
+		"./midimessage",
+		"./midimessage/channel",
+		"./midimessage/realtime",
+		"./midimessage/syscommon",
+		"./midimessage/sysex",
+	}
+
+	out, err := exec.Command(goTool, append([]string{"list", "-deps", "-tags", "miditiny"}, core...)...).Output()
+	if err != nil {
+		t.Fatalf("go list: %v", err)
+	}
+
+	for _, pkg := range strings.Fields(string(out)) {
+		if pkg == "reflect" || pkg == "fmt" {
+			t.Errorf("the tiny profile of the live core depends on %s", pkg)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package transform
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/clock"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/rng"
+	"github.com/gomidi/midi/state"
+)
+
+// ArpMode is the order in which an Arpeggiator plays the notes
+type ArpMode int
+
+const (
+	// ArpUp plays the notes from the lowest to the highest
+	ArpUp ArpMode = iota
+
+	// ArpDown plays the notes from the highest to the lowest
+	ArpDown
+
+	// ArpUpDown plays the notes up and down, without repeating the highest and the lowest note
+	ArpUpDown
+
+	// ArpRandom plays the notes in random order
+	ArpRandom
+)
+
+// Arpeggiator is a transform that plays the sounding notes (held or sustained) as arpeggio.
+// As transform (or writer) it consumes the note messages and tracks them in Notes; other messages are passed.
+// Run plays the arpeggio at the pulses of a clock, each note on the channel and with the velocity it was played with.
+// The fields must not be changed while running.
+type Arpeggiator struct {
+	// Mode is the order of the notes (default: ArpUp)
+	Mode ArpMode
+
+	// Octaves is the number of octaves the notes are repeated in (default: 1)
+	Octaves int
+
+	// Rate are the notes per quarter note (default: 4, i.e. 16th notes). It must be a divisor of 24, otherwise 4 is used.
+	Rate int
+
+	// Gate is the length of the notes as fraction of the distance between two notes (default: 0.5)
+	Gate float64
+
+	// Notes is the tracker of the sounding notes (default: a new tracker). It may be shared with other components.
+	Notes *state.Notes
+
+	// Source is the source of the random numbers for ArpRandom (default: rng.NewTime())
+	Source rng.Source
+
+	mx sync.Mutex
+}
+
+// Transform tracks note messages and swallows them; other messages are passed
+func (a *Arpeggiator) Transform(msg midi.Message) []midi.Message {
+	if _, _, is := noteOf(msg); !is {
+		return []midi.Message{msg}
+	}
+
+	a.notes().Track(msg)
+	return nil
+}
+
+// Name returns the name of the transform
+func (a *Arpeggiator) Name() string {
+	return "arpeggiator"
+}
+
+// Write tracks note messages. It allows to use the arpeggiator as midi.Writer (e.g. as output of a router).
+func (a *Arpeggiator) Write(msg midi.Message) error {
+	a.Transform(msg)
+	return nil
+}
+
+func (a *Arpeggiator) notes() *state.Notes {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	if a.Notes == nil {
+		a.Notes = state.NewNotes()
+	}
+	return a.Notes
+}
+
+// sequence returns the notes of the arpeggio in the order of the mode (for ArpRandom ordered by key)
+func (a *Arpeggiator) sequence() (res []state.Note) {
+	octaves := a.Octaves
+	if octaves < 1 {
+		octaves = 1
+	}
+
+	notes := a.notes().All()
+
+	for o := 0; o < octaves; o++ {
+		for _, n := range notes {
+			if key := int(n.Key) + 12*o; key < 128 {
+				n.Key = uint8(key)
+				res = append(res, n)
+			}
+		}
+	}
+
+	// ordered by key over all channels
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Key < res[j].Key
+	})
+
+	switch a.Mode {
+	case ArpDown:
+		for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+			res[i], res[j] = res[j], res[i]
+		}
+	case ArpUpDown:
+		for i := len(res) - 2; i > 0; i-- {
+			res = append(res, res[i])
+		}
+	}
+
+	return
+}
+
+// Run plays the arpeggio of the sounding notes to w at the pulses of clk, until ctx is done.
+// When no notes are sounding, the arpeggio starts again with its first note.
+// It returns ctx.Err() or the error of a failed write.
+func (a *Arpeggiator) Run(ctx context.Context, clk clock.Clock, w midi.Writer) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rate := a.Rate
+	if rate <= 0 || clock.PPQN%rate != 0 {
+		rate = 4
+	}
+
+	gate := a.Gate
+	if gate <= 0 {
+		gate = 0.5
+	}
+
+	src := a.Source
+	if src == nil && a.Mode == ArpRandom {
+		src = rng.NewTime()
+	}
+
+	var (
+		perStep    = clock.PPQN / rate
+		gatePulses = int(gate * float64(perStep))
+		pulses     int
+		step       int
+		off        midi.Message
+	)
+
+	if gatePulses < 1 {
+		gatePulses = 1
+	}
+
+	write := func(msg midi.Message) {
+		if err == nil {
+			err = w.Write(msg)
+		}
+	}
+
+	clkErr := clk.Run(ctx, func() {
+		if err != nil {
+			return
+		}
+
+		defer func() { pulses++ }()
+
+		rel := pulses % perStep
+
+		// a gate of 1 or more plays legato: the note ends when the next one starts
+		if off != nil && (rel == gatePulses || rel == 0) {
+			write(off)
+			off = nil
+		}
+
+		if rel == 0 {
+			seq := a.sequence()
+
+			if len(seq) == 0 {
+				step = 0
+				return
+			}
+
+			i := step % len(seq)
+			if a.Mode == ArpRandom {
+				i = src.Intn(len(seq))
+			}
+
+			n := seq[i]
+			ch := channel.Channel(n.Channel)
+			write(ch.NoteOn(n.Key, n.Velocity))
+			off = ch.NoteOff(n.Key)
+			step++
+		}
+
+		if err != nil {
+			cancel()
+		}
+	})
+
+	if off != nil {
+		w.Write(off)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return clkErr
+}
@@ -0,0 +1,148 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/rng"
+)
+
+// pulses is a clock that gives the given number of pulses
+type pulses struct {
+	n, current int
+}
+
+func (p *pulses) Run(ctx context.Context, pulse func()) error {
+	for p.current = 0; p.current < p.n; p.current++ {
+		pulse()
+	}
+	return ctx.Err()
+}
+
+type pulseWriter struct {
+	bf  *bytes.Buffer
+	clk *pulses
+}
+
+func (p pulseWriter) Write(msg midi.Message) error {
+	fmt.Fprintf(p.bf, "%v %s\n", p.clk.current, msg)
+	return nil
+}
+
+func TestArpeggiator(t *testing.T) {
+	ch := channel.Channel0
+
+	tests := []struct {
+		arp      *Arpeggiator
+		expected string
+	}{
+		{&Arpeggiator{Mode: ArpUp, Octaves: 2}, `
+0 channel.NoteOn channel 0 key 60 velocity 100
+3 channel.NoteOff channel 0 key 60
+6 channel.NoteOn channel 0 key 64 velocity 90
+9 channel.NoteOff channel 0 key 64
+12 channel.NoteOn channel 0 key 72 velocity 100
+15 channel.NoteOff channel 0 key 72
+18 channel.NoteOn channel 0 key 76 velocity 90
+21 channel.NoteOff channel 0 key 76
+24 channel.NoteOn channel 0 key 60 velocity 100
+27 channel.NoteOff channel 0 key 60
+`},
+		{&Arpeggiator{Mode: ArpDown, Rate: 2, Gate: 1}, `
+0 channel.NoteOn channel 0 key 64 velocity 90
+12 channel.NoteOff channel 0 key 64
+12 channel.NoteOn channel 0 key 60 velocity 100
+24 channel.NoteOff channel 0 key 60
+24 channel.NoteOn channel 0 key 64 velocity 90
+30 channel.NoteOff channel 0 key 64
+`},
+		{&Arpeggiator{Mode: ArpUpDown, Octaves: 2, Rate: 8, Gate: 0.25}, `
+0 channel.NoteOn channel 0 key 60 velocity 100
+1 channel.NoteOff channel 0 key 60
+3 channel.NoteOn channel 0 key 64 velocity 90
+4 channel.NoteOff channel 0 key 64
+6 channel.NoteOn channel 0 key 72 velocity 100
+7 channel.NoteOff channel 0 key 72
+9 channel.NoteOn channel 0 key 76 velocity 90
+10 channel.NoteOff channel 0 key 76
+12 channel.NoteOn channel 0 key 72 velocity 100
+13 channel.NoteOff channel 0 key 72
+15 channel.NoteOn channel 0 key 64 velocity 90
+16 channel.NoteOff channel 0 key 64
+18 channel.NoteOn channel 0 key 60 velocity 100
+19 channel.NoteOff channel 0 key 60
+21 channel.NoteOn channel 0 key 64 velocity 90
+22 channel.NoteOff channel 0 key 64
+24 channel.NoteOn channel 0 key 72 velocity 100
+25 channel.NoteOff channel 0 key 72
+27 channel.NoteOn channel 0 key 76 velocity 90
+28 channel.NoteOff channel 0 key 76
+`},
+	}
+
+	for i, test := range tests {
+		// the note messages are consumed, the others are passed
+		if got := test.arp.Transform(ch.NoteOn(64, 90)); len(got) != 0 {
+			t.Errorf("[%v] note on passed: %v", i, got)
+		}
+		test.arp.Transform(ch.NoteOn(60, 100))
+
+		if got := test.arp.Transform(ch.ControlChange(7, 80)); len(got) != 1 {
+			t.Errorf("[%v] control change not passed", i)
+		}
+
+		clk := &pulses{n: 30}
+
+		var out bytes.Buffer
+		out.WriteString("\n")
+
+		if err := test.arp.Run(context.Background(), clk, pulseWriter{&out, clk}); err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := out.String(), test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
+
+func TestArpeggiatorRandom(t *testing.T) {
+	ch := channel.Channel0
+	arp := &Arpeggiator{Mode: ArpRandom, Octaves: 3, Source: rng.New(42)}
+
+	for _, key := range []uint8{60, 64, 67} {
+		arp.Write(ch.NoteOn(key, 100))
+	}
+
+	clk := &pulses{n: 24 * 4}
+	var out bytes.Buffer
+	arp.Run(context.Background(), clk, pulseWriter{&out, clk})
+
+	keys := map[string]bool{}
+	for _, line := range bytes.Split(out.Bytes(), []byte("\n")) {
+		if bytes.Contains(line, []byte("NoteOn")) {
+			keys[string(bytes.Fields(line)[5])] = true
+		}
+	}
+
+	if len(keys) < 5 {
+		t.Errorf("got %v different keys of 9 in 16 random notes; wanted at least 5", len(keys))
+	}
+
+	// no notes: nothing is played
+	arp.Write(ch.NoteOff(60))
+	arp.Write(ch.NoteOff(64))
+	arp.Write(ch.NoteOff(67))
+
+	out.Reset()
+	clk = &pulses{n: 24}
+	arp.Run(context.Background(), clk, pulseWriter{&out, clk})
+
+	if out.Len() != 0 {
+		t.Errorf("got:\n%s\n\nwanted nothing", out.String())
+	}
+}
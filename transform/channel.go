@@ -0,0 +1,50 @@
+package transform
+
+import (
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// ChannelMap is a transform that maps input channels (keys) to output channels (values).
+// Channels are counted from 0 to 15.
+// Channel messages of channels that are not part of the map and all other messages are passed unchanged.
+type ChannelMap map[uint8]uint8
+
+// Transform remaps the channel of the given message
+func (m ChannelMap) Transform(msg midi.Message) []midi.Message {
+	if cm, is := msg.(channel.Message); is {
+		if to, has := m[cm.Channel()]; has {
+			return []midi.Message{channel.SetChannel(cm, to)}
+		}
+	}
+	return []midi.Message{msg}
+}
+
+// Name returns the name of the transform
+func (m ChannelMap) Name() string {
+	return "channelmap"
+}
+
+// ForceChannel returns a transform that puts all channel messages onto the given channel (0-15).
+// All other messages are passed unchanged.
+func ForceChannel(ch uint8) midi.Transform {
+	if ch > 15 {
+		panic("invalid channel number")
+	}
+	return forceChannel(ch)
+}
+
+type forceChannel uint8
+
+// Transform sets the channel of the given message
+func (f forceChannel) Transform(msg midi.Message) []midi.Message {
+	if cm, is := msg.(channel.Message); is && cm.Channel() != uint8(f) {
+		return []midi.Message{channel.SetChannel(cm, uint8(f))}
+	}
+	return []midi.Message{msg}
+}
+
+// Name returns the name of the transform
+func (f forceChannel) Name() string {
+	return "forcechannel"
+}
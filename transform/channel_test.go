@@ -0,0 +1,64 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package transform
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+func apply(t midi.Transform, msgs ...midi.Message) string {
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+	for _, msg := range msgs {
+		for _, m := range t.Transform(msg) {
+			bf.WriteString(m.String() + "\n")
+		}
+	}
+	return bf.String()
+}
+
+func TestChannelMap(t *testing.T) {
+	got := apply(ChannelMap{1: 9, 9: 1},
+		channel.Channel1.NoteOn(60, 100),
+		channel.Channel9.ProgramChange(3),
+		channel.Channel2.Aftertouch(20),
+		realtime.TimingClock,
+	)
+
+	expected := `
+channel.NoteOn channel 9 key 60 velocity 100
+channel.ProgramChange channel 1 program 3
+channel.Aftertouch channel 2 pressure 20
+TimingClock
+`
+
+	if want := expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestForceChannel(t *testing.T) {
+	got := apply(ForceChannel(4),
+		channel.Channel1.NoteOn(60, 100),
+		channel.Channel4.NoteOff(60),
+		channel.Channel15.ControlChange(7, 80),
+		realtime.Start,
+	)
+
+	expected := `
+channel.NoteOn channel 4 key 60 velocity 100
+channel.NoteOff channel 4 key 60
+channel.ControlChange channel 4 controller 7 ("Volume (MSB)") value 80
+Start
+`
+
+	if want := expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package transform provides ready-made transforms for midi.Pipe and midi.TransformWriter.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi"
+		"github.com/gomidi/midi/transform"
+	)
+
+	// move everything from channel 2 to channel 10 and everything else to channel 1
+	err := midi.Pipe(src, dst, transform.ChannelMap{1: 9}, transform.ForceChannel(0))
+
+	// de-mechanize the notes of a SMF track reproducibly
+	h := &transform.Humanize{Velocity: 8, Ticks: 10, Source: rng.New(42)}
+	h.Track(file.Tracks[1])
+
+	// arpeggiate the held notes of the keyboard in 16th notes over two octaves, following the MIDI clock of a drum machine
+	arp := &transform.Arpeggiator{Mode: transform.ArpUpDown, Octaves: 2}
+	ext := clock.NewExternal()
+	out := midi.NewSyncWriter(synth)
+	go midi.Pipe(midireader.New(keyboard, ext.Receive), out, arp)
+	err = arp.Run(ctx, ext, out)
+
+*/
+package transform
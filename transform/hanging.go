@@ -0,0 +1,131 @@
+package transform
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/state"
+)
+
+// HangingNotes is a transform that guarantees that every note is released.
+// It tracks the sounding notes and synthesizes the missing note offs
+//   - before passing the realtime messages Stop and Reset,
+//   - when the stream ends (it is a midi.Flusher),
+//   - for notes that sound longer than the timeout (if set).
+//
+// Sustained notes are released by additionally releasing the sustain pedal of their channel.
+type HangingNotes struct {
+	mx      sync.Mutex
+	notes   *state.Notes
+	timeout time.Duration
+}
+
+// NewHangingNotes returns a new hanging notes fixer. If timeout is > 0, notes that sound longer
+// are released when the next message passes (or periodically via Watch).
+// The options are passed to the underlying state.Notes tracker.
+func NewHangingNotes(timeout time.Duration, opts ...state.Option) *HangingNotes {
+	return &HangingNotes{
+		notes:   state.NewNotes(opts...),
+		timeout: timeout,
+	}
+}
+
+// Transform tracks the message and prepends the synthesized note offs
+func (h *HangingNotes) Transform(msg midi.Message) []midi.Message {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	var res []midi.Message
+
+	if msg == realtime.Stop || msg == realtime.Reset {
+		res = h.release(h.notes.NoteOffs())
+	} else {
+		res = h.expired()
+	}
+
+	h.notes.Track(msg)
+	return append(res, msg)
+}
+
+// Flush returns the note offs for all notes that are still sounding
+func (h *HangingNotes) Flush() []midi.Message {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	return h.release(h.notes.NoteOffs())
+}
+
+// Name returns the name of the transform
+func (h *HangingNotes) Name() string {
+	return "hangingnotes"
+}
+
+// Watch starts a goroutine that checks for notes that sound longer than the timeout every quarter of the timeout
+// and writes their note offs to w, even when no message passes. Since w is written to
+// concurrently with the pipe, it must be safe for concurrent use.
+// The returned function stops the goroutine. Watch does nothing if no timeout is set.
+func (h *HangingNotes) Watch(w midi.Writer) (stop func()) {
+	if h.timeout <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(h.timeout / 4)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				h.mx.Lock()
+				msgs := h.expired()
+				h.mx.Unlock()
+
+				for _, msg := range msgs {
+					w.Write(msg)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// expired returns the note offs for the notes that sound longer than the timeout
+func (h *HangingNotes) expired() []midi.Message {
+	if h.timeout <= 0 {
+		return nil
+	}
+
+	var res []midi.Message
+	var sustained [16]bool
+
+	for _, n := range h.notes.Hanging(h.timeout) {
+		res = append(res, channel.Channel(n.Channel).NoteOff(n.Key))
+		sustained[n.Channel] = sustained[n.Channel] || n.Sustained
+	}
+
+	for ch, sus := range sustained {
+		if sus {
+			res = append(res, channel.Channel(ch).ControlChange(64, 0))
+		}
+	}
+
+	return h.release(res)
+}
+
+// release tracks the given messages and returns them
+func (h *HangingNotes) release(msgs []midi.Message) []midi.Message {
+	for _, msg := range msgs {
+		h.notes.Track(msg)
+	}
+	return msgs
+}
@@ -0,0 +1,90 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package transform
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/state"
+)
+
+type sliceReader struct {
+	msgs []midi.Message
+}
+
+func (s *sliceReader) Read() (midi.Message, error) {
+	if len(s.msgs) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return msg, nil
+}
+
+type logWriter struct {
+	bf *bytes.Buffer
+}
+
+func (l logWriter) Write(msg midi.Message) error {
+	l.bf.WriteString(msg.String() + "\n")
+	return nil
+}
+
+func TestHangingNotes(t *testing.T) {
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	src := &sliceReader{[]midi.Message{
+		channel.Channel0.NoteOn(60, 100),
+		channel.Channel0.NoteOn(62, 100),
+		realtime.Stop,
+		channel.Channel1.ControlChange(64, 127),
+		channel.Channel1.NoteOn(40, 100),
+		channel.Channel1.NoteOff(40),
+		channel.Channel0.NoteOn(64, 100),
+		// the sustained note 40 is hanging for 3 seconds
+		channel.Channel0.ProgramChange(1),
+		channel.Channel0.NoteOn(65, 100),
+	}}
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+
+	err := midi.Pipe(src, logWriter{&out}, NewHangingNotes(3*time.Second, state.Clock(clock)))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+channel.NoteOn channel 0 key 60 velocity 100
+channel.NoteOn channel 0 key 62 velocity 100
+channel.NoteOff channel 0 key 60
+channel.NoteOff channel 0 key 62
+Stop
+channel.ControlChange channel 1 controller 64 ("Hold Pedal (on/off)") value 127
+channel.NoteOn channel 1 key 40 velocity 100
+channel.NoteOff channel 1 key 40
+channel.NoteOn channel 0 key 64 velocity 100
+channel.NoteOff channel 1 key 40
+channel.ControlChange channel 1 controller 64 ("Hold Pedal (on/off)") value 0
+channel.ProgramChange channel 0 program 1
+channel.NoteOn channel 0 key 65 velocity 100
+channel.NoteOff channel 0 key 64
+channel.NoteOff channel 0 key 65
+`
+
+	if got, want := out.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
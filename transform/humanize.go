@@ -0,0 +1,143 @@
+package transform
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/rng"
+	"github.com/gomidi/midi/smf"
+)
+
+// Humanize is a transform that de-mechanizes generated notes by random offsets of their velocities and times.
+// The velocity of note on messages is changed by up to ±Velocity (clamped to 1-127).
+//
+// In a live pipeline (Transform) note messages can't be played earlier, so they are delayed by up to Delay
+// by sleeping before they are passed; the order of the messages is kept.
+// On SMF tracks (Track) the times of note messages are moved by up to ±Ticks, while notes never end before they start.
+// All other messages are passed unchanged.
+//
+// Pass a Source with a known seed to get reproducible results.
+type Humanize struct {
+	// Velocity is the maximum offset of the velocity
+	Velocity int
+
+	// Delay is the maximum delay of note messages in live pipelines
+	Delay time.Duration
+
+	// Ticks is the maximum offset of the times of note messages in SMF tracks
+	Ticks uint32
+
+	// Source is the source of the random numbers (default: rng.NewTime())
+	Source rng.Source
+
+	// Sleep waits for the delay (default: time.Sleep)
+	Sleep func(time.Duration)
+}
+
+// Transform changes the velocity of note on messages and delays note messages
+func (h *Humanize) Transform(msg midi.Message) []midi.Message {
+	if _, _, is := noteOf(msg); !is {
+		return []midi.Message{msg}
+	}
+
+	if h.Delay > 0 {
+		if d := time.Duration(h.source().Float64() * float64(h.Delay+1)); d > 0 {
+			h.sleep(d)
+		}
+	}
+
+	return []midi.Message{h.velocity(msg)}
+}
+
+// Track changes the velocities and times of the note messages of the track
+func (h *Humanize) Track(t *smf.Track) {
+	type note struct {
+		orig, abs uint64
+	}
+
+	open := map[[2]uint8][]note{}
+
+	for i := range t.Events {
+		ev := &t.Events[i]
+
+		k, on, is := noteOf(ev.Message)
+		if !is {
+			continue
+		}
+
+		ev.Message = h.velocity(ev.Message)
+		orig := ev.AbsTicks
+
+		if abs := int64(orig) + int64(rng.Range(h.source(), int(h.Ticks))); abs > 0 {
+			ev.AbsTicks = uint64(abs)
+		} else {
+			ev.AbsTicks = 0
+		}
+
+		if on {
+			open[k] = append(open[k], note{orig: orig, abs: ev.AbsTicks})
+			continue
+		}
+
+		if notes := open[k]; len(notes) > 0 {
+			open[k] = notes[1:]
+
+			// keep the original length, if the note would end before it starts
+			if ev.AbsTicks <= notes[0].abs {
+				ev.AbsTicks = notes[0].abs + (orig - notes[0].orig)
+			}
+		}
+	}
+
+	sort.SliceStable(t.Events, func(a, b int) bool {
+		return t.Events[a].AbsTicks < t.Events[b].AbsTicks
+	})
+}
+
+// Name returns the name of the transform
+func (h *Humanize) Name() string {
+	return "humanize"
+}
+
+// velocity returns the message with the changed velocity, if it is a note on message
+func (h *Humanize) velocity(msg midi.Message) midi.Message {
+	on, is := msg.(channel.NoteOn)
+
+	if !is || h.Velocity <= 0 {
+		return msg
+	}
+
+	v := float64(int(on.Velocity()) + rng.Range(h.source(), h.Velocity))
+	return channel.Channel(on.Channel()).NoteOn(on.Key(), clampVelocity(v))
+}
+
+func (h *Humanize) source() rng.Source {
+	if h.Source == nil {
+		h.Source = rng.NewTime()
+	}
+	return h.Source
+}
+
+func (h *Humanize) sleep(d time.Duration) {
+	if h.Sleep != nil {
+		h.Sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+// noteOf returns the channel and key of a note on or note off message and whether it is a note on
+func noteOf(msg midi.Message) (k [2]uint8, on bool, is bool) {
+	switch m := msg.(type) {
+	case channel.NoteOn:
+		return [2]uint8{m.Channel(), m.Key()}, true, true
+	case channel.NoteOff:
+		return [2]uint8{m.Channel(), m.Key()}, false, true
+	case channel.NoteOffVelocity:
+		return [2]uint8{m.Channel(), m.Key()}, false, true
+	default:
+		return k, false, false
+	}
+}
@@ -0,0 +1,99 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/rng"
+	"github.com/gomidi/midi/smf"
+)
+
+func TestHumanize(t *testing.T) {
+	src := rng.New(42)
+
+	var delays []time.Duration
+
+	h := &Humanize{
+		Velocity: 10,
+		Delay:    20 * time.Millisecond,
+		Source:   src,
+		Sleep:    func(d time.Duration) { delays = append(delays, d) },
+	}
+
+	ch := channel.Channel1
+	in := []midi.Message{ch.NoteOn(60, 100), ch.ControlChange(7, 80), ch.NoteOn(61, 125), ch.NoteOff(60)}
+
+	run := func() (out []midi.Message) {
+		for _, msg := range in {
+			out = append(out, h.Transform(msg)...)
+		}
+		return
+	}
+
+	first := run()
+
+	for i, msg := range first {
+		switch m := msg.(type) {
+		case channel.NoteOn:
+			orig := in[i].(channel.NoteOn).Velocity()
+			if d := int(m.Velocity()) - int(orig); d < -10 || d > 10 || m.Velocity() > 127 {
+				t.Errorf("[%v] velocity %v out of bounds for %v", i, m.Velocity(), orig)
+			}
+		default:
+			if msg != in[i] {
+				t.Errorf("[%v] got %v; wanted %v", i, msg, in[i])
+			}
+		}
+	}
+
+	for i, d := range delays {
+		if d < 0 || d > 20*time.Millisecond {
+			t.Errorf("[%v] delay %v out of bounds", i, d)
+		}
+	}
+
+	// the same seed gives the same result
+	src.Reset()
+
+	if second := run(); !reflect.DeepEqual(first, second) {
+		t.Errorf("not reproducible: %v != %v", first, second)
+	}
+}
+
+func TestHumanizeTrack(t *testing.T) {
+	h := &Humanize{Ticks: 20, Source: rng.New(7)}
+
+	for i := 0; i < 20; i++ {
+		tr := &smf.Track{}
+		tr.Insert(10, channel.Channel0.NoteOn(60, 100))
+		tr.Insert(12, channel.Channel0.NoteOff(60))
+		tr.Insert(11, channel.Channel0.ControlChange(7, 80))
+
+		h.Track(tr)
+
+		var on, off uint64
+
+		for _, ev := range tr.Events {
+			switch m := ev.Message.(type) {
+			case channel.NoteOn:
+				on = ev.AbsTicks
+				if m.Velocity() != 100 {
+					t.Errorf("[%v] velocity changed to %v", i, m.Velocity())
+				}
+			case channel.NoteOff:
+				off = ev.AbsTicks
+			case channel.ControlChange:
+				if ev.AbsTicks != 11 {
+					t.Errorf("[%v] control change moved to %v", i, ev.AbsTicks)
+				}
+			}
+		}
+
+		if on > 30 || off > 32+20 || off <= on {
+			t.Errorf("[%v] note on @%v, note off @%v", i, on, off)
+		}
+	}
+}
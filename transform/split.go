@@ -0,0 +1,170 @@
+package transform
+
+import (
+	"bytes"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// Zone is a key range of a keyboard split
+type Zone struct {
+	// Low is the lowest key of the zone
+	Low uint8
+
+	// High is the highest key of the zone
+	High uint8
+
+	// Channel is the output channel (0-15) of the zone, -1 keeps the channel of the input
+	Channel int
+
+	// Transpose is the number of semitones the notes of the zone are shifted by
+	Transpose int
+}
+
+// Contains returns true if the given key belongs to the zone
+func (z Zone) Contains(key uint8) bool {
+	return key >= z.Low && key <= z.High
+}
+
+// check panics if the channel of the zone is neither -1 nor 0-15
+func (z Zone) check() {
+	if z.Channel < -1 || z.Channel > 15 {
+		panic("invalid channel number")
+	}
+}
+
+// apply returns the message as it should be sent for the zone or nil, if the message does not belong to the zone.
+// It panics if the channel of the zone is invalid.
+func (z Zone) apply(msg channel.Message) midi.Message {
+	z.check()
+
+	var key uint8
+	switch v := msg.(type) {
+	case channel.NoteOn:
+		key = v.Key()
+	case channel.NoteOff:
+		key = v.Key()
+	case channel.NoteOffVelocity:
+		key = v.Key()
+	case channel.PolyAftertouch:
+		key = v.Key()
+	default:
+		if z.Channel >= 0 {
+			return channel.SetChannel(msg, uint8(z.Channel))
+		}
+		return msg
+	}
+
+	if !z.Contains(key) {
+		return nil
+	}
+
+	m := Transpose{Semitones: z.Transpose, PolyAftertouch: true}.Transform(msg)[0]
+
+	if z.Channel >= 0 {
+		return channel.SetChannel(m.(channel.Message), uint8(z.Channel))
+	}
+	return m
+}
+
+// Split is a transform that routes notes (and polyphonic aftertouch) by their keys to the zones that contain them,
+// using the channel and transposition of the zone. Notes outside of all zones are dropped,
+// while notes inside overlapping zones are sent to each of them (layering).
+// The other channel messages (e.g. the sustain pedal) are sent to the channels of all zones.
+// Non channel messages are passed unchanged.
+type Split []Zone
+
+// NewSplit returns a split with two zones: keys below the split point go to the channel lower,
+// the other keys to the channel upper. Both zones have no transposition.
+// If point is 0, there is no lower zone and the split consists of the upper zone only.
+// It panics if a channel is neither -1 nor 0-15.
+func NewSplit(point uint8, lower, upper int) Split {
+	s := Split{{Low: point, High: 127, Channel: upper}}
+
+	if point > 0 {
+		s = Split{{Low: 0, High: point - 1, Channel: lower}, s[0]}
+	}
+
+	s.check()
+	return s
+}
+
+// check panics if the channel of a zone is neither -1 nor 0-15
+func (s Split) check() {
+	for _, z := range s {
+		z.check()
+	}
+}
+
+// Transform routes the given message to the zones.
+// It panics for channel messages if the channel of a zone is neither -1 nor 0-15.
+func (s Split) Transform(msg midi.Message) []midi.Message {
+	cm, is := msg.(channel.Message)
+	if !is {
+		return []midi.Message{msg}
+	}
+
+	var res []midi.Message
+
+outer:
+	for _, z := range s {
+		m := z.apply(cm)
+		if m == nil {
+			continue
+		}
+
+		// don't send the same message twice, if zones share a channel
+		for _, r := range res {
+			if bytes.Equal(r.Raw(), m.Raw()) {
+				continue outer
+			}
+		}
+
+		res = append(res, m)
+	}
+
+	return res
+}
+
+// Name returns the name of the transform
+func (s Split) Name() string {
+	return "split"
+}
+
+// Writer returns a writer that routes the messages of the zone with index i to dsts[i].
+// Non channel messages are written to all writers.
+// It panics if the number of writers differs from the number of zones or if the channel of a zone is invalid.
+func (s Split) Writer(dsts ...midi.Writer) midi.Writer {
+	if len(dsts) != len(s) {
+		panic("number of writers must match number of zones")
+	}
+	s.check()
+	return &splitWriter{split: s, dsts: dsts}
+}
+
+type splitWriter struct {
+	split Split
+	dsts  []midi.Writer
+}
+
+// Write writes the message to the writers of the zones it belongs to
+func (w *splitWriter) Write(msg midi.Message) error {
+	cm, is := msg.(channel.Message)
+
+	for i, z := range w.split {
+		m := msg
+		if is {
+			m = z.apply(cm)
+			if m == nil {
+				continue
+			}
+		}
+
+		if err := w.dsts[i].Write(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
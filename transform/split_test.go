@@ -0,0 +1,114 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package transform
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+)
+
+func TestSplit(t *testing.T) {
+	split := Split{
+		{Low: 0, High: 59, Channel: 1, Transpose: 12},
+		{Low: 60, High: 127, Channel: 2},
+		// layer
+		{Low: 72, High: 127, Channel: 3, Transpose: -12},
+	}
+
+	got := apply(split,
+		channel.Channel0.NoteOn(48, 100),
+		channel.Channel0.NoteOn(72, 90),
+		channel.Channel0.ControlChange(64, 127),
+		channel.Channel0.NoteOff(48),
+		realtime.Start,
+	)
+
+	expected := `
+channel.NoteOn channel 1 key 60 velocity 100
+channel.NoteOn channel 2 key 72 velocity 90
+channel.NoteOn channel 3 key 60 velocity 90
+channel.ControlChange channel 1 controller 64 ("Hold Pedal (on/off)") value 127
+channel.ControlChange channel 2 controller 64 ("Hold Pedal (on/off)") value 127
+channel.ControlChange channel 3 controller 64 ("Hold Pedal (on/off)") value 127
+channel.NoteOff channel 1 key 60
+Start
+`
+
+	if want := expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestSplitWriter(t *testing.T) {
+	var lower, upper bytes.Buffer
+
+	writeTo := func(bf *bytes.Buffer) midi.Writer {
+		return midi.ProvenanceFunc(func(msg midi.Message, _ midi.Provenance) error {
+			bf.WriteString(msg.String() + "\n")
+			return nil
+		})
+	}
+
+	wr := NewSplit(60, -1, -1).Writer(writeTo(&lower), writeTo(&upper))
+
+	wr.Write(channel.Channel5.NoteOn(59, 100))
+	wr.Write(channel.Channel5.NoteOn(60, 100))
+	wr.Write(channel.Channel5.Pitchbend(100))
+
+	expected := `channel.NoteOn channel 5 key 59 velocity 100
+channel.Pitchbend channel 5 value 100 absValue 0
+`
+	if got, want := lower.String(), expected; got != want {
+		t.Errorf("lower got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	expected = `channel.NoteOn channel 5 key 60 velocity 100
+channel.Pitchbend channel 5 value 100 absValue 0
+`
+	if got, want := upper.String(), expected; got != want {
+		t.Errorf("upper got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+func TestNewSplit(t *testing.T) {
+	// no lower zone
+	got := apply(NewSplit(0, 1, 2),
+		channel.Channel0.NoteOn(0, 100),
+		channel.Channel0.NoteOn(127, 100),
+	)
+
+	expected := `
+channel.NoteOn channel 2 key 0 velocity 100
+channel.NoteOn channel 2 key 127 velocity 100
+`
+
+	if want := expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	for i, chs := range [][2]int{{16, 0}, {0, -2}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("[%v] expected panic for channels %v", i, chs)
+				}
+			}()
+			NewSplit(60, chs[0], chs[1])
+		}()
+	}
+
+	// a split literal is checked when it is used
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected panic for channel 16")
+			}
+		}()
+		Split{{Low: 0, High: 127, Channel: 16}}.Transform(channel.Channel0.NoteOn(60, 100))
+	}()
+}
@@ -0,0 +1,96 @@
+package transform
+
+import (
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// SustainResolver is a transform that resolves the sustain pedal (controller 64) into note lengths:
+// While the pedal of a channel is down, the note offs of the channel are held back. They are released when
+// the pedal goes up or when the key is struck again. The pedal messages themselves are removed, unless KeepPedal is set.
+// This is needed when recording piano performances into SMF files that are used for notation.
+// At the end of the stream, the held back note offs are released (it is a midi.Flusher).
+// The zero value is ready to use.
+type SustainResolver struct {
+	// KeepPedal lets the pedal messages pass
+	KeepPedal bool
+
+	down    [16]bool
+	pending [16][128]midi.Message
+}
+
+// Transform resolves the sustain pedal
+func (s *SustainResolver) Transform(msg midi.Message) []midi.Message {
+	m := msg
+	if cm, is := msg.(channel.ChannelMode); is {
+		m = cm.ControlChange()
+	}
+
+	switch v := m.(type) {
+	case channel.NoteOn:
+		ch, key := v.Channel(), v.Key()
+		if off := s.pending[ch][key]; off != nil {
+			s.pending[ch][key] = nil
+			return []midi.Message{off, msg}
+		}
+	case channel.NoteOff:
+		if s.down[v.Channel()] {
+			s.pending[v.Channel()][v.Key()] = msg
+			return nil
+		}
+	case channel.NoteOffVelocity:
+		if s.down[v.Channel()] {
+			s.pending[v.Channel()][v.Key()] = msg
+			return nil
+		}
+	case channel.ControlChange:
+		ch := v.Channel()
+		switch v.Controller() {
+		case 64:
+			var res []midi.Message
+			if v.Value() >= 64 {
+				s.down[ch] = true
+			} else {
+				res = s.release(ch, nil)
+			}
+			if s.KeepPedal {
+				res = append(res, msg)
+			}
+			return res
+		case 121:
+			// reset all controllers releases the pedal
+			return append(s.release(ch, nil), msg)
+		case 120, 123:
+			// all sound off and all notes off end the held notes anyway
+			s.down[ch] = false
+			s.pending[ch] = [128]midi.Message{}
+		}
+	}
+
+	return []midi.Message{msg}
+}
+
+// Flush releases all held back note offs
+func (s *SustainResolver) Flush() (res []midi.Message) {
+	for ch := range s.pending {
+		res = s.release(uint8(ch), res)
+	}
+	return
+}
+
+// Name returns the name of the transform
+func (s *SustainResolver) Name() string {
+	return "sustainresolver"
+}
+
+// release sets the pedal of the channel up and appends the held back note offs of the channel to res
+func (s *SustainResolver) release(ch uint8, res []midi.Message) []midi.Message {
+	s.down[ch] = false
+	for key, off := range s.pending[ch] {
+		if off != nil {
+			res = append(res, off)
+			s.pending[ch][key] = nil
+		}
+	}
+	return res
+}
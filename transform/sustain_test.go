@@ -0,0 +1,77 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestSustainResolver(t *testing.T) {
+	var s SustainResolver
+
+	got := apply(&s,
+		channel.Channel0.NoteOn(60, 100),
+		channel.Channel0.ControlChange(64, 127),
+		channel.Channel0.NoteOff(60),
+		channel.Channel0.NoteOn(64, 100),
+		channel.Channel0.NoteOff(64),
+		channel.Channel1.NoteOn(40, 100),
+		channel.Channel1.NoteOff(40),
+		channel.Channel0.NoteOn(60, 90),
+		channel.Channel0.NoteOff(60),
+		channel.Channel0.ControlChange(64, 0),
+		channel.Channel0.NoteOn(67, 100),
+		channel.Channel0.NoteOff(67),
+		channel.Channel0.ControlChange(64, 100),
+		channel.Channel0.NoteOn(72, 100),
+		channel.Channel0.NoteOff(72),
+	)
+
+	for _, msg := range s.Flush() {
+		got += msg.String() + "\n"
+	}
+
+	expected := `
+channel.NoteOn channel 0 key 60 velocity 100
+channel.NoteOn channel 0 key 64 velocity 100
+channel.NoteOn channel 1 key 40 velocity 100
+channel.NoteOff channel 1 key 40
+channel.NoteOff channel 0 key 60
+channel.NoteOn channel 0 key 60 velocity 90
+channel.NoteOff channel 0 key 60
+channel.NoteOff channel 0 key 64
+channel.NoteOn channel 0 key 67 velocity 100
+channel.NoteOff channel 0 key 67
+channel.NoteOn channel 0 key 72 velocity 100
+channel.NoteOff channel 0 key 72
+`
+
+	if got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+}
+
+func TestSustainResolverKeepPedal(t *testing.T) {
+	s := &SustainResolver{KeepPedal: true}
+
+	got := apply(s,
+		channel.Channel0.ControlChange(64, 127),
+		channel.Channel0.NoteOn(60, 100),
+		channel.Channel0.NoteOff(60),
+		channel.Channel0.ControlChange(64, 0),
+	)
+
+	expected := `
+channel.ControlChange channel 0 controller 64 ("Hold Pedal (on/off)") value 127
+channel.NoteOn channel 0 key 60 velocity 100
+channel.NoteOff channel 0 key 60
+channel.ControlChange channel 0 controller 64 ("Hold Pedal (on/off)") value 0
+`
+
+	if got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+}
@@ -0,0 +1,145 @@
+package transform
+
+import (
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// Thin is a transform that thins out high frequency streams of control change, pitch bend and
+// aftertouch messages to avoid flooding slow (DIN) MIDI hardware.
+// A message is dropped, if the previous message of the same stream (same kind, channel and controller or key)
+// has been passed less than Interval ago or if its value differs less than Delta (PitchbendDelta for pitch bend).
+// Messages with extreme values (0, 127, the minimum, maximum and center of pitch bend) always pass,
+// so that controllers reliably reach their end positions.
+// The last dropped message of each stream is passed at the end of the stream (it is a midi.Flusher).
+//
+// Only continuous controllers are thinned: bank select (0, 32), data entry (6, 38), the switches (64-69),
+// (N)RPN (96-101) and the channel mode messages (120-127) always pass. All other messages pass unchanged.
+type Thin struct {
+	// Interval is the minimum interval between two messages of a stream
+	Interval time.Duration
+
+	// Delta is the minimum difference between the values of two control change or aftertouch messages of a stream.
+	// Delta 1 removes repeated values.
+	Delta int
+
+	// PitchbendDelta is the minimum difference between the values of two pitch bend messages of a channel
+	PitchbendDelta int
+
+	// Clock returns the current time (default: time.Now)
+	Clock func() time.Time
+
+	streams map[thinKey]*thinStream
+	order   []thinKey
+}
+
+type thinKey struct {
+	kind    uint8
+	channel uint8
+	number  uint8
+}
+
+type thinStream struct {
+	value   int
+	time    time.Time
+	pending midi.Message
+}
+
+// Transform passes or drops the message
+func (t *Thin) Transform(msg midi.Message) []midi.Message {
+	var (
+		key          thinKey
+		value, delta int
+		extreme      bool
+	)
+
+	switch v := msg.(type) {
+	case channel.ControlChange:
+		if !thinnable(v.Controller()) {
+			return []midi.Message{msg}
+		}
+		key = thinKey{0xB, v.Channel(), v.Controller()}
+		value, delta = int(v.Value()), t.Delta
+		extreme = value == 0 || value == 127
+	case channel.Pitchbend:
+		key = thinKey{0xE, v.Channel(), 0}
+		value, delta = int(v.Value()), t.PitchbendDelta
+		extreme = value == 0 || value == -8192 || value == 8191
+	case channel.Aftertouch:
+		key = thinKey{0xD, v.Channel(), 0}
+		value, delta = int(v.Pressure()), t.Delta
+		extreme = value == 0 || value == 127
+	case channel.PolyAftertouch:
+		key = thinKey{0xA, v.Channel(), v.Key()}
+		value, delta = int(v.Pressure()), t.Delta
+		extreme = value == 0 || value == 127
+	default:
+		return []midi.Message{msg}
+	}
+
+	now := t.now()
+
+	if t.streams == nil {
+		t.streams = map[thinKey]*thinStream{}
+	}
+
+	st, seen := t.streams[key]
+	if !seen {
+		st = &thinStream{}
+		t.streams[key] = st
+		t.order = append(t.order, key)
+	}
+
+	diff := value - st.value
+	if diff < 0 {
+		diff = -diff
+	}
+
+	if seen && !extreme && (now.Sub(st.time) < t.Interval || diff < delta) {
+		st.pending = msg
+		return nil
+	}
+
+	st.value, st.time, st.pending = value, now, nil
+	return []midi.Message{msg}
+}
+
+// Flush returns the last dropped message of each stream
+func (t *Thin) Flush() (res []midi.Message) {
+	for _, key := range t.order {
+		if st := t.streams[key]; st.pending != nil {
+			res = append(res, st.pending)
+			st.pending = nil
+		}
+	}
+	return
+}
+
+// Name returns the name of the transform
+func (t *Thin) Name() string {
+	return "thin"
+}
+
+func (t *Thin) now() time.Time {
+	if t.Clock != nil {
+		return t.Clock()
+	}
+	return time.Now()
+}
+
+// thinnable returns whether the given controller is a continuous controller
+func thinnable(controller uint8) bool {
+	switch {
+	case controller == 0, controller == 32, controller == 6, controller == 38:
+		return false
+	case controller >= 64 && controller <= 69:
+		return false
+	case controller >= 96 && controller <= 101:
+		return false
+	case controller >= 120:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestThinDelta(t *testing.T) {
+	th := &Thin{Delta: 4, PitchbendDelta: 100}
+
+	got := apply(th,
+		channel.Channel0.ControlChange(1, 10),
+		channel.Channel0.ControlChange(1, 12),
+		channel.Channel0.ControlChange(1, 14),
+		channel.Channel1.ControlChange(1, 13),
+		channel.Channel0.ControlChange(1, 15),
+		channel.Channel0.ControlChange(64, 127),
+		channel.Channel0.ControlChange(64, 127),
+		channel.Channel0.Pitchbend(1000),
+		channel.Channel0.Pitchbend(1050),
+		channel.Channel0.Pitchbend(0),
+		channel.Channel0.ControlChange(1, 16),
+	)
+
+	for _, msg := range th.Flush() {
+		got += msg.String() + "\n"
+	}
+
+	expected := `
+channel.ControlChange channel 0 controller 1 ("Modulation Wheel (MSB)") value 10
+channel.ControlChange channel 0 controller 1 ("Modulation Wheel (MSB)") value 14
+channel.ControlChange channel 1 controller 1 ("Modulation Wheel (MSB)") value 13
+channel.ControlChange channel 0 controller 64 ("Hold Pedal (on/off)") value 127
+channel.ControlChange channel 0 controller 64 ("Hold Pedal (on/off)") value 127
+channel.Pitchbend channel 0 value 1000 absValue 0
+channel.Pitchbend channel 0 value 0 absValue 0
+channel.ControlChange channel 0 controller 1 ("Modulation Wheel (MSB)") value 16
+`
+
+	if got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+}
+
+func TestThinInterval(t *testing.T) {
+	now := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	th := &Thin{Interval: 10 * time.Millisecond, Clock: func() time.Time { return now }}
+
+	got := "\n"
+	step := func(d time.Duration, value uint8) {
+		now = now.Add(d)
+		for _, msg := range th.Transform(channel.Channel0.Aftertouch(value)) {
+			got += msg.String() + "\n"
+		}
+	}
+
+	step(0, 10)
+	step(5*time.Millisecond, 11)
+	step(5*time.Millisecond, 12)
+	step(2*time.Millisecond, 13)
+	step(2*time.Millisecond, 127)
+	step(1*time.Millisecond, 100)
+
+	for _, msg := range th.Flush() {
+		got += msg.String() + "\n"
+	}
+
+	expected := `
+channel.Aftertouch channel 0 pressure 10
+channel.Aftertouch channel 0 pressure 12
+channel.Aftertouch channel 0 pressure 127
+channel.Aftertouch channel 0 pressure 100
+`
+
+	if got != expected {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, expected)
+	}
+}
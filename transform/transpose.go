@@ -0,0 +1,50 @@
+package transform
+
+import (
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// Transpose is a transform that shifts the keys of note on and note off messages by the given number of semitones.
+// The resulting keys are clamped to 0-127. All other messages are passed unchanged.
+type Transpose struct {
+	// Semitones is the number of semitones to shift (negative values shift down)
+	Semitones int
+
+	// PolyAftertouch lets the keys of polyphonic aftertouch messages be shifted, too
+	PolyAftertouch bool
+}
+
+// Transform shifts the key of the given message
+func (t Transpose) Transform(msg midi.Message) []midi.Message {
+	switch v := msg.(type) {
+	case channel.NoteOn:
+		msg = channel.Channel(v.Channel()).NoteOn(t.key(v.Key()), v.Velocity())
+	case channel.NoteOff:
+		msg = channel.Channel(v.Channel()).NoteOff(t.key(v.Key()))
+	case channel.NoteOffVelocity:
+		msg = channel.Channel(v.Channel()).NoteOffVelocity(t.key(v.Key()), v.Velocity())
+	case channel.PolyAftertouch:
+		if t.PolyAftertouch {
+			msg = channel.Channel(v.Channel()).PolyAftertouch(t.key(v.Key()), v.Pressure())
+		}
+	}
+	return []midi.Message{msg}
+}
+
+// Name returns the name of the transform
+func (t Transpose) Name() string {
+	return "transpose"
+}
+
+func (t Transpose) key(key uint8) uint8 {
+	k := int(key) + t.Semitones
+	switch {
+	case k < 0:
+		return 0
+	case k > 127:
+		return 127
+	default:
+		return uint8(k)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		transpose Transpose
+		expected  string
+	}{
+		{
+			Transpose{Semitones: 12},
+			`
+channel.NoteOn channel 1 key 72 velocity 100
+channel.NoteOff channel 1 key 72
+channel.NoteOn channel 2 key 127 velocity 10
+channel.PolyAftertouch channel 1 key 60 pressure 30
+channel.ControlChange channel 1 controller 7 ("Volume (MSB)") value 80
+`,
+		},
+		{
+			Transpose{Semitones: -61, PolyAftertouch: true},
+			`
+channel.NoteOn channel 1 key 0 velocity 100
+channel.NoteOff channel 1 key 0
+channel.NoteOn channel 2 key 59 velocity 10
+channel.PolyAftertouch channel 1 key 0 pressure 30
+channel.ControlChange channel 1 controller 7 ("Volume (MSB)") value 80
+`,
+		},
+	}
+
+	for _, test := range tests {
+		got := apply(test.transpose,
+			channel.Channel1.NoteOn(60, 100),
+			channel.Channel1.NoteOff(60),
+			channel.Channel2.NoteOn(120, 10),
+			channel.Channel1.PolyAftertouch(60, 30),
+			channel.Channel1.ControlChange(7, 80),
+		)
+
+		if want := test.expected; got != want {
+			t.Errorf("%+v got:\n%s\n\nwanted:\n%s\n\n", test.transpose, got, want)
+		}
+	}
+}
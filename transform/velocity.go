@@ -0,0 +1,64 @@
+package transform
+
+import (
+	"math"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+// VelocityCurve is a transform that remaps the velocities of note on messages via a lookup table:
+// the velocity v is replaced by the entry at index v.
+// Note on messages with velocity 0 (which are note offs) are passed unchanged and
+// positive velocities never become 0. All other messages are passed unchanged.
+// Custom curves can be created by filling the table, for common curves use LinearVelocity and GammaVelocity.
+type VelocityCurve [128]uint8
+
+// LinearVelocity returns a velocity curve that scales the velocity by the given factor and adds the given offset.
+// The results are clamped to 1-127.
+func LinearVelocity(factor float64, offset int) *VelocityCurve {
+	var c VelocityCurve
+	for i := range c {
+		c[i] = clampVelocity(float64(i)*factor + float64(offset))
+	}
+	return &c
+}
+
+// GammaVelocity returns a velocity curve of the form 127 * (v/127)^gamma.
+// A gamma below 1 makes soft playing louder, a gamma above 1 makes it softer.
+func GammaVelocity(gamma float64) *VelocityCurve {
+	var c VelocityCurve
+	for i := range c {
+		c[i] = clampVelocity(127 * math.Pow(float64(i)/127, gamma))
+	}
+	return &c
+}
+
+func clampVelocity(v float64) uint8 {
+	v = math.Round(v)
+	switch {
+	case v < 1:
+		return 1
+	case v > 127:
+		return 127
+	default:
+		return uint8(v)
+	}
+}
+
+// Transform remaps the velocity of the given message
+func (c *VelocityCurve) Transform(msg midi.Message) []midi.Message {
+	if on, is := msg.(channel.NoteOn); is && on.Velocity() > 0 {
+		vel := c[on.Velocity()&0x7F]
+		if vel == 0 {
+			vel = 1
+		}
+		msg = channel.Channel(on.Channel()).NoteOn(on.Key(), vel)
+	}
+	return []midi.Message{msg}
+}
+
+// Name returns the name of the transform
+func (c *VelocityCurve) Name() string {
+	return "velocitycurve"
+}
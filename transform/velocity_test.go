@@ -0,0 +1,67 @@
+//go:build !tinygo && !miditiny
+// +build !tinygo,!miditiny
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/gomidi/midi/midimessage/channel"
+)
+
+func TestVelocityCurve(t *testing.T) {
+	var custom VelocityCurve
+	for i := range custom {
+		custom[i] = 64
+	}
+
+	tests := []struct {
+		curve    *VelocityCurve
+		expected string
+	}{
+		{
+			LinearVelocity(0.5, 10),
+			`
+channel.NoteOn channel 1 key 60 velocity 11
+channel.NoteOn channel 1 key 61 velocity 42
+channel.NoteOn channel 1 key 62 velocity 74
+channel.NoteOff channel 1 key 60
+channel.ControlChange channel 1 controller 7 ("Volume (MSB)") value 80
+`,
+		},
+		{
+			GammaVelocity(0.5),
+			`
+channel.NoteOn channel 1 key 60 velocity 11
+channel.NoteOn channel 1 key 61 velocity 90
+channel.NoteOn channel 1 key 62 velocity 127
+channel.NoteOff channel 1 key 60
+channel.ControlChange channel 1 controller 7 ("Volume (MSB)") value 80
+`,
+		},
+		{
+			&custom,
+			`
+channel.NoteOn channel 1 key 60 velocity 64
+channel.NoteOn channel 1 key 61 velocity 64
+channel.NoteOn channel 1 key 62 velocity 64
+channel.NoteOff channel 1 key 60
+channel.ControlChange channel 1 controller 7 ("Volume (MSB)") value 80
+`,
+		},
+	}
+
+	for i, test := range tests {
+		got := apply(test.curve,
+			channel.Channel1.NoteOn(60, 1),
+			channel.Channel1.NoteOn(61, 64),
+			channel.Channel1.NoteOn(62, 127),
+			channel.Channel1.NoteOff(60),
+			channel.Channel1.ControlChange(7, 80),
+		)
+
+		if want := test.expected; got != want {
+			t.Errorf("[%v] got:\n%s\n\nwanted:\n%s\n\n", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package transport provides the playing state and song position of MIDI sequencers.
+
+A Transport interprets the Start, Continue, Stop, song position pointer and timing clock messages of another
+device (slave) or generates them for other devices (master). Its State tells whether the song is playing
+and the song position in 16th notes (the unit of song position pointers). The listeners registered with OnChange
+are informed about each change, so that the player, sequencers and other components stay in sync.
+
+A Transport is a clock.Clock that runs only while playing. As master, it is driven by another clock (e.g. a clock.Internal).
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/clock"
+		"github.com/gomidi/midi/midireader"
+		"github.com/gomidi/midi/transport"
+	)
+
+	// slave: follows the messages of a drum machine
+	tr := transport.New()
+	rd := midireader.New(input, tr.Receive)
+
+	// song position pointers are read as usual and passed via Write
+	go func() {
+		for {
+			msg, err := rd.Read()
+			if err != nil {
+				return
+			}
+			tr.Write(msg)
+		}
+	}()
+
+	tr.OnChange(func(c transport.Change, s transport.State) {
+		if c == transport.Located {
+			fmt.Printf("at bar %v\n", s.Position/16+1)
+		}
+	})
+
+	// master: sends the clock and the transport messages to out
+	master := transport.New(transport.Output(midiwriter.New(out)))
+	go master.Drive(ctx, clock.NewInternal(120))
+
+	master.Start()
+
+*/
+package transport
@@ -0,0 +1,321 @@
+package transport
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/clock"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+)
+
+// PulsesPerStep are the MIDI clock pulses per 16th note, the unit of song position pointers
+const PulsesPerStep = clock.PPQN / 4
+
+var (
+	_ clock.Clock = &Transport{}
+	_ midi.Writer = &Transport{}
+)
+
+// Change is the kind of a change of the transport
+type Change int
+
+const (
+	// Started is a change by a Start message: playing from the start of the song
+	Started Change = iota
+
+	// Continued is a change by a Continue message: playing from the current position
+	Continued
+
+	// Stopped is a change by a Stop message
+	Stopped
+
+	// Located is a change of the position by a song position pointer
+	Located
+
+	// Clocked is a pulse that is played at the position while playing; afterwards the position is advanced by the pulse
+	Clocked
+)
+
+var changeNames = map[Change]string{
+	Started:   "Started",
+	Continued: "Continued",
+	Stopped:   "Stopped",
+	Located:   "Located",
+	Clocked:   "Clocked",
+}
+
+// String returns the name of the change
+func (c Change) String() string {
+	return changeNames[c]
+}
+
+// State is the state of a transport
+type State struct {
+	// Playing is whether the song is playing
+	Playing bool
+
+	// Position is the song position in 16th notes (the unit of song position pointers)
+	Position uint32
+
+	// Pulse is the pulse within the 16th note (0-5)
+	Pulse uint8
+}
+
+// Pulses returns the position in MIDI clock pulses since the start of the song
+func (s State) Pulses() int {
+	return int(s.Position)*PulsesPerStep + int(s.Pulse)
+}
+
+// start starts playing at the start of the song
+func (s *State) start() bool {
+	*s = State{Playing: true}
+	return true
+}
+
+// play plays at the position
+func (s *State) play() bool {
+	s.Playing = true
+	return true
+}
+
+// stop stops playing
+func (s *State) stop() bool {
+	s.Playing = false
+	return true
+}
+
+// locate returns a change of the state to the position in 16th notes
+func locate(position uint32) func(*State) bool {
+	return func(s *State) bool {
+		s.Position, s.Pulse = position, 0
+		return true
+	}
+}
+
+// advance advances the position by a pulse, if playing
+func (s *State) advance() bool {
+	if !s.Playing {
+		return false
+	}
+
+	s.Pulse++
+	if s.Pulse == PulsesPerStep {
+		s.Pulse = 0
+		s.Position++
+	}
+
+	return true
+}
+
+// Option is an option for a Transport
+type Option func(*Transport)
+
+// Output is an option that sets the writer the messages generated by Start, Continue, Stop, Locate and Pulse are written to
+func Output(w midi.Writer) Option {
+	return func(t *Transport) {
+		t.out = w
+	}
+}
+
+// listener is a callback for changes
+type listener struct {
+	id int
+	fn func(Change, State)
+}
+
+// Transport is the playing state and song position, that is changed by the Start, Continue, Stop, song position pointer
+// and timing clock messages of another device (slave) or generates these messages for other devices (master).
+// The listeners are informed about each change. It is safe for concurrent use.
+type Transport struct {
+	mx        sync.Mutex
+	state     State
+	out       midi.Writer
+	listeners []listener
+	nextID    int
+}
+
+// New returns a stopped transport at the start of the song
+func New(opts ...Option) *Transport {
+	t := &Transport{}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// State returns the current state
+func (t *Transport) State() State {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	return t.state
+}
+
+// OnChange adds a listener that is called after each change with the kind of the change and the new state
+// (for Clocked the state of the pulse that is played). The listeners are called in the goroutine that causes
+// the change and must not block. OnChange returns a function that removes the listener.
+func (t *Transport) OnChange(fn func(c Change, s State)) (remove func()) {
+	t.mx.Lock()
+	id := t.nextID
+	t.nextID++
+	t.listeners = append(t.listeners, listener{id, fn})
+	t.mx.Unlock()
+
+	return func() {
+		t.mx.Lock()
+		defer t.mx.Unlock()
+
+		for i, l := range t.listeners {
+			if l.id == id {
+				t.listeners = append(t.listeners[:i:i], t.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// change changes the state with fn, writes msg to the output (if both are not nil) and informs the listeners,
+// if fn returns true
+func (t *Transport) change(c Change, msg midi.Message, fn func(s *State) bool) (err error) {
+	t.mx.Lock()
+	before := t.state
+	changed := fn(&t.state)
+	s := t.state
+	out := t.out
+	listeners := t.listeners
+	t.mx.Unlock()
+
+	// the pulse is played at the position before it is advanced
+	if c == Clocked {
+		s = before
+	}
+
+	if out != nil && msg != nil {
+		err = out.Write(msg)
+	}
+
+	if !changed {
+		return
+	}
+
+	for _, l := range listeners {
+		l.fn(c, s)
+	}
+
+	return
+}
+
+// Start starts playing from the start of the song and writes a Start message
+func (t *Transport) Start() error {
+	return t.change(Started, realtime.Start, (*State).start)
+}
+
+// Continue continues playing at the current position and writes a Continue message
+func (t *Transport) Continue() error {
+	return t.change(Continued, realtime.Continue, (*State).play)
+}
+
+// Stop stops playing and writes a Stop message
+func (t *Transport) Stop() error {
+	return t.change(Stopped, realtime.Stop, (*State).stop)
+}
+
+// Locate sets the position in 16th notes and writes a song position pointer. Positions beyond the range of
+// song position pointers (16383) are written as the maximum.
+func (t *Transport) Locate(position uint32) error {
+	return t.change(Located, syscommon.SPPAtSixteenth(position), locate(position))
+}
+
+// Pulse writes a timing clock message. While playing, the pulse is played (see Clocked) and the position is advanced.
+// MIDI clock is usually sent also when stopped, so that the tempo of the slaves is known.
+func (t *Transport) Pulse() error {
+	return t.change(Clocked, realtime.TimingClock, (*State).advance)
+}
+
+// Receive receives a realtime message of another device, it can be passed as realtime handler to midireader.New.
+// Start, Continue, Stop and timing clock messages change the state like the corresponding methods, but nothing is written
+// to the output. Other messages are ignored.
+func (t *Transport) Receive(msg realtime.Message) {
+	t.receive(msg)
+}
+
+// Write receives a message of another device like Receive, including song position pointers.
+// It allows to use the transport as midi.Writer (e.g. as output of a router). It always returns nil.
+func (t *Transport) Write(msg midi.Message) error {
+	t.receive(msg)
+	return nil
+}
+
+func (t *Transport) receive(msg midi.Message) {
+	if spp, is := msg.(syscommon.SPP); is {
+		t.change(Located, nil, locate(uint32(spp)))
+		return
+	}
+
+	switch msg {
+	case realtime.Start:
+		t.change(Started, nil, (*State).start)
+	case realtime.Continue:
+		t.change(Continued, nil, (*State).play)
+	case realtime.Stop:
+		t.change(Stopped, nil, (*State).stop)
+	case realtime.TimingClock:
+		t.change(Clocked, nil, (*State).advance)
+	}
+}
+
+// Run calls pulse for each pulse that is played (see Clocked), until ctx is done. It returns ctx.Err().
+// This way, the transport is a clock.Clock that only runs while playing, e.g. to drive a sequencer.
+// The pulses are buffered for a quarter note, if pulse is slow.
+func (t *Transport) Run(ctx context.Context, pulse func()) error {
+	pulses := make(chan struct{}, clock.PPQN)
+
+	remove := t.OnChange(func(c Change, s State) {
+		if c != Clocked {
+			return
+		}
+
+		select {
+		case pulses <- struct{}{}:
+		default:
+		}
+	})
+
+	defer remove()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-pulses:
+			pulse()
+		}
+	}
+}
+
+// Drive generates the pulses with the given clock (master), until ctx is done or writing to the output fails.
+// It returns the error of the write or ctx.Err().
+func (t *Transport) Drive(ctx context.Context, clk clock.Clock) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var err error
+
+	clkErr := clk.Run(ctx, func() {
+		if err != nil {
+			return
+		}
+		if err = t.Pulse(); err != nil {
+			cancel()
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return clkErr
+}
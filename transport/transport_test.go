@@ -0,0 +1,178 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+)
+
+type logWriter struct {
+	bf *bytes.Buffer
+}
+
+func (l logWriter) Write(msg midi.Message) error {
+	fmt.Fprintf(l.bf, "write %s\n", msg)
+	return nil
+}
+
+// listen logs the changes of the transport, except the pulses
+func listen(t *Transport, bf *bytes.Buffer) {
+	t.OnChange(func(c Change, s State) {
+		if c != Clocked {
+			fmt.Fprintf(bf, "%s %v %v:%v\n", c, s.Playing, s.Position, s.Pulse)
+		}
+	})
+}
+
+func TestReceive(t *testing.T) {
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+
+	tr := New()
+	listen(tr, &bf)
+
+	msgs := []midi.Message{
+		// ignored while stopped
+		realtime.TimingClock,
+		realtime.Start,
+	}
+
+	for i := 0; i < 8; i++ {
+		msgs = append(msgs, realtime.TimingClock)
+	}
+
+	msgs = append(msgs,
+		realtime.Stop,
+		syscommon.SPP(16),
+		channel.Channel0.NoteOn(60, 100),
+		realtime.Continue,
+		realtime.TimingClock,
+	)
+
+	for _, msg := range msgs {
+		tr.Write(msg)
+	}
+
+	expected := `
+Started true 0:0
+Stopped false 1:2
+Located false 16:0
+Continued true 16:0
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+
+	if got, want := tr.State(), (State{true, 16, 1}); got != want {
+		t.Errorf("State() = %v; wanted %v", got, want)
+	}
+
+	if got, want := tr.State().Pulses(), 97; got != want {
+		t.Errorf("Pulses() = %v; wanted %v", got, want)
+	}
+}
+
+func TestMaster(t *testing.T) {
+	var bf bytes.Buffer
+	bf.WriteString("\n")
+
+	tr := New(Output(logWriter{&bf}))
+	listen(tr, &bf)
+
+	remove := tr.OnChange(func(c Change, s State) {
+		if c == Clocked {
+			fmt.Fprintf(&bf, "pulse %v:%v\n", s.Position, s.Pulse)
+		}
+	})
+
+	tr.Pulse()
+	tr.Locate(4)
+	tr.Continue()
+	tr.Pulse()
+	tr.Pulse()
+	remove()
+	tr.Pulse()
+	tr.Stop()
+
+	expected := `
+write TimingClock
+write syscommon.SPP: 4
+Located false 4:0
+write Continue
+Continued true 4:0
+write TimingClock
+pulse 4:0
+write TimingClock
+pulse 4:1
+write TimingClock
+write Stop
+Stopped false 4:3
+`
+
+	if got, want := bf.String(), expected; got != want {
+		t.Errorf("got:\n%s\n\nwanted:\n%s\n\n", got, want)
+	}
+}
+
+// pulses is a clock that generates the given number of pulses
+type pulses int
+
+func (p pulses) Run(ctx context.Context, pulse func()) error {
+	for i := 0; i < int(p); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pulse()
+	}
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	tr := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	played := make(chan struct{}, 100)
+
+	go tr.Run(ctx, func() {
+		played <- struct{}{}
+	})
+
+	// wait until Run listens
+	for {
+		tr.mx.Lock()
+		n := len(tr.listeners)
+		tr.mx.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	tr.Drive(ctx, pulses(3))
+	tr.Start()
+	tr.Drive(ctx, pulses(5))
+
+	for i := 0; i < 5; i++ {
+		<-played
+	}
+
+	if got, want := tr.State(), (State{true, 0, 5}); got != want {
+		t.Errorf("State() = %v; wanted %v", got, want)
+	}
+
+	select {
+	case <-played:
+		t.Errorf("pulses while stopped must not be played")
+	default:
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package ump provides a reader of Universal MIDI Packets (UMP), as defined by the MIDI 2.0 specification.
+
+A packet consists of one to four 32-bit words; the message type in the upper nibble of the first word
+determines its size. MIDI 1.0 channel voice and system messages are wrapped in MIDI1 and System, so that
+the messages of the existing midimessage packages can be used. MIDI 2.0 channel voice messages
+(with 16-bit velocity and 32-bit controller values) are returned as their own types.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/ump"
+	)
+
+	rd := ump.NewReader(conn) // the words are read big-endian
+
+	for {
+		m, err := rd.Read()
+
+		// at the end io.EOF will be returned
+		if err != nil {
+			break
+		}
+
+		switch msg := m.(type) {
+		case ump.NoteOn:
+			fmt.Printf(
+			  "NoteOn at group %v channel %v: key %v velocity: %v\n",
+			  msg.Group(),
+			  msg.Channel(),
+			  msg.Key(),
+			  msg.Velocity(), // 0-65535
+			)
+		case ump.ControlChange:
+			...
+		case ump.MIDI1:
+			// msg.Message() is a MIDI 1.0 channel message, e.g. a channel.NoteOn
+		}
+	}
+
+Messages can be built with the same pattern as the channel messages and written via their Raw bytes:
+
+	ump.Ch(0, 1).NoteOn(60, 0x8000)
+
+Words that can't be parsed (e.g. a MIDI 1.0 packet with a system status byte) are reported as a *midi.ReadError
+that is not Fatal, so that reading can continue with the next packet. Packets of unknown message types are
+returned as Unknown.
+*/
+package ump
@@ -0,0 +1,623 @@
+package ump
+
+import "strconv"
+
+// statuses of MIDI 2.0 channel voice messages
+const (
+	statusRegisteredPerNote  = 0x0
+	statusAssignablePerNote  = 0x1
+	statusRegistered         = 0x2
+	statusAssignable         = 0x3
+	statusRelativeRegistered = 0x4
+	statusRelativeAssignable = 0x5
+	statusPerNotePitchbend   = 0x6
+	statusNoteOff            = 0x8
+	statusNoteOn             = 0x9
+	statusPolyPressure       = 0xA
+	statusControlChange      = 0xB
+	statusProgramChange      = 0xC
+	statusChannelPressure    = 0xD
+	statusPitchbend          = 0xE
+	statusPerNoteManagement  = 0xF
+)
+
+// Channel is a MIDI channel within a group, that creates MIDI 2.0 channel voice messages
+type Channel struct {
+	group, channel uint8
+}
+
+// Ch returns the channel (0-15) of the group (0-15)
+func Ch(group, channel uint8) Channel {
+	return Channel{group & 0x0F, channel & 0x0F}
+}
+
+// Group returns the group of the channel
+func (c Channel) Group() uint8 {
+	return c.group
+}
+
+// Channel returns the number of the channel (0-15)
+func (c Channel) Channel() uint8 {
+	return c.channel
+}
+
+// voice returns the first word of a message of the channel
+func (c Channel) voice(status, b2, b3 uint8) channelVoice {
+	return channelVoice{c.group, c.channel, status, b2, b3}
+}
+
+// channelVoice is the common part of the MIDI 2.0 channel voice messages: the first word
+type channelVoice struct {
+	group, channel uint8
+	status         uint8
+	b2, b3         uint8
+}
+
+// Group returns the group of the message
+func (c channelVoice) Group() uint8 {
+	return c.group
+}
+
+// Channel returns the channel of the message
+func (c channelVoice) Channel() uint8 {
+	return c.channel
+}
+
+// word0 returns the first word of the message
+func (c channelVoice) word0() uint32 {
+	return typeMIDI2<<28 | uint32(c.group)<<24 | uint32(c.status)<<20 | uint32(c.channel)<<16 | uint32(c.b2)<<8 | uint32(c.b3)
+}
+
+// prefix returns the start of the string of the message
+func (c channelVoice) prefix(name string) string {
+	return "ump." + name + " group " + itoa(uint64(c.group)) + " channel " + itoa(uint64(c.channel))
+}
+
+func parseMIDI2(w0, w1 uint32) Message {
+	cv := channelVoice{
+		group:   uint8(w0>>24) & 0x0F,
+		channel: uint8(w0>>16) & 0x0F,
+		status:  uint8(w0>>20) & 0x0F,
+		b2:      uint8(w0 >> 8),
+		b3:      uint8(w0),
+	}
+
+	switch cv.status {
+	case statusNoteOn:
+		return NoteOn{note{cv, w1}}
+	case statusNoteOff:
+		return NoteOff{note{cv, w1}}
+	case statusPolyPressure:
+		return PolyPressure{cv, w1}
+	case statusControlChange:
+		return ControlChange{cv, w1}
+	case statusProgramChange:
+		return ProgramChange{cv, w1}
+	case statusChannelPressure:
+		return ChannelPressure{cv, w1}
+	case statusPitchbend:
+		return Pitchbend{cv, w1}
+	case statusRegistered, statusAssignable:
+		return Controller{cv, w1}
+	case statusRelativeRegistered, statusRelativeAssignable:
+		return RelativeController{cv, w1}
+	case statusRegisteredPerNote, statusAssignablePerNote:
+		return PerNoteController{cv, w1}
+	case statusPerNotePitchbend:
+		return PerNotePitchbend{cv, w1}
+	case statusPerNoteManagement:
+		return PerNoteManagement{cv, w1}
+	default:
+		return Unknown{w0, w1}
+	}
+}
+
+// note is the common part of note on and note off messages
+type note struct {
+	channelVoice
+	w1 uint32
+}
+
+// Key returns the key of the note
+func (n note) Key() uint8 {
+	return n.b2 & 0x7F
+}
+
+// Velocity returns the 16-bit velocity of the note
+func (n note) Velocity() uint16 {
+	return uint16(n.w1 >> 16)
+}
+
+// AttributeType returns the type of the attribute (0 for none, 3 for pitch 7.9)
+func (n note) AttributeType() uint8 {
+	return n.b3
+}
+
+// Attribute returns the data of the attribute
+func (n note) Attribute() uint16 {
+	return uint16(n.w1)
+}
+
+// Words returns the words of the packet
+func (n note) Words() []uint32 {
+	return []uint32{n.word0(), n.w1}
+}
+
+// Raw returns the bytes of the packet
+func (n note) Raw() []byte {
+	return raw(n.Words()...)
+}
+
+func (n note) string(name string) string {
+	s := n.prefix(name) + " key " + itoa(uint64(n.Key())) + " velocity " + itoa(uint64(n.Velocity()))
+	if n.AttributeType() != 0 {
+		s += " attribute " + itoa(uint64(n.AttributeType())) + " " + itoa(uint64(n.Attribute()))
+	}
+	return s
+}
+
+// NoteOn is a MIDI 2.0 note on message with 16-bit velocity and an optional attribute.
+// Unlike MIDI 1.0, a velocity of 0 is no note off.
+type NoteOn struct {
+	note
+}
+
+// NoteOn returns a note on message with the 16-bit velocity
+func (c Channel) NoteOn(key uint8, velocity uint16) NoteOn {
+	return c.NoteOnAttribute(key, velocity, 0, 0)
+}
+
+// NoteOnAttribute returns a note on message with the 16-bit velocity and the attribute
+func (c Channel) NoteOnAttribute(key uint8, velocity uint16, attributeType uint8, attribute uint16) NoteOn {
+	return NoteOn{note{c.voice(statusNoteOn, key&0x7F, attributeType), uint32(velocity)<<16 | uint32(attribute)}}
+}
+
+// String returns the channel, key and velocity of the message
+func (n NoteOn) String() string {
+	return n.string("NoteOn")
+}
+
+// NoteOff is a MIDI 2.0 note off message with 16-bit velocity and an optional attribute
+type NoteOff struct {
+	note
+}
+
+// NoteOff returns a note off message with the 16-bit velocity
+func (c Channel) NoteOff(key uint8, velocity uint16) NoteOff {
+	return NoteOff{note{c.voice(statusNoteOff, key&0x7F, 0), uint32(velocity) << 16}}
+}
+
+// String returns the channel, key and velocity of the message
+func (n NoteOff) String() string {
+	return n.string("NoteOff")
+}
+
+// value32 is the common part of the messages with a 32-bit value in the second word
+type value32 struct {
+	channelVoice
+	w1 uint32
+}
+
+// Words returns the words of the packet
+func (v value32) Words() []uint32 {
+	return []uint32{v.word0(), v.w1}
+}
+
+// Raw returns the bytes of the packet
+func (v value32) Raw() []byte {
+	return raw(v.Words()...)
+}
+
+// PolyPressure is a MIDI 2.0 polyphonic aftertouch message with 32-bit pressure
+type PolyPressure value32
+
+// PolyPressure returns a polyphonic aftertouch message with the 32-bit pressure
+func (c Channel) PolyPressure(key uint8, pressure uint32) PolyPressure {
+	return PolyPressure{c.voice(statusPolyPressure, key&0x7F, 0), pressure}
+}
+
+// Key returns the key
+func (m PolyPressure) Key() uint8 {
+	return m.b2 & 0x7F
+}
+
+// Pressure returns the 32-bit pressure
+func (m PolyPressure) Pressure() uint32 {
+	return m.w1
+}
+
+// Words returns the words of the packet
+func (m PolyPressure) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m PolyPressure) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel, key and pressure of the message
+func (m PolyPressure) String() string {
+	return m.prefix("PolyPressure") + " key " + itoa(uint64(m.Key())) + " pressure " + itoa(uint64(m.w1))
+}
+
+// ControlChange is a MIDI 2.0 control change message with a 32-bit value
+type ControlChange value32
+
+// ControlChange returns a control change message with the 32-bit value
+func (c Channel) ControlChange(controller uint8, value uint32) ControlChange {
+	return ControlChange{c.voice(statusControlChange, controller&0x7F, 0), value}
+}
+
+// Controller returns the controller (index)
+func (m ControlChange) Controller() uint8 {
+	return m.b2 & 0x7F
+}
+
+// Value returns the 32-bit value
+func (m ControlChange) Value() uint32 {
+	return m.w1
+}
+
+// Words returns the words of the packet
+func (m ControlChange) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m ControlChange) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel, controller and value of the message
+func (m ControlChange) String() string {
+	return m.prefix("ControlChange") + " controller " + itoa(uint64(m.Controller())) + " value " + itoa(uint64(m.w1))
+}
+
+// ProgramChange is a MIDI 2.0 program change message with an optional bank
+type ProgramChange value32
+
+// ProgramChange returns a program change message without bank
+func (c Channel) ProgramChange(program uint8) ProgramChange {
+	return ProgramChange{c.voice(statusProgramChange, 0, 0), uint32(program&0x7F) << 24}
+}
+
+// ProgramChangeBank returns a program change message with the bank (most and least significant byte)
+func (c Channel) ProgramChangeBank(program, bankMSB, bankLSB uint8) ProgramChange {
+	return ProgramChange{c.voice(statusProgramChange, 0, 1), uint32(program&0x7F)<<24 | uint32(bankMSB&0x7F)<<8 | uint32(bankLSB&0x7F)}
+}
+
+// Program returns the program
+func (m ProgramChange) Program() uint8 {
+	return uint8(m.w1>>24) & 0x7F
+}
+
+// Bank returns the bank (most and least significant byte) and whether the message selects a bank
+func (m ProgramChange) Bank() (msb, lsb uint8, ok bool) {
+	return uint8(m.w1>>8) & 0x7F, uint8(m.w1) & 0x7F, m.b3&1 == 1
+}
+
+// Words returns the words of the packet
+func (m ProgramChange) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m ProgramChange) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel, program and bank of the message
+func (m ProgramChange) String() string {
+	s := m.prefix("ProgramChange") + " program " + itoa(uint64(m.Program()))
+	if msb, lsb, ok := m.Bank(); ok {
+		s += " bank " + itoa(uint64(msb)) + " " + itoa(uint64(lsb))
+	}
+	return s
+}
+
+// ChannelPressure is a MIDI 2.0 channel aftertouch message with 32-bit pressure
+type ChannelPressure value32
+
+// ChannelPressure returns a channel aftertouch message with the 32-bit pressure
+func (c Channel) ChannelPressure(pressure uint32) ChannelPressure {
+	return ChannelPressure{c.voice(statusChannelPressure, 0, 0), pressure}
+}
+
+// Pressure returns the 32-bit pressure
+func (m ChannelPressure) Pressure() uint32 {
+	return m.w1
+}
+
+// Words returns the words of the packet
+func (m ChannelPressure) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m ChannelPressure) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel and pressure of the message
+func (m ChannelPressure) String() string {
+	return m.prefix("ChannelPressure") + " pressure " + itoa(uint64(m.w1))
+}
+
+// PitchbendCenter is the value of a pitch bend message without bend
+const PitchbendCenter = 0x80000000
+
+// Pitchbend is a MIDI 2.0 pitch bend message with an unsigned 32-bit value (PitchbendCenter for no bend)
+type Pitchbend value32
+
+// Pitchbend returns a pitch bend message with the unsigned 32-bit value
+func (c Channel) Pitchbend(value uint32) Pitchbend {
+	return Pitchbend{c.voice(statusPitchbend, 0, 0), value}
+}
+
+// Value returns the unsigned 32-bit value
+func (m Pitchbend) Value() uint32 {
+	return m.w1
+}
+
+// Bend returns the value relative to PitchbendCenter
+func (m Pitchbend) Bend() int64 {
+	return int64(m.w1) - PitchbendCenter
+}
+
+// Words returns the words of the packet
+func (m Pitchbend) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m Pitchbend) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel and value of the message
+func (m Pitchbend) String() string {
+	return m.prefix("Pitchbend") + " value " + itoa(uint64(m.w1))
+}
+
+// Controller is a MIDI 2.0 registered (RPN) or assignable (NRPN) controller message with a 32-bit value
+type Controller value32
+
+// Registered returns a registered controller (RPN) message with the 32-bit value
+func (c Channel) Registered(bank, index uint8, value uint32) Controller {
+	return Controller{c.voice(statusRegistered, bank&0x7F, index&0x7F), value}
+}
+
+// Assignable returns an assignable controller (NRPN) message with the 32-bit value
+func (c Channel) Assignable(bank, index uint8, value uint32) Controller {
+	return Controller{c.voice(statusAssignable, bank&0x7F, index&0x7F), value}
+}
+
+// Registered returns whether it is a registered controller (RPN), otherwise it is an assignable one (NRPN)
+func (m Controller) Registered() bool {
+	return m.status == statusRegistered
+}
+
+// Bank returns the bank of the controller
+func (m Controller) Bank() uint8 {
+	return m.b2 & 0x7F
+}
+
+// Index returns the index of the controller
+func (m Controller) Index() uint8 {
+	return m.b3 & 0x7F
+}
+
+// Value returns the 32-bit value
+func (m Controller) Value() uint32 {
+	return m.w1
+}
+
+// Words returns the words of the packet
+func (m Controller) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m Controller) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel, kind, bank, index and value of the message
+func (m Controller) String() string {
+	name := "Assignable"
+	if m.Registered() {
+		name = "Registered"
+	}
+	return m.prefix(name) + " bank " + itoa(uint64(m.Bank())) + " index " + itoa(uint64(m.Index())) + " value " + itoa(uint64(m.w1))
+}
+
+// RelativeController is a MIDI 2.0 relative registered or assignable controller message with a signed 32-bit change
+type RelativeController value32
+
+// RelativeRegistered returns a relative registered controller message
+func (c Channel) RelativeRegistered(bank, index uint8, change int32) RelativeController {
+	return RelativeController{c.voice(statusRelativeRegistered, bank&0x7F, index&0x7F), uint32(change)}
+}
+
+// RelativeAssignable returns a relative assignable controller message
+func (c Channel) RelativeAssignable(bank, index uint8, change int32) RelativeController {
+	return RelativeController{c.voice(statusRelativeAssignable, bank&0x7F, index&0x7F), uint32(change)}
+}
+
+// Registered returns whether it is a registered controller (RPN), otherwise it is an assignable one (NRPN)
+func (m RelativeController) Registered() bool {
+	return m.status == statusRelativeRegistered
+}
+
+// Bank returns the bank of the controller
+func (m RelativeController) Bank() uint8 {
+	return m.b2 & 0x7F
+}
+
+// Index returns the index of the controller
+func (m RelativeController) Index() uint8 {
+	return m.b3 & 0x7F
+}
+
+// Change returns the signed change of the value
+func (m RelativeController) Change() int32 {
+	return int32(m.w1)
+}
+
+// Words returns the words of the packet
+func (m RelativeController) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m RelativeController) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel, kind, bank, index and change of the message
+func (m RelativeController) String() string {
+	name := "RelativeAssignable"
+	if m.Registered() {
+		name = "RelativeRegistered"
+	}
+	return m.prefix(name) + " bank " + itoa(uint64(m.Bank())) + " index " + itoa(uint64(m.Index())) + " change " + strconv.FormatInt(int64(m.Change()), 10)
+}
+
+// PerNoteController is a MIDI 2.0 registered or assignable per-note controller message with a 32-bit value
+type PerNoteController value32
+
+// PerNoteRegistered returns a registered per-note controller message
+func (c Channel) PerNoteRegistered(key, index uint8, value uint32) PerNoteController {
+	return PerNoteController{c.voice(statusRegisteredPerNote, key&0x7F, index), value}
+}
+
+// PerNoteAssignable returns an assignable per-note controller message
+func (c Channel) PerNoteAssignable(key, index uint8, value uint32) PerNoteController {
+	return PerNoteController{c.voice(statusAssignablePerNote, key&0x7F, index), value}
+}
+
+// Registered returns whether it is a registered controller, otherwise it is an assignable one
+func (m PerNoteController) Registered() bool {
+	return m.status == statusRegisteredPerNote
+}
+
+// Key returns the key
+func (m PerNoteController) Key() uint8 {
+	return m.b2 & 0x7F
+}
+
+// Index returns the index of the controller
+func (m PerNoteController) Index() uint8 {
+	return m.b3
+}
+
+// Value returns the 32-bit value
+func (m PerNoteController) Value() uint32 {
+	return m.w1
+}
+
+// Words returns the words of the packet
+func (m PerNoteController) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m PerNoteController) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel, kind, key, index and value of the message
+func (m PerNoteController) String() string {
+	name := "PerNoteAssignable"
+	if m.Registered() {
+		name = "PerNoteRegistered"
+	}
+	return m.prefix(name) + " key " + itoa(uint64(m.Key())) + " index " + itoa(uint64(m.Index())) + " value " + itoa(uint64(m.w1))
+}
+
+// PerNotePitchbend is a MIDI 2.0 per-note pitch bend message with an unsigned 32-bit value (PitchbendCenter for no bend)
+type PerNotePitchbend value32
+
+// PerNotePitchbend returns a per-note pitch bend message
+func (c Channel) PerNotePitchbend(key uint8, value uint32) PerNotePitchbend {
+	return PerNotePitchbend{c.voice(statusPerNotePitchbend, key&0x7F, 0), value}
+}
+
+// Key returns the key
+func (m PerNotePitchbend) Key() uint8 {
+	return m.b2 & 0x7F
+}
+
+// Value returns the unsigned 32-bit value
+func (m PerNotePitchbend) Value() uint32 {
+	return m.w1
+}
+
+// Words returns the words of the packet
+func (m PerNotePitchbend) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m PerNotePitchbend) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel, key and value of the message
+func (m PerNotePitchbend) String() string {
+	return m.prefix("PerNotePitchbend") + " key " + itoa(uint64(m.Key())) + " value " + itoa(uint64(m.w1))
+}
+
+// PerNoteManagement is a MIDI 2.0 per-note management message
+type PerNoteManagement value32
+
+// PerNoteManagement returns a per-note management message that detaches the per-note controllers from
+// previously received notes and/or resets them to their defaults
+func (c Channel) PerNoteManagement(key uint8, detach, reset bool) PerNoteManagement {
+	var flags uint8
+	if detach {
+		flags |= 2
+	}
+	if reset {
+		flags |= 1
+	}
+	return PerNoteManagement{c.voice(statusPerNoteManagement, key&0x7F, flags), 0}
+}
+
+// Key returns the key
+func (m PerNoteManagement) Key() uint8 {
+	return m.b2 & 0x7F
+}
+
+// Detach returns whether the per-note controllers are detached from previously received notes
+func (m PerNoteManagement) Detach() bool {
+	return m.b3&2 != 0
+}
+
+// Reset returns whether the per-note controllers are reset to their defaults
+func (m PerNoteManagement) Reset() bool {
+	return m.b3&1 != 0
+}
+
+// Words returns the words of the packet
+func (m PerNoteManagement) Words() []uint32 {
+	return value32(m).Words()
+}
+
+// Raw returns the bytes of the packet
+func (m PerNoteManagement) Raw() []byte {
+	return value32(m).Raw()
+}
+
+// String returns the channel, key and flags of the message
+func (m PerNoteManagement) String() string {
+	s := m.prefix("PerNoteManagement") + " key " + itoa(uint64(m.Key()))
+	if m.Detach() {
+		s += " detach"
+	}
+	if m.Reset() {
+		s += " reset"
+	}
+	return s
+}
@@ -0,0 +1,77 @@
+package ump
+
+import (
+	"io"
+
+	"github.com/gomidi/midi"
+)
+
+var _ midi.Reader = &Reader{}
+
+// Reader reads Universal MIDI Packets from a stream of 32-bit words in big endian order
+type Reader struct {
+	src    io.Reader
+	offset int64
+	buf    [16]byte
+}
+
+// NewReader returns a reader that reads the packets from src
+func NewReader(src io.Reader) *Reader {
+	return &Reader{src: src}
+}
+
+// Read reads the next packet and returns it as Message. At the end of src, it returns io.EOF.
+// If src ends within a packet, a *midi.ReadError with the cause midi.ErrUnexpectedEOF is returned.
+// A packet with an invalid MIDI 1.0 message results in a *midi.ReadError with the cause midi.ErrInvalidMessage;
+// the next call of Read returns the next packet.
+func (r *Reader) Read() (midi.Message, error) {
+	start := r.offset
+
+	if err := r.read(r.buf[:4]); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, r.unexpected(err)
+	}
+
+	w0 := word(r.buf[:4])
+	words := []uint32{w0}
+
+	if n := Size(w0); n > 1 {
+		if err := r.read(r.buf[4 : n*4]); err != nil {
+			return nil, r.unexpected(err)
+		}
+
+		for i := 1; i < n; i++ {
+			words = append(words, word(r.buf[i*4:]))
+		}
+	}
+
+	msg, err := Parse(words...)
+
+	if err != nil {
+		return nil, &midi.ReadError{Err: err, Offset: start, Track: -1}
+	}
+
+	return msg, nil
+}
+
+// read reads exactly len(b) bytes
+func (r *Reader) read(b []byte) error {
+	n, err := io.ReadFull(r.src, b)
+	r.offset += int64(n)
+	return err
+}
+
+// unexpected returns a ReadError for the end of the input within a packet; other errors are returned unchanged
+func (r *Reader) unexpected(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return &midi.ReadError{Err: midi.ErrUnexpectedEOF, Offset: r.offset, Track: -1, Fatal: true}
+	}
+	return err
+}
+
+// word returns the big endian word of the first 4 bytes of b
+func word(b []byte) uint32 {
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
@@ -0,0 +1,423 @@
+package ump
+
+import (
+	"strconv"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/internal/midilib"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+)
+
+// Message is a message of a Universal MIDI Packet. Raw returns the bytes of the packet, the words in big endian order.
+type Message interface {
+	midi.Message
+
+	// Group returns the group (0-15) of the message, 0 for messages without group
+	Group() uint8
+
+	// Words returns the words of the packet (1-4)
+	Words() []uint32
+}
+
+// message types of the first word of a packet
+const (
+	typeUtility = 0x0
+	typeSystem  = 0x1
+	typeMIDI1   = 0x2
+	typeData64  = 0x3
+	typeMIDI2   = 0x4
+	typeData128 = 0x5
+)
+
+// Size returns the number of words (1-4) of the packet that starts with the given word, i.e. 32, 64, 96 or 128 bits
+func Size(word0 uint32) int {
+	switch word0 >> 28 {
+	case 0x0, 0x1, 0x2, 0x6, 0x7:
+		return 1
+	case 0x3, 0x4, 0x8, 0x9, 0xA:
+		return 2
+	case 0xB, 0xC:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// Parse parses the words of a single packet. Packets of unknown or reserved message types are returned as Unknown.
+// The error has the cause midi.ErrInvalidMessage, if the number of words does not match the message type or the
+// packet contains an invalid MIDI 1.0 message.
+func Parse(words ...uint32) (Message, error) {
+	if len(words) == 0 || len(words) != Size(words[0]) {
+		return nil, midilib.InvalidMessage(raw(words...), "UMP")
+	}
+
+	w0 := words[0]
+	group := uint8(w0>>24) & 0x0F
+
+	switch w0 >> 28 {
+	case typeUtility:
+		return parseUtility(w0), nil
+	case typeSystem, typeMIDI1:
+		return parseMIDI1(w0)
+	case typeData64:
+		return SysEx7{group: group, status: uint8(w0>>20) & 0x0F, data: sysex7Data(words)}, nil
+	case typeMIDI2:
+		return parseMIDI2(w0, words[1]), nil
+	case typeData128:
+		return Data128{group: group, status: uint8(w0>>20) & 0x0F, words: [4]uint32{words[0], words[1], words[2], words[3]}}, nil
+	default:
+		return Unknown(append([]uint32(nil), words...)), nil
+	}
+}
+
+// parseMIDI1 parses the MIDI 1.0 channel voice and system messages
+func parseMIDI1(w0 uint32) (Message, error) {
+	var (
+		group  = uint8(w0>>24) & 0x0F
+		status = byte(w0 >> 16)
+		bt     = []byte{status, byte(w0 >> 8), byte(w0)}
+		msg    midi.Message
+		err    error
+	)
+
+	switch {
+	case w0>>28 == typeMIDI1 && status >= 0x80 && status < 0xF0:
+		if status>>4 == 0xC || status>>4 == 0xD {
+			bt = bt[:2]
+		}
+		msg, err = channel.ParseMessage(bt)
+	case w0>>28 == typeSystem && status >= 0xF8:
+		msg, err = realtime.ParseMessage(bt[:1])
+	case w0>>28 == typeSystem && status > 0xF0 && status < 0xF7:
+		switch status {
+		case 0xF1, 0xF3:
+			bt = bt[:2]
+		case 0xF6:
+			bt = bt[:1]
+		}
+		msg, err = syscommon.ParseMessage(bt)
+	default:
+		return nil, midilib.InvalidMessage(raw(w0), "UMP")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if w0>>28 == typeSystem {
+		return System{group: group, msg: msg}, nil
+	}
+
+	return MIDI1{group: group, msg: msg}, nil
+}
+
+// raw returns the bytes of the words in big endian order
+func raw(words ...uint32) []byte {
+	b := make([]byte, 0, len(words)*4)
+	for _, w := range words {
+		b = append(b, byte(w>>24), byte(w>>16), byte(w>>8), byte(w))
+	}
+	return b
+}
+
+// hex returns the words as hex string
+func hex(words []uint32) string {
+	var s string
+	for i, w := range words {
+		if i > 0 {
+			s += " "
+		}
+		h := strconv.FormatUint(uint64(w), 16)
+		for len(h) < 8 {
+			h = "0" + h
+		}
+		s += h
+	}
+	return s
+}
+
+// itoa returns the decimal representation of n
+func itoa(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
+
+// MIDI1 is a MIDI 1.0 channel voice message in a packet (message type 2). Message returns the channel message.
+type MIDI1 struct {
+	group uint8
+	msg   midi.Message
+}
+
+// Group returns the group of the message
+func (m MIDI1) Group() uint8 {
+	return m.group
+}
+
+// Message returns the MIDI 1.0 channel message
+func (m MIDI1) Message() midi.Message {
+	return m.msg
+}
+
+// Words returns the word of the packet
+func (m MIDI1) Words() []uint32 {
+	return []uint32{midi1Word(typeMIDI1, m.group, m.msg.Raw())}
+}
+
+// Raw returns the bytes of the packet
+func (m MIDI1) Raw() []byte {
+	return raw(m.Words()...)
+}
+
+// String returns the group and the channel message
+func (m MIDI1) String() string {
+	return "ump.MIDI1 group " + itoa(uint64(m.group)) + " " + m.msg.String()
+}
+
+// System is a system realtime or system common message in a packet (message type 1). Message returns the
+// realtime or syscommon message.
+type System struct {
+	group uint8
+	msg   midi.Message
+}
+
+// Group returns the group of the message
+func (m System) Group() uint8 {
+	return m.group
+}
+
+// Message returns the realtime or syscommon message
+func (m System) Message() midi.Message {
+	return m.msg
+}
+
+// Words returns the word of the packet
+func (m System) Words() []uint32 {
+	return []uint32{midi1Word(typeSystem, m.group, m.msg.Raw())}
+}
+
+// Raw returns the bytes of the packet
+func (m System) Raw() []byte {
+	return raw(m.Words()...)
+}
+
+// String returns the group and the system message
+func (m System) String() string {
+	return "ump.System group " + itoa(uint64(m.group)) + " " + m.msg.String()
+}
+
+// midi1Word returns the word for the MIDI 1.0 message bytes
+func midi1Word(typ, group uint8, bt []byte) uint32 {
+	w := uint32(typ)<<28 | uint32(group&0x0F)<<24
+	for i := 0; i < len(bt) && i < 3; i++ {
+		w |= uint32(bt[i]) << (16 - 8*uint(i))
+	}
+	return w
+}
+
+// Utility is a utility message (message type 0): NoOp, JRClock or JRTimestamp and others
+type Utility struct {
+	status uint8
+	data   uint32
+}
+
+// NoOp is the utility message without operation
+var NoOp = Utility{}
+
+// utility statuses
+const (
+	utilityNoOp        = 0x0
+	utilityJRClock     = 0x1
+	utilityJRTimestamp = 0x2
+)
+
+// JRClock returns a jitter reduction clock message with the sender clock time in units of 1/31250 seconds
+func JRClock(time uint16) Utility {
+	return Utility{status: utilityJRClock, data: uint32(time)}
+}
+
+// JRTimestamp returns a jitter reduction timestamp message with the time in units of 1/31250 seconds
+func JRTimestamp(time uint16) Utility {
+	return Utility{status: utilityJRTimestamp, data: uint32(time)}
+}
+
+func parseUtility(w0 uint32) Utility {
+	return Utility{status: uint8(w0>>20) & 0x0F, data: w0 & 0xFFFFF}
+}
+
+// Group returns 0, utility messages have no group
+func (m Utility) Group() uint8 {
+	return 0
+}
+
+// Status returns the status of the utility message (0 for NoOp, 1 for JRClock, 2 for JRTimestamp)
+func (m Utility) Status() uint8 {
+	return m.status
+}
+
+// Time returns the time of the jitter reduction messages in units of 1/31250 seconds
+func (m Utility) Time() uint16 {
+	return uint16(m.data)
+}
+
+// Words returns the word of the packet
+func (m Utility) Words() []uint32 {
+	return []uint32{uint32(m.status&0x0F)<<20 | m.data&0xFFFFF}
+}
+
+// Raw returns the bytes of the packet
+func (m Utility) Raw() []byte {
+	return raw(m.Words()...)
+}
+
+// String returns the kind and the time of the utility message
+func (m Utility) String() string {
+	switch m.status {
+	case utilityNoOp:
+		return "ump.NoOp"
+	case utilityJRClock:
+		return "ump.JRClock time " + itoa(uint64(m.Time()))
+	case utilityJRTimestamp:
+		return "ump.JRTimestamp time " + itoa(uint64(m.Time()))
+	default:
+		return "ump.Utility status " + itoa(uint64(m.status)) + " data " + itoa(uint64(m.data))
+	}
+}
+
+// statuses of SysEx7 packets
+const (
+	// SysExComplete is the status of a sysex that fits in a single packet
+	SysExComplete = 0x0
+
+	// SysExStart is the status of the first packet of a sysex
+	SysExStart = 0x1
+
+	// SysExContinue is the status of a packet in the middle of a sysex
+	SysExContinue = 0x2
+
+	// SysExEnd is the status of the last packet of a sysex
+	SysExEnd = 0x3
+)
+
+// SysEx7 is a packet of up to 6 bytes of a system exclusive message (message type 3), without F0 and F7
+type SysEx7 struct {
+	group  uint8
+	status uint8
+	data   []byte
+}
+
+// NewSysEx7 returns a packet of a sysex with the given status (see SysExComplete). Only up to 6 bytes are kept.
+func NewSysEx7(group, status uint8, data []byte) SysEx7 {
+	if len(data) > 6 {
+		data = data[:6]
+	}
+	return SysEx7{group: group & 0x0F, status: status & 0x0F, data: append([]byte(nil), data...)}
+}
+
+// sysex7Data returns the data bytes of a SysEx7 packet
+func sysex7Data(words []uint32) []byte {
+	n := int(words[0]>>16) & 0x0F
+	if n > 6 {
+		n = 6
+	}
+	return append([]byte(nil), raw(words...)[2:2+n]...)
+}
+
+// Group returns the group of the message
+func (m SysEx7) Group() uint8 {
+	return m.group
+}
+
+// Status returns the status of the packet (see SysExComplete)
+func (m SysEx7) Status() uint8 {
+	return m.status
+}
+
+// Data returns the bytes of the packet
+func (m SysEx7) Data() []byte {
+	return m.data
+}
+
+// Words returns the words of the packet
+func (m SysEx7) Words() []uint32 {
+	var b [8]byte
+	b[0] = typeData64<<4 | m.group&0x0F
+	b[1] = m.status<<4 | uint8(len(m.data))
+	copy(b[2:], m.data)
+	return []uint32{
+		uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]),
+		uint32(b[4])<<24 | uint32(b[5])<<16 | uint32(b[6])<<8 | uint32(b[7]),
+	}
+}
+
+// Raw returns the bytes of the packet
+func (m SysEx7) Raw() []byte {
+	return raw(m.Words()...)
+}
+
+// String returns the group, status and data of the packet
+func (m SysEx7) String() string {
+	s := "ump.SysEx7 group " + itoa(uint64(m.group)) + " status " + itoa(uint64(m.status)) + " data"
+	for _, b := range m.data {
+		s += " " + itoa(uint64(b))
+	}
+	return s
+}
+
+// Data128 is a 128-bit data message (message type 5), i.e. a packet of a SysEx8 or mixed data set message
+type Data128 struct {
+	group  uint8
+	status uint8
+	words  [4]uint32
+}
+
+// Group returns the group of the message
+func (m Data128) Group() uint8 {
+	return m.group
+}
+
+// Status returns the status of the packet (0-3 for SysEx8 like for SysEx7, 8 and 9 for mixed data sets)
+func (m Data128) Status() uint8 {
+	return m.status
+}
+
+// Words returns the words of the packet
+func (m Data128) Words() []uint32 {
+	return m.words[:]
+}
+
+// Raw returns the bytes of the packet
+func (m Data128) Raw() []byte {
+	return raw(m.Words()...)
+}
+
+// String returns the group, status and the words of the packet
+func (m Data128) String() string {
+	return "ump.Data128 group " + itoa(uint64(m.group)) + " status " + itoa(uint64(m.status)) + " words " + hex(m.words[:])
+}
+
+// Unknown is a packet of a message type that is not supported (e.g. flex data or UMP stream) or reserved
+type Unknown []uint32
+
+// Group returns the group of the packet
+func (m Unknown) Group() uint8 {
+	if len(m) == 0 {
+		return 0
+	}
+	return uint8(m[0]>>24) & 0x0F
+}
+
+// Words returns the words of the packet
+func (m Unknown) Words() []uint32 {
+	return m
+}
+
+// Raw returns the bytes of the packet
+func (m Unknown) Raw() []byte {
+	return raw(m...)
+}
+
+// String returns the words of the packet
+func (m Unknown) String() string {
+	return "ump.Unknown " + hex(m)
+}
@@ -0,0 +1,141 @@
+package ump
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/channel"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midimessage/syscommon"
+	"github.com/gomidi/midi/transform"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		input    Message
+		expected string
+		words    string
+	}{
+		{NoOp, "ump.NoOp", "00000000"},
+		{JRTimestamp(1000), "ump.JRTimestamp time 1000", "002003e8"},
+		{System{1, realtime.TimingClock}, "ump.System group 1 TimingClock", "11f80000"},
+		{System{0, syscommon.SPP(4)}, "ump.System group 0 syscommon.SPP: 4", "10f20400"},
+		{System{0, syscommon.SongSelect(3)}, "ump.System group 0 syscommon.SongSelect: 3", "10f30300"},
+		{MIDI1{2, channel.Channel3.NoteOn(60, 100)}, "ump.MIDI1 group 2 channel.NoteOn channel 3 key 60 velocity 100", "22933c64"},
+		{MIDI1{0, channel.Channel0.ProgramChange(5)}, "ump.MIDI1 group 0 channel.ProgramChange channel 0 program 5", "20c00500"},
+		{Ch(0, 1).NoteOn(60, 0x8000), "ump.NoteOn group 0 channel 1 key 60 velocity 32768", "40913c00 80000000"},
+		{Ch(0, 1).NoteOnAttribute(60, 0xFFFF, 3, 0x1234), "ump.NoteOn group 0 channel 1 key 60 velocity 65535 attribute 3 4660", "40913c03 ffff1234"},
+		{Ch(15, 15).NoteOff(60, 0), "ump.NoteOff group 15 channel 15 key 60 velocity 0", "4f8f3c00 00000000"},
+		{Ch(0, 0).PolyPressure(61, 1<<31), "ump.PolyPressure group 0 channel 0 key 61 pressure 2147483648", "40a03d00 80000000"},
+		{Ch(0, 0).ControlChange(7, 0xFFFFFFFF), "ump.ControlChange group 0 channel 0 controller 7 value 4294967295", "40b00700 ffffffff"},
+		{Ch(0, 0).ProgramChange(5), "ump.ProgramChange group 0 channel 0 program 5", "40c00000 05000000"},
+		{Ch(0, 0).ProgramChangeBank(5, 1, 2), "ump.ProgramChange group 0 channel 0 program 5 bank 1 2", "40c00001 05000102"},
+		{Ch(0, 0).ChannelPressure(42), "ump.ChannelPressure group 0 channel 0 pressure 42", "40d00000 0000002a"},
+		{Ch(0, 0).Pitchbend(PitchbendCenter), "ump.Pitchbend group 0 channel 0 value 2147483648", "40e00000 80000000"},
+		{Ch(0, 0).Registered(0, 1, 100), "ump.Registered group 0 channel 0 bank 0 index 1 value 100", "40200001 00000064"},
+		{Ch(0, 0).Assignable(3, 4, 5), "ump.Assignable group 0 channel 0 bank 3 index 4 value 5", "40300304 00000005"},
+		{Ch(0, 0).RelativeRegistered(0, 1, -2), "ump.RelativeRegistered group 0 channel 0 bank 0 index 1 change -2", "40400001 fffffffe"},
+		{Ch(0, 0).RelativeAssignable(0, 1, 2), "ump.RelativeAssignable group 0 channel 0 bank 0 index 1 change 2", "40500001 00000002"},
+		{Ch(0, 0).PerNoteRegistered(60, 7, 9), "ump.PerNoteRegistered group 0 channel 0 key 60 index 7 value 9", "40003c07 00000009"},
+		{Ch(0, 0).PerNoteAssignable(60, 7, 9), "ump.PerNoteAssignable group 0 channel 0 key 60 index 7 value 9", "40103c07 00000009"},
+		{Ch(0, 0).PerNotePitchbend(60, 1), "ump.PerNotePitchbend group 0 channel 0 key 60 value 1", "40603c00 00000001"},
+		{Ch(0, 0).PerNoteManagement(60, true, true), "ump.PerNoteManagement group 0 channel 0 key 60 detach reset", "40f03c03 00000000"},
+		{NewSysEx7(0, SysExComplete, []byte{0x7E, 0x7F, 0x06, 0x01}), "ump.SysEx7 group 0 status 0 data 126 127 6 1", "30047e7f 06010000"},
+		{Data128{0, 8, [4]uint32{0x50800000, 1, 2, 3}}, "ump.Data128 group 0 status 8 words 50800000 00000001 00000002 00000003", "50800000 00000001 00000002 00000003"},
+		{Unknown{0xD0000000, 0, 0, 0}, "ump.Unknown d0000000 00000000 00000000 00000000", "d0000000 00000000 00000000 00000000"},
+		{Unknown{0xB0000000, 0, 0}, "ump.Unknown b0000000 00000000 00000000", "b0000000 00000000 00000000"},
+	}
+
+	var bf bytes.Buffer
+
+	for _, test := range tests {
+		bf.Write(test.input.Raw())
+	}
+
+	rd := NewReader(&bf)
+
+	for i, test := range tests {
+		msg, err := rd.Read()
+
+		if err != nil {
+			t.Fatalf("[%v] unexpected error: %v", i, err)
+		}
+
+		if got, want := msg.String(), test.expected; got != want {
+			t.Errorf("[%v] got: %#v; wanted %#v", i, got, want)
+		}
+
+		if got, want := hex(msg.(Message).Words()), test.words; got != want {
+			t.Errorf("[%v] words: %#v; wanted %#v", i, got, want)
+		}
+	}
+
+	if _, err := rd.Read(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	on := Ch(3, 4).NoteOnAttribute(60, 1000, 3, 7)
+
+	if got, want := fmt.Sprint(on.Group(), on.Channel(), on.Key(), on.Velocity(), on.AttributeType(), on.Attribute()), "3 4 60 1000 3 7"; got != want {
+		t.Errorf("NoteOn: %v; wanted %v", got, want)
+	}
+
+	pc := Ch(0, 0).ProgramChangeBank(5, 1, 2)
+	msb, lsb, ok := pc.Bank()
+
+	if got, want := fmt.Sprint(pc.Program(), msb, lsb, ok), "5 1 2 true"; got != want {
+		t.Errorf("ProgramChange: %v; wanted %v", got, want)
+	}
+
+	if got, want := Ch(0, 0).Pitchbend(0).Bend(), int64(-PitchbendCenter); got != want {
+		t.Errorf("Bend() = %v; wanted %v", got, want)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	// a MIDI 2.0 packet without its second word
+	_, err := NewReader(bytes.NewReader([]byte{0x40, 0x90, 0x3c, 0x00, 0x80})).Read()
+
+	var re *midi.ReadError
+
+	if !errors.Is(err, midi.ErrUnexpectedEOF) || !errors.As(err, &re) || re.Offset != 5 || !re.Fatal {
+		t.Errorf("expected fatal ErrUnexpectedEOF at offset 5, got %v", err)
+	}
+
+	// a MIDI 1.0 packet with a system message, followed by a valid packet
+	rd := NewReader(bytes.NewReader([]byte{0x20, 0xF8, 0x00, 0x00, 0x10, 0xF8, 0x00, 0x00}))
+	_, err = rd.Read()
+
+	if !errors.Is(err, midi.ErrInvalidMessage) || !errors.As(err, &re) || re.Fatal {
+		t.Errorf("expected ErrInvalidMessage, got %v", err)
+	}
+
+	if msg, err := rd.Read(); err != nil || msg.String() != "ump.System group 0 TimingClock" {
+		t.Errorf("expected the next packet, got %v, %v", msg, err)
+	}
+
+	if _, err := Parse(0x40000000); !errors.Is(err, midi.ErrInvalidMessage) {
+		t.Errorf("expected ErrInvalidMessage for missing word, got %v", err)
+	}
+}
+
+func TestNoChannelMessage(t *testing.T) {
+	on := Ch(0, 1).NoteOn(60, 0x8000)
+
+	if _, is := midi.Message(on).(channel.Message); is {
+		t.Fatalf("ump.NoteOn must not be a channel.Message")
+	}
+
+	// the channel transforms must pass them through
+	out := transform.ForceChannel(4).Transform(on)
+
+	if len(out) != 1 || out[0] != midi.Message(on) {
+		t.Errorf("got: %v; wanted %v", out, on)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2017 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package webmidi connects the inputs and outputs of the Web MIDI API of browsers to midi.Reader and midi.Writer,
+so that browser based Go programs (GOOS=js GOARCH=wasm) can use the same processing code as native programs.
+
+The package is only available when compiling for js/wasm.
+
+Usage
+
+	import (
+		"github.com/gomidi/midi/webmidi"
+	)
+
+	access, err := webmidi.RequestAccess(false)
+
+	ins := access.Inputs()
+	outs := access.Outputs()
+
+	rd := webmidi.NewReader(ins[0], nil)
+	defer rd.Close()
+
+	wr := webmidi.NewWriter(outs[0])
+
+	err = midi.Pipe(rd, wr, transform.Transpose{Semitones: 12})
+
+*/
+package webmidi
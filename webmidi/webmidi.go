@@ -0,0 +1,224 @@
+//go:build js && wasm
+// +build js,wasm
+
+package webmidi
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"syscall/js"
+
+	"github.com/gomidi/midi"
+	"github.com/gomidi/midi/midimessage/realtime"
+	"github.com/gomidi/midi/midireader"
+)
+
+// Port is an input or output port of the Web MIDI API
+type Port struct {
+	js.Value
+}
+
+// ID returns the id of the port
+func (p Port) ID() string {
+	return p.Get("id").String()
+}
+
+// Name returns the name of the port
+func (p Port) Name() string {
+	return p.Get("name").String()
+}
+
+// Manufacturer returns the manufacturer of the port
+func (p Port) Manufacturer() string {
+	return p.Get("manufacturer").String()
+}
+
+// Access is the MIDIAccess object of the Web MIDI API
+type Access struct {
+	js.Value
+}
+
+// RequestAccess requests the access to the MIDI devices via navigator.requestMIDIAccess and waits until
+// the user granted or denied it. If sysex is true, the permission to send and receive sysex is requested too.
+// RequestAccess blocks, so it must not be called from a javascript callback.
+func RequestAccess(sysex bool) (*Access, error) {
+	navigator := js.Global().Get("navigator")
+
+	if navigator.IsUndefined() || navigator.Get("requestMIDIAccess").IsUndefined() {
+		return nil, errors.New("Web MIDI API not supported")
+	}
+
+	opts := js.Global().Get("Object").New()
+	opts.Set("sysex", sysex)
+
+	type result struct {
+		access js.Value
+		err    error
+	}
+
+	ch := make(chan result, 1)
+
+	var onSuccess, onFailure js.Func
+
+	onSuccess = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		ch <- result{access: args[0]}
+		return nil
+	})
+
+	onFailure = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		ch <- result{err: errors.New("MIDI access denied: " + args[0].Call("toString").String())}
+		return nil
+	})
+
+	defer onSuccess.Release()
+	defer onFailure.Release()
+
+	navigator.Call("requestMIDIAccess", opts).Call("then", onSuccess, onFailure)
+
+	res := <-ch
+	if res.err != nil {
+		return nil, res.err
+	}
+	return &Access{res.access}, nil
+}
+
+func ports(m js.Value) (ps []Port) {
+	fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		ps = append(ps, Port{args[0]})
+		return nil
+	})
+	m.Call("forEach", fn)
+	fn.Release()
+	return
+}
+
+// Inputs returns the available input ports
+func (a *Access) Inputs() []Port {
+	return ports(a.Get("inputs"))
+}
+
+// Outputs returns the available output ports
+func (a *Access) Outputs() []Port {
+	return ports(a.Get("outputs"))
+}
+
+// Reader is a midi.Reader that reads the messages received by an input port
+type Reader struct {
+	port      Port
+	rthandler func(realtime.Message)
+	parser    *midireader.Parser
+	onMessage js.Func
+	messages  chan midi.Message
+	closed    chan struct{}
+	once      sync.Once
+}
+
+// NewReader returns a reader for the given input port. It registers the onmidimessage handler of the port.
+// Realtime messages are passed to rthandler (if not nil) and are not returned by Read.
+// Of the options, NoteOffVelocity, ValidateSysEx and SysExHeader are supported.
+func NewReader(input Port, rthandler func(realtime.Message), options ...midireader.Option) *Reader {
+	r := &Reader{
+		port:      input,
+		rthandler: rthandler,
+		parser:    midireader.NewParser(options...),
+		messages:  make(chan midi.Message, 1024),
+		closed:    make(chan struct{}),
+	}
+
+	r.onMessage = js.FuncOf(r.handle)
+	input.Set("onmidimessage", r.onMessage)
+	return r
+}
+
+// handle is the onmidimessage handler
+func (r *Reader) handle(this js.Value, args []js.Value) interface{} {
+	data := args[0].Get("data")
+	bf := make([]byte, data.Get("length").Int())
+	js.CopyBytesToGo(bf, data)
+
+	for _, b := range bf {
+		msg, ok := r.parser.Feed(b)
+		if !ok {
+			continue
+		}
+
+		if rt, is := msg.(realtime.Message); is {
+			if r.rthandler != nil {
+				r.rthandler(rt)
+			}
+			continue
+		}
+
+		// javascript callbacks must not block: drop the message if the reader does not keep up
+		select {
+		case r.messages <- msg:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Name returns the name of the port
+func (r *Reader) Name() string {
+	return r.port.Name()
+}
+
+// Read returns the next message. It blocks until a message is received or the reader is closed,
+// in which case io.EOF is returned.
+// Read must not be called from a javascript callback.
+func (r *Reader) Read() (midi.Message, error) {
+	select {
+	case msg := <-r.messages:
+		return msg, nil
+	case <-r.closed:
+		return nil, io.EOF
+	}
+}
+
+// Close unregisters the onmidimessage handler
+func (r *Reader) Close() error {
+	r.once.Do(func() {
+		r.port.Set("onmidimessage", js.Null())
+		r.onMessage.Release()
+		close(r.closed)
+	})
+	return nil
+}
+
+// Writer is a midi.Writer that sends messages to an output port
+type Writer struct {
+	port Port
+}
+
+// NewWriter returns a writer for the given output port.
+// Since the Web MIDI API expects complete messages, running status is not used.
+func NewWriter(output Port) *Writer {
+	return &Writer{port: output}
+}
+
+// Write sends the message to the output port.
+// Exceptions thrown by the send method of the port are returned as errors.
+func (w *Writer) Write(msg midi.Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if jsErr, is := r.(js.Error); is {
+				err = jsErr
+				return
+			}
+			panic(r)
+		}
+	}()
+
+	raw := msg.Raw()
+	arr := js.Global().Get("Uint8Array").New(len(raw))
+	js.CopyBytesToJS(arr, raw)
+	w.port.Call("send", arr)
+	return nil
+}
+
+// Name returns the name of the port
+func (w *Writer) Name() string {
+	return w.port.Name()
+}